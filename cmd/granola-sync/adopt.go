@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+var adoptUpgrade bool
+
+func newAdoptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "Link existing hand-made meeting pages to Granola meetings",
+		Long: `Scan the Logseq graph for meeting pages you created by hand that match
+meetings in the Granola cache (by title and date) and record them in the sync
+state, so syncing doesn't create duplicate pages for them.
+
+Adopted pages are left as they are, even when the meeting changes in Granola,
+unless --upgrade is given, which regenerates them in the standard granola-sync
+format so sync keeps them up to date. --upgrade also upgrades pages adopted
+earlier.`,
+		RunE: runAdopt,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().BoolVar(&adoptUpgrade, "upgrade", false, "regenerate adopted pages in the granola-sync format")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show matches without changing anything")
+	return cmd
+}
+
+func runAdopt(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	result, err := syncer.Adopt(adoptUpgrade, dryRun)
+	if err != nil {
+		return fmt.Errorf("adopt failed: %w", err)
+	}
+
+	if len(result.Matches) == 0 {
+		fmt.Println("No matching hand-made meeting pages found.")
+	}
+	for _, m := range result.Matches {
+		fmt.Printf("%s\n  granola-id: %s\n  page: %s\n", m.Title, m.DocumentID, m.PagePath)
+		if m.NewPath != m.PagePath {
+			fmt.Printf("  upgraded: %s\n", m.NewPath)
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDRY RUN - %d page(s) would be adopted\n", len(result.Matches))
+	} else {
+		fmt.Printf("\nAdopted %d page(s)\n", len(result.Matches))
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("Errors: %d\n", len(result.Errors))
+		for _, e := range result.Errors {
+			slog.Error("adopt error", "error", e)
+		}
+	}
+
+	return nil
+}
@@ -41,7 +41,12 @@ Examples:
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load("")
+	// Setting a value saves the file, so leave environment overrides out of it
+	load := config.Load
+	if len(args) == 2 {
+		load = config.LoadFile
+	}
+	cfg, err := load(cfgPath)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -70,7 +75,7 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		if err := cfg.Set(args[0], args[1]); err != nil {
 			return err
 		}
-		if err := cfg.Save(""); err != nil {
+		if err := cfg.Save(cfgPath); err != nil {
 			return fmt.Errorf("saving config: %w", err)
 		}
 		fmt.Printf("Set %s = %s\n", args[0], args[1])
@@ -116,7 +121,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	cfg.UserName = userName
 
 	// Save config
-	configPath := config.ConfigPath()
+	configPath := config.ProfileConfigPath(profile)
 	if err := cfg.Save(configPath); err != nil {
 		return fmt.Errorf("saving config: %w", err)
 	}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/control"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newPauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause watch mode syncing",
+		Long:  "Stop the daemon syncing meetings until resumed. Manual syncs still run.",
+		Args:  cobra.NoArgs,
+		RunE:  runPause,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
+}
+
+func newResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume watch mode syncing",
+		Args:  cobra.NoArgs,
+		RunE:  runResume,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
+}
+
+func newSyncNowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "now",
+		Short: "Sync all new and updated meetings now",
+		Long: `Ask the running daemon to sync every new and updated meeting now, even while
+syncing is paused, and wait for it to finish. Without a daemon, the sync runs here.`,
+		Args: cobra.NoArgs,
+		RunE: runSyncNow,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+	return cmd
+}
+
+// daemonClient returns a client for the daemon's control socket, or nil if the
+// socket is disabled in config
+func daemonClient(cfg *config.Config) *control.Client {
+	if cfg.ControlSocket == "" {
+		return nil
+	}
+	return control.NewClient(cfg.ControlSocket)
+}
+
+// askDaemon runs fn against the daemon, reporting false if no daemon is
+// listening so the caller can do the work itself
+func askDaemon(cfg *config.Config, fn func(ctx context.Context, c *control.Client) error) (bool, error) {
+	client := daemonClient(cfg)
+	if client == nil {
+		return false, nil
+	}
+	err := fn(context.Background(), client)
+	if errors.Is(err, control.ErrNotRunning) {
+		return false, nil
+	}
+	return true, err
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := pauseSyncing(cfg, true); err != nil {
+		return err
+	}
+	fmt.Println(`Syncing paused. Run "granola-sync resume" to resume.`)
+	return nil
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := pauseSyncing(cfg, false); err != nil {
+		return err
+	}
+	fmt.Println("Syncing resumed.")
+	return nil
+}
+
+// pauseSyncing pauses or resumes the daemon, or the state store when no daemon
+// is listening
+func pauseSyncing(cfg *config.Config, paused bool) error {
+	if paused {
+		asked, err := askDaemon(cfg, func(ctx context.Context, c *control.Client) error { return c.Pause(ctx) })
+		if err != nil {
+			return fmt.Errorf("pausing daemon: %w", err)
+		}
+		if asked {
+			return nil
+		}
+	} else {
+		asked, err := askDaemon(cfg, func(ctx context.Context, c *control.Client) error { return c.Resume(ctx) })
+		if err != nil {
+			return fmt.Errorf("resuming daemon: %w", err)
+		}
+		if asked {
+			return nil
+		}
+	}
+	return setPaused(paused)
+}
+
+// setPaused pauses or resumes syncing in the state store, for when no daemon is
+// listening; a daemon started later picks it up
+func setPaused(paused bool) error {
+	_, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+	if paused {
+		err = sync.Pause(store, time.Now())
+	} else {
+		err = sync.Resume(store)
+	}
+	if err != nil {
+		return fmt.Errorf("updating pause state: %w", err)
+	}
+	return nil
+}
+
+func runSyncNow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	var result *control.SyncResult
+	asked, err := askDaemon(cfg, func(ctx context.Context, c *control.Client) error {
+		var err error
+		result, err = c.Sync(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
+	if asked {
+		fmt.Printf("Sync complete:\n")
+		fmt.Printf("  New meetings: %d\n", result.NewMeetings)
+		fmt.Printf("  Updated meetings: %d\n", result.UpdatedMeetings)
+		if result.AdoptedMeetings > 0 {
+			fmt.Printf("  Adopted pages: %d\n", result.AdoptedMeetings)
+		}
+		fmt.Printf("  Journal entries: %d\n", result.NewJournals)
+		if len(result.Errors) > 0 {
+			fmt.Printf("  Errors: %d (see \"granola-sync logs\")\n", len(result.Errors))
+		}
+		return nil
+	}
+
+	fmt.Println("No daemon is running; syncing here.")
+	_, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+	syncResult, err := syncer.Sync(sync.SyncOptions{})
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
+	fmt.Printf("\nSync complete:\n")
+	printSyncResult(syncResult)
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	stdsync "sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/control"
+	"github.com/philrhinehart/granola-sync/internal/health"
+	"github.com/philrhinehart/granola-sync/internal/state"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+// daemon is watch mode as seen through the control socket
+type daemon struct {
+	syncer  *sync.Syncer
+	store   state.Store
+	opts    sync.SyncOptions
+	status  *health.Status
+	mu      *stdsync.Mutex // Held while the syncer is in use
+	started time.Time
+	syncing atomic.Bool
+}
+
+// watchSync runs a watch mode sync unless syncing is paused
+func (d *daemon) watchSync() error {
+	paused, err := sync.PausedSince(d.store)
+	if err != nil {
+		return err
+	}
+	if paused != nil {
+		slog.Info("syncing paused", "since", paused.Local().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	result, err := d.sync()
+	if err != nil {
+		return err
+	}
+	if result.NewMeetings > 0 || result.UpdatedMeetings > 0 {
+		slog.Info("sync complete",
+			"new", result.NewMeetings,
+			"updated", result.UpdatedMeetings,
+			"journals", result.NewJournals,
+		)
+	}
+	return nil
+}
+
+// sync runs a sync once the syncer is free, and records its outcome for the
+// health endpoint
+func (d *daemon) sync() (*sync.SyncResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.syncing.Store(true)
+	defer d.syncing.Store(false)
+
+	result, err := d.syncer.Sync(d.opts)
+	if err != nil {
+		d.status.RecordSync(time.Now(), err)
+		return nil, err
+	}
+	var syncErr error
+	if n := len(result.Errors); n > 0 {
+		syncErr = fmt.Errorf("%d meetings failed to sync, last: %w", n, result.Errors[n-1])
+	}
+	d.status.RecordSync(time.Now(), syncErr)
+	return result, nil
+}
+
+func (d *daemon) Status() (*control.Status, error) {
+	paused, err := sync.PausedSince(d.store)
+	if err != nil {
+		return nil, err
+	}
+	return &control.Status{
+		PID:       os.Getpid(),
+		StartedAt: d.started,
+		Syncing:   d.syncing.Load(),
+		PausedAt:  paused,
+		Health:    d.status.Report(),
+	}, nil
+}
+
+func (d *daemon) Sync(ctx context.Context) (*control.SyncResult, error) {
+	slog.Info("sync requested over the control socket")
+	result, err := d.sync()
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]string, len(result.Errors))
+	for i, e := range result.Errors {
+		errs[i] = e.Error()
+	}
+	return &control.SyncResult{
+		NewMeetings:     result.NewMeetings,
+		UpdatedMeetings: result.UpdatedMeetings,
+		AdoptedMeetings: result.AdoptedMeetings,
+		NewJournals:     result.NewJournals,
+		Errors:          errs,
+	}, nil
+}
+
+func (d *daemon) Pause() error {
+	slog.Info("syncing paused over the control socket")
+	return sync.Pause(d.store, time.Now())
+}
+
+func (d *daemon) Resume() error {
+	slog.Info("syncing resumed over the control socket")
+	return sync.Resume(d.store)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/doctor"
+)
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the setup and suggest fixes",
+		Long: `Check that the Granola cache exists and parses, the cache version is
+supported, the Logseq graph is valid and writable, the state store opens, and
+the launchd service is running from a binary that still exists. Each failed
+check prints a hint on how to fix it.`,
+		RunE: runDoctor,
+		// Failed checks are reported above; usage would bury them
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		fmt.Printf("[%s] config: %v\n", doctor.Fail, err)
+		fmt.Println("       fix the config file, or recreate it with: granola-sync config init")
+		return fmt.Errorf("config could not be loaded")
+	}
+	setupLogging(cfg.LogFormat)
+
+	failed := 0
+	for _, r := range doctor.Run(cfg) {
+		fmt.Printf("[%s] %s: %s\n", r.Status, r.Name, r.Detail)
+		if r.Hint != "" {
+			fmt.Printf("       %s\n", r.Hint)
+		}
+		if r.Status == doctor.Fail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	fmt.Println("\nAll checks passed.")
+	return nil
+}
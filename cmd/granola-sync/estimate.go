@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newEstimateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate what a backfill would write",
+		Long: `Parse the Granola cache, apply the configured filters, and report how many
+pages and journal entries a backfill would write, roughly how much disk it
+would use, and how long it would take going by past syncs. Nothing is written.`,
+		RunE: runEstimate,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().StringVar(&sinceStr, "since", "", "only count meetings since date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&untilStr, "until", "", "only count meetings before date (YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&force, "force", false, "count every meeting, as backfill --force rewrites them even if already synced")
+	return cmd
+}
+
+func runEstimate(cmd *cobra.Command, args []string) error {
+	opts := sync.SyncOptions{Force: force}
+	if sinceStr != "" {
+		t, err := time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			return fmt.Errorf("parsing since date: %w", err)
+		}
+		opts.Since = &t
+	}
+	if untilStr != "" {
+		t, err := time.Parse("2006-01-02", untilStr)
+		if err != nil {
+			return fmt.Errorf("parsing until date: %w", err)
+		}
+		opts.Until = &t
+	}
+	if opts.Since != nil && opts.Until != nil && !opts.Until.After(*opts.Since) {
+		return errors.New("--until must be after --since")
+	}
+
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	est, err := syncer.Estimate(opts)
+	if err != nil {
+		return fmt.Errorf("estimating backfill: %w", err)
+	}
+
+	if est.Meetings() == 0 {
+		fmt.Println("Nothing to sync: every matching meeting is up to date.")
+		return nil
+	}
+	if cfg.JournalOnly {
+		fmt.Printf("Meetings:         %d (%d new, %d changed)\n", est.Meetings(), est.NewPages, est.UpdatedPages)
+	} else {
+		fmt.Printf("Pages:            %d new, %d updated\n", est.NewPages, est.UpdatedPages)
+	}
+	fmt.Printf("Journal entries:  %d (%d new journal files)\n", est.JournalEntries, est.NewJournals)
+	fmt.Printf("Disk:             about %s\n", formatBytes(est.Bytes))
+	if est.Duration > 0 {
+		fmt.Printf("Time:             about %s (from past syncs)\n", est.Duration.Round(time.Second))
+	} else {
+		fmt.Println("Time:             unknown until a sync has written some meetings")
+	}
+	return nil
+}
+
+// formatBytes formats a size in bytes with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
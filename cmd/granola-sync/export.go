@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+var (
+	exportOutput  string
+	exportFormat  string
+	exportEncrypt []string
+	exportUntil   string
+)
+
+// Export formats
+const (
+	exportFormatTar    = "tar"
+	exportFormatJSON   = "json"
+	exportFormatNDJSON = "ndjson"
+	exportFormatSQLite = "sqlite"
+	exportFormatAnki   = "anki"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export meetings as a tarball of pages, JSON, a SQLite database or flashcards",
+		Long: `Export the meetings granola-sync would sync, without touching the Logseq
+graph or sync state.
+
+The default format, tar, packages the rendered meeting pages as a gzipped
+tarball. --format json writes an array of normalized meeting records (id,
+title, start/end times, attendees, notes markdown, action items); --format
+ndjson writes one record per line for streaming into other tools.
+
+--format sqlite writes a standalone database with meetings, attendees and
+action_items tables and full-text search over notes, for exploring meeting
+history with SQL or Datasette (datasette meetings.db). It needs -o.
+
+--format anki writes flashcards for Anki's File > Import from bullets labeled
+with flashcard_labels, such as "Decision:" and "Definition:". Re-importing
+updates the cards already in the deck.
+
+With --encrypt age:<recipient> the tarball is encrypted with age
+(https://age-encryption.org) so meeting archives can be moved off a machine
+safely. Decrypt with: age -d -i key.txt meetings.tar.gz.age | tar xz`,
+		RunE: runExport,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "file to write, or - for stdout")
+	cmd.Flags().StringVar(&exportFormat, "format", exportFormatTar, "export format: tar, json, ndjson, sqlite, or anki")
+	cmd.Flags().StringArrayVar(&exportEncrypt, "encrypt", nil, "encrypt for an age recipient (age:age1...); repeat for several recipients")
+	cmd.Flags().StringVar(&sinceStr, "since", "", "only export meetings since date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&exportUntil, "until", "", "only export meetings before date (YYYY-MM-DD)")
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	switch exportFormat {
+	case exportFormatTar, exportFormatJSON, exportFormatNDJSON, exportFormatSQLite, exportFormatAnki:
+	default:
+		return fmt.Errorf("invalid format %q (must be tar, json, ndjson, sqlite, or anki)", exportFormat)
+	}
+	if exportFormat == exportFormatSQLite && (exportOutput == "-" || len(exportEncrypt) > 0) {
+		return fmt.Errorf("--format sqlite writes a database file: give it with -o, without --encrypt")
+	}
+	if exportFormat == exportFormatTar && exportOutput == "-" && len(exportEncrypt) == 0 && isTerminal(os.Stdout) {
+		return fmt.Errorf("refusing to write a tarball to a terminal; use -o or redirect the output")
+	}
+
+	var recipients []age.Recipient
+	for _, spec := range exportEncrypt {
+		recipient, err := export.ParseRecipient(spec)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	var opts sync.SyncOptions
+	if sinceStr != "" {
+		t, err := time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			return fmt.Errorf("parsing since date: %w", err)
+		}
+		opts.Since = &t
+	}
+	if exportUntil != "" {
+		t, err := time.Parse("2006-01-02", exportUntil)
+		if err != nil {
+			return fmt.Errorf("parsing until date: %w", err)
+		}
+		opts.Until = &t
+	}
+
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	if exportFormat == exportFormatSQLite {
+		meetings, err := syncer.ExportMeetings(opts)
+		if err != nil {
+			return fmt.Errorf("loading meetings: %w", err)
+		}
+		if err := export.WriteSQLite(exportOutput, meetings); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d meetings to %s\n", len(meetings), exportOutput)
+		return nil
+	}
+
+	var write func(io.Writer) error
+	var count int
+	switch exportFormat {
+	case exportFormatTar:
+		files, err := syncer.RenderMeetings(opts)
+		if err != nil {
+			return fmt.Errorf("rendering meetings: %w", err)
+		}
+		count = len(files)
+		write = func(w io.Writer) error { return export.WriteArchive(w, files) }
+	case exportFormatAnki:
+		cards, err := syncer.Flashcards(opts)
+		if err != nil {
+			return fmt.Errorf("loading meetings: %w", err)
+		}
+		count = len(cards)
+		write = func(w io.Writer) error { return export.WriteAnkiCSV(w, cards) }
+	default:
+		meetings, err := syncer.ExportMeetings(opts)
+		if err != nil {
+			return fmt.Errorf("loading meetings: %w", err)
+		}
+		count = len(meetings)
+		write = func(w io.Writer) error {
+			if exportFormat == exportFormatNDJSON {
+				return export.WriteNDJSON(w, meetings)
+			}
+			return export.WriteJSON(w, meetings)
+		}
+	}
+
+	if exportOutput == "-" {
+		return writeExport(os.Stdout, write, recipients)
+	}
+
+	out, err := os.OpenFile(exportOutput, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	if err := writeExport(out, write, recipients); err != nil {
+		_ = out.Close()
+		_ = os.Remove(exportOutput)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("closing output file: %w", err)
+	}
+
+	noun := "meetings"
+	if exportFormat == exportFormatAnki {
+		noun = "flashcards"
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d %s to %s\n", count, noun, exportOutput)
+	return nil
+}
+
+// writeExport runs write against w, encrypting the output when recipients are given
+func writeExport(w io.Writer, write func(io.Writer) error, recipients []age.Recipient) error {
+	if len(recipients) == 0 {
+		return write(w)
+	}
+
+	enc, err := export.Encrypt(w, recipients...)
+	if err != nil {
+		return err
+	}
+	if err := write(enc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("finishing encryption: %w", err)
+	}
+	return nil
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+var (
+	listStatus string
+	listAlfred bool
+)
+
+// alfredMaxItems is how many of the newest meetings list --alfred returns
+const alfredMaxItems = 200
+
+// alfredItem is an item in Alfred Script Filter JSON
+type alfredItem struct {
+	UID          string      `json:"uid"`
+	Title        string      `json:"title"`
+	Subtitle     string      `json:"subtitle"`
+	Arg          string      `json:"arg,omitempty"`
+	Valid        bool        `json:"valid"`
+	Match        string      `json:"match"`
+	Autocomplete string      `json:"autocomplete"`
+	QuickLookURL string      `json:"quicklookurl,omitempty"`
+	Text         *alfredText `json:"text,omitempty"`
+}
+
+// alfredText is what Alfred copies (⌘C) and shows in Large Type (⌘L)
+type alfredText struct {
+	Copy      string `json:"copy"`
+	LargeType string `json:"largetype"`
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List meetings and their sync status",
+		Long: `List meetings in the Granola cache with their sync status (synced,
+unsynced, or skipped) and the reason a meeting was skipped or hasn't synced.
+Useful for working out why a meeting didn't show up in Logseq.
+
+With --alfred, prints the newest meetings as Alfred Script Filter JSON. Each
+synced meeting's argument is a logseq:// link that opens its page, so a Script
+Filter piped into "Open URL" jumps straight to meeting notes.`,
+		RunE: runList,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().StringVar(&sinceStr, "since", "", "only list meetings since date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&listStatus, "status", "", "only list meetings with this status (synced, unsynced, skipped)")
+	cmd.Flags().BoolVar(&listAlfred, "alfred", false, "print Alfred Script Filter JSON, newest first, with logseq:// links")
+	return cmd
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	switch listStatus {
+	case "", sync.StatusSynced, sync.StatusUnsynced, sync.StatusSkipped:
+	default:
+		return fmt.Errorf("invalid status %q (must be synced, unsynced, or skipped)", listStatus)
+	}
+
+	var since *time.Time
+	if sinceStr != "" {
+		t, err := time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			return fmt.Errorf("parsing since date: %w", err)
+		}
+		since = &t
+	}
+
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	statuses, err := syncer.List(since)
+	if err != nil {
+		return fmt.Errorf("listing meetings: %w", err)
+	}
+
+	if listAlfred {
+		return printAlfredItems(statuses)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DATE\tTITLE\tSTATUS\tREASON")
+	for _, m := range statuses {
+		if listStatus != "" && m.Status != listStatus {
+			continue
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Date.Format("2006-01-02 15:04"), m.Title, m.Status, m.Reason)
+	}
+	return tw.Flush()
+}
+
+// printAlfredItems prints the newest meetings (of listStatus, if set) as
+// Alfred Script Filter JSON. Meetings without a page can't be opened.
+func printAlfredItems(statuses []sync.MeetingStatus) error {
+	slices.SortStableFunc(statuses, func(a, b sync.MeetingStatus) int {
+		return b.Date.Compare(a.Date)
+	})
+
+	items := []alfredItem{}
+	for _, m := range statuses {
+		if listStatus != "" && m.Status != listStatus {
+			continue
+		}
+		date := m.Date.Format("2006-01-02")
+		subtitle := m.Date.Format("Mon 2 Jan 2006 15:04") + " · " + m.Status
+		if m.Reason != "" {
+			subtitle += " (" + m.Reason + ")"
+		}
+		item := alfredItem{
+			UID:          m.ID,
+			Title:        m.Title,
+			Subtitle:     subtitle,
+			Arg:          m.PageURL,
+			Valid:        m.PageURL != "",
+			Match:        m.Title + " " + date,
+			Autocomplete: m.Title,
+			QuickLookURL: m.PagePath,
+		}
+		if m.PageURL != "" {
+			item.Text = &alfredText{Copy: m.PageURL, LargeType: m.Title}
+		}
+		items = append(items, item)
+		if len(items) == alfredMaxItems {
+			break
+		}
+	}
+	return printJSON(map[string][]alfredItem{"items": items})
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// logOutput is where setupLogging sends logs: stderr, which the service's log
+// file captures
+var logOutput io.Writer = os.Stderr
+
+// setupLogging sends logs to logOutput, at debug level with --verbose, as text
+// or as JSON lines (log_format: json) for log shippers
+func setupLogging(format string) {
+	logLevel := slog.LevelInfo
+	if verbose {
+		logLevel = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{Level: logLevel}
+
+	var handler slog.Handler = slog.NewTextHandler(logOutput, opts)
+	if format == "json" {
+		handler = slog.NewJSONHandler(logOutput, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+}
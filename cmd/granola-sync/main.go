@@ -1,28 +1,87 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
 )
 
+// profile is the named profile selected with --profile, or empty for the
+// default one
+var profile string
+
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "granola-sync",
 		Short: "Sync Granola meeting notes to Logseq",
 		Long:  "A daemon that monitors Granola meeting notes and syncs them to Logseq pages and journal entries.",
+		// Every command reads the selected profile's config file unless given -c
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if profile == "" {
+				return nil
+			}
+			if err := config.ValidateProfile(profile); err != nil {
+				return err
+			}
+			if cfgPath == "" {
+				cfgPath = config.ProfileConfigPath(profile)
+			}
+			return nil
+		},
 	}
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", os.Getenv(config.EnvPrefix+"PROFILE"),
+		"use the named profile: config.<profile>.yaml with its own state and service")
 
 	rootCmd.AddCommand(
 		newRunCmd(),
+		newSyncCmd(),
 		newStartCmd(),
 		newStatusCmd(),
+		newPauseCmd(),
+		newResumeCmd(),
 		newLogsCmd(),
 		newUnloadCmd(),
 		newConfigCmd(),
+		newAdoptCmd(),
+		newListCmd(),
+		newQueueCmd(),
+		newEstimateCmd(),
+		newStatsCmd(),
+		newShowCmd(),
+		newTUICmd(),
+		newMenubarCmd(),
+		newExportCmd(),
+		newDoctorCmd(),
+		newStateCmd(),
+		newMCPCmd(),
+		newServeCmd(),
+		newShortcutsCmd(),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
+
+// Exit statuses of run --check, so a monitor can tell behind from broken
+const (
+	exitCheckPending = 1 // Meetings are waiting to be synced
+	exitCheckFailed  = 2 // The check couldn't sync, or meetings failed to process
+)
+
+// exitError is a command error that exits with a specific status
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/mcp"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve meetings to LLM clients over the Model Context Protocol",
+		Long: `Run a Model Context Protocol server on stdin and stdout, offering tools to
+list, read and search meetings and their attendees from the Granola cache and
+sync state. Nothing leaves the machine except through the client.
+
+Add it to an MCP client's configuration as a command, for example:
+
+  {"mcpServers": {"granola": {"command": "granola-sync", "args": ["mcp"]}}}`,
+		RunE: runMCP,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	server := mcp.NewMeetingServer(syncerMeetings{syncer}, buildVersion())
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// buildVersion is the module version granola-sync was built from, or "dev"
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
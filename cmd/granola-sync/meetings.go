@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/philrhinehart/granola-sync/internal/api"
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+// syncerMeetings serves the MCP server's and the API's meetings through the
+// syncer, so they are filtered and anonymized as in exports
+type syncerMeetings struct {
+	syncer *sync.Syncer
+}
+
+func (m syncerMeetings) List(ctx context.Context) ([]export.Meeting, error) {
+	return m.syncer.CachedMeetings()
+}
+
+func (m syncerMeetings) Get(ctx context.Context, id string) (*export.Meeting, error) {
+	meetings, err := m.syncer.ExportMeetings(sync.SyncOptions{IDs: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(meetings) == 0 {
+		return nil, fmt.Errorf("meeting %s: %w", id, api.ErrNotFound)
+	}
+	return &meetings[0], nil
+}
+
+func (m syncerMeetings) PagePath(id string) (string, error) {
+	return m.syncer.SyncedPagePath(id)
+}
+
+// Sync runs a sync for the API, as "granola-sync sync" does for given IDs
+func (m syncerMeetings) Sync(ctx context.Context, ids []string, force bool) (*api.SyncResult, error) {
+	result, err := m.syncer.Sync(sync.SyncOptions{IDs: ids, Force: force})
+	if err != nil {
+		return nil, fmt.Errorf("sync failed: %w", err)
+	}
+
+	out := &api.SyncResult{
+		NewMeetings:     result.NewMeetings,
+		UpdatedMeetings: result.UpdatedMeetings,
+		AdoptedMeetings: result.AdoptedMeetings,
+		NewJournals:     result.NewJournals,
+		Errors:          []string{},
+		ChangedPages:    append([]string{}, result.ChangedPages...),
+	}
+	for _, e := range result.Errors {
+		out.Errors = append(out.Errors, e.Error())
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/control"
+	"github.com/philrhinehart/granola-sync/internal/menubar"
+	"github.com/philrhinehart/granola-sync/internal/service"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newMenubarCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "menubar",
+		Short: "Run in watch mode with a menu bar item",
+		Long: `Run watch mode like "granola-sync run", with a menu bar item showing the last
+sync and offering Sync Now, Pause and Open Logs. Quit from the menu stops
+syncing. Logs go to the same file as the service's, so "granola-sync logs"
+works too.
+
+Use it instead of the service ("granola-sync start"), e.g. as a login item;
+only one of them can run for a profile.`,
+		Args: cobra.NoArgs,
+		RunE: runMenubar,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+	return cmd
+}
+
+func runMenubar(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	asked, _ := askDaemon(cfg, func(ctx context.Context, c *control.Client) error {
+		_, err := c.Status(ctx)
+		return err
+	})
+	if asked {
+		return errors.New(`granola-sync is already running; stop the service with "granola-sync unload" to use the menu bar instead`)
+	}
+
+	logPath, err := service.LogPath(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+	logOutput = io.MultiWriter(os.Stderr, logFile)
+
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	d, stop, err := startWatch(cfg, store, syncer, sync.SyncOptions{})
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	// Ctrl+C in a terminal quits like the menu's Quit
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		menubar.Quit()
+	}()
+
+	menubar.Run(d, func() error {
+		return exec.Command("open", "-a", "Console", logPath).Run()
+	})
+	return nil
+}
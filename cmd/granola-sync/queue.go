@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newQueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "List meetings waiting to be synced",
+		Long: `List meetings that haven't reached Logseq yet but will: new meetings,
+meetings changed since their last sync, and meetings edited too recently to sync
+(see min_age_seconds), with when each becomes ready.`,
+		RunE: runQueue,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
+}
+
+func runQueue(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	queue, err := syncer.Queue()
+	if err != nil {
+		return fmt.Errorf("listing queued meetings: %w", err)
+	}
+
+	if paused, err := sync.PausedSince(store); err == nil && paused != nil {
+		fmt.Printf("Syncing paused since %s\n\n", paused.Local().Format("2006-01-02 15:04"))
+	}
+	if len(queue) == 0 {
+		fmt.Println("No meetings waiting to be synced.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DATE\tTITLE\tREASON\tREADY")
+	for _, m := range queue {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Date.Format("2006-01-02 15:04"), m.Title, m.Reason, formatReady(m.ReadyAt))
+	}
+	return tw.Flush()
+}
+
+// formatReady describes when a queued meeting can sync
+func formatReady(readyAt time.Time) string {
+	wait := time.Until(readyAt)
+	if wait <= 0 {
+		return "now"
+	}
+	return fmt.Sprintf("in %s (%s)", wait.Round(time.Second), readyAt.Local().Format("15:04:05"))
+}
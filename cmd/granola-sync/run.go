@@ -1,27 +1,46 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	stdsync "sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/control"
 	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/health"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/schedule"
 	"github.com/philrhinehart/granola-sync/internal/state"
 	"github.com/philrhinehart/granola-sync/internal/sync"
 )
 
+// controlPageDebounceSeconds is how long the control page must be left alone
+// before its commands run
+const controlPageDebounceSeconds = 3
+
 var (
 	cfgPath  string
 	backfill bool
 	sinceStr string
+	untilStr string
 	dryRun   bool
+	check    bool
+	force    bool
 	verbose  bool
+
+	chunkDays    int
+	previewLines int
+	fullPreview  bool
 )
 
 func newRunCmd() *cobra.Command {
@@ -34,30 +53,101 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
 	cmd.Flags().BoolVar(&backfill, "backfill", false, "sync all historic meetings")
 	cmd.Flags().StringVar(&sinceStr, "since", "", "backfill meetings since date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&untilStr, "until", "", "backfill meetings before date (YYYY-MM-DD)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be synced without making changes")
+	cmd.Flags().BoolVar(&check, "check", false, "dry run once and exit 1 if any meetings are waiting to be synced, or 2 if the check fails")
+	cmd.Flags().IntVar(&previewLines, "preview-lines", 0, "with --dry-run, show this many lines of each new page (default: first 500 characters)")
+	cmd.Flags().BoolVar(&fullPreview, "full", false, "with --dry-run, print whole new pages")
+	cmd.Flags().IntVar(&chunkDays, "chunk-days", 0, "with --backfill, process meetings in windows of this many days, checkpointing after each")
+	cmd.Flags().BoolVar(&force, "force", false, "with --backfill, rewrite every meeting even if already synced")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
 	return cmd
 }
 
-func runWatch(cmd *cobra.Command, args []string) error {
-	// Setup logging
-	logLevel := slog.LevelInfo
-	if verbose {
-		logLevel = slog.LevelDebug
+func runWatch(cmd *cobra.Command, args []string) (err error) {
+	// Any failure of a check, e.g. an unreadable config, is reported as broken
+	if check {
+		defer func() {
+			var exitErr *exitError
+			if err != nil && !errors.As(err, &exitErr) {
+				err = &exitError{code: exitCheckFailed, err: err}
+			}
+		}()
+	}
+
+	// Rewriting every meeting on each change would thrash the graph
+	if force && !backfill {
+		return errors.New("--force requires --backfill")
+	}
+
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
 	}
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})))
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	// Parse the date window if provided
+	opts := sync.SyncOptions{DryRun: dryRun, Force: force, PreviewLines: previewLines, FullPreview: fullPreview}
+	if sinceStr != "" {
+		t, err := time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			return fmt.Errorf("parsing since date: %w", err)
+		}
+		opts.Since = &t
+	}
+	if untilStr != "" {
+		t, err := time.Parse("2006-01-02", untilStr)
+		if err != nil {
+			return fmt.Errorf("parsing until date: %w", err)
+		}
+		opts.Until = &t
+	}
+	if opts.Since != nil && opts.Until != nil && !opts.Until.After(*opts.Since) {
+		return errors.New("--until must be after --since")
+	}
+
+	// Check mode
+	if check {
+		// A pending sync isn't a usage mistake
+		cmd.SilenceUsage = true
+		opts.DryRun = true
+		opts.Check = true
+		opts.Backfill = backfill
+		return doCheck(syncer, opts)
+	}
+
+	// Backfill mode
+	if backfill {
+		opts.Backfill = true
+		if chunkDays > 0 {
+			return doChunkedBackfill(syncer, opts, chunkDays)
+		}
+		return doBackfill(syncer, opts)
+	}
+
+	// Watch mode
+	return doWatch(cfg, store, syncer, opts)
+}
 
-	// Load config
-	cfg, err := config.Load(cfgPath)
+// openConfigAndStore loads the config, sets up logging in its format, ensures the
+// graph and state directories exist, and opens the state store. The caller must
+// close the store.
+func openConfigAndStore(path string) (*config.Config, state.Store, error) {
+	cfg, err := config.Load(path)
 	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+		return nil, nil, fmt.Errorf("loading config: %w", err)
 	}
+	setupLogging(cfg.LogFormat)
 
 	if verbose {
 		slog.Debug("config loaded",
 			"granola_dir", cfg.GranolaDir,
 			"logseq_base", cfg.LogseqBasePath,
 			"state_db", cfg.StateDBPath,
+			"state_backend", cfg.StateBackend,
 			"user_email", cfg.UserEmail,
 			"user_name", cfg.UserName,
 		)
@@ -65,52 +155,97 @@ func runWatch(cmd *cobra.Command, args []string) error {
 
 	// Ensure directories exist
 	if err := cfg.EnsureDirectories(); err != nil {
-		return fmt.Errorf("ensuring directories: %w", err)
+		return nil, nil, fmt.Errorf("ensuring directories: %w", err)
 	}
 
 	// Open state store
-	store, err := state.NewStore(cfg.StateDBPath)
+	store, err := state.Open(cfg.StateBackend, cfg.StateLocation(), cfg.Machine())
 	if err != nil {
-		return fmt.Errorf("opening state store: %w", err)
+		return nil, nil, fmt.Errorf("opening state store: %w", err)
 	}
-	defer func() { _ = store.Close() }()
 
-	syncer := sync.NewSyncer(cfg, store)
+	return cfg, store, nil
+}
 
-	// Parse since date if provided
-	var since *time.Time
-	if sinceStr != "" {
-		t, err := time.Parse("2006-01-02", sinceStr)
-		if err != nil {
-			return fmt.Errorf("parsing since date: %w", err)
-		}
-		since = &t
+func doBackfill(syncer *sync.Syncer, opts sync.SyncOptions) error {
+	if opts.DryRun {
+		fmt.Print("DRY RUN - showing what would be synced:\n\n")
+	} else {
+		slog.Info("starting backfill")
 	}
 
-	// Backfill mode
-	if backfill {
-		return doBackfill(syncer, since, dryRun)
+	result, err := syncer.Sync(opts)
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
 	}
 
-	// Watch mode
-	return doWatch(cfg, syncer, since, dryRun)
+	fmt.Printf("\nSync complete:\n")
+	printSyncResult(result)
+	return nil
 }
 
-func doBackfill(syncer *sync.Syncer, since *time.Time, dryRun bool) error {
-	if dryRun {
+// doCheck dry runs a sync and fails if it would write anything, for scheduled
+// checks that the daemon is keeping up
+func doCheck(syncer *sync.Syncer, opts sync.SyncOptions) error {
+	result, err := syncer.Sync(opts)
+	if err != nil {
+		return &exitError{code: exitCheckFailed, err: fmt.Errorf("sync failed: %w", err)}
+	}
+
+	fmt.Printf("\nCheck complete:\n")
+	printSyncResult(result)
+	if len(result.Errors) > 0 {
+		return &exitError{code: exitCheckFailed, err: fmt.Errorf("%d meetings failed to process", len(result.Errors))}
+	}
+	if pending := result.NewMeetings + result.UpdatedMeetings; pending > 0 {
+		return &exitError{code: exitCheckPending, err: fmt.Errorf("%d meetings not synced yet", pending)}
+	}
+	return nil
+}
+
+// doChunkedBackfill runs the backfill window by window so it can be interrupted
+// with Ctrl+C between windows and resumed later
+func doChunkedBackfill(syncer *sync.Syncer, opts sync.SyncOptions, chunkDays int) error {
+	if opts.DryRun {
 		fmt.Print("DRY RUN - showing what would be synced:\n\n")
 	} else {
-		slog.Info("starting backfill")
+		slog.Info("starting chunked backfill", "chunk_days", chunkDays)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	onChunk := func(chunk sync.ChunkResult) {
+		r := chunk.Result
+		fmt.Printf("%s to %s: %d new, %d updated, %d journal entries, %d errors\n",
+			chunk.Start.Format("2006-01-02"),
+			chunk.End.AddDate(0, 0, -1).Format("2006-01-02"),
+			r.NewMeetings, r.UpdatedMeetings, r.NewJournals, len(r.Errors),
+		)
 	}
 
-	result, err := syncer.Sync(since, dryRun)
+	result, err := syncer.SyncChunked(ctx, opts, chunkDays, onChunk)
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("\nBackfill interrupted; run it again without --since to resume.\n")
+		printSyncResult(result)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("sync failed: %w", err)
 	}
 
 	fmt.Printf("\nSync complete:\n")
+	printSyncResult(result)
+	return nil
+}
+
+// printSyncResult prints the totals of a backfill
+func printSyncResult(result *sync.SyncResult) {
 	fmt.Printf("  New meetings: %d\n", result.NewMeetings)
 	fmt.Printf("  Updated meetings: %d\n", result.UpdatedMeetings)
+	if result.AdoptedMeetings > 0 {
+		fmt.Printf("  Adopted pages: %d\n", result.AdoptedMeetings)
+	}
 	fmt.Printf("  Journal entries: %d\n", result.NewJournals)
 	if len(result.Errors) > 0 {
 		fmt.Printf("  Errors: %d\n", len(result.Errors))
@@ -118,57 +253,155 @@ func doBackfill(syncer *sync.Syncer, since *time.Time, dryRun bool) error {
 			slog.Error("sync error", "error", e)
 		}
 	}
+}
+
+func doWatch(cfg *config.Config, store state.Store, syncer *sync.Syncer, opts sync.SyncOptions) error {
+	_, stop, err := startWatch(cfg, store, syncer, opts)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	// Wait for shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	slog.Info("watching for changes (press Ctrl+C to stop)")
+	<-sigChan
+
+	slog.Info("shutting down")
 	return nil
 }
 
-func doWatch(cfg *config.Config, syncer *sync.Syncer, since *time.Time, dryRun bool) error {
+// startWatch does the initial sync and starts the watchers, schedule and
+// endpoints that sync from then on. stop shuts them down again.
+func startWatch(cfg *config.Config, store state.Store, syncer *sync.Syncer, opts sync.SyncOptions) (d *daemon, stop func(), err error) {
+	var stops []func()
+	stopAll := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+	// Undo what started if anything after it fails
+	defer func() {
+		if err != nil {
+			stopAll()
+		}
+	}()
+
 	cachePath, err := granola.FindCacheFile(cfg.GranolaDir)
 	if err != nil {
-		return fmt.Errorf("finding cache file: %w", err)
+		return nil, nil, fmt.Errorf("finding cache file: %w", err)
 	}
 	slog.Info("starting watch mode", "path", cachePath)
 
+	// The cache and control page watchers, the schedule and the control socket
+	// all drive the syncer
+	var mu stdsync.Mutex
+
+	status := health.NewStatus()
+	d = &daemon{syncer: syncer, store: store, opts: opts, status: status, mu: &mu, started: time.Now()}
+	syncer.SetWatchStart(d.started)
+	if cfg.HealthPort > 0 {
+		srv, err := health.Serve(cfg.HealthPort, status)
+		if err != nil {
+			return nil, nil, err
+		}
+		stops = append(stops, func() { _ = srv.Close() })
+		slog.Info("serving health endpoint", "url", fmt.Sprintf("http://127.0.0.1:%d/healthz", cfg.HealthPort))
+	}
+
+	if cfg.ControlSocket != "" {
+		srv, err := control.Listen(cfg.ControlSocket, d)
+		if err != nil {
+			return nil, nil, err
+		}
+		stops = append(stops, func() { _ = srv.Close() })
+		slog.Info("listening on control socket", "path", cfg.ControlSocket)
+	}
+
 	// Do initial sync
 	slog.Info("performing initial sync")
-	if _, err := syncer.Sync(since, dryRun); err != nil {
+	if err := d.watchSync(); err != nil {
 		slog.Error("initial sync failed", "error", err)
 	}
 
-	// Setup file watcher
+	// Setup file watcher and schedule
 	onChange := func() {
-		result, err := syncer.Sync(since, dryRun)
-		if err != nil {
+		if err := d.watchSync(); err != nil {
 			slog.Error("sync failed", "error", err)
-			return
 		}
-		if result.NewMeetings > 0 || result.UpdatedMeetings > 0 {
-			slog.Info("sync complete",
-				"new", result.NewMeetings,
-				"updated", result.UpdatedMeetings,
-				"journals", result.NewJournals,
-			)
+	}
+
+	if cfg.WatchCache {
+		watcher, err := granola.NewWatcher(cachePath, cfg.DebounceSeconds, onChange)
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating watcher: %w", err)
+		}
+
+		if err := watcher.Start(); err != nil {
+			return nil, nil, fmt.Errorf("starting watcher: %w", err)
 		}
+		stops = append(stops, watcher.Stop)
+		status.SetWatcher(watcher.Watching)
 	}
 
-	watcher, err := granola.NewWatcher(cachePath, cfg.DebounceSeconds, onChange)
-	if err != nil {
-		return fmt.Errorf("creating watcher: %w", err)
+	// Scheduled syncs catch changes the watcher misses, or replace it
+	if cfg.SyncSchedule != "" {
+		sched, err := schedule.Parse(cfg.SyncSchedule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing sync schedule: %w", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		stops = append(stops, cancel)
+
+		var running atomic.Bool
+		running.Store(true)
+		go func() {
+			defer running.Store(false)
+			schedule.Run(ctx, sched, onChange)
+		}()
+		if !cfg.WatchCache {
+			status.SetWatcher(running.Load)
+		}
+		slog.Info("syncing on a schedule", "schedule", cfg.SyncSchedule)
 	}
 
-	if err := watcher.Start(); err != nil {
-		return fmt.Errorf("starting watcher: %w", err)
+	if cfg.ControlPage {
+		controlWatcher, err := watchControlPage(cfg, syncer, &mu)
+		if err != nil {
+			return nil, nil, err
+		}
+		stops = append(stops, controlWatcher.Stop)
 	}
 
-	// Wait for shutdown signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	return d, stopAll, nil
+}
 
-	slog.Info("watching for changes (press Ctrl+C to stop)")
-	<-sigChan
+// watchControlPage creates the control page if needed, runs any commands already
+// on it, and watches it for new ones
+func watchControlPage(cfg *config.Config, syncer *sync.Syncer, mu *stdsync.Mutex) (*granola.Watcher, error) {
+	if err := logseq.EnsureControlPage(cfg.LogseqBasePath); err != nil {
+		return nil, err
+	}
 
-	slog.Info("shutting down")
-	watcher.Stop()
+	runCommands := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := syncer.RunControlCommands(); err != nil {
+			slog.Error("running control page commands failed", "error", err)
+		}
+	}
+	runCommands()
 
-	return nil
+	// Logseq saves pages as you type, so wait for a pause in editing
+	watcher, err := granola.NewWatcher(logseq.ControlPagePath(cfg.LogseqBasePath), controlPageDebounceSeconds, runCommands)
+	if err != nil {
+		return nil, fmt.Errorf("creating control page watcher: %w", err)
+	}
+	if err := watcher.Start(); err != nil {
+		return nil, fmt.Errorf("starting control page watcher: %w", err)
+	}
+	slog.Info("watching control page", "page", logseq.ControlPageName)
+	return watcher, nil
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/api"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+// defaultServePort is the localhost port "granola-sync serve" listens on
+const defaultServePort = 8377
+
+var servePort int
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve meetings and syncs over a local HTTP API",
+		Long: `Serve a JSON API on localhost so local tools and scripts can read meetings
+and trigger syncs without parsing the Granola cache:
+
+  GET  /meetings        meetings, newest first (?since=, ?until=, ?q=, ?limit=)
+  GET  /meetings/{id}   one meeting with its notes, action items and Logseq page
+  POST /sync            sync now; optional body {"ids": [...], "force": true}
+
+Browser requests are refused, so web pages can't reach the API.`,
+		RunE: runServe,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().IntVarP(&servePort, "port", "p", defaultServePort, "localhost port to listen on")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+	setupLogging(cfg.LogFormat)
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return api.Serve(ctx, servePort, syncerMeetings{syncer})
+}
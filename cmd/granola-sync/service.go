@@ -11,7 +11,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/control"
 	"github.com/philrhinehart/granola-sync/internal/service"
+	"github.com/philrhinehart/granola-sync/internal/state"
+	"github.com/philrhinehart/granola-sync/internal/sync"
 )
 
 func newStartCmd() *cobra.Command {
@@ -24,11 +28,13 @@ func newStartCmd() *cobra.Command {
 }
 
 func newStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show service status",
 		RunE:  runStatus,
 	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
 }
 
 func newLogsCmd() *cobra.Command {
@@ -51,7 +57,7 @@ func newUnloadCmd() *cobra.Command {
 
 func runStart(cmd *cobra.Command, args []string) error {
 	// Check if already running to show appropriate message
-	status, _ := service.GetStatus()
+	status, _ := service.GetStatus(profile)
 	wasRunning := status != nil && status.Running
 
 	if wasRunning {
@@ -60,7 +66,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 		fmt.Println("Installing and starting granola-sync service...")
 	}
 
-	if err := service.Install(); err != nil {
+	if err := service.Install(profile); err != nil {
 		return fmt.Errorf("installing service: %w", err)
 	}
 
@@ -79,7 +85,14 @@ func runStart(cmd *cobra.Command, args []string) error {
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
-	status, err := service.GetStatus()
+	// A running daemon reports what it is doing; launchd only knows whether it runs
+	if st, err := daemonStatus(); err == nil {
+		printDaemonStatus(st)
+		printSyncState()
+		return nil
+	}
+
+	status, err := service.GetStatus(profile)
 	if err != nil {
 		return fmt.Errorf("getting status: %w", err)
 	}
@@ -95,11 +108,99 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println("Service is installed but not running.")
 	}
 
+	printSyncState()
 	return nil
 }
 
+// daemonStatus asks the daemon listening on the control socket what it is doing
+func daemonStatus() (*control.Status, error) {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	client := daemonClient(cfg)
+	if client == nil {
+		return nil, control.ErrNotRunning
+	}
+	return client.Status(context.Background())
+}
+
+// printDaemonStatus shows what the daemon is doing
+func printDaemonStatus(st *control.Status) {
+	fmt.Printf("Daemon is running (PID: %d, since %s)\n", st.PID, st.StartedAt.Local().Format("2006-01-02 15:04"))
+	if st.Syncing {
+		fmt.Println("Syncing now")
+	}
+	if !st.Health.Watching {
+		fmt.Println("Not watching for changes")
+	}
+	switch {
+	case st.Health.LastSync.IsZero():
+	case st.Health.LastError != "" && st.Health.LastErrorAt.Equal(st.Health.LastSync):
+		fmt.Printf("Last sync: %s (failed: %s)\n", st.Health.LastSync.Local().Format("2006-01-02 15:04"), st.Health.LastError)
+	default:
+		fmt.Printf("Last sync: %s\n", st.Health.LastSync.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// recentRunsShown is how many sync runs status lists
+const recentRunsShown = 5
+
+// printSyncState shows the most recent sync runs and the state of output
+// targets. Errors are ignored since the service status is the main output.
+func printSyncState() {
+	_, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return
+	}
+	defer func() { _ = store.Close() }()
+
+	if paused, err := sync.PausedSince(store); err == nil && paused != nil {
+		fmt.Printf("Syncing paused since %s\n", paused.Local().Format("2006-01-02 15:04"))
+	}
+	printRecentRuns(store)
+	printOutputTargets(store)
+}
+
+// printRecentRuns lists the most recent sync runs, newest first
+func printRecentRuns(store state.Store) {
+	runs, err := store.RecentSyncRuns(recentRunsShown)
+	if err != nil || len(runs) == 0 {
+		return
+	}
+
+	fmt.Println("")
+	fmt.Println("Recent syncs:")
+	for i := range runs {
+		fmt.Printf("  %s\n", formatRun(&runs[i]))
+	}
+}
+
+// printOutputTargets shows the circuit-breaker state of optional output targets
+// as of the last sync
+func printOutputTargets(store state.Store) {
+	states, err := sync.LoadBreakerStates(store)
+	if err != nil || len(states) == 0 {
+		return
+	}
+
+	fmt.Println("")
+	fmt.Println("Output targets (last sync):")
+	for _, st := range states {
+		switch {
+		case st.Open:
+			fmt.Printf("  %s: disabled after %d failures at %s (%s)\n",
+				st.Target, st.Failures, st.OpenedAt.Local().Format("2006-01-02 15:04"), st.LastError)
+		case st.Failures > 0:
+			fmt.Printf("  %s: %d recent failures (%s)\n", st.Target, st.Failures, st.LastError)
+		default:
+			fmt.Printf("  %s: ok\n", st.Target)
+		}
+	}
+}
+
 func runLogs(cmd *cobra.Command, args []string) error {
-	logPath, err := service.LogPath()
+	logPath, err := service.LogPath(profile)
 	if err != nil {
 		return err
 	}
@@ -162,7 +263,7 @@ func runLogs(cmd *cobra.Command, args []string) error {
 }
 
 func runUnload(cmd *cobra.Command, args []string) error {
-	if err := service.Unload(); err != nil {
+	if err := service.Unload(profile); err != nil {
 		return fmt.Errorf("unloading service: %w", err)
 	}
 	fmt.Println("Service unloaded and removed.")
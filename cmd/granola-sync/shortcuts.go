@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/api"
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/control"
+	"github.com/philrhinehart/granola-sync/internal/service"
+	"github.com/philrhinehart/granola-sync/internal/state"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+var searchLimit int
+
+func newShortcutsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shortcuts",
+		Short: "Commands for Apple Shortcuts, with JSON output",
+		Long: `Commands for automations such as Apple Shortcuts' "Run Shell Script" action.
+Each prints JSON on stdout, which Shortcuts can read with "Get Dictionary from Input".
+
+  granola-sync shortcuts sync [ID...]   sync now and print the result
+  granola-sync shortcuts last           print the last sync run
+  granola-sync shortcuts search WORDS   print matching meetings, newest first
+
+"install-url-handler" adds an app that opens granola-sync:// URLs, so
+Shortcuts' "Open URLs" action (and links anywhere) can run granola-sync://sync
+and granola-sync://sync?id=ID. Any web page can open these URLs, so pausing
+and resuming are left to "granola-sync pause" and "granola-sync resume".`,
+	}
+
+	syncCmd := &cobra.Command{
+		Use:   "sync [ID...]",
+		Short: "Sync now and print the result as JSON",
+		Long: `Sync the given meetings, or ask the running daemon to sync everything new and
+updated (syncing here if no daemon is running), and print the result as JSON.`,
+		RunE: runShortcutsSync,
+	}
+	lastCmd := &cobra.Command{
+		Use:   "last",
+		Short: "Print the last sync run as JSON (null if none)",
+		Args:  cobra.NoArgs,
+		RunE:  runShortcutsLast,
+	}
+	searchCmd := &cobra.Command{
+		Use:   "search WORDS...",
+		Short: "Print meetings whose title, notes or attendees match every word, as JSON",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runShortcutsSearch,
+	}
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "at most this many meetings")
+	openCmd := &cobra.Command{
+		Use:   "open URL",
+		Short: "Run a granola-sync:// URL",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runShortcutsOpen,
+	}
+	installCmd := &cobra.Command{
+		Use:   "install-url-handler",
+		Short: "Install an app that opens granola-sync:// URLs",
+		Args:  cobra.NoArgs,
+		RunE:  runInstallURLHandler,
+	}
+
+	for _, sub := range []*cobra.Command{syncCmd, lastCmd, searchCmd, openCmd} {
+		sub.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+		cmd.AddCommand(sub)
+	}
+	cmd.AddCommand(installCmd)
+	return cmd
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runShortcutsSync(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	result, err := shortcutSync(cfg, args)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+// shortcutSync syncs the given meetings here, or everything new and updated
+// through the daemon if one is running
+func shortcutSync(cfg *config.Config, ids []string) (*control.SyncResult, error) {
+	if len(ids) == 0 {
+		var result *control.SyncResult
+		asked, err := askDaemon(cfg, func(ctx context.Context, c *control.Client) error {
+			var err error
+			result, err = c.Sync(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sync failed: %w", err)
+		}
+		if asked {
+			return result, nil
+		}
+	}
+
+	_, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+	syncResult, err := syncer.Sync(sync.SyncOptions{IDs: ids})
+	if err != nil {
+		return nil, fmt.Errorf("sync failed: %w", err)
+	}
+	result := &control.SyncResult{
+		NewMeetings:     syncResult.NewMeetings,
+		UpdatedMeetings: syncResult.UpdatedMeetings,
+		AdoptedMeetings: syncResult.AdoptedMeetings,
+		NewJournals:     syncResult.NewJournals,
+		Errors:          []string{},
+	}
+	for _, e := range syncResult.Errors {
+		result.Errors = append(result.Errors, e.Error())
+	}
+	return result, nil
+}
+
+func runShortcutsLast(cmd *cobra.Command, args []string) error {
+	_, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	runs, err := store.RecentSyncRuns(1)
+	if err != nil {
+		return fmt.Errorf("reading sync runs: %w", err)
+	}
+	var last *state.SyncRun
+	if len(runs) > 0 {
+		last = &runs[0]
+	}
+	return printJSON(last)
+}
+
+func runShortcutsSearch(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+	meetings, err := syncer.CachedMeetings()
+	if err != nil {
+		return err
+	}
+	return printJSON(api.Search(meetings, time.Time{}, time.Time{}, strings.Join(args, " "), searchLimit))
+}
+
+func runShortcutsOpen(cmd *cobra.Command, args []string) error {
+	u, err := url.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("parsing URL: %w", err)
+	}
+	if u.Scheme != service.URLScheme {
+		return fmt.Errorf("not a %s:// URL: %s", service.URLScheme, args[0])
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// granola-sync://sync has the action as the host; granola-sync:sync as the path
+	action := u.Host
+	if action == "" {
+		action = strings.Trim(u.Opaque+u.Path, "/")
+	}
+	// Only syncing is allowed: any web page can open a granola-sync:// URL
+	if action != "sync" {
+		return fmt.Errorf("unknown action %q (must be sync)", action)
+	}
+	result, err := shortcutSync(cfg, u.Query()["id"])
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runInstallURLHandler(cmd *cobra.Command, args []string) error {
+	appPath, err := service.InstallURLHandler()
+	if err != nil {
+		return fmt.Errorf("installing URL handler: %w", err)
+	}
+	fmt.Println("Installed", appPath)
+	fmt.Println("Try it: open granola-sync://sync")
+	return nil
+}
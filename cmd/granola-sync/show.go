@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <granola-id>",
+		Short: "Show everything known about one meeting",
+		Long: `Show the parsed metadata, notes source, attendees, filter results, sync
+state, and rendered page for a single meeting. Use "granola-sync list" to find IDs.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runShow,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	return cmd
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	detail, err := syncer.Show(args[0])
+	if err != nil {
+		return err
+	}
+	doc := detail.Doc
+
+	fmt.Printf("Meeting: %s\n", doc.Title)
+	fmt.Printf("  ID:       %s\n", doc.ID)
+	fmt.Printf("  Type:     %s\n", doc.Type)
+	fmt.Printf("  Date:     %s\n", doc.GetMeetingDate().Format("2006-01-02 15:04"))
+	if start, end, tz := doc.GetMeetingTimeRange(); start != "" {
+		fmt.Printf("  Time:     %s - %s %s\n", start, end, tz)
+	}
+	fmt.Printf("  Created:  %s\n", formatShowTime(doc.CreatedAt))
+	fmt.Printf("  Updated:  %s\n", formatShowTime(doc.UpdatedAt))
+	if doc.DeletedAt != nil {
+		fmt.Printf("  Deleted:  %s\n", formatShowTime(*doc.DeletedAt))
+	}
+
+	fmt.Println("\nContent:")
+	fmt.Printf("  Notes:      %s\n", detail.NotesSource)
+	fmt.Printf("  Transcript: %d entries\n", len(doc.Transcript))
+
+	fmt.Println("\nAttendees:")
+	attendees := doc.GetAttendees()
+	if len(attendees) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, a := range attendees {
+		if a.Email != "" {
+			fmt.Printf("  %s <%s>\n", a.Name, a.Email)
+		} else {
+			fmt.Printf("  %s\n", a.Name)
+		}
+	}
+
+	fmt.Println("\nFilters:")
+	fmt.Printf("  You attended: %t\n", detail.IsAttendee)
+	if detail.SkipReason != "" {
+		fmt.Printf("  Result:       skipped (%s)\n", detail.SkipReason)
+	} else {
+		fmt.Println("  Result:       would sync")
+	}
+
+	fmt.Println("\nSync state:")
+	if detail.Graph != "" {
+		fmt.Printf("  Graph:     %s\n", detail.Graph)
+	}
+	if s := detail.Synced; s != nil {
+		fmt.Printf("  Synced at: %s\n", formatShowTime(s.SyncedAt))
+		fmt.Printf("  Page:      %s\n", s.LogseqPagePath)
+		if s.MachineID != "" {
+			fmt.Printf("  Machine:   %s\n", s.MachineID)
+		}
+		if s.OptedOut {
+			fmt.Println("  Opted out: the page has granola-sync:: ignore, so sync leaves it alone")
+		}
+		if s.Adopted {
+			fmt.Println("  Adopted:   a hand-made page sync leaves alone; adopt --upgrade regenerates it")
+		}
+		if s.GranolaUpdatedAt != nil && !s.GranolaUpdatedAt.Equal(doc.UpdatedAt) {
+			fmt.Println("  Changed in Granola since the last sync")
+		}
+	} else {
+		fmt.Println("  Not synced")
+	}
+
+	fmt.Printf("\nRendered page (%s):\n%s\n", detail.PagePath, detail.Content)
+	return nil
+}
+
+// formatShowTime formats a timestamp in local time
+func formatShowTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+var (
+	resetBefore string
+	resetYes    bool
+)
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Manage the sync state store",
+	}
+
+	resetCmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Clear the sync state so meetings are synced again",
+		Long: `Clear the sync state store, so the next sync treats meetings as new and
+rewrites their pages.
+
+Without --before everything is cleared: synced meetings, exported action items,
+cached panels and the backfill checkpoint. With --before only meetings last
+synced before that date are forgotten.
+
+Pages in the graph are not deleted.`,
+		Args: cobra.NoArgs,
+		RunE: runStateReset,
+	}
+	resetCmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	resetCmd.Flags().StringVar(&resetBefore, "before", "", "only forget meetings synced before this date (YYYY-MM-DD)")
+	resetCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "don't ask for confirmation")
+	cmd.AddCommand(resetCmd)
+
+	rebuildCmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild the sync state from meeting pages in the graph",
+		Long: `Scan the graph's pages for granola-id:: properties and record those meetings
+in the sync state, e.g. after losing the state database. This avoids a full
+backfill rewriting every page and adding duplicate journal entries.
+
+Pages that match what granola-sync would write now are recorded as up to date;
+others are rewritten in place on the next sync. Meetings already in the state
+are left alone.`,
+		Args: cobra.NoArgs,
+		RunE: runStateRebuild,
+	}
+	rebuildCmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	rebuildCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be recorded without changing the state")
+	cmd.AddCommand(rebuildCmd)
+
+	return cmd
+}
+
+func runStateReset(cmd *cobra.Command, args []string) error {
+	var before *time.Time
+	if resetBefore != "" {
+		t, err := time.ParseInLocation("2006-01-02", resetBefore, time.Local)
+		if err != nil {
+			return fmt.Errorf("parsing before date: %w", err)
+		}
+		before = &t
+	}
+
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	if !resetYes {
+		what := "Clear all sync state"
+		if before != nil {
+			what = "Forget meetings synced before " + resetBefore
+		}
+		if len(cfg.Graphs) > 0 {
+			what += fmt.Sprintf(" for all %d graphs", len(cfg.Graphs)+1)
+		}
+		fmt.Printf("%s? [y/N] ", what)
+		scanner := bufio.NewScanner(os.Stdin)
+		if !scanner.Scan() {
+			return fmt.Errorf("reading input")
+		}
+		if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	total, err := store.Reset(before)
+	if err != nil {
+		return fmt.Errorf("resetting state: %w", err)
+	}
+	// The cache may be unchanged, but there's work to do now
+	if err := sync.ForgetCacheFingerprint(store); err != nil {
+		return fmt.Errorf("resetting state: %w", err)
+	}
+
+	// Extra graphs keep their own state stores
+	for _, g := range cfg.Graphs {
+		graphStore, err := state.Open(cfg.StateBackend, cfg.GraphStateLocation(g), cfg.Machine())
+		if err != nil {
+			return fmt.Errorf("opening state store for graph %s: %w", g.Name, err)
+		}
+		n, err := graphStore.Reset(before)
+		_ = graphStore.Close()
+		if err != nil {
+			return fmt.Errorf("resetting state for graph %s: %w", g.Name, err)
+		}
+		total += n
+	}
+
+	fmt.Printf("Forgot %d synced meeting(s); they will be rewritten on the next sync\n", total)
+	return nil
+}
+
+func runStateRebuild(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	result, err := syncer.RebuildState(dryRun)
+	if err != nil {
+		return fmt.Errorf("rebuild failed: %w", err)
+	}
+
+	if dryRun {
+		fmt.Print("DRY RUN - nothing was recorded\n\n")
+	}
+	fmt.Printf("Up to date: %d\n", result.Current)
+	fmt.Printf("To be rewritten on next sync: %d\n", result.Stale)
+	if result.Existing > 0 {
+		fmt.Printf("Already in state: %d\n", result.Existing)
+	}
+	if result.Unknown > 0 {
+		fmt.Printf("Not in the Granola cache: %d\n", result.Unknown)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("Errors: %d\n", len(result.Errors))
+		for _, e := range result.Errors {
+			slog.Error("rebuild error", "error", e)
+		}
+	}
+	return nil
+}
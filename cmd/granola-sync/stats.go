@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+var (
+	statsDays int
+	statsRuns int
+)
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show sync statistics",
+		Long: `Show totals over the most recent sync runs (pages written, journal entries,
+errors, last sync), how long meetings take to reach Logseq after their last
+edit in Granola (time-to-notes, to help tune min_age_seconds and
+debounce_seconds), and the busiest day for synced meetings.`,
+		RunE: runStats,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().IntVar(&statsDays, "days", 30, "only include meetings synced in the last N days")
+	cmd.Flags().IntVar(&statsRuns, "runs", 50, "total the last N sync runs")
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	runs, err := sync.RunReport(store, statsRuns)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Sync history (last %d runs):\n", statsRuns)
+	if runs.Runs == 0 {
+		fmt.Println("  No sync runs recorded yet.")
+	} else {
+		fmt.Printf("  Runs:             %d", runs.Runs)
+		if runs.FailedRuns > 0 {
+			fmt.Printf(" (%d failed)", runs.FailedRuns)
+		}
+		fmt.Println()
+		fmt.Printf("  By trigger:       %s\n", formatTriggers(runs.ByTrigger))
+		fmt.Printf("  Pages written:    %d (%d new, %d updated)\n", runs.PagesWritten(), runs.NewMeetings, runs.UpdatedMeetings)
+		fmt.Printf("  Journal entries:  %d\n", runs.NewJournals)
+		fmt.Printf("  Errors:           %d\n", runs.Errors)
+		fmt.Printf("  Last sync:        %s\n", formatRun(runs.LastRun))
+	}
+	fmt.Println("")
+
+	since := time.Now().AddDate(0, 0, -statsDays)
+	latency, err := sync.LatencyReport(store, since)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Time-to-notes (last %d days):\n", statsDays)
+	if latency.Count == 0 {
+		fmt.Println("  No syncs recorded yet (backfills are not counted).")
+	} else {
+		fmt.Printf("  Syncs: %d\n", latency.Count)
+		fmt.Printf("  p50:   %s\n", formatLatency(latency.P50))
+		fmt.Printf("  p95:   %s\n", formatLatency(latency.P95))
+		fmt.Printf("  max:   %s\n", formatLatency(latency.Max))
+	}
+
+	meetings, err := sync.MeetingReport(store, since)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("")
+	fmt.Printf("Meetings edited in Granola (last %d days):\n", statsDays)
+	if meetings.Count == 0 {
+		fmt.Println("  No synced meetings.")
+	} else {
+		fmt.Printf("  Synced:       %d on %d days\n", meetings.Count, meetings.Days)
+		fmt.Printf("  Busiest day:  %s (%d)\n", meetings.BusiestDay, meetings.BusiestDays)
+	}
+
+	fmt.Println("")
+	fmt.Println("Settings:")
+	fmt.Printf("  min_age_seconds:  %d\n", cfg.MinAgeSeconds)
+	fmt.Printf("  debounce_seconds: %d\n", cfg.DebounceSeconds)
+
+	return nil
+}
+
+// formatLatency rounds a latency to whole seconds for display
+func formatLatency(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// formatTriggers lists run counts by trigger, e.g. "watch 48, backfill 2"
+func formatTriggers(byTrigger map[string]int) string {
+	var parts []string
+	for _, trigger := range slices.Sorted(maps.Keys(byTrigger)) {
+		name := trigger
+		if name == "" {
+			name = "unknown" // Recorded before triggers were
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", name, byTrigger[trigger]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatRun describes a sync run on one line
+func formatRun(run *state.SyncRun) string {
+	desc := run.StartedAt.Local().Format("2006-01-02 15:04:05")
+	if run.Trigger != "" {
+		desc += " (" + run.Trigger + ")"
+	}
+	if run.Failed {
+		return desc + ", failed"
+	}
+	return fmt.Sprintf("%s, took %s: %d new, %d updated, %d errors",
+		desc, formatLatency(run.Duration), run.NewMeetings, run.UpdatedMeetings, run.Errors)
+}
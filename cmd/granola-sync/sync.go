@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/philrhinehart/granola-sync/internal/sync"
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync <granola-id>...",
+		Short: "Sync specific meetings now",
+		Long: `Sync the given meetings right away, however recently they were updated. Use
+--force to rewrite them even if already synced. Use "granola-sync list" to find IDs,
+or "granola-sync sync now" to sync everything new and updated.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runSync,
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "path to config file")
+	cmd.Flags().BoolVar(&force, "force", false, "rewrite the meetings even if already synced")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be synced without making changes")
+	cmd.Flags().BoolVar(&fullPreview, "full", false, "with --dry-run, print whole pages")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+	cmd.AddCommand(newSyncNowCmd())
+	return cmd
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, store, err := openConfigAndStore(cfgPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	syncer := sync.NewSyncer(cfg, store)
+	defer func() { _ = syncer.Close() }()
+
+	opts := sync.SyncOptions{IDs: args, Force: force, DryRun: dryRun, FullPreview: fullPreview}
+	if dryRun {
+		fmt.Print("DRY RUN - showing what would be synced:\n\n")
+	}
+	result, err := syncer.Sync(opts)
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
+
+	fmt.Printf("\nSync complete:\n")
+	printSyncResult(result)
+	if synced := result.NewMeetings + result.UpdatedMeetings + result.AdoptedMeetings; synced == 0 && len(result.Errors) == 0 {
+		fmt.Println(`Nothing synced; the meetings are up to date (use --force) or filtered out (see "granola-sync show").`)
+	}
+	return nil
+}
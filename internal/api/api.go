@@ -0,0 +1,284 @@
+// Package api serves meetings and on-demand syncs over HTTP on localhost, for
+// local tools and scripts.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+)
+
+// ErrNotFound is returned by Backend.Get for an unknown meeting.
+var ErrNotFound = errors.New("not found")
+
+// Backend is where the API reads meetings and runs syncs.
+type Backend interface {
+	// List returns every meeting, with notes as found in the Granola cache
+	List(ctx context.Context) ([]export.Meeting, error)
+	// Get returns one meeting with its notes, or an error wrapping ErrNotFound
+	Get(ctx context.Context, id string) (*export.Meeting, error)
+	// PagePath returns where the meeting was synced in Logseq, or empty
+	PagePath(id string) (string, error)
+	// Sync syncs the given meetings, or all new and updated ones if ids is empty
+	Sync(ctx context.Context, ids []string, force bool) (*SyncResult, error)
+}
+
+// SyncResult is the POST /sync response body.
+type SyncResult struct {
+	NewMeetings     int      `json:"new_meetings"`
+	UpdatedMeetings int      `json:"updated_meetings"`
+	AdoptedMeetings int      `json:"adopted_meetings"`
+	NewJournals     int      `json:"new_journals"`
+	Errors          []string `json:"errors"`
+	ChangedPages    []string `json:"changed_pages"`
+}
+
+// SyncRequest is the optional POST /sync request body.
+type SyncRequest struct {
+	IDs   []string `json:"ids"`
+	Force bool     `json:"force"`
+}
+
+// MeetingSummary is a meeting in GET /meetings results.
+type MeetingSummary struct {
+	ID        string            `json:"id"`
+	Title     string            `json:"title"`
+	Start     time.Time         `json:"start"`
+	End       *time.Time        `json:"end,omitempty"`
+	Attendees []export.Attendee `json:"attendees"`
+}
+
+// Meeting is the GET /meetings/{id} response body.
+type Meeting struct {
+	*export.Meeting
+	LogseqPage string `json:"logseq_page,omitempty"`
+}
+
+// defaultLimit is how many meetings GET /meetings returns unless asked
+const defaultLimit = 50
+
+// server answers API requests. The backend isn't safe for concurrent use, so
+// requests take turns; a sync requested while one is running is refused.
+type server struct {
+	backend Backend
+	mu      sync.Mutex // Held while using the backend
+	syncing sync.Mutex // Held while a sync is requested or running
+}
+
+// Handler serves the API:
+//
+//	GET  /meetings       meetings, newest first (?since=, ?until=, ?q=, ?limit=)
+//	GET  /meetings/{id}  one meeting with its notes and Logseq page
+//	POST /sync           sync now, with an optional SyncRequest body
+//
+// Requests must be addressed to localhost and come from outside a browser, so
+// web pages can't read meetings or trigger syncs.
+func Handler(backend Backend) http.Handler {
+	s := &server{backend: backend}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /meetings", s.list)
+	mux.HandleFunc("GET /meetings/{id}", s.get)
+	mux.HandleFunc("POST /sync", s.sync)
+	return localOnly(mux)
+}
+
+// Serve serves the API on localhost at port until ctx is done. It fails
+// straight away if the port is taken.
+func Serve(ctx context.Context, port int, backend Backend) error {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("listening for API requests: %w", err)
+	}
+	slog.Info("serving API", "url", "http://"+ln.Addr().String())
+
+	srv := &http.Server{Handler: Handler(backend), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving API: %w", err)
+	}
+	return nil
+}
+
+// localOnly refuses requests from browsers (which send Origin on cross-site
+// and script requests) and requests for other hosts (DNS rebinding)
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if host != "localhost" && host != "127.0.0.1" && host != "::1" {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q not allowed", r.Host))
+			return
+		}
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, errors.New("browser requests not allowed"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, err := parseDate("since", query.Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	until, err := parseDate("until", query.Get("until"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit := defaultLimit
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, errors.New("limit must be a positive number"))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	meetings, err := s.backend.List(r.Context())
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	summaries := Search(meetings, since, until, query.Get("q"), limit)
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *server) get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.backend.Get(r.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	pagePath, err := s.backend.PagePath(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Meeting{Meeting: m, LogseqPage: pagePath})
+}
+
+func (s *server) sync(w http.ResponseWriter, r *http.Request) {
+	// The body is optional; without one, everything new or updated is synced
+	var req SyncRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if !s.syncing.TryLock() {
+		writeError(w, http.StatusConflict, errors.New("a sync is already running"))
+		return
+	}
+	defer s.syncing.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, err := s.backend.Sync(r.Context(), req.IDs, req.Force)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Search returns the meetings starting in [since, until) whose title, notes or
+// attendees contain every word of query (ignoring case), newest first and at
+// most limit of them (0 for all). Zero times leave that end of the window open.
+func Search(meetings []export.Meeting, since, until time.Time, query string, limit int) []MeetingSummary {
+	meetings = slices.Clone(meetings)
+	slices.SortStableFunc(meetings, func(a, b export.Meeting) int {
+		return b.Start.Compare(a.Start)
+	})
+
+	words := strings.Fields(strings.ToLower(query))
+	summaries := []MeetingSummary{}
+	for _, m := range meetings {
+		if (!since.IsZero() && m.Start.Before(since)) || (!until.IsZero() && !m.Start.Before(until)) {
+			continue
+		}
+		if !matchesAll(m, words) {
+			continue
+		}
+		attendees := m.Attendees
+		if attendees == nil {
+			attendees = []export.Attendee{}
+		}
+		summaries = append(summaries, MeetingSummary{ID: m.ID, Title: m.Title, Start: m.Start, End: m.End, Attendees: attendees})
+		if len(summaries) == limit {
+			break
+		}
+	}
+	return summaries
+}
+
+// matchesAll reports whether every word is in the meeting's title, notes or
+// attendees
+func matchesAll(m export.Meeting, words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+	var sb strings.Builder
+	sb.WriteString(m.Title + "\n" + m.Notes)
+	for _, a := range m.Attendees {
+		sb.WriteString("\n" + a.Name + " " + a.Email)
+	}
+	text := strings.ToLower(sb.String())
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date like 2025-01-28", name)
+	}
+	return t, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+)
+
+// fakeBackend serves fixed meetings and records syncs
+type fakeBackend struct {
+	meetings []export.Meeting
+	pages    map[string]string
+	syncs    []SyncRequest
+	// started, if set, is signalled when a sync starts; syncs then wait for
+	// release to be closed
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeBackend) List(ctx context.Context) ([]export.Meeting, error) {
+	return append([]export.Meeting(nil), f.meetings...), nil
+}
+
+func (f *fakeBackend) Get(ctx context.Context, id string) (*export.Meeting, error) {
+	for _, m := range f.meetings {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("meeting %s: %w", id, ErrNotFound)
+}
+
+func (f *fakeBackend) PagePath(id string) (string, error) {
+	return f.pages[id], nil
+}
+
+func (f *fakeBackend) Sync(ctx context.Context, ids []string, force bool) (*SyncResult, error) {
+	if f.started != nil {
+		f.started <- struct{}{}
+		<-f.release
+	}
+	f.syncs = append(f.syncs, SyncRequest{IDs: ids, Force: force})
+	return &SyncResult{NewMeetings: 1, Errors: []string{}, ChangedPages: []string{"/graph/pages/Roadmap.md"}}, nil
+}
+
+type APISuite struct {
+	suite.Suite
+	backend *fakeBackend
+	handler http.Handler
+}
+
+func TestAPISuite(t *testing.T) {
+	suite.Run(t, new(APISuite))
+}
+
+func (s *APISuite) SetupTest() {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.Local) }
+	alice := export.Attendee{Name: "Alice Smith", Email: "alice@example.com"}
+	s.backend = &fakeBackend{
+		meetings: []export.Meeting{
+			{ID: "m1", Title: "Roadmap", Start: day(10), Attendees: []export.Attendee{alice}, Notes: "- Ship the beta in March"},
+			{ID: "m3", Title: "Standup", Start: day(28), Notes: "- Beta is blocked on review"},
+			{ID: "m2", Title: "Hiring", Start: day(20), Notes: "- Interview loop"},
+		},
+		pages: map[string]string{"m1": "/graph/pages/Roadmap.md"},
+	}
+	s.handler = Handler(s.backend)
+}
+
+// do sends a request from a local script and returns the response
+func (s *APISuite) do(method, target, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Host = "127.0.0.1:8377"
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *APISuite) decode(rec *httptest.ResponseRecorder, v any) {
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), v))
+}
+
+func (s *APISuite) TestListNewestFirst() {
+	rec := s.do(http.MethodGet, "/meetings", "")
+	s.Equal(http.StatusOK, rec.Code)
+
+	var summaries []MeetingSummary
+	s.decode(rec, &summaries)
+	s.Require().Len(summaries, 3)
+	s.Equal([]string{"m3", "m2", "m1"}, []string{summaries[0].ID, summaries[1].ID, summaries[2].ID})
+	s.Equal([]export.Attendee{{Name: "Alice Smith", Email: "alice@example.com"}}, summaries[2].Attendees)
+	s.NotNil(summaries[0].Attendees, "no attendees is an empty list, not null")
+}
+
+func (s *APISuite) TestListFilters() {
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"date range", "/meetings?since=2025-01-15&until=2025-01-28", []string{"m2"}},
+		{"search", "/meetings?q=BETA", []string{"m3", "m1"}},
+		{"search attendees", "/meetings?q=alice", []string{"m1"}},
+		{"limit", "/meetings?limit=1", []string{"m3"}},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			rec := s.do(http.MethodGet, tt.target, "")
+			s.Equal(http.StatusOK, rec.Code)
+			var summaries []MeetingSummary
+			s.decode(rec, &summaries)
+			var ids []string
+			for _, m := range summaries {
+				ids = append(ids, m.ID)
+			}
+			s.Equal(tt.want, ids)
+		})
+	}
+}
+
+func (s *APISuite) TestSearch() {
+	summaries := Search(s.backend.meetings, time.Time{}, time.Time{}, "beta", 0)
+	s.Require().Len(summaries, 2)
+	s.Equal("m3", summaries[0].ID)
+	s.Equal("m1", summaries[1].ID)
+	s.Equal("m1", s.backend.meetings[0].ID, "the caller's meetings aren't reordered")
+}
+
+func (s *APISuite) TestListBadQuery() {
+	s.Equal(http.StatusBadRequest, s.do(http.MethodGet, "/meetings?since=last-week", "").Code)
+	s.Equal(http.StatusBadRequest, s.do(http.MethodGet, "/meetings?limit=0", "").Code)
+}
+
+func (s *APISuite) TestGet() {
+	rec := s.do(http.MethodGet, "/meetings/m1", "")
+	s.Equal(http.StatusOK, rec.Code)
+
+	var got map[string]any
+	s.decode(rec, &got)
+	s.Equal("Roadmap", got["title"])
+	s.Equal("- Ship the beta in March", got["notes_markdown"])
+	s.Equal("/graph/pages/Roadmap.md", got["logseq_page"])
+}
+
+func (s *APISuite) TestGetNotFound() {
+	rec := s.do(http.MethodGet, "/meetings/nope", "")
+	s.Equal(http.StatusNotFound, rec.Code)
+
+	var got map[string]string
+	s.decode(rec, &got)
+	s.Equal("meeting nope: not found", got["error"])
+}
+
+func (s *APISuite) TestSync() {
+	rec := s.do(http.MethodPost, "/sync", "")
+	s.Equal(http.StatusOK, rec.Code)
+	var result SyncResult
+	s.decode(rec, &result)
+	s.Equal(1, result.NewMeetings)
+	s.Equal([]string{"/graph/pages/Roadmap.md"}, result.ChangedPages)
+
+	rec = s.do(http.MethodPost, "/sync", `{"ids": ["m1"], "force": true}`)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal([]SyncRequest{{}, {IDs: []string{"m1"}, Force: true}}, s.backend.syncs)
+
+	s.Equal(http.StatusBadRequest, s.do(http.MethodPost, "/sync", `{"ids": "m1"}`).Code)
+	s.Equal(http.StatusMethodNotAllowed, s.do(http.MethodGet, "/sync", "").Code)
+}
+
+func (s *APISuite) TestSyncAlreadyRunning() {
+	s.backend.started = make(chan struct{})
+	s.backend.release = make(chan struct{})
+	done := make(chan int)
+	go func() { done <- s.do(http.MethodPost, "/sync", "").Code }()
+
+	<-s.backend.started
+	s.Equal(http.StatusConflict, s.do(http.MethodPost, "/sync", "").Code)
+
+	close(s.backend.release)
+	s.Equal(http.StatusOK, <-done)
+}
+
+func (s *APISuite) TestRefusesBrowsersAndOtherHosts() {
+	req := httptest.NewRequest(http.MethodPost, "/sync", nil)
+	req.Host = "localhost:8377"
+	req.Header.Set("Origin", "https://example.com")
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	s.Equal(http.StatusForbidden, rec.Code)
+
+	// A page on a domain rebound to 127.0.0.1
+	req = httptest.NewRequest(http.MethodGet, "/meetings", nil)
+	req.Host = "attacker.example:8377"
+	rec = httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	s.Equal(http.StatusForbidden, rec.Code)
+
+	s.Empty(s.backend.syncs)
+}
@@ -0,0 +1,124 @@
+// Package clock lets code that depends on the current time run against a fake
+// clock, so recency rules (min_age_seconds, debouncing) can be tested without
+// waiting on the wall clock.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time, waits and makes tickers
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks on C, like time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the wall clock
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// Fake is a clock that only moves when told to. Its tickers fire as Advance
+// passes their ticks; like time.Ticker, a tick is dropped if the last one
+// hasn't been received.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// NewFake creates a fake clock set to now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake clock's time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d, firing any ticks it passes
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	for _, t := range f.tickers {
+		t.fire(f.now)
+	}
+}
+
+// Sleep returns at once, advancing the clock by d as if the wait had passed
+func (f *Fake) Sleep(d time.Duration) {
+	f.Advance(d)
+}
+
+// Set moves the clock to now, firing any ticks it passes
+func (f *Fake) Set(now time.Time) {
+	f.Advance(now.Sub(f.Now()))
+}
+
+// NewTicker returns a ticker that fires every d of fake time
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTicker{clock: f, c: make(chan time.Time, 1), period: d, next: f.now.Add(d)}
+	f.tickers = append(f.tickers, t)
+	return t
+}
+
+type fakeTicker struct {
+	clock   *Fake
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	t.stopped = true
+	t.clock.mu.Unlock()
+}
+
+// fire sends a tick if now has reached the next one. The clock's mutex is held.
+func (t *fakeTicker) fire(now time.Time) {
+	if t.stopped || now.Before(t.next) {
+		return
+	}
+	select {
+	case t.c <- now:
+	default:
+	}
+	for !now.Before(t.next) {
+		t.next = t.next.Add(t.period)
+	}
+}
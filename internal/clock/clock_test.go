@@ -0,0 +1,84 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClockSuite struct {
+	suite.Suite
+	start time.Time
+	clock *Fake
+}
+
+func TestClockSuite(t *testing.T) {
+	suite.Run(t, new(ClockSuite))
+}
+
+func (s *ClockSuite) SetupTest() {
+	s.start = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	s.clock = NewFake(s.start)
+}
+
+func (s *ClockSuite) TestFakeNowOnlyMovesWhenAdvanced() {
+	s.Equal(s.start, s.clock.Now())
+
+	s.clock.Advance(90 * time.Second)
+	s.Equal(s.start.Add(90*time.Second), s.clock.Now())
+
+	s.clock.Set(s.start.Add(time.Hour))
+	s.Equal(s.start.Add(time.Hour), s.clock.Now())
+}
+
+func (s *ClockSuite) TestFakeSleepAdvances() {
+	ticker := s.clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	s.clock.Sleep(time.Second)
+	s.Equal(s.start.Add(time.Second), s.clock.Now())
+	s.Len(ticker.C(), 1, "a sleep passes ticks like Advance")
+}
+
+func (s *ClockSuite) TestFakeTickerFiresAsTimePasses() {
+	ticker := s.clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	s.clock.Advance(500 * time.Millisecond)
+	s.Empty(ticker.C(), "no tick before the interval")
+
+	s.clock.Advance(500 * time.Millisecond)
+	s.Require().Len(ticker.C(), 1)
+	s.Equal(s.start.Add(time.Second), <-ticker.C())
+
+	// Ticks that aren't received are dropped, not queued
+	s.clock.Advance(5 * time.Second)
+	s.Len(ticker.C(), 1)
+	<-ticker.C()
+
+	s.clock.Advance(time.Second)
+	s.Len(ticker.C(), 1, "the schedule continues from the latest tick")
+}
+
+func (s *ClockSuite) TestFakeTickerStops() {
+	ticker := s.clock.NewTicker(time.Second)
+	ticker.Stop()
+
+	s.clock.Advance(time.Minute)
+	s.Empty(ticker.C())
+}
+
+func (s *ClockSuite) TestRealClock() {
+	before := time.Now()
+	now := Real.Now()
+	s.False(now.Before(before))
+
+	ticker := Real.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		s.Fail("real ticker didn't fire")
+	}
+}
@@ -2,33 +2,410 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/philrhinehart/granola-sync/internal/schedule"
 )
 
 type Config struct {
 	GranolaDir      string `yaml:"granola_dir"`
 	LogseqBasePath  string `yaml:"logseq_base_path"`
 	StateDBPath     string `yaml:"state_db_path"`
+	StateBackend    string `yaml:"state_backend"`
+	StateDSN        string `yaml:"state_dsn"`
 	DebounceSeconds int    `yaml:"debounce_seconds"`
 	MinAgeSeconds   int    `yaml:"min_age_seconds"`
 	LogLevel        string `yaml:"log_level"`
+	LogFormat       string `yaml:"log_format"` // "text" or "json"
 	UserEmail       string `yaml:"user_email"`
 	UserName        string `yaml:"user_name"`
+
+	// IncludeAttendeeEmails adds mailto links for attendees on meeting pages.
+	// Off by default since emails end up in the graph.
+	IncludeAttendeeEmails bool `yaml:"include_attendee_emails"`
+
+	// PersonPages appends a "met in" backlink to each attendee's [[@Name]] page.
+	PersonPages bool `yaml:"person_pages"`
+
+	// MachineID identifies this host in the state store. Defaults to the hostname.
+	MachineID string `yaml:"machine_id"`
+	// AdoptExistingPages records meeting pages already in the graph (found by their
+	// granola-id:: property) in state instead of rewriting them. Useful when several
+	// hosts sync into the same graph.
+	AdoptExistingPages bool `yaml:"adopt_existing_pages"`
+	// ControlPage watches the "granola-sync control" page in watch mode and runs
+	// commands written on it as tasks (resync, pause, resume).
+	ControlPage bool `yaml:"control_page"`
+
+	// IncludeTranscript renders the meeting transcript in a collapsed section.
+	IncludeTranscript bool `yaml:"include_transcript"`
+	// IncludeMyNotes renders the user's own typed notes in a My Notes section after
+	// Granola's summary.
+	IncludeMyNotes bool `yaml:"include_my_notes"`
+	// JournalOnly writes each meeting's full notes as a block in the day's journal
+	// instead of a separate page.
+	JournalOnly bool `yaml:"journal_only"`
+	// JournalHeading, if set, nests journal entries under this top-level block
+	// (e.g. "📅 **Meetings**") instead of at the top level of the journal
+	JournalHeading string `yaml:"journal_heading"`
+	// WriteOrder is whether a new meeting's page is written before its journal
+	// entry ("page_first", so tools watching the graph never see a reference to a
+	// missing page) or after it ("journal_first").
+	WriteOrder string `yaml:"write_order"`
+
+	// MetricsTextfilePath, if set, is rewritten after each sync with Prometheus metrics
+	// for the node_exporter textfile collector (e.g. .../textfile/granola_sync.prom).
+	MetricsTextfilePath string `yaml:"metrics_textfile_path"`
+	// HealthPort, if set, serves GET /healthz on this localhost port in watch mode,
+	// reporting the last sync, the last error and whether the watcher is running.
+	HealthPort int `yaml:"health_port"`
+
+	// OutputFailureThreshold disables an optional output target (person pages, exports)
+	// for the rest of a run after this many consecutive failures. 0 never disables.
+	OutputFailureThreshold int `yaml:"output_failure_threshold"`
+
+	// Notifications shows a macOS notification when new meetings are synced and
+	// when syncs keep failing.
+	Notifications bool `yaml:"notifications"`
+	// NotifyAfterFailures is how many syncs in a row must fail or have errors
+	// before notifying. 0 never notifies of failures.
+	NotifyAfterFailures int `yaml:"notify_after_failures"`
+
+	// PageProperties is where page properties (meeting-date, granola-id, tags) are
+	// written: "bullet" under the title bullet, "block" as top-of-file key:: value
+	// lines, or "frontmatter" as YAML frontmatter.
+	PageProperties string `yaml:"page_properties"`
+
+	// AnonymizeAttendees replaces attendee names with stable pseudonyms in rendered
+	// pages, journals, and person pages, for graphs that are published or shared.
+	AnonymizeAttendees bool `yaml:"anonymize_attendees"`
+	// AnonymizeKey keys the pseudonym hash. Set it to a private value so pseudonyms
+	// can't be reversed by hashing known names or emails.
+	AnonymizeKey string `yaml:"anonymize_key"`
+
+	// TodoistTasks enables exporting the user's action items to Todoist. The API
+	// token is read from the Keychain.
+	TodoistTasks bool `yaml:"todoist_tasks"`
+	// TodoistProjectID is the project for exported tasks. Defaults to the Inbox.
+	TodoistProjectID string `yaml:"todoist_project_id"`
+
+	// RemindersList enables creating Apple Reminders for the user's action items
+	// in the named list (created if missing). macOS only.
+	RemindersList string `yaml:"reminders_list"`
+
+	// ClassificationRules stamp a classification:: property on meeting pages. Rules
+	// are tried in order and the first match wins.
+	ClassificationRules []ClassificationRule `yaml:"classification_rules,omitempty"`
+	// DefaultClassification applies when no rule matches. Empty leaves it off.
+	DefaultClassification string `yaml:"default_classification"`
+
+	// BackupRetention is how many previous versions of each meeting page to keep
+	// before it is overwritten. 0 disables backups.
+	BackupRetention int `yaml:"backup_retention"`
+	// BackupDir holds page backups. Defaults to .granola-sync/backups in the graph.
+	BackupDir string `yaml:"backup_dir"`
+
+	// ArchiveDir, if set, mirrors every meeting page into this directory outside
+	// the graph as plain markdown, kept up to date as pages are rewritten.
+	ArchiveDir string `yaml:"archive_dir"`
+	// ArchiveMode is how archive files are made: "copy" (with YAML frontmatter,
+	// the default) or "hardlink" (links to the graph's pages).
+	ArchiveMode string `yaml:"archive_mode"`
+	// JSONSidecars writes a .json file next to each meeting page with the meeting's
+	// structured data (IDs, times, attendees, notes) for other scripts.
+	JSONSidecars bool `yaml:"json_sidecars"`
+	// SpotlightMetadata sets Spotlight metadata (title, date, attendees and tags
+	// as keywords) on meeting pages, so macOS search finds meetings by attendee.
+	SpotlightMetadata bool `yaml:"spotlight_metadata"`
+
+	// Graphs are extra Logseq graphs that meetings matching their routing rules are
+	// written to instead of logseq_base_path. The first matching graph wins.
+	Graphs []GraphConfig `yaml:"graphs,omitempty"`
+
+	// UnfurlLinks turns links to these kinds of documents (google_docs, figma, jira)
+	// in the notes into [[page]] references. Other links stay markdown links.
+	UnfurlLinks []string `yaml:"unfurl_links"`
+
+	// IncludeTitlePatterns, if set, only syncs meetings whose title matches one of these
+	// regular expressions (case-insensitive).
+	IncludeTitlePatterns []string `yaml:"include_title_patterns"`
+	// ExcludeTitlePatterns skips meetings whose title matches any of these regular
+	// expressions (case-insensitive), e.g. "^focus time$" or "lunch".
+	ExcludeTitlePatterns []string `yaml:"exclude_title_patterns"`
+
+	// ExcludeAttendeePatterns leaves attendees whose name or email matches any of
+	// these regular expressions (case-insensitive) off meeting pages, e.g. bots
+	// and group aliases.
+	ExcludeAttendeePatterns []string `yaml:"exclude_attendee_patterns"`
+	// ExcludeResourceAttendees leaves meeting rooms and other calendar resources
+	// off meeting pages.
+	ExcludeResourceAttendees bool `yaml:"exclude_resource_attendees"`
+
+	// AttendeeAliases maps attendee names or emails (case-insensitive) to the
+	// names of the user's person pages, e.g. "Bob Smith (he/him)": Bob Smith.
+	AttendeeAliases map[string]string `yaml:"attendee_aliases,omitempty"`
+	// NormalizeAttendeeNames cleans up attendee names without an alias: drops
+	// asides like "(he/him)", turns "Smith, Bob" around and capitalizes
+	// all-lowercase names.
+	NormalizeAttendeeNames bool `yaml:"normalize_attendee_names"`
+
+	// NormalizeGlyphs replaces emoji and checkbox glyphs in notes (e.g. ✅, ❌, ➡️) with
+	// text or task markers.
+	NormalizeGlyphs bool `yaml:"normalize_glyphs"`
+	// GlyphReplacements adds to or overrides the built-in glyph replacements. Map a
+	// glyph to itself to keep it.
+	GlyphReplacements map[string]string `yaml:"glyph_replacements,omitempty"`
+
+	// IncludeDomains, if set, only syncs meetings with an attendee (or, with
+	// domain_filter_by organizer, an organizer) from one of these email domains.
+	IncludeDomains []string `yaml:"include_domains"`
+	// DomainFilterBy is who include_domains applies to: "attendee" (anyone invited,
+	// the default) or "organizer".
+	DomainFilterBy string `yaml:"domain_filter_by"`
+
+	// DetectLanguage adds a lang:: property with the notes' dominant language, which
+	// also lets action item sections be found by their localized headers.
+	DetectLanguage bool `yaml:"detect_language"`
+
+	// SkipDeclined skips meetings where the user's calendar response is one of
+	// SkipResponseStatuses.
+	SkipDeclined bool `yaml:"skip_declined"`
+	// SkipResponseStatuses are the calendar responses skip_declined skips:
+	// declined, needsAction, tentative or accepted. Defaults to declined.
+	SkipResponseStatuses []string `yaml:"skip_response_statuses"`
+	// CalendarBlocks is what to do with focus time, out-of-office and all-day
+	// events without other attendees: "sync" them like meetings, "skip" them, or
+	// "tag" their pages calendar-block and leave them out of the journal.
+	CalendarBlocks string `yaml:"calendar_blocks"`
+
+	// DeriveTitleTag tags meeting pages with a tag derived from the title, e.g.
+	// [[Project Review]] for "Project Review 2025-01-28".
+	DeriveTitleTag bool `yaml:"derive_title_tag"`
+	// TitleTagMinMeetings only derives the title tag for recurring meetings: those
+	// whose tag at least this many meetings share. 0 or 1 tags every meeting.
+	TitleTagMinMeetings int `yaml:"title_tag_min_meetings"`
+
+	// TagRules tag meeting pages whose title or attendees match, e.g. Sprint
+	// meetings with [[Engineering]] and [[Sprint]]. Every matching rule applies.
+	TagRules []TagRule `yaml:"tag_rules,omitempty"`
+
+	// TagAllowlist, if set, is the only tags meeting pages get besides Granola
+	// Notes (case-insensitive).
+	TagAllowlist []string `yaml:"tag_allowlist"`
+	// TagDenylist are tags meeting pages never get, even if allowlisted.
+	TagDenylist []string `yaml:"tag_denylist"`
+
+	// PageNameTemplate lays out meeting page names, e.g. "meetings/{year}/{month}/{date}
+	// {title}". Placeholders: {title}, {date}, {year}, {month}, {day}, {week} and
+	// {isoyear}. Empty uses "meetings/{date}/{title}".
+	PageNameTemplate string `yaml:"page_name_template"`
+
+	// RerenderBatchSize is how many existing pages each sync re-renders after the
+	// page format changes (formatting options or a new version of granola-sync), so
+	// the graph converges without rewriting every page at once. 0 leaves pages until
+	// their meetings change.
+	RerenderBatchSize int `yaml:"rerender_batch_size"`
+	// DeferOnBattery holds off backfills and page re-renders on a Mac running
+	// on battery ("battery") or in Low Power Mode ("low_power", also set by
+	// "battery"), until it's plugged in. "off" never defers. New and updated
+	// meetings sync either way.
+	DeferOnBattery string `yaml:"defer_on_battery"`
+
+	// RemoteGraph writes the graph over WebDAV or SFTP instead of to
+	// logseq_base_path: webdav://host/path, webdavs://host/path or
+	// sftp://[user@]host/path. Passwords come from the Keychain.
+	RemoteGraph string `yaml:"remote_graph"`
+
+	// S3Bucket uploads every meeting's markdown to an S3-compatible bucket
+	// for archiving. Keys are <s3_prefix>/<year>/<month>/<date> <title> (<id>).md.
+	S3Bucket string `yaml:"s3_bucket"`
+	S3Region string `yaml:"s3_region"`
+	S3Prefix string `yaml:"s3_prefix"`
+	// S3Endpoint is the URL of an S3-compatible store; empty means AWS
+	S3Endpoint string `yaml:"s3_endpoint"`
+	// S3 credentials default to the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	// environment variables
+	S3AccessKeyID     string `yaml:"s3_access_key_id"`
+	S3SecretAccessKey string `yaml:"s3_secret_access_key"`
+	// S3Sidecars uploads each meeting's JSON sidecar next to its markdown
+	S3Sidecars bool `yaml:"s3_sidecars"`
+
+	// SyncSchedule also syncs in watch mode on a fixed schedule: an interval such
+	// as "15m" or a cron expression such as "*/15 9-18 * * 1-5"
+	SyncSchedule string `yaml:"sync_schedule"`
+
+	// WatchCache watches the cache file for changes. Turn it off to sync only on
+	// sync_schedule where the cache can't be watched reliably.
+	WatchCache bool `yaml:"watch_cache"`
+
+	// PreSyncHook and PostSyncHook are shell commands run in the graph directory
+	// before and after each sync. The post-sync hook gets the outcome in
+	// GRANOLA_SYNC_* environment variables.
+	PreSyncHook  string `yaml:"pre_sync_hook"`
+	PostSyncHook string `yaml:"post_sync_hook"`
+
+	// FlashcardLabels are the bullet labels ("Decision: ...") export --format anki
+	// turns into flashcards. Definition bullets become a card per term.
+	FlashcardLabels []string `yaml:"flashcard_labels"`
+	// FlashcardTags limits flashcards to meetings with one of these page tags
+	FlashcardTags []string `yaml:"flashcard_tags"`
+
+	// PagePlugins are shell commands that transform each rendered meeting page, in
+	// order. Each reads a JSON request (the document, page name and content) on
+	// stdin and writes {"content": "..."} on stdout.
+	PagePlugins []string `yaml:"page_plugins"`
+
+	// ReadwiseHighlights sends each synced meeting's note sections to Readwise as
+	// highlights, with the access token from the Keychain (account readwise).
+	ReadwiseHighlights bool `yaml:"readwise_highlights"`
+
+	// ReflectGraphID, if set, creates a Reflect note of each synced meeting's note
+	// sections in this graph, with the access token from the Keychain (account
+	// reflect).
+	ReflectGraphID string `yaml:"reflect_graph_id"`
+
+	// TaskManager, if set, creates a task in Things, OmniFocus or Apple
+	// Reminders for each of the user's action items. Set in the config file.
+	TaskManager *TaskManagerConfig `yaml:"task_manager,omitempty"`
+
+	// IssueTracker, if set, files action items that start with a prefix as
+	// Jira or Linear issues. Set in the config file.
+	IssueTracker *IssueTrackerConfig `yaml:"issue_tracker,omitempty"`
+
+	// ControlSocket is the Unix socket watch mode listens on so CLI commands
+	// (status, sync now, pause, resume) can talk to it. Empty disables it.
+	ControlSocket string `yaml:"control_socket"`
+
+	// SlackPosts post the notes of meetings with matching tags to Slack after
+	// they sync. Set in the config file.
+	SlackPosts []SlackPost `yaml:"slack_posts,omitempty"`
+
+	// OutboxDir, if set, gets a JSON event file for each meeting created or
+	// updated by a sync, for automations to pick up and delete.
+	OutboxDir string `yaml:"outbox_dir"`
+
+	// Profile names the profile the config was loaded for, from a config file
+	// named config.<profile>.yaml. Empty for the default profile.
+	Profile string `yaml:"-"`
+}
+
+// SlackPost posts the notes of meetings tagged with any of Tags (ignoring
+// case) to a Slack channel, as a message or, with Canvas, as a canvas in the
+// channel. Edited notes update the message or canvas.
+type SlackPost struct {
+	Tags    []string `yaml:"tags"`
+	Channel string   `yaml:"channel"` // Channel ID, e.g. C0123456789
+	Canvas  bool     `yaml:"canvas,omitempty"`
+}
+
+// ClassificationRule matches meetings by attendee email domain (subdomains
+// included) or by keyword in the title or notes (case-insensitive)
+type ClassificationRule struct {
+	Classification string   `yaml:"classification"`
+	Domains        []string `yaml:"domains,omitempty"`
+	Keywords       []string `yaml:"keywords,omitempty"`
+}
+
+// TagRule adds tags to meetings with an attendee email domain (subdomains
+// included) in Domains or a title matching one of TitlePatterns (regular
+// expressions, case-insensitive)
+type TagRule struct {
+	Tags          []string `yaml:"tags"`
+	TitlePatterns []string `yaml:"title_patterns,omitempty"`
+	Domains       []string `yaml:"domains,omitempty"`
+}
+
+// GraphConfig is an extra Logseq graph with its own sync state. A meeting is
+// routed to it when an attendee's email domain (subdomains included), its
+// calendar, or its title (a case-insensitive regular expression) matches.
+type GraphConfig struct {
+	Name           string `yaml:"name"`
+	LogseqBasePath string `yaml:"logseq_base_path"`
+	// StateDBPath defaults to state-<name>.db next to the main state file
+	StateDBPath string `yaml:"state_db_path,omitempty"`
+	// StateDSN is required with the postgres backend
+	StateDSN      string   `yaml:"state_dsn,omitempty"`
+	Domains       []string `yaml:"domains,omitempty"`
+	Calendars     []string `yaml:"calendars,omitempty"`
+	TitlePatterns []string `yaml:"title_patterns,omitempty"`
+}
+
+// Task manager apps
+const (
+	TaskAppThings    = "things"
+	TaskAppOmniFocus = "omnifocus"
+	TaskAppReminders = "reminders"
+)
+
+// TaskManagerConfig is the app the user's action items become tasks in. List is
+// the Things project or area or the OmniFocus project (each defaulting to the
+// inbox), or the Reminders list, which is required. Tags are added in Things
+// and OmniFocus, which create any that are missing.
+type TaskManagerConfig struct {
+	App  string   `yaml:"app"`
+	List string   `yaml:"list,omitempty"`
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// Issue tracker apps
+const (
+	IssueAppJira   = "jira"
+	IssueAppLinear = "linear"
+)
+
+// DefaultIssuePrefix marks the action items filed as issues
+const DefaultIssuePrefix = "FILE:"
+
+// IssueTrackerConfig files action items that start with Prefix (ignoring case,
+// after any owner) as issues. Project is the Jira project key or the Linear
+// team ID. Jira also needs the site URL and the account's Email; IssueType
+// defaults to Task. API tokens are read from the Keychain (account jira or
+// linear).
+type IssueTrackerConfig struct {
+	App       string `yaml:"app"`
+	Prefix    string `yaml:"prefix,omitempty"`
+	Project   string `yaml:"project"`
+	URL       string `yaml:"url,omitempty"`
+	Email     string `yaml:"email,omitempty"`
+	IssueType string `yaml:"issue_type,omitempty"`
 }
 
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
-		GranolaDir:      filepath.Join(homeDir, "Library", "Application Support", "Granola"),
-		LogseqBasePath:  findLogseqGraph(homeDir),
-		StateDBPath:     filepath.Join(homeDir, ".config", "granola-sync", "state.db"),
-		DebounceSeconds: 30,
-		MinAgeSeconds:   60,
-		LogLevel:        "info",
+		GranolaDir:             filepath.Join(homeDir, "Library", "Application Support", "Granola"),
+		LogseqBasePath:         findLogseqGraph(homeDir),
+		StateDBPath:            filepath.Join(homeDir, ".config", "granola-sync", "state.db"),
+		ControlSocket:          filepath.Join(homeDir, ".config", "granola-sync", "control.sock"),
+		StateBackend:           "sqlite",
+		DebounceSeconds:        30,
+		MinAgeSeconds:          60,
+		OutputFailureThreshold: 3,
+		NotifyAfterFailures:    3,
+		BackupRetention:        5,
+		PageProperties:         "bullet",
+		DomainFilterBy:         "attendee",
+		SkipResponseStatuses:   []string{"declined"},
+		CalendarBlocks:         "sync",
+		WriteOrder:             "page_first",
+		RerenderBatchSize:      25,
+		DeferOnBattery:         "off",
+		S3Region:               "us-east-1",
+		WatchCache:             true,
+		FlashcardLabels:        []string{"Decision", "Definition"},
+		DeriveTitleTag:         true,
+		ArchiveMode:            "copy",
+		LogLevel:               "info",
+		LogFormat:              "text",
 	}
 }
 
@@ -44,7 +421,7 @@ func findLogseqGraph(homeDir string) string {
 	}
 
 	for _, path := range candidates {
-		if isLogseqGraph(path) {
+		if IsLogseqGraph(path) {
 			return path
 		}
 	}
@@ -52,9 +429,9 @@ func findLogseqGraph(homeDir string) string {
 	return "" // No graph found, user must configure
 }
 
-// isLogseqGraph checks if a directory appears to be a Logseq graph
+// IsLogseqGraph reports whether a directory appears to be a Logseq graph
 // by looking for characteristic subdirectories (pages/, journals/, or logseq/).
-func isLogseqGraph(path string) bool {
+func IsLogseqGraph(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil || !info.IsDir() {
 		return false
@@ -72,37 +449,408 @@ func isLogseqGraph(path string) bool {
 	return false
 }
 
+// EnvPrefix starts the environment variables that override config keys, e.g.
+// GRANOLA_SYNC_LOGSEQ_BASE_PATH for logseq_base_path
+const EnvPrefix = "GRANOLA_SYNC_"
+
+// Load reads the config file at path (the default location if empty) and
+// applies GRANOLA_SYNC_* environment variable overrides
 func Load(path string) (*Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+// LoadFile reads the config file without environment overrides, for editing
+// it without saving the overrides into it
+func LoadFile(path string) (*Config, error) {
+	return load(path, nil)
+}
+
+func load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
 	cfg := DefaultConfig()
 
 	if path == "" {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return cfg, nil // Return defaults if can't find home
+			return cfg, cfg.applyEnv(lookupEnv) // Use defaults if can't find home
 		}
 		path = filepath.Join(homeDir, ".config", "granola-sync", "config.yaml")
 	}
+	if profile := profileFromPath(path); profile != "" {
+		cfg.setProfile(profile)
+	}
 
 	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return cfg, nil // Use defaults if config doesn't exist
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("reading config: %w", err)
 	}
+	// Use defaults if config doesn't exist
+	if err == nil {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing config: %w", err)
+		}
+	}
+
+	if err := cfg.applyEnv(lookupEnv); err != nil {
+		return nil, err
+	}
+
+	for i, rule := range cfg.ClassificationRules {
+		if rule.Classification == "" {
+			return nil, fmt.Errorf("classification_rules[%d]: classification is required", i)
+		}
+		if len(rule.Domains) == 0 && len(rule.Keywords) == 0 {
+			return nil, fmt.Errorf("classification_rules[%d]: needs domains or keywords", i)
+		}
+	}
+
+	for i, rule := range cfg.TagRules {
+		if len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("tag_rules[%d]: needs tags", i)
+		}
+		if len(rule.TitlePatterns) == 0 && len(rule.Domains) == 0 {
+			return nil, fmt.Errorf("tag_rules[%d]: needs title_patterns or domains", i)
+		}
+		if err := validatePatterns(fmt.Sprintf("tag_rules[%d].title_patterns", i), rule.TitlePatterns); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, post := range cfg.SlackPosts {
+		if post.Channel == "" {
+			return nil, fmt.Errorf("slack_posts[%d]: channel is required", i)
+		}
+		if len(post.Tags) == 0 {
+			return nil, fmt.Errorf("slack_posts[%d]: needs tags", i)
+		}
+	}
 
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parsing config: %w", err)
+	if err := validateUnfurlLinks(cfg.UnfurlLinks); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns("include_title_patterns", cfg.IncludeTitlePatterns); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns("exclude_title_patterns", cfg.ExcludeTitlePatterns); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns("exclude_attendee_patterns", cfg.ExcludeAttendeePatterns); err != nil {
+		return nil, err
+	}
+	if err := validateResponseStatuses(cfg.SkipResponseStatuses); err != nil {
+		return nil, err
+	}
+	if err := validatePageNameTemplate(cfg.PageNameTemplate); err != nil {
+		return nil, err
+	}
+	if cfg.ArchiveMode != "copy" && cfg.ArchiveMode != "hardlink" {
+		return nil, fmt.Errorf("invalid value for archive_mode: %s (must be copy or hardlink)", cfg.ArchiveMode)
+	}
+	if err := validateCalendarBlocks(cfg.CalendarBlocks); err != nil {
+		return nil, err
+	}
+	if err := validateDeferOnBattery(cfg.DeferOnBattery); err != nil {
+		return nil, err
+	}
+	if err := validateWriteOrder(cfg.WriteOrder); err != nil {
+		return nil, err
+	}
+	if err := validateLogFormat(cfg.LogFormat); err != nil {
+		return nil, err
+	}
+	if err := validateRemoteGraph(cfg.RemoteGraph); err != nil {
+		return nil, err
+	}
+	if cfg.RemoteGraph != "" && cfg.ControlPage {
+		return nil, fmt.Errorf("control_page can't be used with remote_graph: the control page is watched on the local disk")
+	}
+	if cfg.RemoteGraph != "" && cfg.ArchiveMode == "hardlink" {
+		return nil, fmt.Errorf("archive_mode hardlink can't be used with remote_graph: remote pages can't be linked into the local archive")
+	}
+	if err := validateSyncSchedule(cfg.SyncSchedule); err != nil {
+		return nil, err
+	}
+	if err := validateTaskManager(cfg.TaskManager, cfg.RemindersList); err != nil {
+		return nil, err
+	}
+	if err := validateIssueTracker(cfg.IssueTracker); err != nil {
+		return nil, err
+	}
+	if !cfg.WatchCache && cfg.SyncSchedule == "" {
+		return nil, fmt.Errorf("watch_cache is off but no sync_schedule is set, so nothing would sync")
 	}
 
 	// Expand paths
 	cfg.GranolaDir = expandPath(cfg.GranolaDir)
 	cfg.LogseqBasePath = expandPath(cfg.LogseqBasePath)
 	cfg.StateDBPath = expandPath(cfg.StateDBPath)
+	cfg.MetricsTextfilePath = expandPath(cfg.MetricsTextfilePath)
+	cfg.BackupDir = expandPath(cfg.BackupDir)
+	cfg.ArchiveDir = expandPath(cfg.ArchiveDir)
+	cfg.ControlSocket = expandPath(cfg.ControlSocket)
+	cfg.OutboxDir = expandPath(cfg.OutboxDir)
+
+	if err := cfg.loadGraphs(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
 
+// applyEnv sets each key that config set accepts from its GRANOLA_SYNC_<KEY>
+// environment variable, if set
+func (c *Config) applyEnv(lookupEnv func(string) (string, bool)) error {
+	if lookupEnv == nil {
+		return nil
+	}
+	for _, key := range c.keys() {
+		name := EnvPrefix + strings.ToUpper(key)
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := c.Set(key, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// keys returns the config keys Get and Set handle, in file order. Blocks like
+// graphs and task_manager are only set in the config file.
+func (c *Config) keys() []string {
+	var keys []string
+	t := reflect.TypeOf(*c)
+	for i := range t.NumField() {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		if _, err := c.Get(key); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// loadGraphs validates the extra graphs and fills in their default state paths
+func (c *Config) loadGraphs() error {
+	names := make(map[string]bool)
+	for i := range c.Graphs {
+		g := &c.Graphs[i]
+		switch {
+		case g.Name == "":
+			return fmt.Errorf("graphs[%d]: name is required", i)
+		case strings.ContainsAny(g.Name, `/\`):
+			return fmt.Errorf("graphs[%d]: name %q can't contain path separators", i, g.Name)
+		case names[g.Name]:
+			return fmt.Errorf("graphs[%d]: duplicate name %q", i, g.Name)
+		case g.LogseqBasePath == "":
+			return fmt.Errorf("graphs[%d]: logseq_base_path is required", i)
+		case len(g.Domains) == 0 && len(g.Calendars) == 0 && len(g.TitlePatterns) == 0:
+			return fmt.Errorf("graphs[%d]: needs domains, calendars, or title_patterns", i)
+		case c.StateBackend == "postgres" && g.StateDSN == "":
+			return fmt.Errorf("graphs[%d]: state_dsn is required with the postgres backend", i)
+		}
+		for _, pattern := range g.TitlePatterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("graphs[%d]: invalid title pattern: %w", i, err)
+			}
+		}
+		names[g.Name] = true
+
+		g.LogseqBasePath = expandPath(g.LogseqBasePath)
+		g.StateDBPath = expandPath(g.StateDBPath)
+		if g.StateDBPath == "" {
+			ext := filepath.Ext(c.StateDBPath)
+			g.StateDBPath = strings.TrimSuffix(c.StateDBPath, ext) + "-" + g.Name + ext
+		}
+	}
+	return nil
+}
+
+// validateTaskManager checks the task_manager block, if any
+func validateTaskManager(tm *TaskManagerConfig, remindersList string) error {
+	if tm == nil {
+		return nil
+	}
+	switch tm.App {
+	case TaskAppThings, TaskAppOmniFocus:
+	case TaskAppReminders:
+		if tm.List == "" {
+			return fmt.Errorf("task_manager: list is required for reminders")
+		}
+		if remindersList != "" {
+			return fmt.Errorf("task_manager: reminders_list is also set; use one or the other")
+		}
+		if len(tm.Tags) > 0 {
+			return fmt.Errorf("task_manager: tags aren't supported for reminders")
+		}
+	default:
+		return fmt.Errorf("task_manager: invalid app %q (must be %s, %s or %s)", tm.App, TaskAppThings, TaskAppOmniFocus, TaskAppReminders)
+	}
+	return nil
+}
+
+// validateIssueTracker checks the issue_tracker block, if any, and fills in
+// its defaults
+func validateIssueTracker(it *IssueTrackerConfig) error {
+	if it == nil {
+		return nil
+	}
+	if it.Prefix == "" {
+		it.Prefix = DefaultIssuePrefix
+	}
+	if it.Project == "" {
+		return fmt.Errorf("issue_tracker: project is required")
+	}
+	switch it.App {
+	case IssueAppJira:
+		if it.URL == "" || it.Email == "" {
+			return fmt.Errorf("issue_tracker: url and email are required for jira")
+		}
+		if u, err := url.Parse(it.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("issue_tracker: invalid url %q", it.URL)
+		}
+		if it.IssueType == "" {
+			it.IssueType = "Task"
+		}
+	case IssueAppLinear:
+		if it.URL != "" || it.Email != "" || it.IssueType != "" {
+			return fmt.Errorf("issue_tracker: url, email and issue_type are only for jira")
+		}
+	default:
+		return fmt.Errorf("issue_tracker: invalid app %q (must be %s or %s)", it.App, IssueAppJira, IssueAppLinear)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated config value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// unfurlKinds are the kinds of links unfurl_links accepts
+var unfurlKinds = []string{"google_docs", "figma", "jira"}
+
+// validateUnfurlLinks checks every unfurl_links entry is a known kind
+func validateUnfurlLinks(kinds []string) error {
+	for _, k := range kinds {
+		if !slices.Contains(unfurlKinds, k) {
+			return fmt.Errorf("invalid value for unfurl_links: %s (must be %s)", k, strings.Join(unfurlKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// responseStatuses are the calendar response statuses skip_response_statuses accepts
+var responseStatuses = []string{"declined", "needsAction", "tentative", "accepted"}
+
+// validateResponseStatuses checks every skip_response_statuses entry is a
+// calendar response status
+func validateResponseStatuses(statuses []string) error {
+	for _, st := range statuses {
+		if !slices.Contains(responseStatuses, st) {
+			return fmt.Errorf("invalid value for skip_response_statuses: %s (must be %s)", st, strings.Join(responseStatuses, ", "))
+		}
+	}
+	return nil
+}
+
+// validateCalendarBlocks checks a calendar_blocks value is one of the known modes
+func validateCalendarBlocks(mode string) error {
+	switch mode {
+	case "sync", "skip", "tag":
+		return nil
+	}
+	return fmt.Errorf("invalid value for calendar_blocks: %s (must be sync, skip or tag)", mode)
+}
+
+// validateDeferOnBattery checks a defer_on_battery value is one of the known modes
+func validateDeferOnBattery(mode string) error {
+	switch mode {
+	case "off", "low_power", "battery":
+		return nil
+	}
+	return fmt.Errorf("invalid value for defer_on_battery: %s (must be off, low_power or battery)", mode)
+}
+
+// validateWriteOrder checks a write_order value is one of the known orders
+func validateWriteOrder(order string) error {
+	switch order {
+	case "page_first", "journal_first":
+		return nil
+	}
+	return fmt.Errorf("invalid value for write_order: %s (must be page_first or journal_first)", order)
+}
+
+// validateRemoteGraph checks a remote_graph value is a WebDAV or SFTP URL with
+// a host and no password
+func validateRemoteGraph(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for remote_graph: %w", err)
+	}
+	switch {
+	case u.Scheme != "webdav" && u.Scheme != "webdavs" && u.Scheme != "sftp":
+		return fmt.Errorf("invalid value for remote_graph: %s (must be a webdav://, webdavs:// or sftp:// URL)", value)
+	case u.Host == "":
+		return fmt.Errorf("invalid value for remote_graph: %s (missing host)", value)
+	}
+	if _, ok := u.User.Password(); ok {
+		return fmt.Errorf("invalid value for remote_graph: passwords go in the Keychain, not the URL")
+	}
+	return nil
+}
+
+// validateSyncSchedule checks a sync_schedule value is an interval or a cron
+// expression
+func validateSyncSchedule(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := schedule.Parse(value); err != nil {
+		return fmt.Errorf("invalid value for sync_schedule: %w", err)
+	}
+	return nil
+}
+
+// validateLogFormat checks a log_format value is one of the known formats
+func validateLogFormat(format string) error {
+	switch format {
+	case "text", "json":
+		return nil
+	}
+	return fmt.Errorf("invalid value for log_format: %s (must be text or json)", format)
+}
+
+// validatePageNameTemplate checks a page_name_template includes the title, so
+// meetings on the same day get separate pages
+func validatePageNameTemplate(template string) error {
+	if template != "" && !strings.Contains(template, "{title}") {
+		return fmt.Errorf("invalid value for page_name_template: %s (must include {title})", template)
+	}
+	return nil
+}
+
+// validatePatterns checks every entry of a regular expression list compiles
+func validatePatterns(key string, patterns []string) error {
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
 func expandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
 		homeDir, err := os.UserHomeDir()
@@ -132,9 +880,51 @@ func (c *Config) EnsureDirectories() error {
 		return fmt.Errorf("creating journals directory: %w", err)
 	}
 
+	// And the same for each extra graph
+	for _, g := range c.Graphs {
+		if err := os.MkdirAll(filepath.Dir(g.StateDBPath), 0o755); err != nil {
+			return fmt.Errorf("creating state directory for graph %s: %w", g.Name, err)
+		}
+		for _, dir := range []string{"pages", "journals"} {
+			if err := os.MkdirAll(filepath.Join(g.LogseqBasePath, dir), 0o755); err != nil {
+				return fmt.Errorf("creating %s directory for graph %s: %w", dir, g.Name, err)
+			}
+		}
+	}
+
 	return nil
 }
 
+// StateLocation returns where the state store lives: the Postgres connection
+// string for the postgres backend, otherwise the state file path
+func (c *Config) StateLocation() string {
+	if c.StateBackend == "postgres" {
+		return c.StateDSN
+	}
+	return c.StateDBPath
+}
+
+// Machine returns machine_id, defaulting to the hostname
+func (c *Config) Machine() string {
+	if c.MachineID != "" {
+		return c.MachineID
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// GraphStateLocation returns where an extra graph's state store lives, like
+// StateLocation does for the main graph
+func (c *Config) GraphStateLocation(g GraphConfig) string {
+	if c.StateBackend == "postgres" {
+		return g.StateDSN
+	}
+	return g.StateDBPath
+}
+
 // ConfigPath returns the default config file path
 func ConfigPath() string {
 	homeDir, err := os.UserHomeDir()
@@ -144,6 +934,49 @@ func ConfigPath() string {
 	return filepath.Join(homeDir, ".config", "granola-sync", "config.yaml")
 }
 
+// profileRe matches profile names, which go into file names and service labels
+var profileRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateProfile checks a profile name, e.g. work
+func ValidateProfile(profile string) error {
+	if !profileRe.MatchString(profile) {
+		return fmt.Errorf("invalid profile %q (use letters, digits, - and _)", profile)
+	}
+	return nil
+}
+
+// ProfileConfigPath returns the config file path of a profile,
+// ~/.config/granola-sync/config.<profile>.yaml, or the default config file path
+// for the empty profile
+func ProfileConfigPath(profile string) string {
+	path := ConfigPath()
+	if profile == "" || path == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(path), "config."+profile+".yaml")
+}
+
+// profileFromPath returns the profile a config file is for, from its name
+func profileFromPath(path string) string {
+	name, ok := strings.CutPrefix(filepath.Base(path), "config.")
+	if !ok {
+		return ""
+	}
+	profile, ok := strings.CutSuffix(name, ".yaml")
+	if !ok || ValidateProfile(profile) != nil {
+		return ""
+	}
+	return profile
+}
+
+// setProfile marks the config as a profile's and gives the profile its own
+// default state file and control socket, so profiles don't share state
+func (c *Config) setProfile(profile string) {
+	c.Profile = profile
+	c.StateDBPath = filepath.Join(filepath.Dir(c.StateDBPath), "state."+profile+".db")
+	c.ControlSocket = filepath.Join(filepath.Dir(c.ControlSocket), "control."+profile+".sock")
+}
+
 // Save writes the config to the specified path
 func (c *Config) Save(path string) error {
 	if path == "" {
@@ -177,6 +1010,10 @@ func (c *Config) Get(key string) (string, error) {
 		return c.LogseqBasePath, nil
 	case "state_db_path":
 		return c.StateDBPath, nil
+	case "state_backend":
+		return c.StateBackend, nil
+	case "state_dsn":
+		return c.StateDSN, nil
 	case "debounce_seconds":
 		return fmt.Sprintf("%d", c.DebounceSeconds), nil
 	case "min_age_seconds":
@@ -187,6 +1024,142 @@ func (c *Config) Get(key string) (string, error) {
 		return c.UserEmail, nil
 	case "user_name":
 		return c.UserName, nil
+	case "include_attendee_emails":
+		return strconv.FormatBool(c.IncludeAttendeeEmails), nil
+	case "person_pages":
+		return strconv.FormatBool(c.PersonPages), nil
+	case "machine_id":
+		return c.MachineID, nil
+	case "adopt_existing_pages":
+		return strconv.FormatBool(c.AdoptExistingPages), nil
+	case "include_transcript":
+		return strconv.FormatBool(c.IncludeTranscript), nil
+	case "metrics_textfile_path":
+		return c.MetricsTextfilePath, nil
+	case "output_failure_threshold":
+		return fmt.Sprintf("%d", c.OutputFailureThreshold), nil
+	case "page_properties":
+		return c.PageProperties, nil
+	case "anonymize_attendees":
+		return strconv.FormatBool(c.AnonymizeAttendees), nil
+	case "anonymize_key":
+		return c.AnonymizeKey, nil
+	case "todoist_tasks":
+		return strconv.FormatBool(c.TodoistTasks), nil
+	case "todoist_project_id":
+		return c.TodoistProjectID, nil
+	case "reminders_list":
+		return c.RemindersList, nil
+	case "default_classification":
+		return c.DefaultClassification, nil
+	case "backup_retention":
+		return fmt.Sprintf("%d", c.BackupRetention), nil
+	case "backup_dir":
+		return c.BackupDir, nil
+	case "unfurl_links":
+		return strings.Join(c.UnfurlLinks, ","), nil
+	case "include_title_patterns":
+		return strings.Join(c.IncludeTitlePatterns, ","), nil
+	case "exclude_title_patterns":
+		return strings.Join(c.ExcludeTitlePatterns, ","), nil
+	case "exclude_attendee_patterns":
+		return strings.Join(c.ExcludeAttendeePatterns, ","), nil
+	case "exclude_resource_attendees":
+		return strconv.FormatBool(c.ExcludeResourceAttendees), nil
+	case "normalize_glyphs":
+		return strconv.FormatBool(c.NormalizeGlyphs), nil
+	case "normalize_attendee_names":
+		return strconv.FormatBool(c.NormalizeAttendeeNames), nil
+	case "include_domains":
+		return strings.Join(c.IncludeDomains, ","), nil
+	case "domain_filter_by":
+		return c.DomainFilterBy, nil
+	case "detect_language":
+		return strconv.FormatBool(c.DetectLanguage), nil
+	case "skip_declined":
+		return strconv.FormatBool(c.SkipDeclined), nil
+	case "skip_response_statuses":
+		return strings.Join(c.SkipResponseStatuses, ","), nil
+	case "derive_title_tag":
+		return strconv.FormatBool(c.DeriveTitleTag), nil
+	case "title_tag_min_meetings":
+		return fmt.Sprintf("%d", c.TitleTagMinMeetings), nil
+	case "include_my_notes":
+		return strconv.FormatBool(c.IncludeMyNotes), nil
+	case "tag_allowlist":
+		return strings.Join(c.TagAllowlist, ","), nil
+	case "tag_denylist":
+		return strings.Join(c.TagDenylist, ","), nil
+	case "journal_only":
+		return strconv.FormatBool(c.JournalOnly), nil
+	case "page_name_template":
+		return c.PageNameTemplate, nil
+	case "archive_dir":
+		return c.ArchiveDir, nil
+	case "archive_mode":
+		return c.ArchiveMode, nil
+	case "journal_heading":
+		return c.JournalHeading, nil
+	case "calendar_blocks":
+		return c.CalendarBlocks, nil
+	case "control_page":
+		return strconv.FormatBool(c.ControlPage), nil
+	case "write_order":
+		return c.WriteOrder, nil
+	case "notifications":
+		return strconv.FormatBool(c.Notifications), nil
+	case "notify_after_failures":
+		return fmt.Sprintf("%d", c.NotifyAfterFailures), nil
+	case "health_port":
+		return fmt.Sprintf("%d", c.HealthPort), nil
+	case "json_sidecars":
+		return strconv.FormatBool(c.JSONSidecars), nil
+	case "spotlight_metadata":
+		return strconv.FormatBool(c.SpotlightMetadata), nil
+	case "log_format":
+		return c.LogFormat, nil
+	case "rerender_batch_size":
+		return fmt.Sprintf("%d", c.RerenderBatchSize), nil
+	case "defer_on_battery":
+		return c.DeferOnBattery, nil
+	case "remote_graph":
+		return c.RemoteGraph, nil
+	case "s3_bucket":
+		return c.S3Bucket, nil
+	case "s3_region":
+		return c.S3Region, nil
+	case "s3_endpoint":
+		return c.S3Endpoint, nil
+	case "s3_prefix":
+		return c.S3Prefix, nil
+	case "s3_access_key_id":
+		return c.S3AccessKeyID, nil
+	case "s3_secret_access_key":
+		return c.S3SecretAccessKey, nil
+	case "s3_sidecars":
+		return strconv.FormatBool(c.S3Sidecars), nil
+	case "sync_schedule":
+		return c.SyncSchedule, nil
+	case "watch_cache":
+		return strconv.FormatBool(c.WatchCache), nil
+	case "pre_sync_hook":
+		return c.PreSyncHook, nil
+	case "post_sync_hook":
+		return c.PostSyncHook, nil
+	case "flashcard_labels":
+		return strings.Join(c.FlashcardLabels, ","), nil
+	case "flashcard_tags":
+		return strings.Join(c.FlashcardTags, ","), nil
+	case "page_plugins":
+		return strings.Join(c.PagePlugins, ","), nil
+	case "readwise_highlights":
+		return strconv.FormatBool(c.ReadwiseHighlights), nil
+	case "reflect_graph_id":
+		return c.ReflectGraphID, nil
+	case "control_socket":
+		return c.ControlSocket, nil
+	case "outbox_dir":
+		return c.OutboxDir, nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
@@ -201,6 +1174,13 @@ func (c *Config) Set(key, value string) error {
 		c.LogseqBasePath = expandPath(value)
 	case "state_db_path":
 		c.StateDBPath = expandPath(value)
+	case "state_backend":
+		if value != "sqlite" && value != "json" && value != "postgres" {
+			return fmt.Errorf("invalid value for state_backend: %s (must be sqlite, json, or postgres)", value)
+		}
+		c.StateBackend = value
+	case "state_dsn":
+		c.StateDSN = value
 	case "debounce_seconds":
 		var v int
 		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
@@ -219,6 +1199,300 @@ func (c *Config) Set(key, value string) error {
 		c.UserEmail = value
 	case "user_name":
 		c.UserName = value
+	case "include_attendee_emails":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for include_attendee_emails: %w", err)
+		}
+		c.IncludeAttendeeEmails = v
+	case "person_pages":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for person_pages: %w", err)
+		}
+		c.PersonPages = v
+	case "machine_id":
+		c.MachineID = value
+	case "adopt_existing_pages":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for adopt_existing_pages: %w", err)
+		}
+		c.AdoptExistingPages = v
+	case "include_transcript":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for include_transcript: %w", err)
+		}
+		c.IncludeTranscript = v
+	case "metrics_textfile_path":
+		c.MetricsTextfilePath = expandPath(value)
+	case "output_failure_threshold":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
+			return fmt.Errorf("invalid value for output_failure_threshold: %w", err)
+		}
+		c.OutputFailureThreshold = v
+	case "page_properties":
+		if value != "bullet" && value != "block" && value != "frontmatter" {
+			return fmt.Errorf("invalid value for page_properties: %s (must be bullet, block, or frontmatter)", value)
+		}
+		c.PageProperties = value
+	case "anonymize_attendees":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for anonymize_attendees: %w", err)
+		}
+		c.AnonymizeAttendees = v
+	case "anonymize_key":
+		c.AnonymizeKey = value
+	case "todoist_tasks":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for todoist_tasks: %w", err)
+		}
+		c.TodoistTasks = v
+	case "todoist_project_id":
+		c.TodoistProjectID = value
+	case "reminders_list":
+		c.RemindersList = value
+	case "default_classification":
+		c.DefaultClassification = value
+	case "backup_retention":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
+			return fmt.Errorf("invalid value for backup_retention: %w", err)
+		}
+		c.BackupRetention = v
+	case "backup_dir":
+		c.BackupDir = expandPath(value)
+	case "unfurl_links":
+		kinds := splitList(value)
+		if err := validateUnfurlLinks(kinds); err != nil {
+			return err
+		}
+		c.UnfurlLinks = kinds
+	case "include_title_patterns":
+		patterns := splitList(value)
+		if err := validatePatterns("include_title_patterns", patterns); err != nil {
+			return err
+		}
+		c.IncludeTitlePatterns = patterns
+	case "exclude_title_patterns":
+		patterns := splitList(value)
+		if err := validatePatterns("exclude_title_patterns", patterns); err != nil {
+			return err
+		}
+		c.ExcludeTitlePatterns = patterns
+	case "exclude_attendee_patterns":
+		patterns := splitList(value)
+		if err := validatePatterns("exclude_attendee_patterns", patterns); err != nil {
+			return err
+		}
+		c.ExcludeAttendeePatterns = patterns
+	case "exclude_resource_attendees":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for exclude_resource_attendees: %w", err)
+		}
+		c.ExcludeResourceAttendees = v
+	case "normalize_glyphs":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for normalize_glyphs: %w", err)
+		}
+		c.NormalizeGlyphs = v
+	case "normalize_attendee_names":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for normalize_attendee_names: %w", err)
+		}
+		c.NormalizeAttendeeNames = v
+	case "include_domains":
+		c.IncludeDomains = splitList(value)
+	case "domain_filter_by":
+		if value != "attendee" && value != "organizer" {
+			return fmt.Errorf("invalid value for domain_filter_by: %s (must be attendee or organizer)", value)
+		}
+		c.DomainFilterBy = value
+	case "detect_language":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for detect_language: %w", err)
+		}
+		c.DetectLanguage = v
+	case "skip_declined":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for skip_declined: %w", err)
+		}
+		c.SkipDeclined = v
+	case "skip_response_statuses":
+		statuses := splitList(value)
+		if err := validateResponseStatuses(statuses); err != nil {
+			return err
+		}
+		c.SkipResponseStatuses = statuses
+	case "derive_title_tag":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for derive_title_tag: %w", err)
+		}
+		c.DeriveTitleTag = v
+	case "title_tag_min_meetings":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
+			return fmt.Errorf("invalid value for title_tag_min_meetings: %w", err)
+		}
+		c.TitleTagMinMeetings = v
+	case "include_my_notes":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for include_my_notes: %w", err)
+		}
+		c.IncludeMyNotes = v
+	case "tag_allowlist":
+		c.TagAllowlist = splitList(value)
+	case "tag_denylist":
+		c.TagDenylist = splitList(value)
+	case "journal_only":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for journal_only: %w", err)
+		}
+		c.JournalOnly = v
+	case "page_name_template":
+		if err := validatePageNameTemplate(value); err != nil {
+			return err
+		}
+		c.PageNameTemplate = value
+	case "archive_dir":
+		c.ArchiveDir = expandPath(value)
+	case "archive_mode":
+		if value != "copy" && value != "hardlink" {
+			return fmt.Errorf("invalid value for archive_mode: %s (must be copy or hardlink)", value)
+		}
+		c.ArchiveMode = value
+	case "journal_heading":
+		c.JournalHeading = value
+	case "calendar_blocks":
+		if err := validateCalendarBlocks(value); err != nil {
+			return err
+		}
+		c.CalendarBlocks = value
+	case "control_page":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for control_page: %w", err)
+		}
+		c.ControlPage = v
+	case "write_order":
+		if err := validateWriteOrder(value); err != nil {
+			return err
+		}
+		c.WriteOrder = value
+	case "notifications":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for notifications: %w", err)
+		}
+		c.Notifications = v
+	case "notify_after_failures":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
+			return fmt.Errorf("invalid value for notify_after_failures: %w", err)
+		}
+		c.NotifyAfterFailures = v
+	case "health_port":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
+			return fmt.Errorf("invalid value for health_port: %w", err)
+		}
+		c.HealthPort = v
+	case "json_sidecars":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for json_sidecars: %w", err)
+		}
+		c.JSONSidecars = v
+	case "spotlight_metadata":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for spotlight_metadata: %w", err)
+		}
+		c.SpotlightMetadata = v
+	case "log_format":
+		if err := validateLogFormat(value); err != nil {
+			return err
+		}
+		c.LogFormat = value
+	case "rerender_batch_size":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil {
+			return fmt.Errorf("invalid value for rerender_batch_size: %w", err)
+		}
+		c.RerenderBatchSize = v
+	case "defer_on_battery":
+		if err := validateDeferOnBattery(value); err != nil {
+			return err
+		}
+		c.DeferOnBattery = value
+	case "remote_graph":
+		if err := validateRemoteGraph(value); err != nil {
+			return err
+		}
+		c.RemoteGraph = value
+	case "s3_bucket":
+		c.S3Bucket = value
+	case "s3_region":
+		c.S3Region = value
+	case "s3_endpoint":
+		c.S3Endpoint = value
+	case "s3_prefix":
+		c.S3Prefix = value
+	case "s3_access_key_id":
+		c.S3AccessKeyID = value
+	case "s3_secret_access_key":
+		c.S3SecretAccessKey = value
+	case "s3_sidecars":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for s3_sidecars: %w", err)
+		}
+		c.S3Sidecars = v
+	case "sync_schedule":
+		if err := validateSyncSchedule(value); err != nil {
+			return err
+		}
+		c.SyncSchedule = value
+	case "watch_cache":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for watch_cache: %w", err)
+		}
+		c.WatchCache = v
+	case "pre_sync_hook":
+		c.PreSyncHook = value
+	case "post_sync_hook":
+		c.PostSyncHook = value
+	case "flashcard_labels":
+		c.FlashcardLabels = splitList(value)
+	case "flashcard_tags":
+		c.FlashcardTags = splitList(value)
+	case "page_plugins":
+		c.PagePlugins = splitList(value)
+	case "readwise_highlights":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for readwise_highlights: %w", err)
+		}
+		c.ReadwiseHighlights = v
+	case "reflect_graph_id":
+		c.ReflectGraphID = value
+	case "control_socket":
+		c.ControlSocket = expandPath(value)
+	case "outbox_dir":
+		c.OutboxDir = expandPath(value)
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
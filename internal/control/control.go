@@ -0,0 +1,213 @@
+// Package control lets CLI commands talk to the running watch mode daemon over
+// a Unix socket, instead of inferring its state from launchd and log files.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/health"
+)
+
+// ErrNotRunning is returned by the client when no daemon is listening.
+var ErrNotRunning = errors.New("daemon not running")
+
+// Daemon is the running watch mode process.
+type Daemon interface {
+	// Status reports what the daemon is doing
+	Status() (*Status, error)
+	// Sync syncs every new and updated meeting, even while syncing is paused,
+	// once any sync already running finishes
+	Sync(ctx context.Context) (*SyncResult, error)
+	// Pause stops watch mode syncing until Resume
+	Pause() error
+	// Resume restarts watch mode syncing
+	Resume() error
+}
+
+// Status is the GET /status response body.
+type Status struct {
+	PID       int           `json:"pid"`
+	StartedAt time.Time     `json:"started_at"`
+	Syncing   bool          `json:"syncing"`
+	PausedAt  *time.Time    `json:"paused_at,omitempty"`
+	Health    health.Report `json:"health"`
+}
+
+// SyncResult is the POST /sync response body.
+type SyncResult struct {
+	NewMeetings     int      `json:"new_meetings"`
+	UpdatedMeetings int      `json:"updated_meetings"`
+	AdoptedMeetings int      `json:"adopted_meetings"`
+	NewJournals     int      `json:"new_journals"`
+	Errors          []string `json:"errors"`
+}
+
+// Handler serves the control API:
+//
+//	GET  /status  what the daemon is doing
+//	POST /sync    sync now and wait for the result
+//	POST /pause   pause syncing
+//	POST /resume  resume syncing
+func Handler(d Daemon) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		status, err := d.Status()
+		respond(w, status, err)
+	})
+	mux.HandleFunc("POST /sync", func(w http.ResponseWriter, r *http.Request) {
+		result, err := d.Sync(r.Context())
+		respond(w, result, err)
+	})
+	mux.HandleFunc("POST /pause", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, struct{}{}, d.Pause())
+	})
+	mux.HandleFunc("POST /resume", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, struct{}{}, d.Resume())
+	})
+	return mux
+}
+
+// Server serves the control API on a Unix socket.
+type Server struct {
+	srv  *http.Server
+	path string
+}
+
+// Listen serves the control API for d on the socket at path, replacing a socket
+// left behind by a daemon that has exited. It fails if another daemon is
+// listening there.
+func Listen(path string, d Daemon) (*Server, error) {
+	if _, err := NewClient(path).Status(context.Background()); err == nil {
+		return nil, fmt.Errorf("another daemon is listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("removing stale control socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on control socket: %w", err)
+	}
+	// Only the user may control the daemon
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("restricting control socket: %w", err)
+	}
+
+	srv := &http.Server{Handler: Handler(d), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("control socket stopped", "error", err)
+		}
+	}()
+	return &Server{srv: srv, path: path}, nil
+}
+
+// Close stops serving, waiting briefly for requests in progress, and removes
+// the socket.
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.srv.Shutdown(ctx)
+	if rmErr := os.Remove(s.path); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// Client talks to the daemon listening on a socket.
+type Client struct {
+	client *http.Client
+}
+
+// NewClient creates a client for the daemon listening on the socket at path.
+func NewClient(path string) *Client {
+	return &Client{client: &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}}
+}
+
+// Status asks the daemon what it is doing.
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	var status Status
+	if err := c.do(ctx, http.MethodGet, "/status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Sync asks the daemon to sync now and waits for the result.
+func (c *Client) Sync(ctx context.Context) (*SyncResult, error) {
+	var result SyncResult
+	if err := c.do(ctx, http.MethodPost, "/sync", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Pause asks the daemon to pause syncing.
+func (c *Client) Pause(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/pause", nil)
+}
+
+// Resume asks the daemon to resume syncing.
+func (c *Client) Resume(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/resume", nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, v any) error {
+	// The host is ignored; requests go to the socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://granola-sync"+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return ErrNotRunning
+		}
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+			return fmt.Errorf("daemon returned %d", resp.StatusCode)
+		}
+		return errors.New(body.Error)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// respond writes v as JSON, or err as {"error": ...} with status 500
+func respond(w http.ResponseWriter, v any, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		v = map[string]string{"error": err.Error()}
+	}
+	_ = json.NewEncoder(w).Encode(v)
+}
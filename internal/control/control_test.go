@@ -0,0 +1,128 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeDaemon records the commands it is sent
+type fakeDaemon struct {
+	paused  bool
+	syncs   int
+	syncErr error
+}
+
+func (f *fakeDaemon) Status() (*Status, error) {
+	status := &Status{PID: 42, StartedAt: time.Date(2025, 1, 28, 9, 0, 0, 0, time.UTC)}
+	if f.paused {
+		at := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+		status.PausedAt = &at
+	}
+	return status, nil
+}
+
+func (f *fakeDaemon) Sync(ctx context.Context) (*SyncResult, error) {
+	if f.syncErr != nil {
+		return nil, f.syncErr
+	}
+	f.syncs++
+	return &SyncResult{NewMeetings: 2, Errors: []string{}}, nil
+}
+
+func (f *fakeDaemon) Pause() error {
+	f.paused = true
+	return nil
+}
+
+func (f *fakeDaemon) Resume() error {
+	f.paused = false
+	return nil
+}
+
+type ControlSuite struct {
+	suite.Suite
+	path   string
+	daemon *fakeDaemon
+	server *Server
+	client *Client
+}
+
+func TestControlSuite(t *testing.T) {
+	suite.Run(t, new(ControlSuite))
+}
+
+func (s *ControlSuite) SetupTest() {
+	// Socket paths are limited to about 100 bytes, so keep the directory short
+	dir, err := os.MkdirTemp("", "ctl")
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = os.RemoveAll(dir) })
+	s.path = filepath.Join(dir, "control.sock")
+
+	s.daemon = &fakeDaemon{}
+	s.server, err = Listen(s.path, s.daemon)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = s.server.Close() })
+	s.client = NewClient(s.path)
+}
+
+func (s *ControlSuite) TestStatus() {
+	status, err := s.client.Status(context.Background())
+	s.Require().NoError(err)
+	s.Equal(42, status.PID)
+	s.Nil(status.PausedAt)
+
+	info, err := os.Stat(s.path)
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
+
+func (s *ControlSuite) TestPauseAndResume() {
+	ctx := context.Background()
+	s.Require().NoError(s.client.Pause(ctx))
+	status, err := s.client.Status(ctx)
+	s.Require().NoError(err)
+	s.NotNil(status.PausedAt)
+
+	s.Require().NoError(s.client.Resume(ctx))
+	s.False(s.daemon.paused)
+}
+
+func (s *ControlSuite) TestSync() {
+	result, err := s.client.Sync(context.Background())
+	s.Require().NoError(err)
+	s.Equal(2, result.NewMeetings)
+	s.Equal(1, s.daemon.syncs)
+
+	s.daemon.syncErr = errors.New("finding cache file: not found")
+	_, err = s.client.Sync(context.Background())
+	s.EqualError(err, "finding cache file: not found")
+}
+
+func (s *ControlSuite) TestNotRunning() {
+	s.Require().NoError(s.server.Close())
+	_, err := s.client.Status(context.Background())
+	s.ErrorIs(err, ErrNotRunning)
+
+	_, err = os.Stat(s.path)
+	s.True(os.IsNotExist(err), "closing removes the socket")
+}
+
+func (s *ControlSuite) TestListen() {
+	_, err := Listen(s.path, &fakeDaemon{})
+	s.ErrorContains(err, "another daemon is listening")
+
+	// A file left behind by a daemon that exited is replaced
+	s.Require().NoError(s.server.Close())
+	s.Require().NoError(os.WriteFile(s.path, nil, 0o600))
+	server, err := Listen(s.path, s.daemon)
+	s.Require().NoError(err)
+	defer func() { _ = server.Close() }()
+	_, err = s.client.Status(context.Background())
+	s.NoError(err)
+}
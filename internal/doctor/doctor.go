@@ -0,0 +1,234 @@
+// Package doctor diagnoses common setup problems.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/service"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	Pass Status = "PASS"
+	Warn Status = "WARN"
+	Fail Status = "FAIL"
+	Skip Status = "SKIP"
+)
+
+// Result is the outcome of one check, with a hint on how to fix a problem.
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Hint   string
+}
+
+// Run performs every check against cfg.
+func Run(cfg *config.Config) []Result {
+	var results []Result
+	results = append(results, checkConfig(cfg))
+	results = append(results, checkCache(cfg.GranolaDir)...)
+	results = append(results, checkAuthToken(cfg.GranolaDir))
+	results = append(results, checkGraph(cfg.LogseqBasePath)...)
+	results = append(results, checkState(cfg))
+	results = append(results, checkService(cfg.Profile)...)
+	return results
+}
+
+func pass(name, detail string) Result { return Result{Name: name, Status: Pass, Detail: detail} }
+
+func fail(name, detail, hint string) Result {
+	return Result{Name: name, Status: Fail, Detail: detail, Hint: hint}
+}
+
+func warn(name, detail, hint string) Result {
+	return Result{Name: name, Status: Warn, Detail: detail, Hint: hint}
+}
+
+// checkConfig checks the settings needed to decide which meetings are yours
+func checkConfig(cfg *config.Config) Result {
+	const name = "config"
+	if cfg.UserEmail == "" {
+		return fail(name, "user_email is not set", "run: granola-sync config init")
+	}
+	if cfg.UserName == "" {
+		return warn(name, "user_name is not set, so your action items won't be marked as TODOs",
+			"run: granola-sync config user_name \"Your Name\"")
+	}
+	return pass(name, fmt.Sprintf("syncing meetings for %s", cfg.UserEmail))
+}
+
+// checkCache checks the Granola cache file exists, is readable, has a known
+// version, and parses
+func checkCache(granolaDir string) []Result {
+	path, err := granola.FindCacheFile(granolaDir)
+	if err != nil {
+		return []Result{fail("granola cache", err.Error(),
+			"open Granola at least once, or set granola_dir to the folder containing cache-v*.json")}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		hint := "check the file's permissions"
+		if errors.Is(err, os.ErrPermission) {
+			hint = "grant Full Disk Access to your terminal (and the granola-sync binary for the service)"
+		}
+		return []Result{fail("granola cache", err.Error(), hint)}
+	}
+	results := []Result{pass("granola cache", path)}
+
+	version, err := granola.CacheVersion(data)
+	switch {
+	case err != nil:
+		results = append(results, fail("cache version", err.Error(),
+			"the cache file is corrupt or mid-write; try again after Granola has saved"))
+		return results
+	case version < granola.MinCacheVersion || version > granola.MaxCacheVersion:
+		results = append(results, warn("cache version",
+			fmt.Sprintf("version %d is untested (supported: %d-%d)", version, granola.MinCacheVersion, granola.MaxCacheVersion),
+			"Granola changed its cache format; check for a granola-sync update"))
+	default:
+		results = append(results, pass("cache version", fmt.Sprintf("version %d", version)))
+	}
+
+	docs, err := granola.ParseCacheData(data)
+	if err != nil {
+		results = append(results, fail("cache parses", err.Error(),
+			"Granola may have changed its cache format; check for a granola-sync update"))
+		return results
+	}
+	return append(results, pass("cache parses", fmt.Sprintf("%d documents", len(docs))))
+}
+
+// checkAuthToken checks the Granola token used to fetch notes missing from the cache
+func checkAuthToken(granolaDir string) Result {
+	if _, err := granola.LoadAuthToken(granolaDir); err != nil {
+		return warn("granola auth token", err.Error(),
+			"sign in to Granola; without a token, notes missing from the cache can't be fetched")
+	}
+	return pass("granola auth token", "found")
+}
+
+// checkGraph checks the Logseq graph exists and its pages and journals
+// directories are writable
+func checkGraph(basePath string) []Result {
+	if basePath == "" {
+		return []Result{fail("logseq graph", "logseq_base_path is not set",
+			"run: granola-sync config logseq_base_path /path/to/graph")}
+	}
+	if !config.IsLogseqGraph(basePath) {
+		return []Result{fail("logseq graph", basePath+" doesn't look like a Logseq graph (no pages/, journals/, or logseq/)",
+			"point logseq_base_path at the graph folder you opened in Logseq")}
+	}
+	results := []Result{pass("logseq graph", basePath)}
+
+	for _, dir := range []string{"pages", "journals"} {
+		name := dir + " writable"
+		if err := checkWritable(filepath.Join(basePath, dir)); err != nil {
+			results = append(results, fail(name, err.Error(),
+				"check the folder's permissions, and that iCloud hasn't offloaded it"))
+			continue
+		}
+		results = append(results, pass(name, filepath.Join(basePath, dir)))
+	}
+	return results
+}
+
+// checkWritable creates and removes a file in dir, creating dir if needed
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".granola-sync-doctor-*")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// checkState checks the state store opens
+func checkState(cfg *config.Config) Result {
+	const name = "state store"
+	backend := cfg.StateBackend
+	if backend == "" {
+		backend = state.BackendSQLite
+	}
+	if backend != state.BackendPostgres {
+		if err := os.MkdirAll(filepath.Dir(cfg.StateDBPath), 0o755); err != nil {
+			return fail(name, err.Error(), "check state_db_path and its folder's permissions")
+		}
+	}
+
+	store, err := state.Open(backend, cfg.StateLocation(), cfg.Machine())
+	if err != nil {
+		hint := "check state_db_path and its folder's permissions"
+		if backend == state.BackendPostgres {
+			hint = "check state_dsn and that the database server is reachable"
+		}
+		return fail(name, err.Error(), hint)
+	}
+	_ = store.Close()
+
+	detail := backend
+	if backend != state.BackendPostgres {
+		detail += " at " + cfg.StateDBPath
+	}
+	return pass(name, detail)
+}
+
+// checkService checks a profile's launchd service is running and its plist
+// points at a binary that still exists
+func checkService(profile string) []Result {
+	if runtime.GOOS != "darwin" {
+		return []Result{{Name: "launchd service", Status: Skip, Detail: "only available on macOS"}}
+	}
+
+	// The command hints need to name the profile
+	cli := "granola-sync"
+	if profile != "" {
+		cli += " --profile " + profile
+	}
+
+	binary, err := service.InstalledBinaryPath(profile)
+	if err != nil {
+		return []Result{fail("launchd service", err.Error(), "reinstall with: "+cli+" start")}
+	}
+	if binary == "" {
+		return []Result{warn("launchd service", "not installed", "to sync in the background, run: "+cli+" start")}
+	}
+
+	var results []Result
+	status, err := service.GetStatus(profile)
+	switch {
+	case err != nil:
+		results = append(results, fail("launchd service", err.Error(), "check that launchctl works in this session"))
+	case status == nil:
+		results = append(results, fail("launchd service", "installed but not loaded", "run: "+cli+" start"))
+	case !status.Running:
+		results = append(results, fail("launchd service", "loaded but not running", "see why with: "+cli+" logs"))
+	default:
+		results = append(results, pass("launchd service", fmt.Sprintf("running (PID %d)", status.PID)))
+	}
+
+	info, err := os.Stat(binary)
+	switch {
+	case err != nil:
+		results = append(results, fail("service binary", binary+" is missing",
+			"the binary moved (e.g. after reinstalling); run: "+cli+" start"))
+	case info.Mode()&0o111 == 0:
+		results = append(results, fail("service binary", binary+" is not executable", "run: chmod +x "+binary))
+	default:
+		results = append(results, pass("service binary", binary))
+	}
+	return results
+}
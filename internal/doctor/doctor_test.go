@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+)
+
+type DoctorSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestDoctorSuite(t *testing.T) {
+	suite.Run(t, new(DoctorSuite))
+}
+
+func (s *DoctorSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+// statuses maps each result's name to its status
+func statuses(results []Result) map[string]Status {
+	m := make(map[string]Status)
+	for _, r := range results {
+		m[r.Name] = r.Status
+	}
+	return m
+}
+
+func (s *DoctorSuite) writeCache(content string) {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "cache-v4.json"), []byte(content), 0o644))
+}
+
+func (s *DoctorSuite) TestCheckCache() {
+	s.Run("missing", func() {
+		results := checkCache(s.dir)
+		s.Require().Len(results, 1)
+		s.Equal(Fail, results[0].Status)
+		s.NotEmpty(results[0].Hint)
+	})
+
+	s.Run("valid", func() {
+		s.writeCache(`{"cache": {"state": {"documents": {"doc-1": {"id": "doc-1", "title": "A"}}}}, "version": 5}`)
+		s.Equal(map[string]Status{
+			"granola cache": Pass,
+			"cache version": Pass,
+			"cache parses":  Pass,
+		}, statuses(checkCache(s.dir)))
+	})
+
+	s.Run("unknown version", func() {
+		s.writeCache(`{"cache": {"state": {"documents": {}}}, "version": 9}`)
+		s.Equal(Warn, statuses(checkCache(s.dir))["cache version"])
+	})
+
+	s.Run("corrupt", func() {
+		s.writeCache(`{"cache": `)
+		got := statuses(checkCache(s.dir))
+		s.Equal(Fail, got["cache version"])
+		s.NotContains(got, "cache parses")
+	})
+}
+
+func (s *DoctorSuite) TestCheckGraph() {
+	s.Equal(Fail, checkGraph("")[0].Status)
+	s.Equal(Fail, checkGraph(s.dir)[0].Status, "an empty folder isn't a graph")
+
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.dir, "pages"), 0o755))
+	s.Equal(map[string]Status{
+		"logseq graph":      Pass,
+		"pages writable":    Pass,
+		"journals writable": Pass,
+	}, statuses(checkGraph(s.dir)))
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, "pages"))
+	s.NoError(err)
+	s.Empty(entries, "the probe file is removed")
+}
+
+func (s *DoctorSuite) TestCheckState() {
+	cfg := &config.Config{StateBackend: "sqlite", StateDBPath: filepath.Join(s.dir, "state", "state.db")}
+	s.Equal(Pass, checkState(cfg).Status)
+
+	cfg = &config.Config{StateBackend: "nosuch"}
+	s.Equal(Fail, checkState(cfg).Status)
+}
+
+func (s *DoctorSuite) TestCheckConfig() {
+	s.Equal(Fail, checkConfig(&config.Config{}).Status)
+	s.Equal(Warn, checkConfig(&config.Config{UserEmail: "a@b.c"}).Status)
+	s.Equal(Pass, checkConfig(&config.Config{UserEmail: "a@b.c", UserName: "A"}).Status)
+}
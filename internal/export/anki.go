@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Flashcard is a spaced-repetition card. Anki matches cards on Front when a
+// deck is re-imported, so re-exporting updates cards rather than duplicating
+// them.
+type Flashcard struct {
+	Front string
+	Back  string
+	Tags  []string
+}
+
+// ankiHeader tells Anki's importer how to read the file (Anki 2.1.54+)
+const ankiHeader = "#separator:tab\n#html:true\n#columns:Front\tBack\tTags\n#tags column:3\n"
+
+// WriteAnkiCSV writes cards to w as a tab-separated file for Anki's
+// File > Import, with fields as HTML.
+func WriteAnkiCSV(w io.Writer, cards []Flashcard) error {
+	if _, err := io.WriteString(w, ankiHeader); err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	for _, card := range cards {
+		tags := make([]string, len(card.Tags))
+		for i, tag := range card.Tags {
+			// Anki tags are space-separated
+			tags[i] = strings.Join(strings.Fields(tag), "_")
+		}
+		if err := cw.Write([]string{ankiHTML(card.Front), ankiHTML(card.Back), strings.Join(tags, " ")}); err != nil {
+			return fmt.Errorf("writing card: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("writing cards: %w", err)
+	}
+	return nil
+}
+
+// ankiHTML escapes text for an HTML field, keeping its line breaks
+func ankiHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
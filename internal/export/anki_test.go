@@ -0,0 +1,30 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AnkiSuite struct {
+	suite.Suite
+}
+
+func TestAnkiSuite(t *testing.T) {
+	suite.Run(t, new(AnkiSuite))
+}
+
+func (s *AnkiSuite) TestWriteAnkiCSV() {
+	cards := []Flashcard{
+		{Front: "ARR", Back: "annual recurring revenue\n\nPlanning (2025-01-28)", Tags: []string{"meeting", "granola-sync", "definition"}},
+		{Front: "Roadmap <review>: Decision?", Back: "• Ship\t\"now\"", Tags: []string{"team sync"}},
+	}
+
+	var buf bytes.Buffer
+	s.Require().NoError(WriteAnkiCSV(&buf, cards))
+	s.Equal(ankiHeader+
+		"ARR\tannual recurring revenue<br><br>Planning (2025-01-28)\tmeeting granola-sync definition\n"+
+		"Roadmap &lt;review&gt;: Decision?\t\"• Ship\t&#34;now&#34;\"\tteam_sync\n",
+		buf.String())
+}
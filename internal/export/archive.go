@@ -0,0 +1,73 @@
+// Package export packages rendered meetings into portable archives.
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+)
+
+// File is one file in an archive.
+type File struct {
+	Name    string // Slash-separated path inside the archive
+	Content []byte
+	ModTime time.Time
+}
+
+// WriteArchive writes files to w as a gzipped tarball.
+func WriteArchive(w io.Writer, files []File) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    f.Name,
+			Mode:    0o644,
+			Size:    int64(len(f.Content)),
+			ModTime: f.ModTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("writing header for %s: %w", f.Name, err)
+		}
+		if _, err := tw.Write(f.Content); err != nil {
+			return fmt.Errorf("writing %s: %w", f.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("closing gzip: %w", err)
+	}
+	return nil
+}
+
+// ParseRecipient parses an --encrypt value of the form "age:<recipient>",
+// where the recipient is an age X25519 public key (age1...).
+func ParseRecipient(spec string) (age.Recipient, error) {
+	scheme, value, ok := strings.Cut(spec, ":")
+	if !ok || scheme != "age" {
+		return nil, fmt.Errorf("unsupported encryption %q (expected age:<recipient>)", spec)
+	}
+	recipient, err := age.ParseX25519Recipient(value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing age recipient: %w", err)
+	}
+	return recipient, nil
+}
+
+// Encrypt returns a writer that age-encrypts everything written to it for the
+// recipients. The caller must close it to flush the final chunk.
+func Encrypt(w io.Writer, recipients ...age.Recipient) (io.WriteCloser, error) {
+	enc, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("starting encryption: %w", err)
+	}
+	return enc, nil
+}
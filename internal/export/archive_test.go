@@ -0,0 +1,88 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/suite"
+)
+
+type ArchiveSuite struct {
+	suite.Suite
+}
+
+func TestArchiveSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveSuite))
+}
+
+// readArchive returns the name and content of each file in a gzipped tarball
+func (s *ArchiveSuite) readArchive(r io.Reader) map[string]string {
+	gz, err := gzip.NewReader(r)
+	s.Require().NoError(err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		content, err := io.ReadAll(tr)
+		s.Require().NoError(err)
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
+
+func (s *ArchiveSuite) TestWriteArchive() {
+	var buf bytes.Buffer
+	err := WriteArchive(&buf, []File{
+		{Name: "pages/a.md", Content: []byte("- A"), ModTime: time.Now()},
+		{Name: "pages/b.md", Content: []byte("- B"), ModTime: time.Now()},
+	})
+	s.Require().NoError(err)
+
+	s.Equal(map[string]string{"pages/a.md": "- A", "pages/b.md": "- B"}, s.readArchive(&buf))
+}
+
+func (s *ArchiveSuite) TestEncryptRoundTrip() {
+	identity, err := age.GenerateX25519Identity()
+	s.Require().NoError(err)
+	recipient, err := ParseRecipient("age:" + identity.Recipient().String())
+	s.Require().NoError(err)
+
+	var buf bytes.Buffer
+	enc, err := Encrypt(&buf, recipient)
+	s.Require().NoError(err)
+	s.Require().NoError(WriteArchive(enc, []File{{Name: "pages/a.md", Content: []byte("- Secret")}}))
+	s.Require().NoError(enc.Close())
+
+	s.NotContains(buf.String(), "Secret")
+
+	dec, err := age.Decrypt(&buf, identity)
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"pages/a.md": "- Secret"}, s.readArchive(dec))
+}
+
+func (s *ArchiveSuite) TestParseRecipientErrors() {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"missing_scheme", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+		{"unknown_scheme", "gpg:ABCDEF"},
+		{"bad_key", "age:not-a-key"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := ParseRecipient(tt.spec)
+			s.Error(err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// Meeting is a normalized meeting record for JSON export.
+type Meeting struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Start       time.Time  `json:"start"`
+	End         *time.Time `json:"end,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Attendees   []Attendee `json:"attendees"`
+	Notes       string     `json:"notes_markdown"`
+	NotesSource string     `json:"notes_source,omitempty"`
+	// ActionItems are set by the syncer, which knows how notes are rendered
+	ActionItems []ActionItem `json:"action_items,omitempty"`
+}
+
+// Attendee is a meeting attendee.
+type Attendee struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// ActionItem is an action item from a meeting's notes. Mine is set for the
+// user's own.
+type ActionItem struct {
+	Owner string `json:"owner,omitempty"`
+	Text  string `json:"text"`
+	Mine  bool   `json:"mine,omitempty"`
+}
+
+// NewMeeting builds the export record for a document. Start is the calendar
+// event start, or the creation time for meetings without an event.
+func NewMeeting(doc *granola.Document) Meeting {
+	m := Meeting{
+		ID:        doc.ID,
+		Title:     doc.Title,
+		Start:     doc.GetMeetingDate(),
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+		Attendees: []Attendee{},
+	}
+	if event := doc.GoogleCalendarEvent; event != nil && event.End != nil {
+		if end, err := time.Parse(time.RFC3339, event.End.DateTime); err == nil {
+			end = end.Local()
+			m.End = &end
+		}
+	}
+	for _, a := range doc.GetAttendees() {
+		m.Attendees = append(m.Attendees, Attendee{Name: a.Name, Email: a.Email})
+	}
+
+	switch {
+	case doc.NotesMarkdown != nil && *doc.NotesMarkdown != "":
+		m.Notes = *doc.NotesMarkdown
+		m.NotesSource = doc.NotesSource
+	case doc.NotesPlain != nil:
+		m.Notes = *doc.NotesPlain
+	}
+	return m
+}
+
+// WriteJSON writes meetings to w as an indented JSON array.
+func WriteJSON(w io.Writer, meetings []Meeting) error {
+	if meetings == nil {
+		meetings = []Meeting{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(meetings); err != nil {
+		return fmt.Errorf("encoding meetings: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSON writes meetings to w as newline-delimited JSON, one per line.
+func WriteNDJSON(w io.Writer, meetings []Meeting) error {
+	enc := json.NewEncoder(w)
+	for _, m := range meetings {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("encoding meeting %s: %w", m.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type MeetingsSuite struct {
+	suite.Suite
+}
+
+func TestMeetingsSuite(t *testing.T) {
+	suite.Run(t, new(MeetingsSuite))
+}
+
+func (s *MeetingsSuite) TestNewMeeting() {
+	notes := "- Ship it"
+	doc := &granola.Document{
+		ID:            "doc-1",
+		Title:         "Roadmap",
+		CreatedAt:     time.Date(2025, 1, 28, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2025, 1, 28, 11, 0, 0, 0, time.UTC),
+		NotesMarkdown: &notes,
+		NotesSource:   granola.NotesFromPanel,
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Start: &granola.EventTime{DateTime: "2025-01-28T10:00:00Z"},
+			End:   &granola.EventTime{DateTime: "2025-01-28T10:30:00Z"},
+			Attendees: []granola.Attendee{
+				{Email: "alice@example.com", DisplayName: "Alice"},
+			},
+		},
+	}
+
+	m := NewMeeting(doc)
+	s.Equal("doc-1", m.ID)
+	s.True(m.Start.Equal(time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)))
+	s.Require().NotNil(m.End)
+	s.True(m.End.Equal(time.Date(2025, 1, 28, 10, 30, 0, 0, time.UTC)))
+	s.Equal([]Attendee{{Name: "Alice", Email: "alice@example.com"}}, m.Attendees)
+	s.Equal("- Ship it", m.Notes)
+	s.Equal(granola.NotesFromPanel, m.NotesSource)
+
+	// No calendar event: start falls back to creation time, no end
+	m = NewMeeting(&granola.Document{ID: "doc-2", CreatedAt: doc.CreatedAt})
+	s.True(m.Start.Equal(doc.CreatedAt))
+	s.Nil(m.End)
+	s.NotNil(m.Attendees, "attendees encode as [] rather than null")
+}
+
+func (s *MeetingsSuite) TestWriteJSON() {
+	meetings := []Meeting{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+
+	var buf bytes.Buffer
+	s.Require().NoError(WriteJSON(&buf, meetings))
+	var decoded []Meeting
+	s.Require().NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	s.Len(decoded, 2)
+
+	buf.Reset()
+	s.Require().NoError(WriteJSON(&buf, nil))
+	s.Equal("[]\n", buf.String())
+}
+
+func (s *MeetingsSuite) TestWriteNDJSON() {
+	meetings := []Meeting{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+
+	var buf bytes.Buffer
+	s.Require().NoError(WriteNDJSON(&buf, meetings))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	s.Require().Len(lines, 2)
+	for i, line := range lines {
+		var m Meeting
+		s.Require().NoError(json.Unmarshal([]byte(line), &m))
+		s.Equal(meetings[i].ID, m.ID)
+	}
+}
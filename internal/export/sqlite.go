@@ -0,0 +1,145 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema lays meetings out for exploring with Datasette or SQL: one row
+// per meeting, attendee and action item, with full-text search over titles
+// and notes in the table Datasette looks for (meetings_fts).
+const sqliteSchema = `
+CREATE TABLE meetings (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	start TEXT NOT NULL,
+	"end" TEXT,
+	created_at TEXT NOT NULL,
+	updated_at TEXT NOT NULL,
+	notes_markdown TEXT NOT NULL,
+	notes_source TEXT
+);
+CREATE INDEX meetings_start ON meetings(start);
+
+CREATE TABLE attendees (
+	meeting_id TEXT NOT NULL REFERENCES meetings(id),
+	name TEXT NOT NULL,
+	email TEXT
+);
+CREATE INDEX attendees_meeting_id ON attendees(meeting_id);
+CREATE INDEX attendees_email ON attendees(email);
+
+CREATE TABLE action_items (
+	meeting_id TEXT NOT NULL REFERENCES meetings(id),
+	owner TEXT,
+	text TEXT NOT NULL,
+	mine INTEGER NOT NULL
+);
+CREATE INDEX action_items_meeting_id ON action_items(meeting_id);
+
+CREATE VIRTUAL TABLE meetings_fts USING fts5(title, notes_markdown, content='meetings');
+`
+
+// WriteSQLite writes meetings to a new SQLite database at path, replacing any
+// file already there once the database is complete.
+func WriteSQLite(path string, meetings []Meeting) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".granola-sync-export-*.db")
+	if err != nil {
+		return fmt.Errorf("creating database: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if err := writeSQLite(tmpPath, meetings); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeSQLite(path string, meetings []Meeting) (err error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("closing database: %w", closeErr)
+		}
+	}()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("creating tables: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, m := range meetings {
+		if err := insertMeeting(tx, m); err != nil {
+			return fmt.Errorf("writing meeting %s: %w", m.ID, err)
+		}
+	}
+	if _, err := tx.Exec(`INSERT INTO meetings_fts(rowid, title, notes_markdown) SELECT rowid, title, notes_markdown FROM meetings`); err != nil {
+		return fmt.Errorf("indexing notes: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	return nil
+}
+
+// insertMeeting adds a meeting's rows. Times are RFC 3339 strings in UTC, which
+// sort and compare correctly as text.
+func insertMeeting(tx *sql.Tx, m Meeting) error {
+	var end *string
+	if m.End != nil {
+		formatted := sqlTime(*m.End)
+		end = &formatted
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO meetings (id, title, start, "end", created_at, updated_at, notes_markdown, notes_source) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ID, m.Title, sqlTime(m.Start), end,
+		sqlTime(m.CreatedAt), sqlTime(m.UpdatedAt),
+		m.Notes, nullString(m.NotesSource),
+	); err != nil {
+		return err
+	}
+
+	for _, a := range m.Attendees {
+		if _, err := tx.Exec(`INSERT INTO attendees (meeting_id, name, email) VALUES (?, ?, ?)`,
+			m.ID, a.Name, nullString(a.Email)); err != nil {
+			return err
+		}
+	}
+	for _, item := range m.ActionItems {
+		if _, err := tx.Exec(`INSERT INTO action_items (meeting_id, owner, text, mine) VALUES (?, ?, ?, ?)`,
+			m.ID, nullString(item.Owner), item.Text, item.Mine); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sqlTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SQLiteSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestSQLiteSuite(t *testing.T) {
+	suite.Run(t, new(SQLiteSuite))
+}
+
+func (s *SQLiteSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "meetings.db")
+}
+
+func (s *SQLiteSuite) TestWriteSQLite() {
+	start := time.Date(2025, 1, 28, 9, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	meetings := []Meeting{
+		{
+			ID:        "doc-1",
+			Title:     "Roadmap",
+			Start:     start,
+			CreatedAt: start,
+			UpdatedAt: start,
+			Attendees: []Attendee{{Name: "Alice", Email: "alice@example.com"}, {Name: "Bob"}},
+			Notes:     "- Ship the quarterly roadmap",
+			ActionItems: []ActionItem{
+				{Owner: "Alice", Text: "Send the deck", Mine: true},
+				{Text: "Book a room"},
+			},
+		},
+		{ID: "doc-2", Title: "Standup", Start: start.Add(24 * time.Hour), Notes: "- Nothing new"},
+	}
+	s.Require().NoError(WriteSQLite(s.path, meetings))
+
+	db, err := sql.Open("sqlite", s.path)
+	s.Require().NoError(err)
+	defer func() { _ = db.Close() }()
+
+	var title, startCol string
+	var end sql.NullString
+	s.Require().NoError(db.QueryRow(`SELECT title, start, "end" FROM meetings WHERE id = 'doc-1'`).Scan(&title, &startCol, &end))
+	s.Equal("Roadmap", title)
+	s.Equal("2025-01-28T14:00:00Z", startCol)
+	s.False(end.Valid)
+
+	var email sql.NullString
+	s.Require().NoError(db.QueryRow(`SELECT email FROM attendees WHERE name = 'Bob'`).Scan(&email))
+	s.False(email.Valid)
+
+	var owner string
+	s.Require().NoError(db.QueryRow(`SELECT owner FROM action_items WHERE mine = 1`).Scan(&owner))
+	s.Equal("Alice", owner)
+
+	var id string
+	s.Require().NoError(db.QueryRow(`SELECT m.id FROM meetings_fts f JOIN meetings m ON m.rowid = f.rowid WHERE meetings_fts MATCH 'quarterly'`).Scan(&id))
+	s.Equal("doc-1", id)
+}
+
+func (s *SQLiteSuite) TestWriteSQLiteReplacesExistingFile() {
+	s.Require().NoError(os.WriteFile(s.path, []byte("not a database"), 0o644))
+
+	s.Require().NoError(WriteSQLite(s.path, []Meeting{{ID: "a", Title: "A"}}))
+
+	db, err := sql.Open("sqlite", s.path)
+	s.Require().NoError(err)
+	defer func() { _ = db.Close() }()
+	var count int
+	s.Require().NoError(db.QueryRow(`SELECT COUNT(*) FROM meetings`).Scan(&count))
+	s.Equal(1, count)
+
+	entries, err := os.ReadDir(filepath.Dir(s.path))
+	s.Require().NoError(err)
+	s.Len(entries, 1, "no temporary files left behind")
+}
@@ -1,12 +1,15 @@
 package granola
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,12 +20,20 @@ type CacheFileRaw struct {
 	Version int             `json:"version"`
 }
 
-// CacheState represents the inner JSON structure
-type CacheState struct {
-	State struct {
-		Documents      map[string]*Document                 `json:"documents"`
-		DocumentPanels map[string]map[string]*DocumentPanel `json:"documentPanels"`
-	} `json:"state"`
+// Cache file versions the parser is known to handle. v3 files double-encode the
+// cache as a string; later versions embed it as an object.
+const (
+	MinCacheVersion = 3
+	MaxCacheVersion = 5
+)
+
+// CacheVersion returns the version field of cache file data
+func CacheVersion(data []byte) (int, error) {
+	var raw CacheFileRaw
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return 0, fmt.Errorf("parsing outer JSON: %w", err)
+	}
+	return raw.Version, nil
 }
 
 // DocumentPanel represents a panel containing notes/summary for a document (v3 format)
@@ -49,71 +60,101 @@ func FindCacheFile(dir string) (string, error) {
 	return matches[len(matches)-1], nil
 }
 
+// PanelCache remembers markdown extracted from document panels so unchanged
+// panels aren't re-extracted on every parse
+type PanelCache interface {
+	// Markdown returns the markdown cached for a panel at stamp, its
+	// content_updated_at and the extractor version
+	Markdown(panelID, stamp string) (string, bool)
+	// SetMarkdown caches the markdown extracted from a panel
+	SetMarkdown(panelID, stamp, markdown string)
+}
+
+// extractorVersion is part of each panel cache stamp. Bump it when
+// ExtractMarkdownFromContent's output changes, so panels cached by an older
+// version are extracted again.
+const extractorVersion = 1
+
 // ParseCache parses the Granola cache file
 func ParseCache(path string) (map[string]*Document, error) {
-	data, err := os.ReadFile(path)
+	return ParseCacheWith(path, nil)
+}
+
+// ParseCacheWith parses the Granola cache file, reusing markdown from panels
+// (which may be nil) for panels whose content hasn't changed
+func ParseCacheWith(path string, panels PanelCache) (map[string]*Document, error) {
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading cache file: %w", err)
 	}
+	defer func() { _ = f.Close() }()
 
-	return ParseCacheData(data)
+	return parseCacheStream(f, panels)
 }
 
 // ParseCacheData parses the cache data bytes.
 // Supports both v3 (double-encoded string) and v4 (direct object) cache formats.
 func ParseCacheData(data []byte) (map[string]*Document, error) {
-	var raw CacheFileRaw
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("parsing outer JSON: %w", err)
-	}
-
-	// Determine if cache is a string (v3) or object (v4)
-	var innerData []byte
-	if len(raw.Cache) > 0 && raw.Cache[0] == '"' {
-		// v3: double-encoded JSON string
-		var s string
-		if err := json.Unmarshal(raw.Cache, &s); err != nil {
-			return nil, fmt.Errorf("parsing inner JSON string: %w", err)
-		}
-		innerData = []byte(s)
-	} else {
-		// v4: direct JSON object
-		innerData = raw.Cache
-	}
+	return ParseCacheDataWith(data, nil)
+}
 
-	var inner CacheState
-	if err := json.Unmarshal(innerData, &inner); err != nil {
-		return nil, fmt.Errorf("parsing inner JSON: %w", err)
-	}
+// ParseCacheDataWith is ParseCacheData with a panel markdown cache (which may be nil)
+func ParseCacheDataWith(data []byte, panels PanelCache) (map[string]*Document, error) {
+	return parseCacheStream(bytes.NewReader(data), panels)
+}
 
-	// Extract notes from documentPanels (v3) or inline notes content (v4)
-	for docID, doc := range inner.State.Documents {
-		populateNotes(doc, inner.State.DocumentPanels[docID])
+// sortTranscript returns the final transcript entries ordered by start time
+func sortTranscript(entries []TranscriptEntry) []TranscriptEntry {
+	var final []TranscriptEntry
+	for _, e := range entries {
+		if !e.IsFinal || strings.TrimSpace(e.Text) == "" {
+			continue
+		}
+		final = append(final, e)
 	}
-
-	return inner.State.Documents, nil
+	sort.SliceStable(final, func(i, j int) bool {
+		return final[i].StartTimestamp.Before(final[j].StartTimestamp)
+	})
+	return final
 }
 
 // populateNotes sets NotesMarkdown on a document from panels (v3) or inline notes (v4).
-func populateNotes(doc *Document, panels map[string]*DocumentPanel) {
+func populateNotes(doc *Document, panels map[string]*DocumentPanel, cache PanelCache) {
 	if doc.NotesMarkdown != nil && *doc.NotesMarkdown != "" {
+		doc.NotesSource = NotesFromDocument
 		return
 	}
 
-	if md := bestSummaryFromPanels(panels); md != "" {
+	if md := bestSummaryFromPanels(panels, cache); md != "" {
 		doc.NotesMarkdown = &md
+		doc.NotesSource = NotesFromPanel
 		return
 	}
 
 	if doc.Notes != nil {
 		if md := ExtractMarkdownFromContent(doc.Notes); md != "" {
 			doc.NotesMarkdown = &md
+			doc.NotesSource = NotesFromInline
 		}
 	}
 }
 
+// populateMyNotes sets MyNotes from the document's own rich-text notes, unless
+// populateNotes already used them as the meeting's notes
+func populateMyNotes(doc *Document) {
+	if doc.Notes == nil || doc.NotesSource == NotesFromInline {
+		return
+	}
+	doc.MyNotes = ExtractMarkdownFromContent(doc.Notes)
+}
+
 // BestSummaryFromPanels picks the most recently updated "Summary" panel and returns its markdown.
 func BestSummaryFromPanels(panels []*DocumentPanel) string {
+	return bestSummary(panels, nil)
+}
+
+// bestSummary is BestSummaryFromPanels with a panel markdown cache (which may be nil)
+func bestSummary(panels []*DocumentPanel, cache PanelCache) string {
 	var bestContent string
 	var bestTime time.Time
 
@@ -121,7 +162,7 @@ func BestSummaryFromPanels(panels []*DocumentPanel) string {
 		if panel.Title != "Summary" || panel.Content == nil {
 			continue
 		}
-		md := ExtractMarkdownFromContent(panel.Content)
+		md := panelMarkdown(panel, cache)
 		if md == "" {
 			continue
 		}
@@ -142,12 +183,31 @@ func BestSummaryFromPanels(panels []*DocumentPanel) string {
 }
 
 // bestSummaryFromPanels is the map-keyed variant used by the v3 cache parser.
-func bestSummaryFromPanels(panels map[string]*DocumentPanel) string {
+func bestSummaryFromPanels(panels map[string]*DocumentPanel, cache PanelCache) string {
 	slice := make([]*DocumentPanel, 0, len(panels))
 	for _, p := range panels {
 		slice = append(slice, p)
 	}
-	return BestSummaryFromPanels(slice)
+	return bestSummary(slice, cache)
+}
+
+// panelMarkdown extracts a panel's markdown, using the cache when the panel
+// has an ID and content_updated_at to key it by. Panels with unknown node types
+// aren't cached, so they are counted on every parse.
+func panelMarkdown(panel *DocumentPanel, cache PanelCache) string {
+	if cache == nil || panel.ID == "" || panel.ContentUpdatedAt == "" {
+		return ExtractMarkdownFromContent(panel.Content)
+	}
+	stamp := fmt.Sprintf("%s@%d", panel.ContentUpdatedAt, extractorVersion)
+	if md, ok := cache.Markdown(panel.ID, stamp); ok {
+		return md
+	}
+	seen := unknownNodesSeen.Load()
+	md := ExtractMarkdownFromContent(panel.Content)
+	if unknownNodesSeen.Load() == seen {
+		cache.SetMarkdown(panel.ID, stamp, md)
+	}
+	return md
 }
 
 // ExtractMarkdownFromContent converts the rich text content structure to Logseq-formatted bullets
@@ -185,17 +245,129 @@ func extractNodeToLogseq(node interface{}, depth int) string {
 		return extractHeadingNode(nodeMap, indent)
 	case "paragraph":
 		return extractParagraphNode(nodeMap, indent)
-	case "bulletList", "orderedList":
+	case "bulletList", "orderedList", "taskList":
 		return extractListNode(nodeMap, depth)
 	case "listItem":
-		return extractListItemNode(nodeMap, indent, depth)
+		return extractListItemNode(nodeMap, indent, depth, "")
+	case "taskItem":
+		return extractListItemNode(nodeMap, indent, depth, taskMarker(nodeMap))
+	case "table":
+		return extractTableNode(nodeMap, indent)
+	case "codeBlock":
+		return extractCodeBlockNode(nodeMap, indent)
+	case "blockquote":
+		return extractBlockquoteNode(nodeMap, indent)
 	case "text":
 		if text, ok := nodeMap["text"].(string); ok {
-			return text
+			return withLinkMark(nodeMap, text)
 		}
+		return ""
 	}
 
-	return ""
+	recordUnknownNode(nodeType)
+	return extractUnknownNode(nodeMap, indent, depth)
+}
+
+// extractUnknownNode passes through a node type the extractor doesn't know
+// (e.g. a callout added by Granola) so its content isn't lost. Runs of
+// inline text become bullets and block children are extracted as usual.
+func extractUnknownNode(nodeMap map[string]interface{}, indent string, depth int) string {
+	if text, ok := nodeMap["text"].(string); ok {
+		if text == "" {
+			return ""
+		}
+		return indent + "- " + withLinkMark(nodeMap, text) + "\n"
+	}
+
+	content, ok := nodeMap["content"].([]interface{})
+	if !ok {
+		// Leaf nodes such as images carry their content in attrs
+		if attrs, ok := nodeMap["attrs"].(map[string]interface{}); ok {
+			if src, _ := attrs["src"].(string); src != "" {
+				alt, _ := attrs["alt"].(string)
+				return indent + "- ![" + alt + "](" + src + ")\n"
+			}
+		}
+		return ""
+	}
+
+	var result, inline string
+	flush := func() {
+		if inline != "" {
+			result += indent + "- " + inline + "\n"
+			inline = ""
+		}
+	}
+	for _, child := range content {
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if isInlineNode(childMap) {
+			inline += inlineText(childMap)
+			continue
+		}
+		flush()
+		result += extractNodeToLogseq(childMap, depth)
+	}
+	flush()
+	return result
+}
+
+// isInlineNode reports whether a node is inline text rather than a block
+func isInlineNode(nodeMap map[string]interface{}) bool {
+	nodeType, _ := nodeMap["type"].(string)
+	switch nodeType {
+	case "text", "hardBreak":
+		return true
+	case "":
+		_, ok := nodeMap["text"].(string)
+		return ok
+	}
+	return false
+}
+
+// knownNodeTypes are the node types the extractor handles itself
+var knownNodeTypes = map[string]bool{
+	"doc": true, "heading": true, "paragraph": true, "bulletList": true,
+	"orderedList": true, "listItem": true, "text": true, "hardBreak": true,
+	"table": true, "tableRow": true, "tableHeader": true, "tableCell": true,
+	"taskList": true, "taskItem": true, "codeBlock": true, "blockquote": true,
+}
+
+var (
+	unknownNodesMu sync.Mutex
+	unknownNodes   = make(map[string]int)
+	// unknownNodesSeen counts every unknown node ever passed through; unlike
+	// unknownNodes it is never reset
+	unknownNodesSeen atomic.Int64
+)
+
+// recordUnknownNode counts a node type the extractor passed through
+func recordUnknownNode(nodeType string) {
+	if knownNodeTypes[nodeType] {
+		return
+	}
+	if nodeType == "" {
+		nodeType = "(untyped)"
+	}
+	unknownNodesMu.Lock()
+	unknownNodes[nodeType]++
+	unknownNodesMu.Unlock()
+	unknownNodesSeen.Add(1)
+}
+
+// TakeUnknownNodeKinds returns how many times each unknown node type was passed
+// through since the last call, and resets the counts
+func TakeUnknownNodeKinds() map[string]int {
+	unknownNodesMu.Lock()
+	defer unknownNodesMu.Unlock()
+	if len(unknownNodes) == 0 {
+		return nil
+	}
+	counts := unknownNodes
+	unknownNodes = make(map[string]int)
+	return counts
 }
 
 func extractHeadingNode(nodeMap map[string]interface{}, indent string) string {
@@ -226,7 +398,9 @@ func extractListNode(nodeMap map[string]interface{}, depth int) string {
 	return result
 }
 
-func extractListItemNode(nodeMap map[string]interface{}, indent string, depth int) string {
+// extractListItemNode renders a list item's paragraphs as bullets and its nested
+// lists one level deeper. A non-empty marker (e.g. "TODO ") prefixes the first bullet.
+func extractListItemNode(nodeMap map[string]interface{}, indent string, depth int, marker string) string {
 	content, ok := nodeMap["content"].([]interface{})
 	if !ok {
 		return ""
@@ -241,18 +415,187 @@ func extractListItemNode(nodeMap map[string]interface{}, indent string, depth in
 		case "paragraph":
 			text := extractTextFromNode(childMap)
 			if text != "" {
-				result += indent + "- " + text + "\n"
+				result += indent + "- " + marker + text + "\n"
+				marker = ""
 			}
-		case "bulletList", "orderedList":
+		case "bulletList", "orderedList", "taskList":
 			if i == 0 {
-				result += indent + "- \n"
+				result += indent + "- " + marker + "\n"
+				marker = ""
 			}
 			result += extractNodeToLogseq(child, depth+1)
+		default:
+			result += extractNodeToLogseq(child, depth)
+		}
+	}
+	return result
+}
+
+// taskMarker returns the Logseq task marker for a taskItem's checked state
+func taskMarker(nodeMap map[string]interface{}) string {
+	attrs, _ := nodeMap["attrs"].(map[string]interface{})
+	if checked, _ := attrs["checked"].(bool); checked {
+		return "DONE "
+	}
+	return "TODO "
+}
+
+// extractCodeBlockNode renders a code block as a fenced block inside a bullet,
+// keeping its line breaks and language
+func extractCodeBlockNode(nodeMap map[string]interface{}, indent string) string {
+	var code strings.Builder
+	content, _ := nodeMap["content"].([]interface{})
+	for _, child := range content {
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if childType, _ := childMap["type"].(string); childType == "hardBreak" {
+			code.WriteString("\n")
+			continue
+		}
+		text, _ := childMap["text"].(string)
+		code.WriteString(text)
+	}
+	if strings.TrimSpace(code.String()) == "" {
+		return ""
+	}
+
+	attrs, _ := nodeMap["attrs"].(map[string]interface{})
+	language, _ := attrs["language"].(string)
+
+	result := indent + "- ```" + language + "\n"
+	for _, line := range strings.Split(strings.TrimRight(code.String(), "\n"), "\n") {
+		result += indent + "  " + line + "\n"
+	}
+	return result + indent + "  ```\n"
+}
+
+// extractBlockquoteNode renders a blockquote as a single bullet with a > line
+// per paragraph
+func extractBlockquoteNode(nodeMap map[string]interface{}, indent string) string {
+	var lines []string
+	inline := ""
+	content, _ := nodeMap["content"].([]interface{})
+	for _, child := range content {
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if isInlineNode(childMap) {
+			inline += inlineText(childMap)
+			continue
+		}
+		lines = append(lines, inline, nestedText(childMap))
+		inline = ""
+	}
+	lines = append(lines, inline)
+
+	var result string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if result == "" {
+			result = indent + "- > " + line + "\n"
+		} else {
+			result += indent + "  > " + line + "\n"
 		}
 	}
 	return result
 }
 
+// extractTableNode renders a table as a markdown table in a single block, with
+// continuation lines indented under the bullet. The first row is the header.
+func extractTableNode(nodeMap map[string]interface{}, indent string) string {
+	rowNodes, _ := nodeMap["content"].([]interface{})
+	var rows [][]string
+	columns := 0
+	for _, rowNode := range rowNodes {
+		rowMap, ok := rowNode.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cellNodes, _ := rowMap["content"].([]interface{})
+		var row []string
+		for _, cellNode := range cellNodes {
+			cellMap, ok := cellNode.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			row = append(row, tableCellText(cellMap))
+		}
+		if len(row) == 0 {
+			continue
+		}
+		rows = append(rows, row)
+		columns = max(columns, len(row))
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	formatRow := func(row []string) string {
+		cells := make([]string, columns)
+		copy(cells, row)
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+	separator := make([]string, columns)
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	result := indent + "- " + formatRow(rows[0]) + "\n"
+	result += indent + "  " + formatRow(separator) + "\n"
+	for _, row := range rows[1:] {
+		result += indent + "  " + formatRow(row) + "\n"
+	}
+	return result
+}
+
+// tableCellText flattens a table cell's paragraphs into one line, escaping pipes
+func tableCellText(cellMap map[string]interface{}) string {
+	var parts []string
+	blocks, _ := cellMap["content"].([]interface{})
+	for _, block := range blocks {
+		blockMap, ok := block.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if text := strings.TrimSpace(nestedText(blockMap)); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	text := strings.Join(parts, " ")
+	text = strings.ReplaceAll(text, "\n", " ")
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+// nestedText returns all text under a node, separating blocks with spaces
+func nestedText(nodeMap map[string]interface{}) string {
+	if isInlineNode(nodeMap) {
+		return inlineText(nodeMap)
+	}
+	content, _ := nodeMap["content"].([]interface{})
+	var parts []string
+	inline := ""
+	for _, child := range content {
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if isInlineNode(childMap) {
+			inline += inlineText(childMap)
+			continue
+		}
+		parts = append(parts, inline, nestedText(childMap))
+		inline = ""
+	}
+	parts = append(parts, inline)
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
 // extractTextFromNode extracts all text content from a node's children
 func extractTextFromNode(nodeMap map[string]interface{}) string {
 	content, ok := nodeMap["content"].([]interface{})
@@ -266,9 +609,43 @@ func extractTextFromNode(nodeMap map[string]interface{}) string {
 		if !ok {
 			continue
 		}
-		if text, ok := childMap["text"].(string); ok {
-			texts = append(texts, text)
-		}
+		texts = append(texts, inlineText(childMap))
 	}
 	return strings.Join(texts, "")
 }
+
+// inlineText returns an inline node's text, including text nested inside
+// inline nodes the extractor doesn't know (e.g. mentions)
+func inlineText(nodeMap map[string]interface{}) string {
+	if text, ok := nodeMap["text"].(string); ok {
+		return withLinkMark(nodeMap, text)
+	}
+	nodeType, _ := nodeMap["type"].(string)
+	if nodeType == "hardBreak" {
+		return " "
+	}
+	recordUnknownNode(nodeType)
+	return extractTextFromNode(nodeMap)
+}
+
+// linkEscaper escapes the characters that would end a markdown link target early
+var linkEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+// withLinkMark renders a text node's link mark, if it has one, as a markdown link
+func withLinkMark(nodeMap map[string]interface{}, text string) string {
+	marks, _ := nodeMap["marks"].([]interface{})
+	for _, mark := range marks {
+		markMap, ok := mark.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if markType, _ := markMap["type"].(string); markType != "link" {
+			continue
+		}
+		attrs, _ := markMap["attrs"].(map[string]interface{})
+		if href, _ := attrs["href"].(string); href != "" && strings.TrimSpace(text) != "" {
+			return "[" + text + "](" + linkEscaper.Replace(href) + ")"
+		}
+	}
+	return text
+}
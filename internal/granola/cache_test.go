@@ -1,9 +1,11 @@
 package granola
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -51,6 +53,7 @@ func (s *CacheSuite) TestParseCacheData() {
 				doc := docs["doc-1"]
 				s.NotNil(doc.NotesMarkdown)
 				s.Contains(*doc.NotesMarkdown, "Meeting summary")
+				s.Equal(NotesFromPanel, doc.NotesSource)
 			},
 		},
 		{
@@ -82,6 +85,32 @@ func (s *CacheSuite) TestParseCacheData() {
 				s.NotNil(doc.NotesMarkdown)
 				s.Contains(*doc.NotesMarkdown, "Action Items")
 				s.Contains(*doc.NotesMarkdown, "Follow up on the proposal")
+				s.Equal(NotesFromInline, doc.NotesSource)
+				s.Empty(doc.MyNotes, "inline notes are already the meeting's notes")
+			},
+		},
+		{
+			name:    "with_my_notes_v4",
+			file:    "with_my_notes_v4.json",
+			wantErr: false,
+			validate: func(docs map[string]*Document) {
+				doc := docs["doc-1"]
+				s.Require().NotNil(doc.NotesMarkdown)
+				s.Contains(*doc.NotesMarkdown, "Meeting summary")
+				s.Equal(NotesFromPanel, doc.NotesSource)
+				s.Equal("- Ask about the Q3 budget\n", doc.MyNotes)
+			},
+		},
+		{
+			name:    "with_transcript_v4",
+			file:    "with_transcript_v4.json",
+			wantErr: false,
+			validate: func(docs map[string]*Document) {
+				doc := docs["doc-1"]
+				s.Require().Len(doc.Transcript, 2, "non-final entries are dropped")
+				s.Equal("Can you hear me?", doc.Transcript[0].Text)
+				s.Equal("microphone", doc.Transcript[0].Source)
+				s.Equal("Hi, thanks for joining.", doc.Transcript[1].Text)
 			},
 		},
 		{
@@ -207,3 +236,230 @@ func (s *CacheSuite) TestExtractMarkdownFromContent() {
 		})
 	}
 }
+
+func (s *CacheSuite) TestExtractUnknownNodes() {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		unknown  map[string]int
+	}{
+		{
+			name: "callout_with_paragraphs",
+			content: `{"content": [{"type": "callout", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "Heads up"}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "Budget is due Friday"}]}]}]}`,
+			expected: "- Heads up\n- Budget is due Friday\n",
+			unknown:  map[string]int{"callout": 1},
+		},
+		{
+			name: "table_cells",
+			content: `{"content": [{"type": "table", "content": [
+				{"type": "tableRow", "content": [
+					{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Owner"}]}]},
+					{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Alice"}]}]}]}]}]}`,
+			expected: "- | Owner | Alice |\n  | --- | --- |\n",
+		},
+		{
+			name: "table_with_header_and_ragged_rows",
+			content: `{"content": [{"type": "table", "content": [
+				{"type": "tableRow", "content": [
+					{"type": "tableHeader", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Owner"}]}]},
+					{"type": "tableHeader", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Task"}]}]}]},
+				{"type": "tableRow", "content": [
+					{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Alice"}]}]},
+					{"type": "tableCell", "content": [
+						{"type": "paragraph", "content": [{"type": "text", "text": "Ship a|b"}]},
+						{"type": "paragraph", "content": [{"type": "text", "text": "by Friday"}]}]}]},
+				{"type": "tableRow", "content": [
+					{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Bob"}]}]}]}]}]}`,
+			expected: "- | Owner | Task |\n  | --- | --- |\n  | Alice | Ship a\\|b by Friday |\n  | Bob |  |\n",
+		},
+		{
+			name:     "unknown_with_inline_text",
+			content:  `{"content": [{"type": "aside", "content": [{"type": "text", "text": "Aside"}]}]}`,
+			expected: "- Aside\n",
+			unknown:  map[string]int{"aside": 1},
+		},
+		{
+			name: "code_block",
+			content: `{"content": [{"type": "codeBlock", "attrs": {"language": "go"}, "content": [
+				{"type": "text", "text": "if err != nil {\n\treturn err\n}\n"}]}]}`,
+			expected: "- ```go\n  if err != nil {\n  \treturn err\n  }\n  ```\n",
+		},
+		{
+			name:     "code_block_without_language",
+			content:  `{"content": [{"type": "codeBlock", "content": [{"type": "text", "text": "make test"}]}]}`,
+			expected: "- ```\n  make test\n  ```\n",
+		},
+		{
+			name:     "empty_code_block",
+			content:  `{"content": [{"type": "codeBlock", "attrs": {"language": "sh"}}]}`,
+			expected: "",
+		},
+		{
+			name: "blockquote",
+			content: `{"content": [{"type": "blockquote", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "Ship it "}, {"type": "text", "text": "on Friday"}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "— the CEO"}]}]}]}`,
+			expected: "- > Ship it on Friday\n  > — the CEO\n",
+		},
+		{
+			name:     "blockquote_with_inline_text",
+			content:  `{"content": [{"type": "blockquote", "content": [{"type": "text", "text": "Quoted"}]}]}`,
+			expected: "- > Quoted\n",
+		},
+		{
+			name:     "image",
+			content:  `{"content": [{"type": "image", "attrs": {"src": "https://example.com/a.png", "alt": "Chart"}}]}`,
+			expected: "- ![Chart](https://example.com/a.png)\n",
+			unknown:  map[string]int{"image": 1},
+		},
+		{
+			name: "inline_mention_in_paragraph",
+			content: `{"content": [{"type": "paragraph", "content": [
+				{"type": "text", "text": "Ask "},
+				{"type": "mention", "content": [{"type": "text", "text": "Bob"}]},
+				{"type": "hardBreak"},
+				{"type": "text", "text": "today"}]}]}`,
+			expected: "- Ask Bob today\n",
+			unknown:  map[string]int{"mention": 1},
+		},
+		{
+			name: "link_marks",
+			content: `{"content": [{"type": "paragraph", "content": [
+				{"type": "text", "text": "See "},
+				{"type": "text", "text": "the spec", "marks": [{"type": "bold"}, {"type": "link", "attrs": {"href": "https://docs.google.com/document/d/abc (draft)"}}]},
+				{"type": "text", "text": " and ", "marks": [{"type": "link", "attrs": {"href": ""}}]},
+				{"type": "text", "text": "notes", "marks": [{"type": "italic"}]}]}]}`,
+			expected: "- See [the spec](https://docs.google.com/document/d/abc%20%28draft%29) and notes\n",
+		},
+		{
+			name: "heading_inside_list_item",
+			content: `{"content": [{"type": "bulletList", "content": [{"type": "listItem", "content": [
+				{"type": "heading", "content": [{"type": "text", "text": "Topic"}]}]}]}]}`,
+			expected: "- **Topic**\n",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var content interface{}
+			s.Require().NoError(json.Unmarshal([]byte(tt.content), &content))
+
+			TakeUnknownNodeKinds()
+			s.Equal(tt.expected, ExtractMarkdownFromContent(content))
+			s.Equal(tt.unknown, TakeUnknownNodeKinds())
+		})
+	}
+}
+
+func (s *CacheSuite) TestExtractTaskList() {
+	content := `{"content": [{"type": "taskList", "content": [
+		{"type": "taskItem", "attrs": {"checked": false}, "content": [
+			{"type": "paragraph", "content": [{"type": "text", "text": "Alice: Draft the plan"}]},
+			{"type": "taskList", "content": [
+				{"type": "taskItem", "attrs": {"checked": true}, "content": [
+					{"type": "paragraph", "content": [{"type": "text", "text": "Collect numbers"}]}]}]}]},
+		{"type": "taskItem", "attrs": {"checked": true}, "content": [
+			{"type": "paragraph", "content": [{"type": "text", "text": "Book the room"}]}]},
+		{"type": "taskItem", "content": [
+			{"type": "paragraph", "content": [{"type": "text", "text": "No attrs"}]}]}]}]}`
+
+	var parsed interface{}
+	s.Require().NoError(json.Unmarshal([]byte(content), &parsed))
+
+	TakeUnknownNodeKinds()
+	s.Equal("- TODO Alice: Draft the plan\n\t- DONE Collect numbers\n- DONE Book the room\n- TODO No attrs\n",
+		ExtractMarkdownFromContent(parsed))
+	s.Nil(TakeUnknownNodeKinds())
+}
+
+// FuzzExtractMarkdownFromContent checks arbitrary (valid JSON) rich text never
+// panics and produces valid UTF-8 from valid UTF-8
+func FuzzExtractMarkdownFromContent(f *testing.F) {
+	seeds := []string{
+		`{"content": [{"type": "paragraph", "content": [{"text": "Hello"}]}]}`,
+		`{"content": [{"type": "bulletList", "content": [{"type": "listItem", "content": [{"type": "paragraph", "content": [{"text": "a"}]}, {"type": "orderedList", "content": []}]}]}]}`,
+		`{"content": [{"type": "table", "content": [{"type": "tableRow", "content": [{"type": "tableCell", "content": [{"text": "x"}]}]}]}]}`,
+		`{"content": [{"type": "image", "attrs": {"src": "a.png"}}, {"type": 7}, null, "text", []]}`,
+		`{"content": {"type": "paragraph"}}`,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var content interface{}
+		if err := json.Unmarshal(data, &content); err != nil {
+			t.Skip()
+		}
+		out := ExtractMarkdownFromContent(content)
+		if utf8.Valid(data) && !utf8.ValidString(out) {
+			t.Errorf("invalid UTF-8 output %q", out)
+		}
+	})
+}
+
+// mapPanelCache is an in-memory PanelCache
+type mapPanelCache map[string][2]string
+
+func (c mapPanelCache) Markdown(panelID, stamp string) (string, bool) {
+	entry, ok := c[panelID]
+	if !ok || entry[0] != stamp {
+		return "", false
+	}
+	return entry[1], true
+}
+
+func (c mapPanelCache) SetMarkdown(panelID, stamp, markdown string) {
+	c[panelID] = [2]string{stamp, markdown}
+}
+
+func (s *CacheSuite) TestParseCacheDataWithPanelCache() {
+	data := []byte(`{"cache": {"state": {"documents": {"doc-1": {"id": "doc-1", "title": "Test Meeting", "created_at": "2024-01-15T10:00:00Z", "updated_at": "2024-01-15T11:00:00Z"}},
+		"documentPanels": {"doc-1": {"panel-1": {"id": "panel-1", "title": "Summary", "content_updated_at": "2024-01-15T11:00:00Z",
+			"content": {"content": [{"type": "paragraph", "content": [{"text": "Fresh summary"}]}]}}}}}}, "version": 4}`)
+
+	// A miss extracts the panel and caches it
+	cache := mapPanelCache{}
+	docs, err := ParseCacheDataWith(data, cache)
+	s.Require().NoError(err)
+	s.Contains(*docs["doc-1"].NotesMarkdown, "Fresh summary")
+	s.Equal([2]string{"2024-01-15T11:00:00Z@1", *docs["doc-1"].NotesMarkdown}, cache["panel-1"])
+
+	// An unchanged panel is served from the cache without re-extracting
+	cache["panel-1"] = [2]string{"2024-01-15T11:00:00Z@1", "- Cached summary\n"}
+	docs, err = ParseCacheDataWith(data, cache)
+	s.Require().NoError(err)
+	s.Equal("- Cached summary\n", *docs["doc-1"].NotesMarkdown)
+
+	// An edited panel is extracted again
+	cache["panel-1"] = [2]string{"2024-01-15T10:30:00Z@1", "- Stale summary\n"}
+	docs, err = ParseCacheDataWith(data, cache)
+	s.Require().NoError(err)
+	s.Contains(*docs["doc-1"].NotesMarkdown, "Fresh summary")
+
+	// So is a panel cached by an older extractor
+	cache["panel-1"] = [2]string{"2024-01-15T11:00:00Z", "- Old extractor summary\n"}
+	docs, err = ParseCacheDataWith(data, cache)
+	s.Require().NoError(err)
+	s.Contains(*docs["doc-1"].NotesMarkdown, "Fresh summary")
+}
+
+func (s *CacheSuite) TestPanelCacheSkipsUnknownNodes() {
+	data := []byte(`{"cache": {"state": {"documents": {"doc-1": {"id": "doc-1", "title": "Test Meeting", "created_at": "2024-01-15T10:00:00Z", "updated_at": "2024-01-15T11:00:00Z"}},
+		"documentPanels": {"doc-1": {"panel-1": {"id": "panel-1", "title": "Summary", "content_updated_at": "2024-01-15T11:00:00Z",
+			"content": {"content": [{"type": "callout", "content": [{"type": "text", "text": "Heads up"}]}]}}}}}}, "version": 4}`)
+
+	// Unknown nodes are counted on every parse, so their panels aren't cached
+	cache := mapPanelCache{}
+	for range 2 {
+		TakeUnknownNodeKinds()
+		docs, err := ParseCacheDataWith(data, cache)
+		s.Require().NoError(err)
+		s.Contains(*docs["doc-1"].NotesMarkdown, "Heads up")
+		s.Equal(map[string]int{"callout": 1}, TakeUnknownNodeKinds())
+	}
+	s.Empty(cache)
+}
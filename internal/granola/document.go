@@ -1,6 +1,9 @@
 package granola
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Document represents a Granola meeting document
 type Document struct {
@@ -16,18 +19,65 @@ type Document struct {
 	Overview            *string              `json:"overview"`
 	GoogleCalendarEvent *GoogleCalendarEvent `json:"google_calendar_event"`
 	People              *People              `json:"people"`
+
+	// Transcript is populated from the cache's transcripts map, not the document itself
+	Transcript []TranscriptEntry `json:"-"`
+	// NotesSource records where NotesMarkdown came from (one of the NotesFrom constants)
+	NotesSource string `json:"-"`
+	// MyNotes is the user's own typed notes as Logseq bullets, set when
+	// NotesMarkdown is a summary rather than those notes
+	MyNotes string `json:"-"`
+}
+
+// Sources of a document's NotesMarkdown
+const (
+	NotesFromDocument = "document"      // notes_markdown on the document itself
+	NotesFromPanel    = "summary panel" // documentPanels in the cache
+	NotesFromInline   = "inline notes"  // the document's rich-text notes (v4)
+	NotesFromAPI      = "API panel"     // fetched from the Granola API
+)
+
+// TranscriptEntry is one utterance of a meeting transcript
+type TranscriptEntry struct {
+	ID             string    `json:"id"`
+	DocumentID     string    `json:"document_id"`
+	StartTimestamp time.Time `json:"start_timestamp"`
+	EndTimestamp   time.Time `json:"end_timestamp"`
+	Text           string    `json:"text"`
+	Source         string    `json:"source"` // "microphone" (the user) or "system" (everyone else)
+	IsFinal        bool      `json:"is_final"`
 }
 
 type GoogleCalendarEvent struct {
-	ID        string     `json:"id"`
-	Summary   string     `json:"summary"`
-	Start     *EventTime `json:"start"`
-	End       *EventTime `json:"end"`
-	Attendees []Attendee `json:"attendees"`
+	ID             string          `json:"id"`
+	CalendarID     string          `json:"calendarId"`
+	Summary        string          `json:"summary"`
+	Start          *EventTime      `json:"start"`
+	End            *EventTime      `json:"end"`
+	Attendees      []Attendee      `json:"attendees"`
+	Location       string          `json:"location"`
+	HangoutLink    string          `json:"hangoutLink"`
+	ConferenceData *ConferenceData `json:"conferenceData"`
+	// EventType is "default" for ordinary events, or "focusTime", "outOfOffice"
+	// or "workingLocation"
+	EventType string `json:"eventType"`
+}
+
+// ConferenceData describes how to join a calendar event's video call (Meet,
+// Zoom, Teams add-ons)
+type ConferenceData struct {
+	EntryPoints []EntryPoint `json:"entryPoints"`
+}
+
+// EntryPoint is one way to join a conference
+type EntryPoint struct {
+	EntryPointType string `json:"entryPointType"` // "video", "phone", "sip" or "more"
+	URI            string `json:"uri"`
 }
 
 type EventTime struct {
 	DateTime string `json:"dateTime"`
+	Date     string `json:"date"` // Set instead of DateTime for all-day events
 	TimeZone string `json:"timeZone"`
 }
 
@@ -37,6 +87,8 @@ type Attendee struct {
 	ResponseStatus string `json:"responseStatus"`
 	Self           bool   `json:"self"`
 	Organizer      bool   `json:"organizer"`
+	// Resource marks meeting rooms and other bookable resources
+	Resource bool `json:"resource"`
 }
 
 type People struct {
@@ -102,11 +154,35 @@ func (d *Document) GetMeetingTimeRange() (start, end, tz string) {
 	return start, end, tz
 }
 
-// GetAttendeeNames returns a list of attendee names
-func (d *Document) GetAttendeeNames() []string {
-	var names []string
+// MeetingAttendee is an attendee resolved to a display name and email
+type MeetingAttendee struct {
+	Name  string
+	Email string
+	// Resource is set for meeting rooms and other calendar resources
+	Resource bool
+}
+
+// resourceDomain is the email domain of Google Calendar's rooms and resources
+const resourceDomain = "resource.calendar.google.com"
+
+// GetAttendees returns the meeting attendees with their best display name and email
+func (d *Document) GetAttendees() []MeetingAttendee {
+	var attendees []MeetingAttendee
 	seen := make(map[string]bool)
 
+	// Calendar resources by email, since People.Attendees doesn't flag them
+	resources := make(map[string]bool)
+	if d.GoogleCalendarEvent != nil {
+		for _, a := range d.GoogleCalendarEvent.Attendees {
+			if a.Resource && a.Email != "" {
+				resources[strings.ToLower(a.Email)] = true
+			}
+		}
+	}
+	isResource := func(email string) bool {
+		return resources[strings.ToLower(email)] || strings.HasSuffix(strings.ToLower(email), "@"+resourceDomain)
+	}
+
 	// Get from People.Attendees first (has better names)
 	if d.People != nil {
 		for _, a := range d.People.Attendees {
@@ -116,13 +192,13 @@ func (d *Document) GetAttendeeNames() []string {
 			}
 			if name != "" && !seen[name] {
 				seen[name] = true
-				names = append(names, name)
+				attendees = append(attendees, MeetingAttendee{Name: name, Email: a.Email, Resource: isResource(a.Email)})
 			}
 		}
 	}
 
 	// Fall back to GoogleCalendarEvent attendees if no People attendees
-	if len(names) == 0 && d.GoogleCalendarEvent != nil {
+	if len(attendees) == 0 && d.GoogleCalendarEvent != nil {
 		for _, a := range d.GoogleCalendarEvent.Attendees {
 			name := a.DisplayName
 			if name == "" {
@@ -131,11 +207,20 @@ func (d *Document) GetAttendeeNames() []string {
 			}
 			if name != "" && !seen[name] {
 				seen[name] = true
-				names = append(names, name)
+				attendees = append(attendees, MeetingAttendee{Name: name, Email: a.Email, Resource: isResource(a.Email)})
 			}
 		}
 	}
 
+	return attendees
+}
+
+// GetAttendeeNames returns a list of attendee names
+func (d *Document) GetAttendeeNames() []string {
+	var names []string
+	for _, a := range d.GetAttendees() {
+		names = append(names, a.Name)
+	}
 	return names
 }
 
@@ -216,3 +301,121 @@ func (d *Document) IsUserAttendee(userEmail string) bool {
 func (d *Document) HasNotes() bool {
 	return d.NotesMarkdown != nil && *d.NotesMarkdown != ""
 }
+
+// GetCalendar returns the calendar the meeting's event came from: the event's
+// calendar ID, or else the email of the user's own (self) attendee entry.
+// Returns empty string for meetings without a calendar event.
+func (d *Document) GetCalendar() string {
+	if d.GoogleCalendarEvent == nil {
+		return ""
+	}
+	if d.GoogleCalendarEvent.CalendarID != "" {
+		return d.GoogleCalendarEvent.CalendarID
+	}
+	for _, a := range d.GoogleCalendarEvent.Attendees {
+		if a.Self {
+			return a.Email
+		}
+	}
+	return ""
+}
+
+// GetUserResponseStatus returns the user's response to the meeting's calendar
+// invite (e.g. "accepted", "declined", "needsAction"): that of the attendee with
+// userEmail, or of the self attendee if userEmail is empty. Returns empty string
+// for meetings without a calendar event or without the user among the attendees.
+func (d *Document) GetUserResponseStatus(userEmail string) string {
+	if d.GoogleCalendarEvent == nil {
+		return ""
+	}
+	for _, a := range d.GoogleCalendarEvent.Attendees {
+		if (userEmail == "" && a.Self) || (userEmail != "" && a.Email == userEmail) {
+			return a.ResponseStatus
+		}
+	}
+	return ""
+}
+
+// GetMeetingLink returns the link to join the meeting's video call: the event's
+// Meet link, its conference video entry point, or a URL given as its location.
+// Returns empty string if the event has none.
+func (d *Document) GetMeetingLink() string {
+	event := d.GoogleCalendarEvent
+	if event == nil {
+		return ""
+	}
+	if event.HangoutLink != "" {
+		return event.HangoutLink
+	}
+	if event.ConferenceData != nil {
+		for _, ep := range event.ConferenceData.EntryPoints {
+			if ep.EntryPointType == "video" && ep.URI != "" {
+				return ep.URI
+			}
+		}
+	}
+	if isURL(event.Location) {
+		return strings.TrimSpace(event.Location)
+	}
+	return ""
+}
+
+// GetLocation returns the calendar event's location on one line, unless it is
+// just the meeting link. Returns empty string if the event has none.
+func (d *Document) GetLocation() string {
+	if d.GoogleCalendarEvent == nil || isURL(d.GoogleCalendarEvent.Location) {
+		return ""
+	}
+	return strings.Join(strings.Fields(d.GoogleCalendarEvent.Location), " ")
+}
+
+// isURL reports whether s is a single http(s) URL
+func isURL(s string) bool {
+	s = strings.TrimSpace(s)
+	return (strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")) && !strings.ContainsAny(s, " \t\n")
+}
+
+// IsAllDay returns true if the meeting's calendar event is an all-day event
+func (d *Document) IsAllDay() bool {
+	event := d.GoogleCalendarEvent
+	return event != nil && event.Start != nil && event.Start.DateTime == "" && event.Start.Date != ""
+}
+
+// IsCalendarBlock returns true if the meeting's calendar event blocks out time
+// rather than being a meeting: focus time, out of office, a working location,
+// or an all-day event without attendees other than the user
+func (d *Document) IsCalendarBlock() bool {
+	event := d.GoogleCalendarEvent
+	if event == nil {
+		return false
+	}
+	switch event.EventType {
+	case "focusTime", "outOfOffice", "workingLocation":
+		return true
+	}
+	if !d.IsAllDay() {
+		return false
+	}
+	for _, a := range event.Attendees {
+		if !a.Self {
+			return false
+		}
+	}
+	return true
+}
+
+// GetOrganizerEmail returns the meeting organizer's email: the calendar event's
+// organizer, or else the creator of the notes. Returns empty string if unknown.
+func (d *Document) GetOrganizerEmail() string {
+	if d.GoogleCalendarEvent != nil {
+		for _, a := range d.GoogleCalendarEvent.Attendees {
+			if a.Organizer {
+				return a.Email
+			}
+		}
+	}
+	if d.People != nil && d.People.Creator != nil {
+		return d.People.Creator.Email
+	}
+	return ""
+}
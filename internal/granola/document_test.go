@@ -148,6 +148,28 @@ func (s *DocumentSuite) TestGetAttendeeNames() {
 	}
 }
 
+func (s *DocumentSuite) TestGetAttendeesResources() {
+	doc := &Document{
+		People: &People{Attendees: []AttendeeInfo{
+			{Name: "Alice", Email: "alice@example.com"},
+			{Name: "Board Room", Email: "board-room@example.com"},
+			{Name: "Floor 3", Email: "c_1884@resource.calendar.google.com"},
+		}},
+		GoogleCalendarEvent: &GoogleCalendarEvent{
+			Attendees: []Attendee{
+				{Email: "alice@example.com"},
+				{Email: "Board-Room@example.com", Resource: true},
+			},
+		},
+	}
+
+	s.Equal([]MeetingAttendee{
+		{Name: "Alice", Email: "alice@example.com"},
+		{Name: "Board Room", Email: "board-room@example.com", Resource: true},
+		{Name: "Floor 3", Email: "c_1884@resource.calendar.google.com", Resource: true},
+	}, doc.GetAttendees())
+}
+
 func (s *DocumentSuite) TestIsUserAttendee() {
 	tests := []struct {
 		name      string
@@ -296,6 +318,163 @@ func (s *DocumentSuite) TestHasNotes() {
 	}
 }
 
+func (s *DocumentSuite) TestGetCalendar() {
+	tests := []struct {
+		name string
+		doc  *Document
+		want string
+	}{
+		{
+			name: "no_calendar_event",
+			doc:  &Document{},
+			want: "",
+		},
+		{
+			name: "calendar_id",
+			doc: &Document{GoogleCalendarEvent: &GoogleCalendarEvent{
+				CalendarID: "team@group.calendar.google.com",
+				Attendees:  []Attendee{{Email: "me@example.com", Self: true}},
+			}},
+			want: "team@group.calendar.google.com",
+		},
+		{
+			name: "falls_back_to_self_attendee",
+			doc: &Document{GoogleCalendarEvent: &GoogleCalendarEvent{
+				Attendees: []Attendee{{Email: "bob@example.com"}, {Email: "me@example.com", Self: true}},
+			}},
+			want: "me@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.doc.GetCalendar())
+		})
+	}
+}
+
+func (s *DocumentSuite) TestGetUserResponseStatus() {
+	event := &Document{GoogleCalendarEvent: &GoogleCalendarEvent{Attendees: []Attendee{
+		{Email: "org@example.com", Organizer: true, ResponseStatus: "accepted"},
+		{Email: "me@example.com", Self: true, ResponseStatus: "declined"},
+	}}}
+	tests := []struct {
+		name      string
+		doc       *Document
+		userEmail string
+		want      string
+	}{
+		{"by_email", event, "me@example.com", "declined"},
+		{"by_self", event, "", "declined"},
+		{"not_attendee", event, "other@example.com", ""},
+		{"no_event", &Document{}, "me@example.com", ""},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.doc.GetUserResponseStatus(tt.userEmail))
+		})
+	}
+}
+
+func (s *DocumentSuite) TestGetMeetingLinkAndLocation() {
+	tests := []struct {
+		name         string
+		event        *GoogleCalendarEvent
+		wantLink     string
+		wantLocation string
+	}{
+		{
+			name:         "meet_link_and_room",
+			event:        &GoogleCalendarEvent{HangoutLink: "https://meet.google.com/abc-defg-hij", Location: "HQ\n4th floor"},
+			wantLink:     "https://meet.google.com/abc-defg-hij",
+			wantLocation: "HQ 4th floor",
+		},
+		{
+			name: "conference_video_entry_point",
+			event: &GoogleCalendarEvent{ConferenceData: &ConferenceData{EntryPoints: []EntryPoint{
+				{EntryPointType: "phone", URI: "tel:+1-555-0100"},
+				{EntryPointType: "video", URI: "https://zoom.us/j/123"},
+			}}},
+			wantLink: "https://zoom.us/j/123",
+		},
+		{
+			name:     "url_location",
+			event:    &GoogleCalendarEvent{Location: " https://zoom.us/j/456 "},
+			wantLink: "https://zoom.us/j/456",
+		},
+		{
+			name: "no_event",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			doc := &Document{GoogleCalendarEvent: tt.event}
+			s.Equal(tt.wantLink, doc.GetMeetingLink())
+			s.Equal(tt.wantLocation, doc.GetLocation())
+		})
+	}
+}
+
+func (s *DocumentSuite) TestIsCalendarBlock() {
+	allDay := &EventTime{Date: "2025-01-28"}
+	timed := &EventTime{DateTime: "2025-01-28T09:00:00Z"}
+	tests := []struct {
+		name       string
+		event      *GoogleCalendarEvent
+		wantAllDay bool
+		want       bool
+	}{
+		{"no_event", nil, false, false},
+		{"meeting", &GoogleCalendarEvent{Start: timed, Attendees: []Attendee{{Email: "a@example.com"}}}, false, false},
+		{"focus_time", &GoogleCalendarEvent{Start: timed, EventType: "focusTime"}, false, true},
+		{"all_day_alone", &GoogleCalendarEvent{Start: allDay, Attendees: []Attendee{{Email: "me@example.com", Self: true}}}, true, true},
+		{"all_day_with_others", &GoogleCalendarEvent{Start: allDay, Attendees: []Attendee{{Email: "a@example.com"}}}, true, false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			doc := &Document{GoogleCalendarEvent: tt.event}
+			s.Equal(tt.wantAllDay, doc.IsAllDay())
+			s.Equal(tt.want, doc.IsCalendarBlock())
+		})
+	}
+}
+
+func (s *DocumentSuite) TestGetOrganizerEmail() {
+	creator := &People{Creator: &PersonInfo{Email: "creator@example.com"}}
+	tests := []struct {
+		name string
+		doc  *Document
+		want string
+	}{
+		{
+			name: "calendar_organizer",
+			doc: &Document{People: creator, GoogleCalendarEvent: &GoogleCalendarEvent{
+				Attendees: []Attendee{{Email: "me@example.com", Self: true}, {Email: "org@example.com", Organizer: true}},
+			}},
+			want: "org@example.com",
+		},
+		{
+			name: "falls_back_to_creator",
+			doc:  &Document{People: creator, GoogleCalendarEvent: &GoogleCalendarEvent{}},
+			want: "creator@example.com",
+		},
+		{
+			name: "unknown",
+			doc:  &Document{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.doc.GetOrganizerEmail())
+		})
+	}
+}
+
 func (s *DocumentSuite) TestGetMeetingTimeRange() {
 	tests := []struct {
 		name      string
@@ -0,0 +1,322 @@
+package granola
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The cache file can run to hundreds of megabytes, so it is parsed as a stream:
+// the outer object is scanned for the cache field, a v3 cache string is
+// unescaped as it is read, and the inner state is decoded one document at a time.
+// Neither the file nor the inner JSON is ever held in memory whole.
+
+// cacheContents holds the parts of the inner cache state the parser uses
+type cacheContents struct {
+	documents   map[string]*Document
+	panels      map[string]map[string]*DocumentPanel
+	transcripts map[string][]TranscriptEntry
+}
+
+// parseCacheStream parses a cache file read from r
+func parseCacheStream(r io.Reader, panelCache PanelCache) (map[string]*Document, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	inner, err := cacheReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("parsing outer JSON: %w", err)
+	}
+
+	contents, err := decodeCacheState(json.NewDecoder(inner))
+	// The decoder may report a bad string as the JSON ending early
+	if str, ok := inner.(*stringReader); ok && str.err != nil {
+		return nil, fmt.Errorf("parsing inner JSON string: %w", str.err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing inner JSON: %w", err)
+	}
+
+	// Extract notes from documentPanels (v3) or inline notes content (v4)
+	for docID, doc := range contents.documents {
+		populateNotes(doc, contents.panels[docID], panelCache)
+		populateMyNotes(doc)
+		doc.Transcript = sortTranscript(contents.transcripts[docID])
+	}
+
+	return contents.documents, nil
+}
+
+// cacheReader finds the cache field of the outer object and returns a reader of
+// the inner JSON: the unescaped string in v3, or the object itself in v4
+func cacheReader(br *bufio.Reader) (io.Reader, error) {
+	dec := json.NewDecoder(br)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key != "cache" {
+			if err := skipValue(dec); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Continue from where the decoder stopped reading, before the colon
+		rest := bufio.NewReader(io.MultiReader(dec.Buffered(), br))
+		c, err := skipSpace(rest, ':')
+		if err != nil {
+			return nil, err
+		}
+		switch c {
+		case '"':
+			return &stringReader{r: rest}, nil
+		case '{':
+			return io.MultiReader(strings.NewReader("{"), rest), nil
+		default:
+			return nil, fmt.Errorf("cache is neither a string nor an object (found %q)", c)
+		}
+	}
+	return nil, errors.New("no cache field")
+}
+
+// skipSpace returns the first byte after whitespace and an optional sep
+func skipSpace(r *bufio.Reader, sep byte) (byte, error) {
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == sep:
+			sep = 0
+		default:
+			return c, nil
+		}
+	}
+}
+
+// decodeCacheState decodes the inner cache object
+func decodeCacheState(dec *json.Decoder) (*cacheContents, error) {
+	contents := &cacheContents{documents: make(map[string]*Document)}
+	err := decodeObject(dec, func(key string) error {
+		if key != "state" {
+			return skipValue(dec)
+		}
+		return decodeObject(dec, func(key string) error {
+			switch key {
+			case "documents":
+				return decodeObject(dec, func(id string) error {
+					var doc *Document
+					if err := dec.Decode(&doc); err != nil {
+						return err
+					}
+					if doc != nil {
+						contents.documents[id] = doc
+					}
+					return nil
+				})
+			case "documentPanels":
+				contents.panels = make(map[string]map[string]*DocumentPanel)
+				return decodeObject(dec, func(id string) error {
+					var panels map[string]*DocumentPanel
+					if err := dec.Decode(&panels); err != nil {
+						return err
+					}
+					contents.panels[id] = panels
+					return nil
+				})
+			case "transcripts":
+				contents.transcripts = make(map[string][]TranscriptEntry)
+				return decodeObject(dec, func(id string) error {
+					var entries []TranscriptEntry
+					if err := dec.Decode(&entries); err != nil {
+						return err
+					}
+					contents.transcripts[id] = entries
+					return nil
+				})
+			default:
+				return skipValue(dec)
+			}
+		})
+	})
+	return contents, err
+}
+
+// decodeObject calls field with each key of the next object in dec, which must
+// consume the key's value. A null object has no keys.
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected object, found %v", tok)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := field(key.(string)); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // Closing brace
+	return err
+}
+
+// expectDelim reads the next token from dec, which must be delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v, found %v", delim, tok)
+	}
+	return nil
+}
+
+// skipValue reads past the next value in dec without keeping it
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// stringReader reads the contents of a JSON string, unescaped, from just after
+// its opening quote up to its closing quote
+type stringReader struct {
+	r       *bufio.Reader
+	pending []byte // Unescaped bytes that didn't fit the last read
+	done    bool
+	err     error // Why the string is malformed
+}
+
+func (s *stringReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.pending) > 0 {
+			copied := copy(p[n:], s.pending)
+			s.pending = s.pending[copied:]
+			n += copied
+			continue
+		}
+		if s.done {
+			break
+		}
+
+		c, err := s.r.ReadByte()
+		if err != nil {
+			s.err = noEOF(err)
+			return n, s.err
+		}
+		switch c {
+		case '"':
+			s.done = true
+		case '\\':
+			if s.pending, err = s.readEscape(); err != nil {
+				s.err = err
+				return n, err
+			}
+		default:
+			p[n] = c
+			n++
+		}
+	}
+	if n == 0 && s.done {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// readEscape reads an escape sequence after its backslash and returns its bytes
+func (s *stringReader) readEscape() ([]byte, error) {
+	c, err := s.r.ReadByte()
+	if err != nil {
+		return nil, noEOF(err)
+	}
+	switch c {
+	case '"', '\\', '/':
+		return []byte{c}, nil
+	case 'b':
+		return []byte{'\b'}, nil
+	case 'f':
+		return []byte{'\f'}, nil
+	case 'n':
+		return []byte{'\n'}, nil
+	case 'r':
+		return []byte{'\r'}, nil
+	case 't':
+		return []byte{'\t'}, nil
+	case 'u':
+		r, err := s.readHex()
+		if err != nil {
+			return nil, err
+		}
+		// A surrogate pair is written as two escapes; a lone surrogate is invalid
+		if utf16.IsSurrogate(r) {
+			if next, err := s.r.Peek(6); err == nil && string(next[:2]) == `\u` {
+				if low, err := strconv.ParseUint(string(next[2:]), 16, 16); err == nil {
+					if paired := utf16.DecodeRune(r, rune(low)); paired != utf8.RuneError {
+						_, _ = s.r.Discard(6)
+						return utf8.AppendRune(nil, paired), nil
+					}
+				}
+			}
+			r = utf8.RuneError
+		}
+		return utf8.AppendRune(nil, r), nil
+	default:
+		return nil, fmt.Errorf("invalid escape %q", `\`+string(c))
+	}
+}
+
+// readHex reads the four hex digits of a \u escape
+func (s *stringReader) readHex() (rune, error) {
+	var digits [4]byte
+	if _, err := io.ReadFull(s.r, digits[:]); err != nil {
+		return 0, noEOF(err)
+	}
+	v, err := strconv.ParseUint(string(digits[:]), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid escape %q", `\u`+string(digits[:]))
+	}
+	return rune(v), nil
+}
+
+// noEOF reports running out of input inside the string as an error rather
+// than a clean end
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package granola
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamSuite struct {
+	suite.Suite
+}
+
+func TestStreamSuite(t *testing.T) {
+	suite.Run(t, new(StreamSuite))
+}
+
+// v3Cache double-encodes an inner cache object the way v3 files do
+func v3Cache(inner string) string {
+	encoded, _ := json.Marshal(inner)
+	return `{"version": 3, "cache": ` + string(encoded) + `}`
+}
+
+func (s *StreamSuite) TestParsesV3Escapes() {
+	inner := `{"state": {"documents": {"doc-1": {"id": "doc-1", "title": "Café \"sync\" 🚀\\ok",
+		"notes_markdown": "line one\nline two\ttabbed"}}}}`
+
+	docs, err := parseCacheStream(iotest.OneByteReader(strings.NewReader(v3Cache(inner))), nil)
+	s.Require().NoError(err)
+	s.Require().Contains(docs, "doc-1")
+	s.Equal(`Café "sync" 🚀\ok`, docs["doc-1"].Title)
+	s.Equal("line one\nline two\ttabbed", *docs["doc-1"].NotesMarkdown)
+}
+
+func (s *StreamSuite) TestSkipsOtherFields() {
+	data := `{"version": 4, "meta": {"nested": [1, {"a": [true, null]}]}, "cache": {"state": {
+		"people": [{"name": "Alice"}],
+		"documents": {"doc-1": {"id": "doc-1", "title": "Planning"}, "doc-2": null},
+		"transcripts": {"doc-1": [{"text": "Hello", "is_final": true, "start_timestamp": "2024-01-15T10:00:00Z"}]},
+		"documentPanels": null}, "extra": "ignored"}}`
+
+	docs, err := parseCacheStream(strings.NewReader(data), nil)
+	s.Require().NoError(err)
+	s.Len(docs, 1)
+	s.Equal("Planning", docs["doc-1"].Title)
+	s.Len(docs["doc-1"].Transcript, 1)
+}
+
+func (s *StreamSuite) TestErrors() {
+	tests := []struct {
+		name        string
+		data        string
+		errContains string
+	}{
+		{"no_cache_field", `{"version": 3}`, "parsing outer JSON: no cache field"},
+		{"cache_not_object", `{"cache": 3}`, "parsing outer JSON: cache is neither"},
+		{"unterminated_string", `{"cache": "{\"state\": {`, "parsing inner JSON string"},
+		{"bad_escape", `{"cache": "{\"state\": \q}"}`, "parsing inner JSON string"},
+		{"documents_not_object", `{"cache": {"state": {"documents": []}}}`, "parsing inner JSON: expected object"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := parseCacheStream(strings.NewReader(tt.data), nil)
+			s.Require().Error(err)
+			s.Contains(err.Error(), tt.errContains)
+		})
+	}
+}
+
+func FuzzStringReader(f *testing.F) {
+	for _, seed := range []string{`plain`, `a\"b\\c\/d`, `\b\f\n\r\t`, `é中`, `🚀`, `\ud83d`, `\ud83dx`, `\ud83dA`, `\ud83d\ud83d\ude80`} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, body string) {
+		// Invalid UTF-8 is left for the inner decoder to replace
+		var want string
+		if !utf8.ValidString(body) || json.Unmarshal([]byte(`"`+body+`"`), &want) != nil {
+			t.Skip()
+		}
+		r := &stringReader{r: newBufioReader(body + `"trailing`)}
+		got, err := io.ReadAll(iotest.HalfReader(r))
+		if err != nil {
+			t.Fatalf("reading %q: %v", body, err)
+		}
+		if string(got) != want {
+			t.Errorf("unescaped %q to %q, want %q", body, got, want)
+		}
+	})
+}
+
+func newBufioReader(s string) *bufio.Reader {
+	return bufio.NewReader(strings.NewReader(s))
+}
@@ -2,23 +2,33 @@ package granola
 
 import (
 	"log/slog"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/philrhinehart/granola-sync/internal/clock"
 )
 
-// Watcher monitors the Granola cache file for changes
+// Watcher monitors the Granola cache file for changes.
+//
+// Granola replaces the cache file atomically (write to a temp file, then rename),
+// which invalidates a watch on the file itself. The watcher therefore watches the
+// parent directory and filters events down to the cache filename.
 type Watcher struct {
 	path           string
+	dir            string
 	debounce       time.Duration
 	onChange       func()
+	clock          clock.Clock
 	watcher        *fsnotify.Watcher
 	stop           chan struct{}
 	stopped        chan struct{}
 	mu             sync.Mutex
 	lastEventTime  time.Time
 	pendingTrigger bool
+	watching       bool
 }
 
 // NewWatcher creates a new file watcher with debouncing
@@ -29,9 +39,11 @@ func NewWatcher(path string, debounceSeconds int, onChange func()) (*Watcher, er
 	}
 
 	w := &Watcher{
-		path:     path,
+		path:     filepath.Clean(path),
+		dir:      filepath.Dir(path),
 		debounce: time.Duration(debounceSeconds) * time.Second,
 		onChange: onChange,
+		clock:    clock.Real,
 		watcher:  fsWatcher,
 		stop:     make(chan struct{}),
 		stopped:  make(chan struct{}),
@@ -40,16 +52,36 @@ func NewWatcher(path string, debounceSeconds int, onChange func()) (*Watcher, er
 	return w, nil
 }
 
+// SetClock replaces the wall clock used to debounce changes. It must be called
+// before Start.
+func (w *Watcher) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
 // Start begins watching the file
 func (w *Watcher) Start() error {
-	if err := w.watcher.Add(w.path); err != nil {
+	if err := w.watcher.Add(w.dir); err != nil {
 		return err
 	}
+	w.setWatching(true)
 
 	go w.run()
 	return nil
 }
 
+// Watching reports whether the watch on the cache directory is established
+func (w *Watcher) Watching() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.watching
+}
+
+func (w *Watcher) setWatching(watching bool) {
+	w.mu.Lock()
+	w.watching = watching
+	w.mu.Unlock()
+}
+
 // Stop stops the watcher
 func (w *Watcher) Stop() {
 	close(w.stop)
@@ -60,7 +92,7 @@ func (w *Watcher) Stop() {
 func (w *Watcher) run() {
 	defer close(w.stopped)
 
-	ticker := time.NewTicker(500 * time.Millisecond)
+	ticker := w.clock.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
 
 	for {
@@ -72,14 +104,7 @@ func (w *Watcher) run() {
 			if !ok {
 				return
 			}
-			// Trigger on WRITE events (file content changed)
-			if event.Has(fsnotify.Write) {
-				w.mu.Lock()
-				w.lastEventTime = time.Now()
-				w.pendingTrigger = true
-				w.mu.Unlock()
-				slog.Debug("cache file changed", "event", event.Op.String())
-			}
+			w.handleEvent(event)
 
 		case err, ok := <-w.watcher.Errors:
 			if !ok {
@@ -87,16 +112,67 @@ func (w *Watcher) run() {
 			}
 			slog.Error("watcher error", "error", err)
 
-		case <-ticker.C:
-			w.mu.Lock()
-			if w.pendingTrigger && time.Since(w.lastEventTime) >= w.debounce {
-				w.pendingTrigger = false
-				w.mu.Unlock()
-				slog.Info("triggering sync after debounce")
-				w.onChange()
-			} else {
-				w.mu.Unlock()
-			}
+		case <-ticker.C():
+			w.tick()
 		}
 	}
 }
+
+// tick re-establishes a lost directory watch and triggers a sync once changes
+// have settled for the debounce period
+func (w *Watcher) tick() {
+	if !w.Watching() {
+		w.rewatch()
+	}
+
+	w.mu.Lock()
+	if w.pendingTrigger && w.clock.Now().Sub(w.lastEventTime) >= w.debounce {
+		w.pendingTrigger = false
+		w.mu.Unlock()
+		slog.Info("triggering sync after debounce")
+		w.onChange()
+	} else {
+		w.mu.Unlock()
+	}
+}
+
+// handleEvent filters directory events down to changes of the cache file
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	name := filepath.Clean(event.Name)
+
+	// The watched directory itself went away; the watch is dead until it's re-added
+	if name == w.dir && (event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)) {
+		slog.Warn("cache directory removed, waiting for it to reappear", "dir", w.dir)
+		w.setWatching(false)
+		return
+	}
+
+	if name != w.path {
+		return
+	}
+
+	// WRITE covers in-place updates, CREATE covers atomic replacement via rename.
+	// REMOVE/RENAME of the old file is followed by a CREATE for the new one.
+	if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
+		w.mu.Lock()
+		w.lastEventTime = w.clock.Now()
+		w.pendingTrigger = true
+		w.mu.Unlock()
+		slog.Debug("cache file changed", "event", event.Op.String())
+	}
+}
+
+// rewatch re-adds the directory watch, triggering a sync once it succeeds since
+// changes may have been missed while the directory was gone
+func (w *Watcher) rewatch() {
+	if err := w.watcher.Add(w.dir); err != nil {
+		return
+	}
+	w.setWatching(true)
+	slog.Info("re-established cache directory watch", "dir", w.dir)
+
+	w.mu.Lock()
+	w.lastEventTime = w.clock.Now()
+	w.pendingTrigger = true
+	w.mu.Unlock()
+}
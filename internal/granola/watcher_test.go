@@ -0,0 +1,102 @@
+package granola
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/clock"
+)
+
+type WatcherSuite struct {
+	suite.Suite
+	dir       string
+	cachePath string
+	triggered chan struct{}
+	watcher   *Watcher
+}
+
+func TestWatcherSuite(t *testing.T) {
+	suite.Run(t, new(WatcherSuite))
+}
+
+func (s *WatcherSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.cachePath = filepath.Join(s.dir, "cache-v3.json")
+	s.Require().NoError(os.WriteFile(s.cachePath, []byte("{}"), 0o644))
+
+	s.triggered = make(chan struct{}, 10)
+	var err error
+	s.watcher, err = NewWatcher(s.cachePath, 0, func() { s.triggered <- struct{}{} })
+	s.Require().NoError(err)
+	s.Require().NoError(s.watcher.Start())
+}
+
+func (s *WatcherSuite) TearDownTest() {
+	s.watcher.Stop()
+}
+
+func (s *WatcherSuite) waitForTrigger() bool {
+	select {
+	case <-s.triggered:
+		return true
+	case <-time.After(3 * time.Second):
+		return false
+	}
+}
+
+func (s *WatcherSuite) TestTriggersOnWrite() {
+	s.Require().NoError(os.WriteFile(s.cachePath, []byte(`{"a":1}`), 0o644))
+	s.True(s.waitForTrigger(), "expected sync trigger after write")
+}
+
+func (s *WatcherSuite) TestSurvivesAtomicReplacement() {
+	// Replace the file via rename twice; both must be observed
+	for i := 0; i < 2; i++ {
+		tmp := filepath.Join(s.dir, "cache-v3.json.tmp")
+		s.Require().NoError(os.WriteFile(tmp, []byte(`{"b":2}`), 0o644))
+		s.Require().NoError(os.Rename(tmp, s.cachePath))
+		s.True(s.waitForTrigger(), "expected sync trigger after replacement %d", i+1)
+	}
+}
+
+func (s *WatcherSuite) TestIgnoresOtherFiles() {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "other.json"), []byte("{}"), 0o644))
+	select {
+	case <-s.triggered:
+		s.Fail("unexpected trigger for unrelated file")
+	case <-time.After(time.Second):
+	}
+}
+
+func (s *WatcherSuite) TestDebouncesWithClock() {
+	fake := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	triggered := 0
+	w, err := NewWatcher(s.cachePath, 5, func() { triggered++ })
+	s.Require().NoError(err)
+	defer func() { _ = w.watcher.Close() }()
+	w.SetClock(fake)
+	w.setWatching(true)
+
+	w.handleEvent(fsnotify.Event{Name: s.cachePath, Op: fsnotify.Write})
+	fake.Advance(4 * time.Second)
+	w.tick()
+	s.Zero(triggered, "changes haven't settled yet")
+
+	// Another write restarts the debounce period
+	w.handleEvent(fsnotify.Event{Name: s.cachePath, Op: fsnotify.Write})
+	fake.Advance(4 * time.Second)
+	w.tick()
+	s.Zero(triggered)
+
+	fake.Advance(time.Second)
+	w.tick()
+	s.Equal(1, triggered)
+
+	w.tick()
+	s.Equal(1, triggered, "one trigger per burst of changes")
+}
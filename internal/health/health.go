@@ -0,0 +1,108 @@
+// Package health serves watch mode's health over HTTP for uptime monitors.
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status tracks the health of watch mode: the outcome of the last sync and
+// whether the cache watcher is running.
+type Status struct {
+	mu          sync.Mutex
+	lastSync    time.Time
+	lastSyncOK  bool
+	lastError   string
+	lastErrorAt time.Time
+	watching    func() bool // nil until the watcher starts
+}
+
+// Report is the /healthz response body.
+type Report struct {
+	Status      string    `json:"status"` // "ok" or "error"
+	Watching    bool      `json:"watching"`
+	LastSync    time.Time `json:"last_sync,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+}
+
+// NewStatus creates a status with no syncs recorded.
+func NewStatus() *Status {
+	return &Status{}
+}
+
+// SetWatcher sets how to tell whether the cache watcher is running.
+func (s *Status) SetWatcher(watching func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watching = watching
+}
+
+// RecordSync records the outcome of a sync. err is nil for a sync that
+// succeeded without errors.
+func (s *Status) RecordSync(at time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSync = at
+	s.lastSyncOK = err == nil
+	if err != nil {
+		s.lastError = err.Error()
+		s.lastErrorAt = at
+	}
+}
+
+// Report returns the current health. It is "ok" while the watcher is running and
+// the last sync, if any, succeeded.
+func (s *Status) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := Report{
+		Status:      "ok",
+		Watching:    s.watching != nil && s.watching(),
+		LastSync:    s.lastSync,
+		LastError:   s.lastError,
+		LastErrorAt: s.lastErrorAt,
+	}
+	if !report.Watching || (!s.lastSync.IsZero() && !s.lastSyncOK) {
+		report.Status = "error"
+	}
+	return report
+}
+
+// Handler serves the report as JSON at /healthz, with status 503 when unhealthy.
+func (s *Status) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		report := s.Report()
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+	return mux
+}
+
+// Serve serves the health endpoint on localhost at port until the returned
+// server is shut down. It fails straight away if the port is taken.
+func Serve(port int, status *Status) (*http.Server, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("listening for health checks: %w", err)
+	}
+
+	srv := &http.Server{Handler: status.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("health endpoint stopped", "error", err)
+		}
+	}()
+	return srv, nil
+}
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StatusSuite struct {
+	suite.Suite
+}
+
+func TestStatusSuite(t *testing.T) {
+	suite.Run(t, new(StatusSuite))
+}
+
+// get requests /healthz and decodes the report
+func (s *StatusSuite) get(status *Status) (int, Report) {
+	rec := httptest.NewRecorder()
+	status.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var report Report
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func (s *StatusSuite) TestHealthy() {
+	status := NewStatus()
+	status.SetWatcher(func() bool { return true })
+	at := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+	status.RecordSync(at, nil)
+
+	code, report := s.get(status)
+	s.Equal(http.StatusOK, code)
+	s.Equal("ok", report.Status)
+	s.True(report.Watching)
+	s.True(at.Equal(report.LastSync))
+	s.Empty(report.LastError)
+}
+
+func (s *StatusSuite) TestWatcherNotRunning() {
+	code, report := s.get(NewStatus())
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.Equal("error", report.Status)
+	s.False(report.Watching)
+}
+
+func (s *StatusSuite) TestLastSyncFailed() {
+	status := NewStatus()
+	status.SetWatcher(func() bool { return true })
+	failedAt := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+	status.RecordSync(failedAt, errors.New("parsing cache: unexpected EOF"))
+
+	code, report := s.get(status)
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.Equal("parsing cache: unexpected EOF", report.LastError)
+
+	// The error is still reported once syncs recover
+	status.RecordSync(failedAt.Add(time.Minute), nil)
+	code, report = s.get(status)
+	s.Equal(http.StatusOK, code)
+	s.Equal("parsing cache: unexpected EOF", report.LastError)
+	s.True(failedAt.Equal(report.LastErrorAt))
+}
@@ -0,0 +1,124 @@
+// Package jira creates issues through the Jira REST API.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrUnauthorized is returned when Jira rejects the email and API token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Client communicates with the Jira REST API of one site.
+type Client struct {
+	client  *http.Client
+	siteURL string
+	email   string
+	token   string
+}
+
+// NewClient creates a new Jira API client for a site such as
+// https://example.atlassian.net, signing in with an account's email and API
+// token.
+func NewClient(siteURL, email, token string) *Client {
+	return &Client{
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		siteURL: strings.TrimSuffix(siteURL, "/"),
+		email:   email,
+		token:   token,
+	}
+}
+
+// Issue is an issue to create.
+type Issue struct {
+	Project     string // Project key, e.g. ENG
+	Type        string // Issue type name, e.g. Task
+	Summary     string
+	Description string
+}
+
+// createRequest is the body of a create issue request.
+type createRequest struct {
+	Fields createFields `json:"fields"`
+}
+
+type createFields struct {
+	Project     keyField  `json:"project"`
+	IssueType   nameField `json:"issuetype"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+}
+
+type keyField struct {
+	Key string `json:"key"`
+}
+
+type nameField struct {
+	Name string `json:"name"`
+}
+
+// issueResponse is the part of the created issue we use.
+type issueResponse struct {
+	Key string `json:"key"`
+}
+
+// CreateIssue creates an issue and returns its key, e.g. ENG-12.
+func (c *Client) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	body, err := json.Marshal(createRequest{Fields: createFields{
+		Project:     keyField{Key: issue.Project},
+		IssueType:   nameField{Name: issue.Type},
+		Summary:     issue.Summary,
+		Description: issue.Description,
+	}})
+	if err != nil {
+		return "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	// Version 2 takes the description as plain text rather than a document
+	req, err := http.NewRequestWithContext(ctx, "POST", c.siteURL+"/rest/api/2/issue", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.email, c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var created issueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if created.Key == "" {
+		return "", errors.New("API response has no issue key")
+	}
+
+	return created.Key, nil
+}
+
+// IssueURL returns the address of an issue on the site.
+func (c *Client) IssueURL(key string) string {
+	return c.siteURL + "/browse/" + key
+}
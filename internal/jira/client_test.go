@@ -0,0 +1,76 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreateIssue() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("/rest/api/2/issue", r.URL.Path)
+		email, token, ok := r.BasicAuth()
+		s.True(ok)
+		s.Equal("me@example.com", email)
+		s.Equal("test-token", token)
+
+		var body createRequest
+		s.NoError(json.NewDecoder(r.Body).Decode(&body))
+		s.Equal("ENG", body.Fields.Project.Key)
+		s.Equal("Task", body.Fields.IssueType.Name)
+		s.Equal("Fix the login page", body.Fields.Summary)
+		s.Equal("From Roadmap", body.Fields.Description)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "10042", "key": "ENG-12", "self": "https://example.atlassian.net/rest/api/2/issue/10042"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "me@example.com", "test-token")
+	key, err := client.CreateIssue(context.Background(), Issue{
+		Project:     "ENG",
+		Type:        "Task",
+		Summary:     "Fix the login page",
+		Description: "From Roadmap",
+	})
+
+	s.NoError(err)
+	s.Equal("ENG-12", key)
+	s.Equal(server.URL+"/browse/ENG-12", client.IssueURL(key))
+}
+
+func (s *ClientSuite) TestCreateIssueUnauthorized() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "me@example.com", "bad-token").CreateIssue(context.Background(), Issue{Summary: "x"})
+	s.True(errors.Is(err, ErrUnauthorized))
+}
+
+func (s *ClientSuite) TestCreateIssueError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": {"project": "valid project is required"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "me@example.com", "test-token").CreateIssue(context.Background(), Issue{Summary: "x"})
+	s.ErrorContains(err, "valid project is required")
+}
@@ -0,0 +1,22 @@
+// Package keychain reads secrets granola-sync keeps in the macOS Keychain, so
+// passwords and API tokens stay out of the config file.
+package keychain
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Service is the Keychain service granola-sync's secrets are stored under. The
+// account names what the secret is for, e.g. user@host or readwise.
+const Service = "granola-sync"
+
+// Password reads the secret stored for account
+func Password(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", Service, "-a", account, "-w").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading the secret for %s from the Keychain (add it with: security add-generic-password -s %s -a %s -w): %w", account, Service, account, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,134 @@
+// Package linear creates issues through the Linear GraphQL API.
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://api.linear.app/graphql"
+
+// ErrUnauthorized is returned when Linear rejects the API key.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Client communicates with the Linear API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// NewClient creates a new Linear API client with a personal API key.
+func NewClient(baseURL, apiKey string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		baseURL: baseURL,
+		apiKey:  apiKey,
+	}
+}
+
+// Issue is an issue to create.
+type Issue struct {
+	TeamID      string `json:"teamId"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreatedIssue is an issue Linear created.
+type CreatedIssue struct {
+	Identifier string `json:"identifier"` // e.g. ENG-12
+	URL        string `json:"url"`
+}
+
+const createIssueMutation = `mutation IssueCreate($input: IssueCreateInput!) {
+  issueCreate(input: $input) {
+    success
+    issue { identifier url }
+  }
+}`
+
+// graphQLRequest is the body of a GraphQL request.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// createResponse is the part of the issueCreate response we use.
+type createResponse struct {
+	Data struct {
+		IssueCreate struct {
+			Success bool         `json:"success"`
+			Issue   CreatedIssue `json:"issue"`
+		} `json:"issueCreate"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// CreateIssue creates an issue and returns its identifier and address.
+func (c *Client) CreateIssue(ctx context.Context, issue Issue) (*CreatedIssue, error) {
+	body, err := json.Marshal(graphQLRequest{
+		Query:     createIssueMutation,
+		Variables: map[string]any{"input": issue},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Personal API keys are sent as they are, without a scheme
+	req.Header.Set("Authorization", c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrUnauthorized
+	}
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var created createResponse
+	// GraphQL errors can come with any status, so decode them first
+	if err := json.Unmarshal(respBody, &created); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+		}
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(created.Errors) > 0 {
+		messages := make([]string, len(created.Errors))
+		for i, e := range created.Errors {
+			messages[i] = e.Message
+		}
+		return nil, fmt.Errorf("API returned errors: %s", strings.Join(messages, "; "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	result := created.Data.IssueCreate
+	if !result.Success || result.Issue.Identifier == "" {
+		return nil, errors.New("API didn't create the issue")
+	}
+	return &result.Issue, nil
+}
@@ -0,0 +1,72 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreateIssue() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("lin_api_test", r.Header.Get("Authorization"))
+		s.Equal("application/json", r.Header.Get("Content-Type"))
+
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input Issue `json:"input"`
+			} `json:"variables"`
+		}
+		s.NoError(json.NewDecoder(r.Body).Decode(&body))
+		s.Contains(body.Query, "issueCreate")
+		s.Equal(Issue{TeamID: "team-1", Title: "Fix the login page", Description: "From Roadmap"}, body.Variables.Input)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"issueCreate": {"success": true, "issue": {"identifier": "ENG-12", "url": "https://linear.app/acme/issue/ENG-12/fix-the-login-page"}}}}`))
+	}))
+	defer server.Close()
+
+	issue, err := NewClient(server.URL, "lin_api_test").CreateIssue(context.Background(), Issue{
+		TeamID:      "team-1",
+		Title:       "Fix the login page",
+		Description: "From Roadmap",
+	})
+
+	s.NoError(err)
+	s.Equal(&CreatedIssue{Identifier: "ENG-12", URL: "https://linear.app/acme/issue/ENG-12/fix-the-login-page"}, issue)
+}
+
+func (s *ClientSuite) TestCreateIssueUnauthorized() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "bad-key").CreateIssue(context.Background(), Issue{Title: "x"})
+	s.True(errors.Is(err, ErrUnauthorized))
+}
+
+func (s *ClientSuite) TestCreateIssueGraphQLError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": [{"message": "Argument Validation Error"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "lin_api_test").CreateIssue(context.Background(), Issue{Title: "x"})
+	s.ErrorContains(err, "Argument Validation Error")
+}
@@ -0,0 +1,100 @@
+package logseq
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+var (
+	// nameAsideRe matches parenthesized or bracketed asides in a display name,
+	// e.g. "(he/him)" or "[Acme]"
+	nameAsideRe = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\])`)
+	// pronounsRe matches pronouns written into a display name without brackets,
+	// with the separator before them, e.g. " - she/her"
+	pronounsRe = regexp.MustCompile(`(?i)[\s,|–-]*\b(he|she|they|ze|xe)\s*/\s*(him|his|her|hers|they|them|theirs|zir|xem)\b`)
+	// nameSuffixRe matches a suffix written after a comma, e.g. "Jr." in
+	// "Bob Smith, Jr.", which isn't a first name to move to the front
+	nameSuffixRe = regexp.MustCompile(`(?i)^(jr|sr|ii|iii|iv|phd|ph\.d|md|m\.d|esq|cpa|mba)\.?$`)
+)
+
+// AttendeeAliases resolves the names Granola reports for attendees to the names
+// of the user's person pages, so [[@Name]] links land on existing pages. A nil
+// AttendeeAliases leaves names as they are.
+type AttendeeAliases struct {
+	aliases   map[string]string // Lowercased name or email -> canonical name
+	normalize bool
+}
+
+// NewAttendeeAliases creates aliases from a map of names or emails (matched
+// case-insensitively) to canonical names. With normalize, names without an
+// alias are cleaned up: asides like "(he/him)" dropped, "Smith, Bob" turned
+// around and all-lowercase names capitalized.
+func NewAttendeeAliases(aliases map[string]string, normalize bool) *AttendeeAliases {
+	a := &AttendeeAliases{aliases: make(map[string]string, len(aliases)), normalize: normalize}
+	for from, to := range aliases {
+		a.aliases[strings.ToLower(strings.TrimSpace(from))] = strings.TrimSpace(to)
+	}
+	return a
+}
+
+// Attendee returns the attendee with their canonical name. An alias for the
+// email wins over one for the name, which wins over normalizing.
+func (a *AttendeeAliases) Attendee(m granola.MeetingAttendee) granola.MeetingAttendee {
+	if a == nil {
+		return m
+	}
+	if name := a.lookup(m.Email); name != "" {
+		m.Name = name
+		return m
+	}
+	if name := a.lookup(m.Name); name != "" {
+		m.Name = name
+		return m
+	}
+	if a.normalize {
+		m.Name = normalizeName(m.Name)
+		if name := a.lookup(m.Name); name != "" {
+			m.Name = name
+		}
+	}
+	return m
+}
+
+// lookup returns the alias of a name or email, or empty string if it has none
+func (a *AttendeeAliases) lookup(key string) string {
+	if key == "" {
+		return ""
+	}
+	return a.aliases[strings.ToLower(strings.TrimSpace(key))]
+}
+
+// normalizeName cleans up a display name: asides and pronouns are dropped,
+// "Last, First" becomes "First Last" (but "Bob Smith, Jr." stays), spaces are
+// collapsed and an all-lowercase name is capitalized. A name that would end up
+// empty is kept as it was.
+func normalizeName(name string) string {
+	cleaned := nameAsideRe.ReplaceAllString(name, "")
+	cleaned = pronounsRe.ReplaceAllString(cleaned, "")
+	if last, first, ok := strings.Cut(cleaned, ","); ok && !strings.Contains(first, ",") {
+		if last, first = strings.TrimSpace(last), strings.TrimSpace(first); last != "" && first != "" && !nameSuffixRe.MatchString(first) {
+			cleaned = first + " " + last
+		}
+	}
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+	if cleaned == "" {
+		return name
+	}
+	if strings.ToLower(cleaned) == cleaned {
+		words := strings.Fields(cleaned)
+		for i, w := range words {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		cleaned = strings.Join(words, " ")
+	}
+	return cleaned
+}
@@ -0,0 +1,90 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type AliasesSuite struct {
+	suite.Suite
+}
+
+func TestAliasesSuite(t *testing.T) {
+	suite.Run(t, new(AliasesSuite))
+}
+
+func (s *AliasesSuite) TestNormalizeName() {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unchanged", "Bob Smith", "Bob Smith"},
+		{"pronouns in parens", "Bob Smith (he/him)", "Bob Smith"},
+		{"bracketed aside", "Alice Jones [Acme]", "Alice Jones"},
+		{"bare pronouns", "Carol Lee - she/her", "Carol Lee"},
+		{"last, first", "Smith, Bob", "Bob Smith"},
+		{"suffix after comma", "Bob Smith, Jr.", "Bob Smith, Jr."},
+		{"degree after comma", "Alice Jones, PhD", "Alice Jones, PhD"},
+		{"extra spaces", "  Bob   Smith ", "Bob Smith"},
+		{"lowercase", "bob smith", "Bob Smith"},
+		{"mixed case kept", "Ada deLacey", "Ada deLacey"},
+		{"only an aside", "(guest)", "(guest)"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, normalizeName(tt.in))
+		})
+	}
+}
+
+func (s *AliasesSuite) TestAttendee() {
+	aliases := NewAttendeeAliases(map[string]string{
+		"bsmith@example.com": "Bob Smith",
+		"Rob Smith":          "Bob Smith",
+		"Al Jones":           "Alice Jones",
+	}, true)
+
+	tests := []struct {
+		name    string
+		aliases *AttendeeAliases
+		in      granola.MeetingAttendee
+		want    string
+	}{
+		{"nil keeps name", nil, granola.MeetingAttendee{Name: "bob (he/him)"}, "bob (he/him)"},
+		{"email alias", aliases, granola.MeetingAttendee{Name: "Bsmith", Email: "BSmith@example.com"}, "Bob Smith"},
+		{"name alias", aliases, granola.MeetingAttendee{Name: "rob smith"}, "Bob Smith"},
+		{"alias after normalizing", aliases, granola.MeetingAttendee{Name: "Al Jones (she/her)"}, "Alice Jones"},
+		{"normalized", aliases, granola.MeetingAttendee{Name: "carol lee"}, "Carol Lee"},
+		{"not normalized", NewAttendeeAliases(nil, false), granola.MeetingAttendee{Name: "carol lee"}, "carol lee"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.aliases.Attendee(tt.in).Name)
+		})
+	}
+}
+
+func (s *AliasesSuite) TestFormatMeetingPage() {
+	doc := &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		People: &granola.People{Attendees: []granola.AttendeeInfo{
+			{Name: "Bob Smith (he/him)", Email: "bob@example.com"},
+			{Name: "Bob Smith", Email: "bob.smith@example.com"},
+			{Name: "Smith, Alice", Email: "alice@example.com"},
+		}},
+	}
+	opts := FormatOptions{Aliases: NewAttendeeAliases(nil, true)}
+
+	content := FormatMeetingPage(doc, opts)
+
+	// Names that resolve to the same person are listed once
+	s.Contains(content, "\t\t- [[@Bob Smith]]\n\t\t- [[@Alice Smith]]\n")
+	s.NotContains(content, "he/him")
+}
@@ -0,0 +1,73 @@
+package logseq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// Anonymizer replaces attendee names with stable pseudonyms so a graph can be
+// published or screen-shared. The same person always gets the same pseudonym
+// for a given key; without the key the pseudonyms can't be reversed.
+type Anonymizer struct {
+	key      []byte
+	keepName string
+}
+
+// NewAnonymizer creates an anonymizer keyed by key. Attendees named keepName
+// (the user) are left as-is so their own TODOs and person page still work.
+func NewAnonymizer(key, keepName string) *Anonymizer {
+	return &Anonymizer{key: []byte(key), keepName: keepName}
+}
+
+// Attendee returns the pseudonymized attendee. Emails are dropped.
+func (a *Anonymizer) Attendee(m granola.MeetingAttendee) granola.MeetingAttendee {
+	if a == nil || (a.keepName != "" && m.Name == a.keepName) {
+		return m
+	}
+	// Prefer the email as identity since names vary between calendar and Granola
+	identity := strings.ToLower(m.Email)
+	if identity == "" {
+		identity = strings.ToLower(m.Name)
+	}
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(identity))
+	return granola.MeetingAttendee{Name: "Person " + hex.EncodeToString(mac.Sum(nil))[:6]}
+}
+
+// attendees returns a document's attendees as they should be rendered: without
+// excluded attendees, under their canonical names, then pseudonymized.
+// Attendees whose names resolve to the same person are listed once.
+func attendees(doc *granola.Document, opts FormatOptions) []granola.MeetingAttendee {
+	list := doc.GetAttendees()
+	if opts.ExcludeAttendees == nil && opts.Aliases == nil && opts.Anonymizer == nil {
+		return list
+	}
+	seen := make(map[string]bool, len(list))
+	resolved := list[:0]
+	for _, m := range list {
+		if opts.ExcludeAttendees.Excluded(m) {
+			continue
+		}
+		m = opts.Anonymizer.Attendee(opts.Aliases.Attendee(m))
+		if seen[m.Name] {
+			continue
+		}
+		seen[m.Name] = true
+		resolved = append(resolved, m)
+	}
+	return resolved
+}
+
+// attendeeNames returns the rendered names of a document's attendees
+func attendeeNames(doc *granola.Document, opts FormatOptions) []string {
+	list := attendees(doc, opts)
+	names := make([]string, len(list))
+	for i, m := range list {
+		names[i] = m.Name
+	}
+	return names
+}
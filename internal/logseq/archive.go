@@ -0,0 +1,93 @@
+package logseq
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// Archive mirrors meeting pages into a plain markdown directory outside the
+// graph, for backup and search tools. Files are named by date, title and Granola
+// ID, so names never collide.
+type Archive struct {
+	dir      string
+	hardlink bool // Hard-link the graph's page instead of writing a copy
+}
+
+// NewArchive creates an archive in dir. With hardlink, archive files are hard
+// links to the graph's pages where possible.
+func NewArchive(dir string, hardlink bool) *Archive {
+	return &Archive{dir: dir, hardlink: hardlink}
+}
+
+// Path returns the archive file for a meeting:
+// <dir>/<year>/<date> <title> (<granola-id>).md
+func (a *Archive) Path(doc *granola.Document) string {
+	return filepath.Join(a.dir, doc.GetMeetingDate().Format("2006"), ArchiveFilename(doc))
+}
+
+// ArchiveFilename names a meeting's copy outside the graph:
+// <date> <title> (<granola-id>).md
+func ArchiveFilename(doc *granola.Document) string {
+	return fmt.Sprintf("%s %s (%s).md", doc.GetMeetingDate().Format("2006-01-02"), sanitizeTitle(doc.Title), doc.ID)
+}
+
+// Save writes a meeting's archive file and removes any the meeting left under an
+// earlier title or date. In hard link mode pagePath is linked, falling back to
+// writing content when it is empty or can't be linked (e.g. the archive is on
+// another filesystem). A nil archive does nothing.
+func (a *Archive) Save(doc *granola.Document, pagePath, content string) error {
+	if a == nil {
+		return nil
+	}
+
+	path := a.Path(doc)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating archive directory: %w", err)
+	}
+	if err := a.removeStale(doc.ID, path); err != nil {
+		return err
+	}
+
+	if a.hardlink && pagePath != "" {
+		// Pages are replaced rather than rewritten, so the old link has to go
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing old archive link: %w", err)
+		}
+		err := os.Link(pagePath, path)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, syscall.EXDEV) && !errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("linking archive file: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("writing archive file: %w", err)
+	}
+	return nil
+}
+
+// removeStale removes archive files for a meeting other than keep
+func (a *Archive) removeStale(granolaID, keep string) error {
+	matches, err := fs.Glob(os.DirFS(a.dir), "*/* ("+granolaID+").md")
+	if err != nil {
+		return fmt.Errorf("finding old archive files: %w", err)
+	}
+	for _, m := range matches {
+		path := filepath.Join(a.dir, filepath.FromSlash(m))
+		if path == keep {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing old archive file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type ArchiveSuite struct {
+	suite.Suite
+	graphDir   string
+	archiveDir string
+}
+
+func TestArchiveSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveSuite))
+}
+
+func (s *ArchiveSuite) SetupTest() {
+	tmp := s.T().TempDir()
+	s.graphDir = filepath.Join(tmp, "graph")
+	s.archiveDir = filepath.Join(tmp, "archive")
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.graphDir, "pages"), 0o755))
+}
+
+func (s *ArchiveSuite) meeting(title string) *granola.Document {
+	return &granola.Document{
+		ID:    "doc-1",
+		Title: title,
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Start: &granola.EventTime{DateTime: "2025-01-28T10:00:00Z"},
+		},
+	}
+}
+
+func (s *ArchiveSuite) TestCopy() {
+	w := NewWriter(s.graphDir, "", FormatOptions{})
+	w.SetArchive(NewArchive(s.archiveDir, false))
+
+	_, err := w.WriteMeetingPage(s.meeting("Planning"))
+	s.Require().NoError(err)
+
+	content, err := os.ReadFile(filepath.Join(s.archiveDir, "2025", "2025-01-28 Planning (doc-1).md"))
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(string(content), "---\n"), "properties are YAML frontmatter")
+	s.Contains(string(content), "granola-id: \"doc-1\"\n")
+
+	// A renamed meeting leaves no stale copy behind
+	_, err = w.WriteMeetingPage(s.meeting("Roadmap Planning"))
+	s.Require().NoError(err)
+	s.NoFileExists(filepath.Join(s.archiveDir, "2025", "2025-01-28 Planning (doc-1).md"))
+	s.FileExists(filepath.Join(s.archiveDir, "2025", "2025-01-28 Roadmap Planning (doc-1).md"))
+}
+
+func (s *ArchiveSuite) TestHardlink() {
+	w := NewWriter(s.graphDir, "", FormatOptions{})
+	w.SetArchive(NewArchive(s.archiveDir, true))
+
+	for range 2 {
+		pagePath, err := w.WriteMeetingPage(s.meeting("Planning"))
+		s.Require().NoError(err)
+
+		page, err := os.Stat(pagePath)
+		s.Require().NoError(err)
+		archived, err := os.Stat(filepath.Join(s.archiveDir, "2025", "2025-01-28 Planning (doc-1).md"))
+		s.Require().NoError(err)
+		s.True(os.SameFile(page, archived))
+	}
+}
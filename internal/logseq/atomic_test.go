@@ -0,0 +1,66 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AtomicSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestAtomicSuite(t *testing.T) {
+	suite.Run(t, new(AtomicSuite))
+}
+
+func (s *AtomicSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+// entries returns the names of the files in the test directory
+func (s *AtomicSuite) entries() []string {
+	list, err := os.ReadDir(s.dir)
+	s.Require().NoError(err)
+	var names []string
+	for _, e := range list {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func (s *AtomicSuite) TestWriteFileAtomic() {
+	path := filepath.Join(s.dir, "2025_01_28.md")
+
+	s.Require().NoError(writeFileAtomic(path, []byte("- first\n"), 0o644))
+	s.Require().NoError(writeFileAtomic(path, []byte("- first\n- second\n"), 0o644))
+
+	content, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	s.Equal("- first\n- second\n", string(content))
+	s.Equal([]string{"2025_01_28.md"}, s.entries(), "no temp files are left behind")
+}
+
+func (s *AtomicSuite) TestWriteFileAtomicKeepsMode() {
+	path := filepath.Join(s.dir, "page.md")
+	s.Require().NoError(os.WriteFile(path, []byte("old"), 0o600))
+
+	s.Require().NoError(writeFileAtomic(path, []byte("new"), 0o644))
+
+	info, err := os.Stat(path)
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
+
+func (s *AtomicSuite) TestWriteFileAtomicCleansUpOnFailure() {
+	// Replacing a non-empty directory fails at the rename, after the temp file was written
+	target := filepath.Join(s.dir, "page.md")
+	s.Require().NoError(os.Mkdir(target, 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(target, "child"), nil, 0o644))
+
+	s.Error(writeFileAtomic(target, []byte("new"), 0o644))
+	s.Equal([]string{"page.md"}, s.entries(), "the temp file is removed")
+}
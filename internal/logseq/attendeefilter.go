@@ -0,0 +1,37 @@
+package logseq
+
+import (
+	"regexp"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// AttendeeFilter leaves meeting rooms, note-taker bots and group aliases off
+// meeting pages, journals and person pages. A nil AttendeeFilter keeps every
+// attendee.
+type AttendeeFilter struct {
+	patterns  []*regexp.Regexp // Matched against attendee names and emails
+	resources bool             // Exclude calendar resources such as rooms
+}
+
+// NewAttendeeFilter creates a filter excluding attendees whose name or email
+// matches one of patterns and, with resources, calendar resources
+func NewAttendeeFilter(patterns []*regexp.Regexp, resources bool) *AttendeeFilter {
+	return &AttendeeFilter{patterns: patterns, resources: resources}
+}
+
+// Excluded reports whether an attendee is left off pages
+func (f *AttendeeFilter) Excluded(m granola.MeetingAttendee) bool {
+	if f == nil {
+		return false
+	}
+	if f.resources && m.Resource {
+		return true
+	}
+	for _, p := range f.patterns {
+		if p.MatchString(m.Name) || (m.Email != "" && p.MatchString(m.Email)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package logseq
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type AttendeeFilterSuite struct {
+	suite.Suite
+}
+
+func TestAttendeeFilterSuite(t *testing.T) {
+	suite.Run(t, new(AttendeeFilterSuite))
+}
+
+func (s *AttendeeFilterSuite) TestExcluded() {
+	filter := NewAttendeeFilter([]*regexp.Regexp{
+		regexp.MustCompile("(?i)notetaker"),
+		regexp.MustCompile("(?i)^all-staff@"),
+	}, true)
+
+	tests := []struct {
+		name     string
+		filter   *AttendeeFilter
+		attendee granola.MeetingAttendee
+		want     bool
+	}{
+		{"nil keeps everyone", nil, granola.MeetingAttendee{Name: "Notetaker", Resource: true}, false},
+		{"person", filter, granola.MeetingAttendee{Name: "Alice", Email: "alice@example.com"}, false},
+		{"name matches", filter, granola.MeetingAttendee{Name: "Fireflies NoteTaker"}, true},
+		{"email matches", filter, granola.MeetingAttendee{Name: "All Staff", Email: "all-staff@example.com"}, true},
+		{"resource", filter, granola.MeetingAttendee{Name: "Board Room", Resource: true}, true},
+		{"resources kept", NewAttendeeFilter(nil, false), granola.MeetingAttendee{Name: "Board Room", Resource: true}, false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.filter.Excluded(tt.attendee))
+		})
+	}
+}
+
+func (s *AttendeeFilterSuite) TestFormatMeetingPage() {
+	doc := &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Attendees: []granola.Attendee{
+				{Email: "alice@example.com", DisplayName: "Alice"},
+				{Email: "c_1884@resource.calendar.google.com", DisplayName: "Board Room", Resource: true},
+				{Email: "bot@otter.ai", DisplayName: "Otter.ai"},
+			},
+		},
+	}
+	opts := FormatOptions{ExcludeAttendees: NewAttendeeFilter([]*regexp.Regexp{regexp.MustCompile("(?i)otter\\.ai")}, true)}
+
+	content := FormatMeetingPage(doc, opts)
+
+	s.Contains(content, "[[@Alice]]")
+	s.NotContains(content, "Board Room")
+	s.NotContains(content, "Otter")
+}
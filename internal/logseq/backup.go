@@ -0,0 +1,81 @@
+package logseq
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout names backup files so they sort chronologically
+const backupTimeLayout = "20060102T150405.000000000"
+
+// Backups keeps previous versions of pages before they are overwritten, so a
+// bad template or formatting change can't destroy content permanently
+type Backups struct {
+	dir  string
+	keep int
+	now  func() time.Time
+}
+
+// NewBackups stores backups under dir, keeping the newest keep versions of each page
+func NewBackups(dir string, keep int) *Backups {
+	return &Backups{dir: dir, keep: keep, now: time.Now}
+}
+
+// Save copies the current content of path, read from pages, into the backup
+// directory, unless the file doesn't exist yet or already holds newContent.
+// Older backups of the page beyond the retention count are removed.
+func (b *Backups) Save(pages FS, path, newContent string) error {
+	if b == nil || b.keep <= 0 {
+		return nil
+	}
+
+	old, err := pages.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading page to back up: %w", err)
+	}
+	if string(old) == newContent {
+		return nil
+	}
+
+	pageDir := filepath.Join(b.dir, strings.TrimSuffix(filepath.Base(path), ".md"))
+	if err := os.MkdirAll(pageDir, 0o755); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+	backupPath := filepath.Join(pageDir, b.now().UTC().Format(backupTimeLayout)+".md")
+	if err := os.WriteFile(backupPath, old, 0o644); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+
+	return b.prune(pageDir)
+}
+
+// prune removes all but the newest backups in a page's backup directory
+func (b *Backups) prune(pageDir string) error {
+	entries, err := os.ReadDir(pageDir)
+	if err != nil {
+		return fmt.Errorf("listing backups: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for len(names) > b.keep {
+		if err := os.Remove(filepath.Join(pageDir, names[0])); err != nil {
+			return fmt.Errorf("removing old backup: %w", err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
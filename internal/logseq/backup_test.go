@@ -0,0 +1,78 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BackupSuite struct {
+	suite.Suite
+	dir     string
+	backups *Backups
+	clock   time.Time
+}
+
+func TestBackupSuite(t *testing.T) {
+	suite.Run(t, new(BackupSuite))
+}
+
+func (s *BackupSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.backups = NewBackups(filepath.Join(s.dir, "backups"), 2)
+	s.clock = time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+	s.backups.now = func() time.Time {
+		s.clock = s.clock.Add(time.Minute)
+		return s.clock
+	}
+}
+
+// backupContents returns the contents of a page's backups, oldest first
+func (s *BackupSuite) backupContents(page string) []string {
+	pageDir := filepath.Join(s.dir, "backups", page)
+	entries, err := os.ReadDir(pageDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	s.Require().NoError(err)
+	var contents []string
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(pageDir, e.Name()))
+		s.Require().NoError(err)
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+func (s *BackupSuite) TestSave() {
+	path := filepath.Join(s.dir, "meetings___2025-01-28___Standup.md")
+
+	// Nothing to back up for a new page
+	s.Require().NoError(s.backups.Save(OSFS, path, "v1"))
+	s.Nil(s.backupContents("meetings___2025-01-28___Standup"))
+
+	for _, version := range []string{"v1", "v2", "v3"} {
+		s.Require().NoError(os.WriteFile(path, []byte(version), 0o644))
+		s.Require().NoError(s.backups.Save(OSFS, path, version+" edited"))
+	}
+
+	// Only the newest two are kept
+	s.Equal([]string{"v2", "v3"}, s.backupContents("meetings___2025-01-28___Standup"))
+
+	// Unchanged content isn't backed up again
+	s.Require().NoError(s.backups.Save(OSFS, path, "v3"))
+	s.Equal([]string{"v2", "v3"}, s.backupContents("meetings___2025-01-28___Standup"))
+}
+
+func (s *BackupSuite) TestDisabled() {
+	path := filepath.Join(s.dir, "page.md")
+	s.Require().NoError(os.WriteFile(path, []byte("old"), 0o644))
+
+	var none *Backups
+	s.NoError(none.Save(OSFS, path, "new"))
+	s.NoError(NewBackups(filepath.Join(s.dir, "backups"), 0).Save(OSFS, path, "new"))
+	s.Nil(s.backupContents("page"))
+}
@@ -0,0 +1,85 @@
+package logseq
+
+import (
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// ClassificationRule assigns a classification to meetings with an attendee from
+// one of Domains, or with one of Keywords in the title or notes
+type ClassificationRule struct {
+	Classification string
+	Domains        []string
+	Keywords       []string
+}
+
+// Classifier stamps meetings with a data-handling classification (e.g.
+// internal, confidential) from an ordered list of rules
+type Classifier struct {
+	rules    []ClassificationRule
+	fallback string
+}
+
+// NewClassifier creates a classifier. Rules are tried in order and the first
+// match wins; fallback (which may be empty) applies when none match.
+func NewClassifier(rules []ClassificationRule, fallback string) *Classifier {
+	return &Classifier{rules: rules, fallback: fallback}
+}
+
+// Classify returns the meeting's classification, or empty if it has none
+func (c *Classifier) Classify(doc *granola.Document) string {
+	if c == nil {
+		return ""
+	}
+
+	var domains []string
+	for _, a := range doc.GetAttendees() {
+		if _, domain, ok := strings.Cut(a.Email, "@"); ok {
+			domains = append(domains, strings.ToLower(domain))
+		}
+	}
+	text := strings.ToLower(doc.Title + "\n" + notesText(doc))
+
+	for _, rule := range c.rules {
+		if matchesDomain(domains, rule.Domains) || matchesKeyword(text, rule.Keywords) {
+			return rule.Classification
+		}
+	}
+	return c.fallback
+}
+
+// matchesDomain reports whether any attendee domain is one of ruleDomains or a
+// subdomain of one
+func matchesDomain(domains, ruleDomains []string) bool {
+	for _, d := range domains {
+		for _, rd := range ruleDomains {
+			rd = strings.ToLower(strings.TrimPrefix(rd, "@"))
+			if d == rd || strings.HasSuffix(d, "."+rd) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesKeyword reports whether lowercased text contains any keyword
+func matchesKeyword(text string, keywords []string) bool {
+	for _, k := range keywords {
+		if k != "" && strings.Contains(text, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// notesText returns the document's notes in whichever form it has them
+func notesText(doc *granola.Document) string {
+	if doc.NotesMarkdown != nil && *doc.NotesMarkdown != "" {
+		return *doc.NotesMarkdown
+	}
+	if doc.NotesPlain != nil {
+		return *doc.NotesPlain
+	}
+	return ""
+}
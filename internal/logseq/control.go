@@ -0,0 +1,107 @@
+package logseq
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ControlPageName is the page granola-sync reads commands from
+const ControlPageName = "granola-sync control"
+
+// controlCommandRe matches a pending command: a top-level task block
+var controlCommandRe = regexp.MustCompile(`^- (TODO|LATER|NOW|DOING) (.+?)\s*$`)
+
+// controlPageIntro is written to a new control page
+const controlPageIntro = `- Commands for granola-sync. Add a task block and it runs on the next change to this page:
+	- ` + "`TODO resync <granola-id>`" + ` rewrites one meeting (several IDs may be given)
+	- ` + "`TODO resync all`" + ` rewrites every synced meeting, e.g. after changing tag settings
+	- ` + "`TODO pause`" + ` / ` + "`TODO resume`" + ` stop and restart syncing
+`
+
+// ControlResult is the outcome of running a control command
+type ControlResult struct {
+	OK   bool
+	Note string // Added under the command's block
+}
+
+// ControlPagePath returns the control page's path in a graph
+func ControlPagePath(basePath string) string {
+	return filepath.Join(basePath, "pages", ControlPageName+".md")
+}
+
+// EnsureControlPage creates the control page with usage notes if it doesn't exist
+func EnsureControlPage(basePath string) error {
+	path := ControlPagePath(basePath)
+	if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+	if err := writeFileAtomic(path, []byte(controlPageIntro), 0o644); err != nil {
+		return fmt.Errorf("writing control page: %w", err)
+	}
+	return nil
+}
+
+// PendingControlCommands returns the commands of the unfinished task blocks on a
+// control page, without their task keyword
+func PendingControlCommands(content string) []string {
+	var cmds []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := controlCommandRe.FindStringSubmatch(line); m != nil {
+			cmds = append(cmds, m[2])
+		}
+	}
+	return cmds
+}
+
+// CompleteControlCommands marks the pending commands that have a result DONE
+// (or CANCELED if they failed) and adds each result's note under its command.
+// Results are keyed by command text, so the page may have been edited since the
+// commands were read.
+func CompleteControlCommands(content string, results map[string]ControlResult) string {
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines)+len(results))
+	for _, line := range lines {
+		m := controlCommandRe.FindStringSubmatch(line)
+		if m == nil {
+			out = append(out, line)
+			continue
+		}
+		result, ok := results[m[2]]
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		keyword := "DONE"
+		if !result.OK {
+			keyword = "CANCELED"
+		}
+		out = append(out, "- "+keyword+" "+m[2])
+		if result.Note != "" {
+			out = append(out, "\t- "+result.Note)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// ReadControlPage returns the control page's content, or "" if it doesn't exist
+func ReadControlPage(basePath string) (string, error) {
+	content, err := os.ReadFile(ControlPagePath(basePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading control page: %w", err)
+	}
+	return string(content), nil
+}
+
+// WriteControlPage replaces the control page's content
+func WriteControlPage(basePath, content string) error {
+	if err := writeFileAtomic(ControlPagePath(basePath), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("writing control page: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package logseq
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ControlSuite struct {
+	suite.Suite
+}
+
+func TestControlSuite(t *testing.T) {
+	suite.Run(t, new(ControlSuite))
+}
+
+func (s *ControlSuite) TestPendingControlCommands() {
+	content := controlPageIntro +
+		"- TODO resync doc-1 doc-2\n" +
+		"- DONE pause\n\t- paused\n" +
+		"- LATER resume  \n" +
+		"\t- TODO nested tasks are notes\n"
+	s.Equal([]string{"resync doc-1 doc-2", "resume"}, PendingControlCommands(content))
+	s.Empty(PendingControlCommands(controlPageIntro))
+}
+
+func (s *ControlSuite) TestCompleteControlCommands() {
+	content := "- TODO pause\n- TODO frobnicate\n- TODO resume\n"
+	got := CompleteControlCommands(content, map[string]ControlResult{
+		"pause":      {OK: true, Note: "paused syncing"},
+		"frobnicate": {Note: "unknown command"},
+	})
+	s.Equal("- DONE pause\n\t- paused syncing\n- CANCELED frobnicate\n\t- unknown command\n- TODO resume\n", got)
+}
+
+func (s *ControlSuite) TestEnsureControlPage() {
+	base := s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(base+"/pages", 0o755))
+
+	s.Require().NoError(EnsureControlPage(base))
+	content, err := ReadControlPage(base)
+	s.Require().NoError(err)
+	s.Equal(controlPageIntro, content)
+
+	// An existing page is left alone
+	s.Require().NoError(WriteControlPage(base, "- TODO pause\n"))
+	s.Require().NoError(EnsureControlPage(base))
+	content, err = ReadControlPage(base)
+	s.Require().NoError(err)
+	s.Equal("- TODO pause\n", content)
+}
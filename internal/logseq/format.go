@@ -2,8 +2,13 @@ package logseq
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/philrhinehart/granola-sync/internal/granola"
 )
@@ -32,42 +37,131 @@ func formatTimeRange(startTime, endTime, tz string) string {
 	return timeStr
 }
 
+// Page property styles
+const (
+	// PropertiesBullet indents properties under the first (title) bullet
+	PropertiesBullet = "bullet"
+	// PropertiesBlock writes key:: value lines at the top of the file
+	PropertiesBlock = "block"
+	// PropertiesFrontmatter writes a YAML frontmatter block
+	PropertiesFrontmatter = "frontmatter"
+)
+
+// FormatOptions controls optional parts of the rendered meeting page
+type FormatOptions struct {
+	// IncludeAttendeeEmails adds a mailto link after each attendee
+	IncludeAttendeeEmails bool
+	// IncludeTranscript appends the meeting transcript in a collapsed section
+	IncludeTranscript bool
+	// IncludeMyNotes adds the user's own typed notes in a My Notes section
+	IncludeMyNotes bool
+	// PropertiesStyle is where page properties go; empty means PropertiesBullet
+	PropertiesStyle string
+	// ExcludeAttendees, if set, leaves rooms, bots and the like off pages
+	ExcludeAttendees *AttendeeFilter
+	// Aliases, if set, resolves attendee names to the user's person pages
+	Aliases *AttendeeAliases
+	// Anonymizer, if set, replaces attendee names with pseudonyms
+	Anonymizer *Anonymizer
+	// Classifier, if set, adds a classification:: property
+	Classifier *Classifier
+	// Journal is the graph's journal format, for date references and journal files
+	Journal JournalFormat
+	// LinkResolver, if set, turns links in the notes into [[page]] references
+	LinkResolver LinkResolver
+	// Glyphs, if set, replaces emoji and checkbox glyphs in the notes
+	Glyphs *GlyphNormalizer
+	// DetectLanguage adds a lang:: property with the notes' dominant language
+	DetectLanguage bool
+	// TitleTags, if set, limits which meetings get a tag derived from their title
+	TitleTags *TitleTagger
+	// TagRules, if set, adds the tags of the rules a meeting matches
+	TagRules *TagRules
+	// Tags, if set, drops tags not on its allowlist or on its denylist
+	Tags *TagFilter
+	// PageNameTemplate lays out meeting page names (see GetPageName)
+	PageNameTemplate string
+	// TagCalendarBlocks tags pages of calendar blocks (see
+	// granola.Document.IsCalendarBlock) with CalendarBlockTag
+	TagCalendarBlocks bool
+	// JournalHeading, if set, is the journal block that meeting entries are
+	// nested under (e.g. "📅 **Meetings**")
+	JournalHeading string
+	// Transformer, if set, changes each rendered page, e.g. with plugins
+	Transformer PageTransformer
+	// Issues, if set, links action items to the issues filed for them
+	Issues IssueLinker
+}
+
+// pageProperty is a page property with its values
+type pageProperty struct {
+	key    string
+	values []string
+	links  bool // Values are page links
+	list   bool // Rendered as a YAML list in frontmatter
+}
+
+// pageTags returns the tags property of a meeting page
+func pageTags(doc *granola.Document, opts FormatOptions) []string {
+	tags := []string{defaultTag}
+	if tag := opts.TitleTags.Tag(doc.Title); tag != "" {
+		tags = append(tags, tag)
+	}
+	tags = appendTags(tags, opts.TagRules.Tags(doc)...)
+	if opts.TagCalendarBlocks && doc.IsCalendarBlock() {
+		tags = append(tags, CalendarBlockTag)
+	}
+	return opts.Tags.Filter(tags)
+}
+
 // FormatMeetingPage formats a Granola document as a Logseq meeting page
-func FormatMeetingPage(doc *granola.Document) string {
+func FormatMeetingPage(doc *granola.Document, opts FormatOptions) string {
 	var sb strings.Builder
 
-	meetingDate := doc.GetMeetingDate()
-	dateStr := meetingDate.Format("2006-01-02")
+	dateStr := opts.Journal.PageTitle(doc.GetMeetingDate())
 	startTime, endTime, tz := doc.GetMeetingTimeRange()
-	attendees := doc.GetAttendeeNames()
-
-	// Title
-	sb.WriteString(fmt.Sprintf("- %s\n", doc.Title))
+	attendees := attendees(doc, opts)
 
 	// Properties
-	sb.WriteString(fmt.Sprintf("  meeting-date:: [[%s]]\n", dateStr))
+	props := []pageProperty{{key: "meeting-date", values: []string{dateStr}, links: true}}
 	if timeStr := formatTimeRange(startTime, endTime, tz); timeStr != "" {
-		sb.WriteString(fmt.Sprintf("  meeting-time:: %s\n", timeStr))
+		props = append(props, pageProperty{key: "meeting-time", values: []string{timeStr}})
 	}
-	sb.WriteString(fmt.Sprintf("  granola-id:: %s\n", doc.ID))
-
-	// Build tags list
-	var tags []string
-	tags = append(tags, "Granola Notes")
-	if tag := meetingTag(doc.Title); tag != "" {
-		tags = append(tags, tag)
+	if link := doc.GetMeetingLink(); link != "" {
+		props = append(props, pageProperty{key: "meeting-link", values: []string{link}})
+	}
+	if location := doc.GetLocation(); location != "" {
+		props = append(props, pageProperty{key: "location", values: []string{location}})
+	}
+	props = append(props, pageProperty{key: "granola-id", values: []string{doc.ID}})
+	if class := opts.Classifier.Classify(doc); class != "" {
+		props = append(props, pageProperty{key: "classification", values: []string{class}})
+	}
+	if lang := noteLanguage(doc, opts); lang != "" {
+		props = append(props, pageProperty{key: "lang", values: []string{lang}})
 	}
-	var tagLinks []string
-	for _, t := range tags {
-		tagLinks = append(tagLinks, fmt.Sprintf("[[%s]]", t))
+
+	props = append(props, pageProperty{key: "tags", values: pageTags(doc, opts), links: true, list: true})
+
+	// Title
+	switch opts.PropertiesStyle {
+	case PropertiesFrontmatter:
+		sb.WriteString(formatFrontmatter(props))
+		sb.WriteString(fmt.Sprintf("- %s\n", doc.Title))
+	case PropertiesBlock:
+		sb.WriteString(formatPropertyLines(props, ""))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("- %s\n", doc.Title))
+	default:
+		sb.WriteString(fmt.Sprintf("- %s\n", doc.Title))
+		sb.WriteString(formatPropertyLines(props, "  "))
 	}
-	sb.WriteString(fmt.Sprintf("  tags:: %s\n", strings.Join(tagLinks, ", ")))
 
 	// Attendees
 	if len(attendees) > 0 {
 		sb.WriteString("\t- **Attendees**\n")
-		for _, name := range attendees {
-			sb.WriteString(fmt.Sprintf("\t\t- [[@%s]]\n", name))
+		for _, a := range attendees {
+			sb.WriteString(formatAttendee(a, opts))
 		}
 	}
 
@@ -75,23 +169,120 @@ func FormatMeetingPage(doc *granola.Document) string {
 	sb.WriteString("\t- **Notes**\n")
 	if doc.NotesMarkdown != nil && *doc.NotesMarkdown != "" {
 		// Notes from documentPanels are already in Logseq format, just need base indent
-		notes := indentLogseqContent(*doc.NotesMarkdown, 2)
+		notes := opts.Glyphs.Normalize(*doc.NotesMarkdown)
+		notes = indentLogseqContent(UnfurlLinks(notes, opts.LinkResolver), 2)
 		sb.WriteString(notes)
 	} else if doc.NotesPlain != nil && *doc.NotesPlain != "" {
-		notes := convertPlainTextToLogseq(*doc.NotesPlain)
+		notes := opts.Glyphs.Normalize(convertPlainTextToLogseq(*doc.NotesPlain))
 		sb.WriteString(notes)
 	} else {
 		sb.WriteString("\t\t- (No notes taken)\n")
 	}
 
+	// The user's own notes, when the notes above are Granola's summary
+	if opts.IncludeMyNotes && strings.TrimSpace(doc.MyNotes) != "" {
+		sb.WriteString("\t- **My Notes**\n")
+		notes := opts.Glyphs.Normalize(doc.MyNotes)
+		sb.WriteString(indentLogseqContent(UnfurlLinks(notes, opts.LinkResolver), 2))
+	}
+
+	// Transcript
+	if opts.IncludeTranscript && len(doc.Transcript) > 0 {
+		sb.WriteString("\t- ## Transcript\n")
+		sb.WriteString("\t  collapsed:: true\n")
+		sb.WriteString(formatTranscript(doc.Transcript))
+	}
+
 	return sb.String()
 }
 
+// formatTranscript renders transcript entries as bullets, merging consecutive
+// utterances from the same source into one block
+func formatTranscript(entries []granola.TranscriptEntry) string {
+	var sb strings.Builder
+	var texts []string
+	source := ""
+
+	flush := func() {
+		if len(texts) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\t\t- **%s:** %s\n", transcriptSpeaker(source), strings.Join(texts, " ")))
+		texts = nil
+	}
+
+	for _, e := range entries {
+		if e.Source != source {
+			flush()
+			source = e.Source
+		}
+		texts = append(texts, multiSpaceRe.ReplaceAllString(strings.TrimSpace(e.Text), " "))
+	}
+	flush()
+
+	return sb.String()
+}
+
+// transcriptSpeaker labels a transcript source the way Granola does
+func transcriptSpeaker(source string) string {
+	if source == "microphone" {
+		return "Me"
+	}
+	return "Them"
+}
+
+// formatPropertyLines renders properties as Logseq key:: value lines
+func formatPropertyLines(props []pageProperty, indent string) string {
+	var sb strings.Builder
+	for _, p := range props {
+		values := p.values
+		if p.links {
+			values = make([]string, len(p.values))
+			for i, v := range p.values {
+				values[i] = fmt.Sprintf("[[%s]]", v)
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%s%s:: %s\n", indent, p.key, strings.Join(values, ", ")))
+	}
+	return sb.String()
+}
+
+// formatFrontmatter renders properties as a YAML frontmatter block. Logseq
+// treats list values as page references, so only single values get [[ ]].
+func formatFrontmatter(props []pageProperty) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	for _, p := range props {
+		switch {
+		case p.list:
+			quoted := make([]string, len(p.values))
+			for i, v := range p.values {
+				quoted[i] = strconv.Quote(v)
+			}
+			sb.WriteString(fmt.Sprintf("%s: [%s]\n", p.key, strings.Join(quoted, ", ")))
+		case p.links:
+			sb.WriteString(fmt.Sprintf("%s: %s\n", p.key, strconv.Quote("[["+p.values[0]+"]]")))
+		default:
+			sb.WriteString(fmt.Sprintf("%s: %s\n", p.key, strconv.Quote(p.values[0])))
+		}
+	}
+	sb.WriteString("---\n")
+	return sb.String()
+}
+
+// formatAttendee formats a single attendee bullet, optionally with a mailto link
+func formatAttendee(a granola.MeetingAttendee, opts FormatOptions) string {
+	if opts.IncludeAttendeeEmails && a.Email != "" {
+		return fmt.Sprintf("\t\t- [[@%s]] [%s](mailto:%s)\n", a.Name, a.Email, a.Email)
+	}
+	return fmt.Sprintf("\t\t- [[@%s]]\n", a.Name)
+}
+
 // FormatJournalEntry formats a journal reference for a meeting
-func FormatJournalEntry(doc *granola.Document) string {
+func FormatJournalEntry(doc *granola.Document, opts FormatOptions) string {
 	startTime, endTime, tz := doc.GetMeetingTimeRange()
-	attendees := doc.GetAttendeeNames()
-	pageName := GetPageName(doc)
+	attendees := attendeeNames(doc, opts)
+	pageName := GetPageName(doc, opts.PageNameTemplate)
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("- [[%s]]\n", pageName))
@@ -115,6 +306,12 @@ func FormatJournalEntry(doc *granola.Document) string {
 	return sb.String()
 }
 
+// FormatPersonBacklink formats the dated bullet added to an attendee's person page
+func FormatPersonBacklink(doc *granola.Document, opts FormatOptions) string {
+	dateStr := opts.Journal.PageTitle(doc.GetMeetingDate())
+	return fmt.Sprintf("- [[%s]] met in [[%s]]\n", dateStr, GetPageName(doc, opts.PageNameTemplate))
+}
+
 // convertPlainTextToLogseq converts plain text to Logseq bullet format
 func convertPlainTextToLogseq(text string) string {
 	lines := strings.Split(text, "\n")
@@ -161,19 +358,35 @@ var todoSectionNames = []string{
 	"Followups",
 }
 
-// isTodoSectionHeader checks if a line contains a todo section header
-func isTodoSectionHeader(line string) bool {
+// localizedTodoSectionNames contains todo section headers in other languages,
+// by ISO 639-1 code, checked in addition to the English ones
+var localizedTodoSectionNames = map[string][]string{
+	"de": {"Aufgaben", "Nächste Schritte", "Maßnahmen", "To-dos", "Offene Punkte"},
+	"fr": {"Actions", "Prochaines étapes", "Tâches", "À faire", "Suivi"},
+	"es": {"Acciones", "Próximos pasos", "Tareas", "Pendientes", "Seguimiento"},
+	"it": {"Azioni", "Prossimi passi", "Attività", "Compiti", "Da fare"},
+	"nl": {"Actiepunten", "Volgende stappen", "Taken", "Acties", "Te doen"},
+	"pt": {"Ações", "Próximos passos", "Tarefas", "Pendências", "A fazer"},
+}
+
+// isTodoSectionHeader checks if a line contains a todo section header, in English
+// or in the notes' language
+func isTodoSectionHeader(line string, lang string) bool {
+	if !strings.Contains(line, "**") {
+		return false
+	}
 	lineLower := strings.ToLower(line)
-	for _, name := range todoSectionNames {
-		if strings.Contains(lineLower, strings.ToLower(name)) && strings.Contains(line, "**") {
+	for _, name := range append(todoSectionNames, localizedTodoSectionNames[lang]...) {
+		if strings.Contains(lineLower, strings.ToLower(name)) {
 			return true
 		}
 	}
 	return false
 }
 
-// MarkUserTodos adds TODO markers to action items assigned to the user
-func MarkUserTodos(content string, userName string) string {
+// MarkUserTodos adds TODO markers to action items assigned to the user. lang is
+// the notes' language (see DetectLanguage), or empty to match English headers only.
+func MarkUserTodos(content string, userName string, lang string) string {
 	if userName == "" {
 		return content
 	}
@@ -184,14 +397,14 @@ func MarkUserTodos(content string, userName string) string {
 
 	for _, line := range lines {
 		// Check if we're entering a todo section
-		if isTodoSectionHeader(line) {
+		if isTodoSectionHeader(line, lang) {
 			inActionItems = true
 			sb.WriteString(line + "\n")
 			continue
 		}
 
 		// Check if we're leaving the section (new heading)
-		if inActionItems && strings.Contains(line, "**") && !isTodoSectionHeader(line) {
+		if inActionItems && strings.Contains(line, "**") && !isTodoSectionHeader(line, lang) {
 			inActionItems = false
 		}
 
@@ -206,6 +419,202 @@ func MarkUserTodos(content string, userName string) string {
 	return strings.TrimSuffix(sb.String(), "\n")
 }
 
+// ExtractUserTodos returns the text of the action items MarkUserTodos marked
+// as the user's, without the TODO marker and name prefix
+func ExtractUserTodos(content string, userName string) []string {
+	if userName == "" {
+		return nil
+	}
+
+	marker := "- TODO " + userName + ":"
+	var todos []string
+	for _, line := range strings.Split(content, "\n") {
+		idx := strings.Index(line, marker)
+		if idx < 0 {
+			continue
+		}
+		if text := strings.TrimSpace(line[idx+len(marker):]); text != "" {
+			todos = append(todos, text)
+		}
+	}
+	return todos
+}
+
+// ActionItem is an action item from a meeting's notes. Owner is the name before
+// the colon ("- Alice: send the deck"), if any.
+type ActionItem struct {
+	Owner string
+	Text  string
+}
+
+// ExtractActionItems returns every action item in the todo sections of a
+// rendered meeting page, whoever they are assigned to. lang is as for
+// MarkUserTodos.
+func ExtractActionItems(content string, lang string) []ActionItem {
+	var items []ActionItem
+	for _, text := range ExtractActionItemTexts(content, lang) {
+		var item ActionItem
+		// Owners are names, so a colon later in a sentence or in a link isn't one
+		if owner, rest, ok := strings.Cut(text, ":"); ok && owner != "" && len(strings.Fields(owner)) <= 3 && !strings.HasPrefix(rest, "//") {
+			item.Owner, text = strings.TrimSpace(owner), strings.TrimSpace(rest)
+		}
+		if text == "" {
+			continue
+		}
+		item.Text = text
+		items = append(items, item)
+	}
+	return items
+}
+
+// ExtractActionItemTexts returns the text of every action item in the todo
+// sections of a rendered meeting page as written, owner included, without the
+// bullet or any TODO or DONE marker
+func ExtractActionItemTexts(content string, lang string) []string {
+	lines := strings.Split(content, "\n")
+	var texts []string
+	for _, i := range actionItemLines(lines, lang) {
+		texts = append(texts, actionItemText(lines[i]))
+	}
+	return texts
+}
+
+// actionItemLines returns the indexes of the bullets in the todo sections of
+// a rendered meeting page's lines
+func actionItemLines(lines []string, lang string) []int {
+	var indexes []int
+	inActionItems := false
+	for i, line := range lines {
+		if isTodoSectionHeader(line, lang) {
+			inActionItems = true
+			continue
+		}
+		if strings.Contains(line, "**") {
+			inActionItems = false
+		}
+		if inActionItems && strings.Contains(line, "- ") {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// actionItemText returns the end of an action item's line after the bullet
+// and any TODO or DONE marker
+func actionItemText(line string) string {
+	_, text, _ := strings.Cut(line, "- ")
+	for _, marker := range []string{"TODO ", "DONE "} {
+		text = strings.TrimPrefix(text, marker)
+	}
+	return text
+}
+
+// LabeledBullet is a bullet that starts with a label, such as
+// "- Decision: ship in March"
+type LabeledBullet struct {
+	Label string // As given to ExtractLabeledBullets
+	Text  string
+}
+
+// ExtractLabeledBullets returns the bullets of a rendered page that start with
+// one of labels and a colon, ignoring case and bold ("- **Decision:** ...").
+func ExtractLabeledBullets(content string, labels []string) []LabeledBullet {
+	var bullets []LabeledBullet
+	for _, line := range strings.Split(content, "\n") {
+		_, text, ok := strings.Cut(line, "- ")
+		if !ok {
+			continue
+		}
+		label, rest, ok := strings.Cut(text, ":")
+		if !ok {
+			continue
+		}
+		label = strings.TrimSpace(strings.Trim(label, "*"))
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, "**"))
+		if rest == "" {
+			continue
+		}
+		for _, want := range labels {
+			if strings.EqualFold(label, want) {
+				bullets = append(bullets, LabeledBullet{Label: want, Text: rest})
+				break
+			}
+		}
+	}
+	return bullets
+}
+
+// Highlight is a section of a meeting's notes: its heading and top-level
+// bullets, without their sub-bullets
+type Highlight struct {
+	Heading string // Empty for bullets before the first heading
+	Bullets []string
+}
+
+// ExtractHighlights returns the sections of Logseq-formatted notes. A heading
+// is a bullet that is all bold ("- **Next steps**") or a markdown heading
+// ("- ## Next steps"). Sections without bullets are left out.
+func ExtractHighlights(notes string) []Highlight {
+	var highlights []Highlight
+	var current Highlight
+	var depths []int // Indent of each of current's bullets
+	flush := func() {
+		// Key bullets are the section's least indented, whether the notes nest
+		// them under the heading or not
+		if len(depths) > 0 {
+			top := slices.Min(depths)
+			var bullets []string
+			for i, b := range current.Bullets {
+				if depths[i] == top {
+					bullets = append(bullets, b)
+				}
+			}
+			highlights = append(highlights, Highlight{Heading: current.Heading, Bullets: bullets})
+		}
+		current, depths = Highlight{}, nil
+	}
+
+	for _, line := range strings.Split(notes, "\n") {
+		trimmed := strings.TrimLeft(line, "\t ")
+		text, ok := strings.CutPrefix(trimmed, "- ")
+		if !ok {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		if heading, ok := highlightHeading(text); ok {
+			flush()
+			current.Heading = heading
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		current.Bullets = append(current.Bullets, text)
+		depths = append(depths, len(line)-len(trimmed))
+	}
+	flush()
+	return highlights
+}
+
+// highlightHeading returns the heading a bullet's text is, if it is one
+func highlightHeading(text string) (string, bool) {
+	if strings.HasPrefix(text, "#") {
+		if heading := strings.TrimSpace(strings.TrimLeft(text, "#")); heading != "" {
+			return heading, true
+		}
+		return "", false
+	}
+	inner, ok := strings.CutPrefix(text, "**")
+	if !ok {
+		return "", false
+	}
+	inner, ok = strings.CutSuffix(inner, "**")
+	if !ok || inner == "" || strings.Contains(inner, "**") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimSpace(inner), ":"), true
+}
+
 // sanitizeTitle removes characters that aren't safe for filenames
 func sanitizeTitle(title string) string {
 	result := unsafeCharsRe.ReplaceAllString(title, "-")
@@ -213,24 +622,77 @@ func sanitizeTitle(title string) string {
 	return strings.Trim(result, "- ")
 }
 
-// GetPageName returns the Logseq page name for a meeting
-func GetPageName(doc *granola.Document) string {
-	meetingDate := doc.GetMeetingDate()
-	dateStr := meetingDate.Format("2006-01-02")
-	return fmt.Sprintf("meetings/%s/%s", dateStr, sanitizeTitle(doc.Title))
+// DefaultPageNameTemplate lays meeting pages out by day, e.g.
+// "meetings/2025-01-28/Standup"
+const DefaultPageNameTemplate = "meetings/{date}/{title}"
+
+// GetPageName returns the Logseq page name for a meeting, laid out by template.
+// Template placeholders are {title}, {date} (2025-01-28), {year}, {month} and
+// {day}, and {week} and {isoyear} for ISO weeks (e.g. "{isoyear}/W{week}"). An
+// empty template uses DefaultPageNameTemplate.
+func GetPageName(doc *granola.Document, template string) string {
+	if template == "" {
+		template = DefaultPageNameTemplate
+	}
+	date := doc.GetMeetingDate()
+	isoYear, week := date.ISOWeek()
+	return strings.NewReplacer(
+		"{title}", sanitizeTitle(doc.Title),
+		"{date}", date.Format("2006-01-02"),
+		"{year}", date.Format("2006"),
+		"{month}", date.Format("01"),
+		"{day}", date.Format("02"),
+		"{week}", fmt.Sprintf("%02d", week),
+		"{isoyear}", fmt.Sprintf("%d", isoYear),
+	).Replace(template)
+}
+
+// PageURL returns a logseq:// link that opens a page in the graph at basePath
+func PageURL(basePath, pageName string) string {
+	return "logseq://graph/" + url.PathEscape(filepath.Base(basePath)) + "?page=" + url.QueryEscape(pageName)
+}
+
+// FileURL returns a logseq:// link that opens the page stored at pagePath in
+// the graph at basePath
+func FileURL(basePath, pagePath string) string {
+	return PageURL(basePath, pageNameFromFilename(filepath.Base(pagePath)))
+}
+
+// GetPageFilename returns the filename for a meeting page, with namespace
+// separators encoded the way Logseq does (e.g. "meetings___2025-01-28___Standup.md")
+func GetPageFilename(doc *granola.Document, template string) string {
+	return strings.ReplaceAll(GetPageName(doc, template), "/", "___") + ".md"
 }
 
-// GetPageFilename returns the filename for a meeting page
-func GetPageFilename(doc *granola.Document) string {
-	meetingDate := doc.GetMeetingDate()
-	dateStr := meetingDate.Format("2006-01-02")
-	return fmt.Sprintf("meetings___%s___%s.md", dateStr, sanitizeTitle(doc.Title))
+// GetPersonPageFilename returns the filename for an attendee's person page
+// (the "[[@Name]]" page linked from meeting pages)
+func GetPersonPageFilename(name string) string {
+	return "@" + strings.ReplaceAll(name, "/", "___") + ".md"
 }
 
 // GetJournalFilename returns the filename for a journal entry
-func GetJournalFilename(doc *granola.Document) string {
-	meetingDate := doc.GetMeetingDate()
-	return meetingDate.Format("2006_01_02") + ".md"
+func GetJournalFilename(doc *granola.Document, f JournalFormat) string {
+	return f.Filename(doc.GetMeetingDate())
+}
+
+// pageNameFromFilename converts a page filename back to its Logseq page name
+// (e.g. "meetings___2025-01-28___Standup.md" -> "meetings/2025-01-28/Standup")
+func pageNameFromFilename(filename string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(filename, ".md"), "___", "/")
+}
+
+// journalFilenameFromPageName returns the journal filename for the date embedded
+// in a meeting page name, or empty string if the name has no date
+func journalFilenameFromPageName(pageName string, f JournalFormat) string {
+	m := dateYMDRe.FindString(pageName)
+	if m == "" {
+		return ""
+	}
+	date, err := time.Parse("2006-01-02", strings.ReplaceAll(strings.TrimSpace(m), "/", "-"))
+	if err != nil {
+		return ""
+	}
+	return f.Filename(date)
 }
 
 // shortTimezone converts a timezone name to a short abbreviation
@@ -1,9 +1,13 @@
 package logseq
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
 )
 
 type FormatSuite struct {
@@ -38,11 +42,46 @@ func (s *FormatSuite) TestMeetingTag() {
 	}
 }
 
+func (s *FormatSuite) TestGetPageName() {
+	doc := &granola.Document{
+		Title: "Standup: Team A",
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Start: &granola.EventTime{DateTime: "2024-12-30T10:00:00Z"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		template     string
+		wantName     string
+		wantFilename string
+	}{
+		{"default", "", "meetings/2024-12-30/Standup- Team A", "meetings___2024-12-30___Standup- Team A.md"},
+		{"monthly", "meetings/{year}/{month}/{date} {title}", "meetings/2024/12/2024-12-30 Standup- Team A", "meetings___2024___12___2024-12-30 Standup- Team A.md"},
+		{"weekly uses ISO year", "meetings/{isoyear}/W{week}/{title}", "meetings/2025/W01/Standup- Team A", "meetings___2025___W01___Standup- Team A.md"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.wantName, GetPageName(doc, tt.template))
+			s.Equal(tt.wantFilename, GetPageFilename(doc, tt.template))
+		})
+	}
+}
+
+func (s *FormatSuite) TestJournalFilenameFromPageName() {
+	s.Equal("2025_01_28.md", journalFilenameFromPageName("meetings/2025-01-28/Standup", JournalFormat{}))
+	s.Equal("2025_01_28.md", journalFilenameFromPageName("meetings/2025/01/28/Standup", JournalFormat{}))
+	s.Equal("2025_01_28.md", journalFilenameFromPageName("meetings/2025/W05/2025-01-28 Standup", JournalFormat{}))
+	s.Empty(journalFilenameFromPageName("meetings/2025/W05/Standup", JournalFormat{}))
+}
+
 func (s *FormatSuite) TestMarkUserTodos() {
 	tests := []struct {
 		name     string
 		content  string
 		userName string
+		lang     string
 		want     string
 	}{
 		{
@@ -57,6 +96,16 @@ func (s *FormatSuite) TestMarkUserTodos() {
 		- TODO Alice: Update the documentation
 		- Carol: Schedule follow-up meeting`,
 		},
+		{
+			name: "leaves task list items alone",
+			content: `		- **Action Items**
+		- TODO Alice: Update the documentation
+		- DONE Alice: Send the agenda`,
+			userName: "Alice",
+			want: `		- **Action Items**
+		- TODO Alice: Update the documentation
+		- DONE Alice: Send the agenda`,
+		},
 		{
 			name: "does not mark other users",
 			content: `		- **Action Items**
@@ -129,12 +178,311 @@ func (s *FormatSuite) TestMarkUserTodos() {
 			want: `		- **next steps**
 		- TODO Alice: Do something`,
 		},
+		{
+			name: "matches localized header in notes language",
+			content: `		- **Nächste Schritte**
+		- Alice: Angebot prüfen`,
+			userName: "Alice",
+			lang:     "de",
+			want: `		- **Nächste Schritte**
+		- TODO Alice: Angebot prüfen`,
+		},
+		{
+			name: "ignores localized header in other language",
+			content: `		- **Nächste Schritte**
+		- Alice: Angebot prüfen`,
+			userName: "Alice",
+			lang:     "fr",
+			want: `		- **Nächste Schritte**
+		- Alice: Angebot prüfen`,
+		},
 	}
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got := MarkUserTodos(tt.content, tt.userName)
+			got := MarkUserTodos(tt.content, tt.userName, tt.lang)
 			s.Equal(tt.want, got)
 		})
 	}
 }
+
+func (s *FormatSuite) TestExtractActionItems() {
+	content := `- **Summary**
+	- Agreed: ship on Friday
+- **Action Items**
+	- Bob: Review the proposal
+	- TODO Alice: Update the documentation
+	- Book a room for the offsite
+	- Read [the spec](https://example.com)
+	- Carol:
+- **Other Section**
+	- Dave: Not an action item`
+
+	s.Equal([]ActionItem{
+		{Owner: "Bob", Text: "Review the proposal"},
+		{Owner: "Alice", Text: "Update the documentation"},
+		{Text: "Book a room for the offsite"},
+		{Text: "Read [the spec](https://example.com)"},
+	}, ExtractActionItems(content, ""))
+	s.Equal([]string{
+		"Bob: Review the proposal",
+		"Alice: Update the documentation",
+		"Book a room for the offsite",
+		"Read [the spec](https://example.com)",
+		"Carol:",
+	}, ExtractActionItemTexts(content, ""))
+}
+
+func (s *FormatSuite) TestExtractLabeledBullets() {
+	content := `- **Summary**
+	- decision: Ship the beta in March
+	- **Definition:** ARR: annual recurring revenue
+	- Decision:
+	- Decisions were deferred
+	- Alice: Send the deck`
+
+	s.Equal([]LabeledBullet{
+		{Label: "Decision", Text: "Ship the beta in March"},
+		{Label: "Definition", Text: "ARR: annual recurring revenue"},
+	}, ExtractLabeledBullets(content, []string{"Decision", "Definition"}))
+}
+
+func (s *FormatSuite) TestExtractHighlights() {
+	notes := "- Kickoff for the Q3 launch\n" +
+		"- **Decisions:**\n" +
+		"- Ship the beta in March\n" +
+		"\t- Pending legal review\n" +
+		"- Keep pricing as is\n" +
+		"- **Risks**\n" +
+		"- ## Next Steps\n" +
+		"\t- Alice: Send the deck\n" +
+		"\t\t- By Friday\n" +
+		"\t- **Bold** start of a bullet\n"
+
+	s.Equal([]Highlight{
+		{Bullets: []string{"Kickoff for the Q3 launch"}},
+		{Heading: "Decisions", Bullets: []string{"Ship the beta in March", "Keep pricing as is"}},
+		{Heading: "Next Steps", Bullets: []string{"Alice: Send the deck", "**Bold** start of a bullet"}},
+	}, ExtractHighlights(notes))
+}
+
+func (s *FormatSuite) TestFormatMeetingPageAttendeeEmails() {
+	doc := &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		People: &granola.People{
+			Attendees: []granola.AttendeeInfo{
+				{Name: "Bob Smith", Email: "bob@example.com"},
+				{Name: "No Email"},
+			},
+		},
+	}
+
+	s.Run("off by default", func() {
+		got := FormatMeetingPage(doc, FormatOptions{})
+		s.Contains(got, "\t\t- [[@Bob Smith]]\n")
+		s.NotContains(got, "mailto:")
+	})
+
+	s.Run("emits mailto links when enabled", func() {
+		got := FormatMeetingPage(doc, FormatOptions{IncludeAttendeeEmails: true})
+		s.Contains(got, "\t\t- [[@Bob Smith]] [bob@example.com](mailto:bob@example.com)\n")
+		s.Contains(got, "\t\t- [[@No Email]]\n")
+	})
+}
+
+func (s *FormatSuite) TestFormatMeetingPageLinkAndLocation() {
+	doc := &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			HangoutLink: "https://meet.google.com/abc-defg-hij",
+			Location:    "Room 4.01",
+		},
+	}
+
+	got := FormatMeetingPage(doc, FormatOptions{})
+	s.Contains(got, "meeting-link:: https://meet.google.com/abc-defg-hij\n")
+	s.Contains(got, "location:: Room 4.01\n")
+
+	got = FormatMeetingPage(&granola.Document{ID: "doc-2", Title: "Planning"}, FormatOptions{})
+	s.NotContains(got, "meeting-link::")
+	s.NotContains(got, "location::")
+}
+
+func (s *FormatSuite) TestFormatMeetingPageMyNotes() {
+	summary := "- Meeting summary\n"
+	doc := &granola.Document{
+		ID:            "doc-1",
+		Title:         "Planning",
+		NotesMarkdown: &summary,
+		MyNotes:       "- Ask about the budget\n",
+	}
+
+	s.Run("off by default", func() {
+		got := FormatMeetingPage(doc, FormatOptions{})
+		s.NotContains(got, "My Notes")
+	})
+
+	s.Run("adds section after notes when enabled", func() {
+		got := FormatMeetingPage(doc, FormatOptions{IncludeMyNotes: true})
+		s.Contains(got, "\t- **Notes**\n\t\t- Meeting summary\n\t- **My Notes**\n\t\t- Ask about the budget\n")
+	})
+}
+
+func (s *FormatSuite) TestFormatMeetingPageTranscript() {
+	doc := &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		Transcript: []granola.TranscriptEntry{
+			{Text: "Can you hear me?", Source: "microphone"},
+			{Text: "Yes.", Source: "system"},
+			{Text: "Great,  let's\nstart.", Source: "system"},
+		},
+	}
+
+	s.Run("off by default", func() {
+		got := FormatMeetingPage(doc, FormatOptions{})
+		s.NotContains(got, "Transcript")
+	})
+
+	s.Run("renders a collapsed section when enabled", func() {
+		got := FormatMeetingPage(doc, FormatOptions{IncludeTranscript: true})
+		s.Contains(got, "\t- ## Transcript\n\t  collapsed:: true\n"+
+			"\t\t- **Me:** Can you hear me?\n"+
+			"\t\t- **Them:** Yes. Great, let's start.\n")
+	})
+
+	s.Run("omitted when there is no transcript", func() {
+		got := FormatMeetingPage(&granola.Document{ID: "doc-2", Title: "Quiet"}, FormatOptions{IncludeTranscript: true})
+		s.NotContains(got, "Transcript")
+	})
+}
+
+func (s *FormatSuite) TestFormatMeetingPagePropertiesStyle() {
+	doc := &granola.Document{
+		ID:        "doc-1",
+		Title:     "Weekly Sync - Monday",
+		CreatedAt: time.Date(2025, 1, 27, 10, 0, 0, 0, time.Local),
+	}
+
+	s.Run("bullet by default", func() {
+		got := FormatMeetingPage(doc, FormatOptions{})
+		s.True(strings.HasPrefix(got, "- Weekly Sync - Monday\n"+
+			"  meeting-date:: [[2025-01-27]]\n"+
+			"  granola-id:: doc-1\n"+
+			"  tags:: [[Granola Notes]], [[Weekly Sync]]\n"), got)
+	})
+
+	s.Run("block", func() {
+		got := FormatMeetingPage(doc, FormatOptions{PropertiesStyle: PropertiesBlock})
+		s.True(strings.HasPrefix(got, "meeting-date:: [[2025-01-27]]\n"+
+			"granola-id:: doc-1\n"+
+			"tags:: [[Granola Notes]], [[Weekly Sync]]\n"+
+			"\n"+
+			"- Weekly Sync - Monday\n"+
+			"\t- **Notes**\n"), got)
+	})
+
+	s.Run("frontmatter", func() {
+		got := FormatMeetingPage(doc, FormatOptions{PropertiesStyle: PropertiesFrontmatter})
+		s.True(strings.HasPrefix(got, "---\n"+
+			"meeting-date: \"[[2025-01-27]]\"\n"+
+			"granola-id: \"doc-1\"\n"+
+			"tags: [\"Granola Notes\", \"Weekly Sync\"]\n"+
+			"---\n"+
+			"- Weekly Sync - Monday\n"), got)
+
+		page := &Page{Content: got}
+		s.Equal("doc-1", page.GranolaID())
+	})
+}
+
+func (s *FormatSuite) TestAnonymizeAttendees() {
+	doc := &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		People: &granola.People{
+			Attendees: []granola.AttendeeInfo{
+				{Name: "Test User", Email: "test@example.com"},
+				{Name: "Bob Smith", Email: "bob@example.com"},
+			},
+		},
+	}
+	anon := NewAnonymizer("secret", "Test User")
+	opts := FormatOptions{IncludeAttendeeEmails: true, Anonymizer: anon}
+
+	bob := anon.Attendee(granola.MeetingAttendee{Name: "Bob Smith", Email: "bob@example.com"})
+	s.Regexp(`^Person [0-9a-f]{6}$`, bob.Name)
+	s.Empty(bob.Email)
+
+	s.Run("stable and keyed on email", func() {
+		again := anon.Attendee(granola.MeetingAttendee{Name: "Robert Smith", Email: "BOB@example.com"})
+		s.Equal(bob.Name, again.Name)
+
+		other := NewAnonymizer("other", "Test User").Attendee(granola.MeetingAttendee{Name: "Bob Smith", Email: "bob@example.com"})
+		s.NotEqual(bob.Name, other.Name)
+	})
+
+	s.Run("rendered output", func() {
+		page := FormatMeetingPage(doc, opts)
+		s.Contains(page, "\t\t- [[@Test User]] [test@example.com](mailto:test@example.com)\n")
+		s.Contains(page, "\t\t- [[@"+bob.Name+"]]\n")
+		s.NotContains(page, "Bob")
+		s.NotContains(page, "bob@example.com")
+
+		journal := FormatJournalEntry(doc, opts)
+		s.Contains(journal, "[[@"+bob.Name+"]]")
+		s.NotContains(journal, "Bob")
+	})
+}
+
+func (s *FormatSuite) TestClassification() {
+	notes := "We discussed the acquisition timeline"
+	doc := func(title, email string) *granola.Document {
+		return &granola.Document{
+			ID:         "doc-1",
+			Title:      title,
+			NotesPlain: &notes,
+			People: &granola.People{
+				Attendees: []granola.AttendeeInfo{{Name: "Someone", Email: email}},
+			},
+		}
+	}
+	classifier := NewClassifier([]ClassificationRule{
+		{Classification: "confidential", Keywords: []string{"Board"}},
+		{Classification: "internal", Domains: []string{"example.com"}},
+		{Classification: "restricted", Keywords: []string{"ACQUISITION"}},
+	}, "public")
+
+	tests := []struct {
+		name  string
+		doc   *granola.Document
+		class string
+	}{
+		{"keyword_in_title", doc("Board prep", "a@example.com"), "confidential"},
+		{"domain", doc("Weekly", "a@example.com"), "internal"},
+		{"subdomain", doc("Weekly", "a@eu.example.com"), "internal"},
+		{"keyword_in_notes", doc("Weekly", "a@partner.io"), "restricted"},
+		{"lookalike_domain", doc("Weekly", "a@notexample.com"), "restricted"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.class, classifier.Classify(tt.doc))
+		})
+	}
+
+	s.Run("fallback", func() {
+		plain := &granola.Document{ID: "doc-2", Title: "Coffee"}
+		s.Equal("public", classifier.Classify(plain))
+		s.Empty(NewClassifier(nil, "").Classify(plain))
+	})
+
+	s.Run("rendered as a page property", func() {
+		page := FormatMeetingPage(doc("Weekly", "a@example.com"), FormatOptions{Classifier: classifier})
+		s.Contains(page, "  granola-id:: doc-1\n  classification:: internal\n")
+
+		page = FormatMeetingPage(doc("Weekly", "a@example.com"), FormatOptions{})
+		s.NotContains(page, "classification::")
+	})
+}
@@ -0,0 +1,270 @@
+package logseq
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FS is the filesystem a Writer reads and writes a graph through. Library users
+// can back a writer with something other than the local disk, e.g. memory or a
+// remote graph, and dry runs use an overlay that keeps writes in memory.
+//
+// Missing files are reported with errors that satisfy os.IsNotExist.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	// WriteFile replaces name's content as a whole, so readers never see a
+	// partial write. An existing file keeps its permissions.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Stat(name string) (fs.FileInfo, error)
+	Rename(oldname, newname string) error
+	// ReadDir lists a directory, sorted by name
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// OSFS is the local disk. Files are replaced atomically.
+var OSFS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return writeFileAtomic(name, data, perm)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+func (osFS) Rename(oldname, newname string) error { return os.Rename(oldname, newname) }
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+// MemFS is a filesystem held in memory. Directories aren't modeled: any path
+// can be written.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+type memFile struct {
+	data    []byte
+	perm    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS creates an empty in-memory filesystem
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]*memFile)}
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return nil, notExist("open", name)
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.files[name]; ok {
+		perm = f.perm
+	}
+	m.files[name] = &memFile{data: append([]byte(nil), data...), perm: perm, modTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return nil, notExist("stat", name)
+	}
+	return memFileInfo{name: name, file: f}, nil
+}
+
+func (m *MemFS) Rename(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[oldname]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldname)
+	m.files[newname] = f
+	return nil
+}
+
+// ReadDir lists the files directly in a directory. A directory without files
+// doesn't exist.
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var entries []fs.DirEntry
+	for path, f := range m.files {
+		if filepath.Dir(path) == filepath.Clean(name) {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(path), file: f}))
+		}
+	}
+	if len(entries) == 0 {
+		return nil, notExist("readdir", name)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func (m *MemFS) remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, name)
+}
+
+// Files returns the paths of the files in the filesystem, sorted
+func (m *MemFS) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// memFileInfo describes a MemFS file
+type memFileInfo struct {
+	name string
+	file *memFile
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.file.perm }
+func (i memFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
+
+// OverlayFS reads through to a base filesystem but keeps its own writes in
+// memory, leaving the base untouched. Dry runs write through an overlay so they
+// follow the same code path as a real sync.
+type OverlayFS struct {
+	base    FS
+	upper   *MemFS
+	mu      sync.Mutex
+	removed map[string]bool // Files renamed away in the overlay
+}
+
+// NewOverlayFS creates an overlay over base
+func NewOverlayFS(base FS) *OverlayFS {
+	return &OverlayFS{base: base, upper: NewMemFS(), removed: make(map[string]bool)}
+}
+
+func (o *OverlayFS) ReadFile(name string) ([]byte, error) {
+	if data, err := o.upper.ReadFile(name); err == nil {
+		return data, nil
+	}
+	if o.isRemoved(name) {
+		return nil, notExist("open", name)
+	}
+	return o.base.ReadFile(name)
+}
+
+func (o *OverlayFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if info, err := o.Stat(name); err == nil {
+		perm = info.Mode().Perm()
+	}
+	o.setRemoved(name, false)
+	return o.upper.WriteFile(name, data, perm)
+}
+
+func (o *OverlayFS) Stat(name string) (fs.FileInfo, error) {
+	if info, err := o.upper.Stat(name); err == nil {
+		return info, nil
+	}
+	if o.isRemoved(name) {
+		return nil, notExist("stat", name)
+	}
+	return o.base.Stat(name)
+}
+
+func (o *OverlayFS) Rename(oldname, newname string) error {
+	info, err := o.Stat(oldname)
+	if err != nil {
+		return err
+	}
+	data, err := o.ReadFile(oldname)
+	if err != nil {
+		return err
+	}
+	// The moved file keeps its own permissions, not those of what it replaces
+	o.upper.remove(oldname)
+	o.upper.remove(newname)
+	if err := o.upper.WriteFile(newname, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	o.setRemoved(oldname, true)
+	o.setRemoved(newname, false)
+	return nil
+}
+
+func (o *OverlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	base, baseErr := o.base.ReadDir(name)
+	if baseErr != nil && !os.IsNotExist(baseErr) {
+		return nil, baseErr
+	}
+	upper, upperErr := o.upper.ReadDir(name)
+	if baseErr != nil && upperErr != nil {
+		return nil, baseErr
+	}
+
+	written := make(map[string]bool, len(upper))
+	for _, entry := range upper {
+		written[entry.Name()] = true
+	}
+	entries := upper
+	for _, entry := range base {
+		if !written[entry.Name()] && !o.isRemoved(filepath.Join(name, entry.Name())) {
+			entries = append(entries, entry)
+		}
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Changed returns the paths written in the overlay, sorted
+func (o *OverlayFS) Changed() []string {
+	return o.upper.Files()
+}
+
+func (o *OverlayFS) isRemoved(name string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.removed[name]
+}
+
+func (o *OverlayFS) setRemoved(name string, removed bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if removed {
+		o.removed[name] = true
+	} else {
+		delete(o.removed, name)
+	}
+}
+
+// sortEntries sorts directory entries by name, as os.ReadDir does
+func sortEntries(entries []fs.DirEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+}
+
+// notExist returns the error for a missing file
+func notExist(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
@@ -0,0 +1,185 @@
+package logseq
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type FSSuite struct {
+	suite.Suite
+}
+
+func TestFSSuite(t *testing.T) {
+	suite.Run(t, new(FSSuite))
+}
+
+func (s *FSSuite) meeting() *granola.Document {
+	return &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Start: &granola.EventTime{DateTime: "2025-01-28T10:00:00Z"},
+			Attendees: []granola.Attendee{
+				{Email: "bob@example.com", DisplayName: "Bob Smith"},
+			},
+		},
+	}
+}
+
+func (s *FSSuite) TestMemFS() {
+	mem := NewMemFS()
+
+	_, err := mem.ReadFile("/graph/pages/a.md")
+	s.True(os.IsNotExist(err))
+	_, err = mem.Stat("/graph/pages/a.md")
+	s.True(os.IsNotExist(err))
+
+	s.Require().NoError(mem.WriteFile("/graph/pages/a.md", []byte("- one\n"), 0o600))
+	s.Require().NoError(mem.WriteFile("/graph/pages/a.md", []byte("- two\n"), 0o644))
+	info, err := mem.Stat("/graph/pages/a.md")
+	s.Require().NoError(err)
+	s.Equal(int64(6), info.Size())
+	s.Equal(os.FileMode(0o600), info.Mode(), "an existing file keeps its permissions")
+
+	s.Require().NoError(mem.Rename("/graph/pages/a.md", "/graph/pages/b.md"))
+	s.Equal([]string{"/graph/pages/b.md"}, mem.Files())
+	content, err := mem.ReadFile("/graph/pages/b.md")
+	s.Require().NoError(err)
+	s.Equal("- two\n", string(content))
+
+	s.True(os.IsNotExist(mem.Rename("/graph/pages/a.md", "/graph/pages/c.md")))
+
+	s.Require().NoError(mem.WriteFile("/graph/pages/a.md", []byte("- one\n"), 0o644))
+	s.Require().NoError(mem.WriteFile("/graph/journals/2025_01_28.md", []byte("- day\n"), 0o644))
+	entries, err := mem.ReadDir("/graph/pages")
+	s.Require().NoError(err)
+	s.Equal([]string{"a.md", "b.md"}, entryNames(entries))
+	_, err = mem.ReadDir("/graph/assets")
+	s.True(os.IsNotExist(err))
+}
+
+func (s *FSSuite) TestOverlayFSLeavesBaseAlone() {
+	base := NewMemFS()
+	s.Require().NoError(base.WriteFile("old.md", []byte("old"), 0o644))
+	s.Require().NoError(base.WriteFile("kept.md", []byte("kept"), 0o644))
+	overlay := NewOverlayFS(base)
+
+	s.Require().NoError(overlay.WriteFile("kept.md", []byte("changed"), 0o644))
+	s.Require().NoError(overlay.Rename("old.md", "new.md"))
+
+	content, err := overlay.ReadFile("kept.md")
+	s.Require().NoError(err)
+	s.Equal("changed", string(content))
+	content, err = overlay.ReadFile("new.md")
+	s.Require().NoError(err)
+	s.Equal("old", string(content))
+	_, err = overlay.ReadFile("old.md")
+	s.True(os.IsNotExist(err), "renamed away in the overlay")
+	s.Equal([]string{"kept.md", "new.md"}, overlay.Changed())
+	entries, err := overlay.ReadDir(".")
+	s.Require().NoError(err)
+	s.Equal([]string{"kept.md", "new.md"}, entryNames(entries))
+
+	s.Equal([]string{"kept.md", "old.md"}, base.Files())
+	content, err = base.ReadFile("kept.md")
+	s.Require().NoError(err)
+	s.Equal("kept", string(content))
+}
+
+func (s *FSSuite) TestScanOnMemFS() {
+	mem := NewMemFS()
+	s.Require().NoError(mem.WriteFile("/graph/pages/Planning.md", []byte("granola-id:: doc-1\n"), 0o644))
+	s.Require().NoError(mem.WriteFile("/graph/pages/Notes.md", []byte("- notes\n"), 0o644))
+
+	ids, err := ScanGranolaIDs(mem, "/graph")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"doc-1": "/graph/pages/Planning.md"}, ids)
+}
+
+func (s *FSSuite) TestWriterOnMemFS() {
+	mem := NewMemFS()
+	w := NewWriter("/graph", "", FormatOptions{})
+	w.SetFS(mem)
+
+	pagePath, err := w.WriteMeetingPage(s.meeting())
+	s.Require().NoError(err)
+	added, err := w.AppendJournalEntry(s.meeting())
+	s.Require().NoError(err)
+	s.True(added)
+
+	journalPath := filepath.Join("/graph", "journals", "2025_01_28.md")
+	s.Equal([]string{journalPath, pagePath}, mem.Files())
+	content, err := mem.ReadFile(pagePath)
+	s.Require().NoError(err)
+	s.Contains(string(content), "granola-id:: doc-1")
+
+	// Renames happen in the writer's filesystem too
+	doc := s.meeting()
+	doc.Title = "Roadmap Planning"
+	moved, err := w.RenameMeetingPage(pagePath, doc)
+	s.Require().NoError(err)
+	s.True(moved)
+	_, err = mem.Stat(pagePath)
+	s.True(os.IsNotExist(err))
+}
+
+func (s *FSSuite) TestRenameOntoExistingPage() {
+	mem := NewMemFS()
+	w := NewWriter("/graph", "", FormatOptions{})
+	w.SetFS(mem)
+
+	pagePath, err := w.WriteMeetingPage(s.meeting())
+	s.Require().NoError(err)
+	other := filepath.Join("/graph", "pages", "meetings___2025-01-28___Roadmap Planning.md")
+	s.Require().NoError(mem.WriteFile(other, []byte("granola-id:: doc-2\n"), 0o644))
+
+	// Renaming to another meeting's title leaves both pages alone
+	doc := s.meeting()
+	doc.Title = "Roadmap Planning"
+	moved, err := w.RenameMeetingPage(pagePath, doc)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "already exists")
+	s.False(moved)
+
+	content, err := mem.ReadFile(other)
+	s.Require().NoError(err)
+	s.Equal("granola-id:: doc-2\n", string(content))
+	content, err = mem.ReadFile(pagePath)
+	s.Require().NoError(err)
+	s.Contains(string(content), "granola-id:: doc-1")
+}
+
+func (s *FSSuite) TestDryRunWritesNothing() {
+	mem := NewMemFS()
+	w := NewWriter("/graph", "", FormatOptions{})
+	w.SetFS(mem)
+
+	personPath := filepath.Join("/graph", "pages", "@Bob Smith.md")
+	s.Equal([]string{personPath}, w.DryRunPersonBacklinks(s.meeting()))
+
+	before, after, err := w.DryRunJournalContent(s.meeting())
+	s.Require().NoError(err)
+	s.Empty(before)
+	s.Contains(after, "granola-id:: doc-1")
+	s.Empty(mem.Files())
+
+	// Once the backlink is written, a dry run has nothing to add
+	_, err = w.AppendPersonBacklinks(s.meeting())
+	s.Require().NoError(err)
+	s.Empty(w.DryRunPersonBacklinks(s.meeting()))
+}
+
+// entryNames returns the names of directory entries
+func entryNames(entries []fs.DirEntry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names
+}
@@ -0,0 +1,115 @@
+package logseq
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// variationSelector asks for the emoji presentation of the glyph before it.
+// Glyphs are matched with or without it.
+const variationSelector = "\ufe0f"
+
+// DefaultGlyphReplacements are the built-in glyph replacements, which config can
+// extend or override
+var DefaultGlyphReplacements = map[string]string{
+	"✅": "DONE",
+	"☑": "DONE",
+	"✔": "DONE",
+	"❌": "CANCELED",
+	"⬜": "TODO",
+	"☐": "TODO",
+	"➡": "->",
+	"⚠": "Warning:",
+}
+
+// taskMarkers are the Logseq task markers a glyph can become
+var taskMarkers = map[string]bool{
+	"TODO": true, "DOING": true, "DONE": true, "LATER": true, "NOW": true,
+	"WAITING": true, "CANCELED": true, "CANCELLED": true,
+}
+
+// bulletRe splits a block line into its bullet prefix and text
+var bulletRe = regexp.MustCompile(`^(\s*- )(.*)$`)
+
+// GlyphNormalizer replaces emoji and checkbox glyphs in notes with text. A glyph
+// mapped to a task marker (e.g. DONE) becomes the block's marker when it starts
+// the block, and is dropped elsewhere since a marker mid-sentence means nothing.
+type GlyphNormalizer struct {
+	markers map[string]string // Glyphs that start a block, by glyph
+	text    *strings.Replacer
+}
+
+// NewGlyphNormalizer creates a normalizer from a glyph to replacement map
+func NewGlyphNormalizer(replacements map[string]string) *GlyphNormalizer {
+	n := &GlyphNormalizer{markers: make(map[string]string)}
+
+	// Sort for a deterministic replacer, with the variation selector forms first
+	// so they win over the bare glyphs
+	glyphs := make([]string, 0, len(replacements))
+	for glyph := range replacements {
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Strings(glyphs)
+
+	var pairs, variants []string
+	for _, glyph := range glyphs {
+		replacement := replacements[glyph]
+		bare := strings.TrimSuffix(glyph, variationSelector)
+		if taskMarkers[replacement] {
+			n.markers[bare] = replacement
+			replacement = ""
+		}
+		variants = append(variants, bare+variationSelector, replacement)
+		pairs = append(pairs, bare, replacement)
+	}
+	n.text = strings.NewReplacer(append(variants, pairs...)...)
+	return n
+}
+
+// Normalize replaces the glyphs in Logseq-formatted content. A nil normalizer
+// leaves the content unchanged.
+func (n *GlyphNormalizer) Normalize(content string) string {
+	if n == nil {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		m := bulletRe.FindStringSubmatch(line)
+		if m == nil {
+			lines[i] = n.replace(line)
+			continue
+		}
+		prefix, text := m[1], m[2]
+		marker := ""
+		for glyph, mk := range n.markers {
+			if rest, ok := strings.CutPrefix(text, glyph); ok {
+				marker = mk
+				text = strings.TrimPrefix(rest, variationSelector)
+				break
+			}
+		}
+		text = strings.TrimSpace(n.replace(text))
+		if marker != "" {
+			text = strings.TrimSpace(marker + " " + text)
+		}
+		lines[i] = prefix + text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// replace swaps glyphs for their text, collapsing the double spaces left behind
+// by dropped glyphs but keeping the line's indentation
+func (n *GlyphNormalizer) replace(s string) string {
+	replaced := n.text.Replace(s)
+	if replaced == s {
+		return s
+	}
+	text := strings.TrimLeft(replaced, " \t")
+	indent := replaced[:len(replaced)-len(text)]
+	for strings.Contains(text, "  ") {
+		text = strings.ReplaceAll(text, "  ", " ")
+	}
+	return indent + text
+}
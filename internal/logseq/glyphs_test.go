@@ -0,0 +1,64 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GlyphsSuite struct {
+	suite.Suite
+}
+
+func TestGlyphsSuite(t *testing.T) {
+	suite.Run(t, new(GlyphsSuite))
+}
+
+func (s *GlyphsSuite) TestNormalize() {
+	n := NewGlyphNormalizer(DefaultGlyphReplacements)
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "marker_at_block_start",
+			content:  "- ✅ Shipped the beta\n\t- ❌ Dropped the survey\n- ⬜ Alice: Write the doc",
+			expected: "- DONE Shipped the beta\n\t- CANCELED Dropped the survey\n- TODO Alice: Write the doc",
+		},
+		{
+			name:     "variation_selector",
+			content:  "- ☑️ Booked ➡️ next week\n- ⚠️ Budget is tight",
+			expected: "- DONE Booked -> next week\n- Warning: Budget is tight",
+		},
+		{
+			name:     "marker_mid_block_is_dropped",
+			content:  "- Launch went well ✅ and on time",
+			expected: "- Launch went well and on time",
+		},
+		{
+			name:     "continuation_lines_keep_indent",
+			content:  "- | Task | Status |\n  | --- | --- |\n  | Deck | ✅ |",
+			expected: "- | Task | Status |\n  | --- | --- |\n  | Deck | |",
+		},
+		{
+			name:     "no_glyphs",
+			content:  "- Plain notes\n  continued",
+			expected: "- Plain notes\n  continued",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, n.Normalize(tt.content))
+		})
+	}
+}
+
+func (s *GlyphsSuite) TestCustomReplacements() {
+	n := NewGlyphNormalizer(map[string]string{"✅": "✅", "🔥": "(urgent)", "❌": "LATER"})
+	s.Equal("- ✅ Done\n- LATER Revisit (urgent)", n.Normalize("- ✅ Done\n- ❌ Revisit 🔥"))
+
+	var nilNormalizer *GlyphNormalizer
+	s.Equal("- ✅ Done", nilNormalizer.Normalize("- ✅ Done"))
+}
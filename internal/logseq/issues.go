@@ -0,0 +1,30 @@
+package logseq
+
+import (
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// IssueLinker links action items to the issues filed for them in a tracker
+type IssueLinker interface {
+	// LinkIssue returns an action item's text (as from ExtractActionItemTexts)
+	// rewritten to link the issue filed for it, or "" if none was filed
+	LinkIssue(docID, text string) string
+}
+
+// linkIssues rewrites the action items the options' issue linker, if any, has
+// issues for
+func linkIssues(doc *granola.Document, content string, opts FormatOptions) string {
+	if opts.Issues == nil {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for _, i := range actionItemLines(lines, noteLanguage(doc, opts)) {
+		text := actionItemText(lines[i])
+		if linked := opts.Issues.LinkIssue(doc.ID, text); linked != "" {
+			lines[i] = strings.TrimSuffix(lines[i], text) + linked
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,164 @@
+package logseq
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Legacy journal formats, used when the graph's config.edn doesn't set its own
+const (
+	defaultJournalFileFormat  = "yyyy_MM_dd"
+	defaultJournalTitleFormat = "yyyy-MM-dd"
+)
+
+var (
+	journalFileFormatRe  = regexp.MustCompile(`:journal/file-name-format\s+"([^"]*)"`)
+	journalTitleFormatRe = regexp.MustCompile(`:journal/page-title-format\s+"([^"]*)"`)
+	dateFormatterRe      = regexp.MustCompile(`:date-formatter\s+"([^"]*)"`)
+)
+
+// JournalFormat holds a graph's journal date formats as Logseq date patterns
+// (e.g. "yyyy_MM_dd", "MMM do, yyyy"). Empty fields use the legacy defaults.
+type JournalFormat struct {
+	// FileFormat is the journal filename format, without the .md extension
+	FileFormat string
+	// TitleFormat is the journal page name format, used for date page references
+	TitleFormat string
+}
+
+// ReadJournalFormat reads the journal formats from the graph's logseq/config.edn
+// through fsys. A missing file or setting leaves that format at its default.
+func ReadJournalFormat(fsys FS, basePath string) (JournalFormat, error) {
+	data, err := fsys.ReadFile(filepath.Join(basePath, "logseq", "config.edn"))
+	if os.IsNotExist(err) {
+		return JournalFormat{}, nil
+	}
+	if err != nil {
+		return JournalFormat{}, fmt.Errorf("reading config.edn: %w", err)
+	}
+	return parseJournalFormat(string(data)), nil
+}
+
+// parseJournalFormat extracts the journal formats from config.edn contents.
+// Older graphs set the title format with :date-formatter instead.
+func parseJournalFormat(edn string) JournalFormat {
+	edn = stripEDNComments(edn)
+
+	var f JournalFormat
+	if m := journalFileFormatRe.FindStringSubmatch(edn); m != nil {
+		f.FileFormat = m[1]
+	}
+	if m := journalTitleFormatRe.FindStringSubmatch(edn); m != nil {
+		f.TitleFormat = m[1]
+	} else if m := dateFormatterRe.FindStringSubmatch(edn); m != nil {
+		f.TitleFormat = m[1]
+	}
+	return f
+}
+
+// stripEDNComments removes ; line comments, leaving semicolons inside strings
+func stripEDNComments(edn string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(edn, "\n") {
+		inString := false
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+				continue
+			case '"':
+				inString = !inString
+			case ';':
+				if !inString {
+					line = line[:i]
+				}
+			}
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Filename returns the journal filename for a date (e.g. "2025_01_28.md")
+func (f JournalFormat) Filename(date time.Time) string {
+	format := f.FileFormat
+	if format == "" {
+		format = defaultJournalFileFormat
+	}
+	return formatLogseqDate(date, format) + ".md"
+}
+
+// PageTitle returns the journal page name for a date, for [[date]] references
+func (f JournalFormat) PageTitle(date time.Time) string {
+	format := f.TitleFormat
+	if format == "" {
+		format = defaultJournalTitleFormat
+	}
+	return formatLogseqDate(date, format)
+}
+
+// formatLogseqDate formats a date with a Logseq (date-fns style) pattern.
+// Text in single quotes is literal; unrecognized letters pass through.
+func formatLogseqDate(date time.Time, pattern string) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				sb.WriteString(pattern[i+1:])
+				break
+			}
+			sb.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		j := i
+		for j < len(pattern) && pattern[j] == c {
+			j++
+		}
+		token := pattern[i:j]
+		if token == "d" && strings.HasPrefix(pattern[j:], "o") {
+			sb.WriteString(ordinal(date.Day()))
+			i = j + 1
+			continue
+		}
+		sb.WriteString(dateToken(date, token))
+		i = j
+	}
+	return sb.String()
+}
+
+// dateToken formats a single run of a pattern letter
+func dateToken(date time.Time, token string) string {
+	switch token {
+	case "yyyy", "YYYY":
+		return date.Format("2006")
+	case "yy", "YY":
+		return date.Format("06")
+	case "MMMM":
+		return date.Format("January")
+	case "MMM":
+		return date.Format("Jan")
+	case "MM":
+		return date.Format("01")
+	case "M":
+		return date.Format("1")
+	case "dd":
+		return date.Format("02")
+	case "d":
+		return date.Format("2")
+	case "EEEE":
+		return date.Format("Monday")
+	case "EEE", "EE", "E":
+		return date.Format("Mon")
+	default:
+		return token
+	}
+}
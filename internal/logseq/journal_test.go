@@ -0,0 +1,97 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JournalSuite struct {
+	suite.Suite
+}
+
+func TestJournalSuite(t *testing.T) {
+	suite.Run(t, new(JournalSuite))
+}
+
+func (s *JournalSuite) TestFormatLogseqDate() {
+	date := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"yyyy_MM_dd", "2025_01_02"},
+		{"yyyy-MM-dd", "2025-01-02"},
+		{"MMM do, yyyy", "Jan 2nd, 2025"},
+		{"MMMM d, yyyy", "January 2, 2025"},
+		{"EEE, MM/dd/yyyy", "Thu, 01/02/2025"},
+		{"EEEE dd.MM.yy", "Thursday 02.01.25"},
+		{"do MMM yyyy", "2nd Jan 2025"},
+		{"yyyyMMdd", "20250102"},
+		{"'week of' M/d", "week of 1/2"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.pattern, func() {
+			s.Equal(tt.expected, formatLogseqDate(date, tt.pattern))
+		})
+	}
+}
+
+func (s *JournalSuite) TestParseJournalFormat() {
+	tests := []struct {
+		name     string
+		edn      string
+		expected JournalFormat
+	}{
+		{
+			name:     "defaults_commented_out",
+			edn:      "{:meta/version 1\n ;; :journal/file-name-format \"yyyy_MM_dd\"\n :journal/page-title-format \"MMM do, yyyy\"}",
+			expected: JournalFormat{TitleFormat: "MMM do, yyyy"},
+		},
+		{
+			name:     "custom_formats",
+			edn:      "{:journal/page-title-format \"yyyy-MM-dd\" ; ISO\n :journal/file-name-format \"yyyy-MM-dd\"}",
+			expected: JournalFormat{FileFormat: "yyyy-MM-dd", TitleFormat: "yyyy-MM-dd"},
+		},
+		{
+			name:     "legacy_date_formatter",
+			edn:      "{:date-formatter \"EEE, MM/dd/yyyy\"}",
+			expected: JournalFormat{TitleFormat: "EEE, MM/dd/yyyy"},
+		},
+		{
+			name:     "semicolon_in_string",
+			edn:      "{:default-queries \"a;b\" :journal/file-name-format \"yyyy.MM.dd\"}",
+			expected: JournalFormat{FileFormat: "yyyy.MM.dd"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, parseJournalFormat(tt.edn))
+		})
+	}
+}
+
+func (s *JournalSuite) TestReadJournalFormat() {
+	dir := s.T().TempDir()
+
+	// No config.edn keeps the defaults
+	f, err := ReadJournalFormat(OSFS, dir)
+	s.Require().NoError(err)
+	s.Equal(JournalFormat{}, f)
+	date := time.Date(2025, 1, 28, 0, 0, 0, 0, time.UTC)
+	s.Equal("2025_01_28.md", f.Filename(date))
+	s.Equal("2025-01-28", f.PageTitle(date))
+
+	s.Require().NoError(os.MkdirAll(filepath.Join(dir, "logseq"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "logseq", "config.edn"),
+		[]byte("{:journal/file-name-format \"yyyy-MM-dd\"\n :journal/page-title-format \"MMM do, yyyy\"}\n"), 0o644))
+	f, err = ReadJournalFormat(OSFS, dir)
+	s.Require().NoError(err)
+	s.Equal("2025-01-28.md", f.Filename(date))
+	s.Equal("Jan 28th, 2025", f.PageTitle(date))
+}
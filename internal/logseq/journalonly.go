@@ -0,0 +1,139 @@
+package logseq
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// Journal-only mode writes each meeting's notes as a block in the day's journal
+// instead of a page of its own. The block carries the meeting's granola-id::
+// property, so later syncs replace it in place.
+
+// WriteJournalMeeting writes a meeting's notes into its journal, replacing the
+// meeting's block if the journal already has one. Returns the journal path and
+// whether the block is new.
+func (w *Writer) WriteJournalMeeting(doc *granola.Document) (string, bool, error) {
+	journalPath := w.JournalPath(doc)
+
+	existing, err := w.fs.ReadFile(journalPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", false, fmt.Errorf("reading journal: %w", err)
+	}
+
+	content, replaced := replaceMeetingBlock(string(existing), doc.ID, w.formatJournalMeeting(doc), w.opts.JournalHeading)
+	if err := w.fs.WriteFile(journalPath, []byte(content), 0o644); err != nil {
+		return "", false, fmt.Errorf("writing journal: %w", err)
+	}
+	// The journal holds other blocks, so the archive gets a copy
+	if err := w.saveArchive(doc, ""); err != nil {
+		return "", false, fmt.Errorf("archiving meeting: %w", err)
+	}
+	return journalPath, !replaced, nil
+}
+
+// RemoveJournalMeeting removes a meeting's block from a journal, e.g. after the
+// meeting moved to another day. Returns true if a block was removed.
+func (w *Writer) RemoveJournalMeeting(journalPath, granolaID string) (bool, error) {
+	existing, err := w.fs.ReadFile(journalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading journal: %w", err)
+	}
+
+	content, removed := replaceMeetingBlock(string(existing), granolaID, "", "")
+	if !removed {
+		return false, nil
+	}
+	if err := w.fs.WriteFile(journalPath, []byte(content), 0o644); err != nil {
+		return false, fmt.Errorf("writing journal: %w", err)
+	}
+	return true, nil
+}
+
+// DryRunJournalMeeting returns the journal a meeting's block would be written
+// to, the block, and whether the journal already has a block for the meeting
+func (w *Writer) DryRunJournalMeeting(doc *granola.Document) (path, content string, exists bool) {
+	journalPath := w.JournalPath(doc)
+	if existing, err := w.fs.ReadFile(journalPath); err == nil {
+		start, _ := findMeetingBlock(strings.Split(string(existing), "\n"), doc.ID)
+		exists = start >= 0
+	}
+	return journalPath, w.formatJournalMeeting(doc), exists
+}
+
+// DryRunJournalContent returns a meeting's journal as it is and as it would be
+// after writing the meeting's block
+func (w *Writer) DryRunJournalContent(doc *granola.Document) (before, after string, err error) {
+	existing, err := w.fs.ReadFile(w.JournalPath(doc))
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("reading journal: %w", err)
+	}
+
+	dry, overlay := w.dryRun()
+	journalPath, _, err := dry.WriteJournalMeeting(doc)
+	if err != nil {
+		return "", "", err
+	}
+	written, err := overlay.ReadFile(journalPath)
+	if err != nil {
+		return "", "", fmt.Errorf("reading journal: %w", err)
+	}
+	return string(existing), string(written), nil
+}
+
+// JournalPath returns the path of the journal for a meeting's date
+func (w *Writer) JournalPath(doc *granola.Document) string {
+	return filepath.Join(w.basePath, "journals", GetJournalFilename(doc, w.opts.Journal))
+}
+
+// formatJournalMeeting renders a meeting as a journal block: the meeting page
+// with its properties on the title block, since journals can't hold frontmatter
+func (w *Writer) formatJournalMeeting(doc *granola.Document) string {
+	opts := w.opts
+	opts.PropertiesStyle = PropertiesBullet
+	return w.render(doc, opts)
+}
+
+// replaceMeetingBlock replaces the block carrying granolaID in journal content
+// with block, at the old block's depth, or else appends block under heading (see
+// appendUnderHeading). An empty block removes the meeting's block. Returns true
+// if an existing block was replaced.
+func replaceMeetingBlock(content, granolaID, block, heading string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	start, end := findMeetingBlock(lines, granolaID)
+	if start < 0 {
+		if block == "" {
+			return content, false
+		}
+		return appendUnderHeading(content, heading, block), false
+	}
+
+	var sb strings.Builder
+	for _, line := range lines[:start] {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(indentBlock(block, bulletDepth(lines[start])))
+	sb.WriteString(strings.Join(lines[end:], "\n"))
+	return sb.String(), true
+}
+
+// findMeetingBlock returns the line range [start, end) of the block whose
+// properties include granola-id:: granolaID, or -1 if there is none
+func findMeetingBlock(lines []string, granolaID string) (int, int) {
+	start := -1
+	for i, line := range lines {
+		if bulletDepth(line) >= 0 {
+			start = i
+		}
+		if start >= 0 && strings.TrimSpace(line) == "granola-id:: "+granolaID {
+			return start, blockEnd(lines, start)
+		}
+	}
+	return -1, -1
+}
@@ -0,0 +1,84 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JournalOnlySuite struct {
+	suite.Suite
+}
+
+func TestJournalOnlySuite(t *testing.T) {
+	suite.Run(t, new(JournalOnlySuite))
+}
+
+func (s *JournalOnlySuite) TestReplaceMeetingBlock() {
+	journal := "- Morning thoughts\n" +
+		"- Planning\n  granola-id:: doc-1\n\t- **Notes**\n\t\t- Old\n" +
+		"- Lunch with Sam\n"
+
+	tests := []struct {
+		name         string
+		content      string
+		id           string
+		block        string
+		heading      string
+		want         string
+		wantReplaced bool
+	}{
+		{
+			name:         "replaces block in place",
+			content:      journal,
+			id:           "doc-1",
+			block:        "- Planning\n  granola-id:: doc-1\n\t- **Notes**\n\t\t- New\n",
+			want:         "- Morning thoughts\n- Planning\n  granola-id:: doc-1\n\t- **Notes**\n\t\t- New\n- Lunch with Sam\n",
+			wantReplaced: true,
+		},
+		{
+			name:         "removes block",
+			content:      journal,
+			id:           "doc-1",
+			want:         "- Morning thoughts\n- Lunch with Sam\n",
+			wantReplaced: true,
+		},
+		{
+			name:    "appends new block",
+			content: "- Morning thoughts",
+			id:      "doc-2",
+			block:   "- Retro\n  granola-id:: doc-2\n",
+			want:    "- Morning thoughts\n- Retro\n  granola-id:: doc-2\n",
+		},
+		{
+			name:    "appends new block under heading",
+			content: "- Morning thoughts\n",
+			id:      "doc-2",
+			block:   "- Retro\n  granola-id:: doc-2\n",
+			heading: "📅 **Meetings**",
+			want:    "- Morning thoughts\n- 📅 **Meetings**\n\t- Retro\n\t  granola-id:: doc-2\n",
+		},
+		{
+			name:         "replaces nested block at its depth",
+			content:      "- 📅 **Meetings**\n\t- Planning\n\t  granola-id:: doc-1\n\t\t- Old\n\t- Retro\n\t  granola-id:: doc-2\n",
+			id:           "doc-1",
+			block:        "- Planning\n  granola-id:: doc-1\n\t- New\n",
+			want:         "- 📅 **Meetings**\n\t- Planning\n\t  granola-id:: doc-1\n\t\t- New\n\t- Retro\n\t  granola-id:: doc-2\n",
+			wantReplaced: true,
+		},
+		{
+			name:    "ignores id prefix",
+			content: journal,
+			id:      "doc",
+			want:    journal,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, replaced := replaceMeetingBlock(tt.content, tt.id, tt.block, tt.heading)
+			s.Equal(tt.want, got)
+			s.Equal(tt.wantReplaced, replaced)
+		})
+	}
+}
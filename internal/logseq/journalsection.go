@@ -0,0 +1,103 @@
+package logseq
+
+import (
+	"strings"
+)
+
+// A journal heading is a top-level block (e.g. "- 📅 **Meetings**") that meeting
+// entries are nested under instead of landing at the top level of the journal.
+
+// normalizeHeading returns a journal heading without the bullet a user may have
+// included
+func normalizeHeading(heading string) string {
+	return strings.TrimPrefix(strings.TrimSpace(heading), "- ")
+}
+
+// appendUnderHeading appends block to journal content, nested under the
+// top-level heading block, which is added at the end if the journal has none.
+// An empty heading appends block at the top level.
+func appendUnderHeading(content, heading, block string) string {
+	heading = normalizeHeading(heading)
+	if heading != "" {
+		lines := strings.Split(content, "\n")
+		if start := findHeading(lines, heading); start >= 0 {
+			end := blockEnd(lines, start)
+			// Keep blank lines that separate the section from the next block
+			for end > start+1 && lines[end-1] == "" {
+				end--
+			}
+			var sb strings.Builder
+			for _, line := range lines[:end] {
+				sb.WriteString(line + "\n")
+			}
+			sb.WriteString(indentBlock(block, 1))
+			sb.WriteString(strings.Join(lines[end:], "\n"))
+			return sb.String()
+		}
+		block = "- " + heading + "\n" + indentBlock(block, 1)
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + block
+}
+
+// findHeading returns the line of the top-level heading block, or -1
+func findHeading(lines []string, heading string) int {
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") == "- "+heading {
+			return i
+		}
+	}
+	return -1
+}
+
+// blockEnd returns the line after the block starting at lines[start]: the next
+// bullet at the same or a shallower depth, or unindented content
+func blockEnd(lines []string, start int) int {
+	depth := bulletDepth(lines[start])
+	end := start + 1
+	for end < len(lines) {
+		line := lines[end]
+		if isTopLevelLine(line) {
+			break
+		}
+		if d := bulletDepth(line); d >= 0 && d <= depth {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// bulletDepth returns how many tabs a bullet line is indented by, or -1 if the
+// line isn't a bullet
+func bulletDepth(line string) int {
+	trimmed := strings.TrimLeft(line, "\t")
+	if !strings.HasPrefix(trimmed, "- ") {
+		return -1
+	}
+	return len(line) - len(trimmed)
+}
+
+// indentBlock indents every non-empty line of block by depth tabs
+func indentBlock(block string, depth int) string {
+	if depth == 0 {
+		return block
+	}
+	prefix := strings.Repeat("\t", depth)
+	lines := strings.Split(block, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isTopLevelLine reports whether a journal line starts a new top-level block
+// (or is other unindented content)
+func isTopLevelLine(line string) bool {
+	return line != "" && line[0] != ' ' && line[0] != '\t'
+}
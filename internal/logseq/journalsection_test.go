@@ -0,0 +1,62 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JournalSectionSuite struct {
+	suite.Suite
+}
+
+func TestJournalSectionSuite(t *testing.T) {
+	suite.Run(t, new(JournalSectionSuite))
+}
+
+func (s *JournalSectionSuite) TestAppendUnderHeading() {
+	entry := "- [[meetings/2025-01-28/Standup]]\n\t- 9:00 AM\n"
+
+	tests := []struct {
+		name    string
+		content string
+		heading string
+		want    string
+	}{
+		{
+			name:    "no heading appends at top level",
+			content: "- Morning thoughts",
+			want:    "- Morning thoughts\n- [[meetings/2025-01-28/Standup]]\n\t- 9:00 AM\n",
+		},
+		{
+			name:    "adds missing heading",
+			content: "- Morning thoughts\n",
+			heading: "📅 **Meetings**",
+			want:    "- Morning thoughts\n- 📅 **Meetings**\n\t- [[meetings/2025-01-28/Standup]]\n\t\t- 9:00 AM\n",
+		},
+		{
+			name:    "heading given with bullet",
+			content: "",
+			heading: "- 📅 **Meetings**",
+			want:    "- 📅 **Meetings**\n\t- [[meetings/2025-01-28/Standup]]\n\t\t- 9:00 AM\n",
+		},
+		{
+			name:    "appends after existing entries",
+			content: "- 📅 **Meetings**\n\t- [[meetings/2025-01-28/Retro]]\n\n- Evening notes\n",
+			heading: "📅 **Meetings**",
+			want:    "- 📅 **Meetings**\n\t- [[meetings/2025-01-28/Retro]]\n\t- [[meetings/2025-01-28/Standup]]\n\t\t- 9:00 AM\n\n- Evening notes\n",
+		},
+		{
+			name:    "heading with properties",
+			content: "- 📅 **Meetings**\n  collapsed:: true\n",
+			heading: "📅 **Meetings**",
+			want:    "- 📅 **Meetings**\n  collapsed:: true\n\t- [[meetings/2025-01-28/Standup]]\n\t\t- 9:00 AM\n",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, appendUnderHeading(tt.content, tt.heading, entry))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package logseq
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// minLanguageTrigrams is how much text DetectLanguage needs before it guesses
+const minLanguageTrigrams = 60
+
+// languageProfiles are the most frequent character trigrams of each language,
+// most frequent first (spaces mark word boundaries)
+var languageProfiles = map[string][]string{
+	"en": {" th", "the", "he ", "and", " an", "nd ", "ing", " to", "to ", "ng ", "of ", " of", "ed ", "er ", "ion",
+		" in", "in ", "is ", " is", "es ", "re ", "tio", " co", "ent", "hat", "at ", "for", " fo", "or ", "on "},
+	"de": {"en ", "er ", " de", "der", "ie ", " di", "die", "ich", "ch ", "ein", "sch", "und", " un", "nd ", "den",
+		" ei", "te ", "che", "ine", "gen", "es ", "ten", " da", "ung", "cht", "ist", " is", " zu", "st ", "ns "},
+	"fr": {"es ", " de", "de ", "le ", "ent", " le", "nt ", "la ", " la", "re ", "ion", "les", " et", "et ", "on ",
+		"tio", "des", " pa", "que", " qu", "ue ", "ne ", "our", " po", "ait", "est", " un", "pou", " êt", "eme"},
+	"es": {"de ", " de", "os ", " la", "la ", "el ", " el", "que", " qu", "ue ", "en ", "as ", "ent", " en", "ión",
+		"ón ", "ado", " co", "aci", "con", "ara", " pa", "par", "est", "nte", "los", " lo", "por", " po", " es"},
+	"it": {"di ", " di", "la ", "che", " ch", "he ", "to ", "ell", "lla", "del", " de", "one", "ion", "ne ", "per",
+		" pe", "zio", "are", "le ", " co", "con", "ent", "nte", "no ", " la", "ato", "ta ", " il", "il ", " in"},
+	"nl": {"en ", "de ", " de", "van", " va", "an ", "et ", "het", " he", "een", " ee", "er ", "ijk", "oor", "aar",
+		" ge", "ver", "ing", "ng ", " in", "in ", "te ", "nd ", " vo", "voo", "dat", " da", " we", " me", "ij "},
+	"pt": {"de ", " de", "os ", "que", " qu", "ue ", "do ", " do", "da ", " da", "ção", "ão ", "ent", " co", "com",
+		"es ", " pa", "par", "ara", "as ", "nte", " se", "em ", " em", "ado", "est", "não", " nã", "ra ", "mos"},
+}
+
+// DetectLanguage guesses the dominant language of text by scoring its character
+// trigrams against small per-language profiles. Returns an ISO 639-1 code, or
+// empty string if the text is too short or no language stands out.
+func DetectLanguage(text string) string {
+	counts, total := trigramCounts(text)
+	if total < minLanguageTrigrams {
+		return ""
+	}
+
+	best, bestScore, second := "", 0.0, 0.0
+	for lang, profile := range languageProfiles {
+		score := 0.0
+		for rank, trigram := range profile {
+			// Frequent trigrams count for more
+			score += float64(counts[trigram]) * float64(len(profile)-rank)
+		}
+		score /= float64(total)
+		switch {
+		case score > bestScore:
+			best, bestScore, second = lang, score, bestScore
+		case score > second:
+			second = score
+		}
+	}
+
+	// Require a clear winner so mixed or unsupported languages get no property
+	if bestScore == 0 || bestScore < second*1.2 {
+		return ""
+	}
+	return best
+}
+
+// trigramCounts counts the character trigrams of each word in text, lowercased
+// and padded with spaces
+func trigramCounts(text string) (map[string]int, int) {
+	counts := make(map[string]int)
+	total := 0
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+			total++
+		}
+	}
+	return counts, total
+}
+
+// noteLanguage returns the detected language of a meeting's notes, or empty
+// string if detection is off or inconclusive
+func noteLanguage(doc *granola.Document, opts FormatOptions) string {
+	if !opts.DetectLanguage {
+		return ""
+	}
+	return DetectLanguage(notesText(doc))
+}
@@ -0,0 +1,75 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type LanguageSuite struct {
+	suite.Suite
+}
+
+func TestLanguageSuite(t *testing.T) {
+	suite.Run(t, new(LanguageSuite))
+}
+
+func (s *LanguageSuite) TestDetectLanguage() {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english",
+			text: "We discussed the roadmap for the next quarter and agreed that the team should focus on the onboarding flow. " +
+				"Alice will write the proposal and share it with the rest of the group before the end of the week.",
+			want: "en",
+		},
+		{
+			name: "german",
+			text: "Wir haben die Planung für das nächste Quartal besprochen und sind uns einig, dass sich das Team auf die " +
+				"Einarbeitung der neuen Kunden konzentrieren sollte. Alice schreibt den Vorschlag und schickt ihn bis Freitag an die Gruppe.",
+			want: "de",
+		},
+		{
+			name: "french",
+			text: "Nous avons discuté de la feuille de route pour le prochain trimestre et nous sommes convenus que l'équipe " +
+				"devrait se concentrer sur les parcours des nouveaux clients. Alice va rédiger la proposition et la partager avec le groupe.",
+			want: "fr",
+		},
+		{
+			name: "spanish",
+			text: "Hablamos de la planificación para el próximo trimestre y acordamos que el equipo debe centrarse en la " +
+				"incorporación de los nuevos clientes. Alice va a escribir la propuesta y la compartirá con el resto del grupo.",
+			want: "es",
+		},
+		{
+			name: "too short",
+			text: "Quick sync",
+			want: "",
+		},
+		{
+			name: "empty",
+			text: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, DetectLanguage(tt.text))
+		})
+	}
+}
+
+func (s *LanguageSuite) TestFormatMeetingPageLang() {
+	notes := "Wir haben die Planung für das nächste Quartal besprochen und sind uns einig, dass sich das Team " +
+		"auf die Einarbeitung der neuen Kunden konzentrieren sollte. Alice schreibt den Vorschlag bis Freitag."
+	doc := &granola.Document{ID: "doc-1", Title: "Planung", NotesMarkdown: &notes}
+
+	s.Contains(FormatMeetingPage(doc, FormatOptions{DetectLanguage: true}), "lang:: de")
+	s.NotContains(FormatMeetingPage(doc, FormatOptions{}), "lang::")
+}
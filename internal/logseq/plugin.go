@@ -0,0 +1,114 @@
+package logseq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// pluginTimeout is how long a page plugin may take to transform one page
+const pluginTimeout = 30 * time.Second
+
+// PageTransformer changes a meeting's rendered content before it is written
+type PageTransformer interface {
+	// Transform returns the content to write for a meeting rendered with the
+	// given properties style
+	Transform(doc *granola.Document, content, propertiesStyle string) string
+}
+
+// PluginRequest is what a page plugin reads on stdin.
+type PluginRequest struct {
+	Document        *granola.Document `json:"document"`
+	PageName        string            `json:"page_name"`
+	PropertiesStyle string            `json:"properties_style"`
+	Content         string            `json:"content"`
+}
+
+// PluginResponse is what a page plugin writes on stdout.
+type PluginResponse struct {
+	Content *string `json:"content"`
+}
+
+// CommandPlugins transforms pages with a chain of shell commands, each reading a
+// PluginRequest as JSON on stdin and writing a PluginResponse on stdout. A
+// plugin that fails is skipped with a warning, so a broken plugin never stops
+// meetings syncing.
+type CommandPlugins struct {
+	commands         []string
+	pageNameTemplate string
+	// run executes a command with input on stdin; replaced in tests
+	run func(ctx context.Context, command string, input []byte) ([]byte, error)
+}
+
+// NewCommandPlugins creates a transformer running commands in order
+func NewCommandPlugins(commands []string, pageNameTemplate string) *CommandPlugins {
+	return &CommandPlugins{commands: commands, pageNameTemplate: pageNameTemplate, run: runPlugin}
+}
+
+// Transform runs the content through each plugin in turn
+func (p *CommandPlugins) Transform(doc *granola.Document, content, propertiesStyle string) string {
+	for _, command := range p.commands {
+		transformed, err := p.transform(command, doc, content, propertiesStyle)
+		if err != nil {
+			slog.Warn("page plugin failed, skipping it", "plugin", command, "title", doc.Title, "error", err)
+			continue
+		}
+		content = transformed
+	}
+	return content
+}
+
+func (p *CommandPlugins) transform(command string, doc *granola.Document, content, propertiesStyle string) (string, error) {
+	input, err := json.Marshal(PluginRequest{
+		Document:        doc,
+		PageName:        GetPageName(doc, p.pageNameTemplate),
+		PropertiesStyle: propertiesStyle,
+		Content:         content,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+	output, err := p.run(ctx, command, input)
+	if err != nil {
+		return "", err
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if resp.Content == nil {
+		return "", fmt.Errorf("response has no content")
+	}
+	return *resp.Content, nil
+}
+
+func runPlugin(ctx context.Context, command string, input []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// transformPage applies the options' page transformer, if any
+func transformPage(doc *granola.Document, content string, opts FormatOptions) string {
+	if opts.Transformer == nil {
+		return content
+	}
+	return opts.Transformer.Transform(doc, content, opts.PropertiesStyle)
+}
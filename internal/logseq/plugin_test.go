@@ -0,0 +1,86 @@
+package logseq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type PluginSuite struct {
+	suite.Suite
+	doc *granola.Document
+}
+
+func TestPluginSuite(t *testing.T) {
+	suite.Run(t, new(PluginSuite))
+}
+
+func (s *PluginSuite) SetupTest() {
+	s.doc = &granola.Document{ID: "doc-1", Title: "Planning"}
+}
+
+func (s *PluginSuite) TestChainsCommands() {
+	plugins := NewCommandPlugins([]string{
+		`sed 's/"content":"/"content":"first /'`,
+		`sed 's/"content":"/"content":"second /'`,
+	}, "")
+
+	s.Equal("second first - page", plugins.Transform(s.doc, "- page", PropertiesBullet))
+}
+
+func (s *PluginSuite) TestSendsRequest() {
+	plugins := NewCommandPlugins([]string{"plugin"}, "")
+	var req PluginRequest
+	plugins.run = func(ctx context.Context, command string, input []byte) ([]byte, error) {
+		s.Require().NoError(json.Unmarshal(input, &req))
+		return []byte(`{"content": "changed"}`), nil
+	}
+
+	s.Equal("changed", plugins.Transform(s.doc, "- page", PropertiesFrontmatter))
+	s.Equal("doc-1", req.Document.ID)
+	s.Equal(GetPageName(s.doc, ""), req.PageName)
+	s.Equal(PropertiesFrontmatter, req.PropertiesStyle)
+	s.Equal("- page", req.Content)
+}
+
+func (s *PluginSuite) TestSkipsFailingPlugins() {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+	}{
+		{name: "command fails", err: errors.New("exit status 1")},
+		{name: "invalid JSON", output: "not json"},
+		{name: "no content", output: `{}`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			plugins := NewCommandPlugins([]string{"broken", "working"}, "")
+			plugins.run = func(ctx context.Context, command string, input []byte) ([]byte, error) {
+				if command == "working" {
+					return []byte(`{"content": "from working"}`), nil
+				}
+				return []byte(tt.output), tt.err
+			}
+
+			s.Equal("from working", plugins.Transform(s.doc, "- page", PropertiesBullet))
+		})
+	}
+}
+
+func (s *PluginSuite) TestWriterAppliesTransformer() {
+	plugins := NewCommandPlugins([]string{"plugin"}, "")
+	plugins.run = func(ctx context.Context, command string, input []byte) ([]byte, error) {
+		return []byte(`{"content": "- plugged"}`), nil
+	}
+	w := NewWriter(s.T().TempDir(), "", FormatOptions{Transformer: plugins})
+
+	_, content := w.DryRunMeetingPage(s.doc)
+	s.Equal("- plugged", content)
+}
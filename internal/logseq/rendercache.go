@@ -0,0 +1,80 @@
+package logseq
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// RenderCache keeps recently rendered meetings so outputs that render a meeting
+// the same way (pages, journal blocks, the archive, graphs sharing a format)
+// don't repeat the work. Entries are keyed by the writer's template hash, the
+// properties style and the meeting's content hash, and the least recently used
+// are dropped beyond the cache's size. A nil cache renders every time.
+type RenderCache struct {
+	contentHash func(*granola.Document) string
+	size        int
+
+	mu      sync.Mutex
+	order   *list.List // Of *renderEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type renderEntry struct {
+	key     string
+	content string
+}
+
+// NewRenderCache creates a cache of up to size rendered meetings. contentHash
+// identifies a meeting's content; meetings are re-rendered when it changes.
+func NewRenderCache(size int, contentHash func(*granola.Document) string) *RenderCache {
+	return &RenderCache{
+		contentHash: contentHash,
+		size:        size,
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+	}
+}
+
+// render returns a meeting rendered by render, or its cached rendering with the
+// same template, properties style and tags. Tags are part of the key since
+// title tags depend on the other meetings in the cache.
+func (c *RenderCache) render(template string, doc *granola.Document, opts FormatOptions, render func() string) string {
+	if c == nil {
+		return render()
+	}
+
+	key := strings.Join([]string{
+		template,
+		opts.PropertiesStyle,
+		doc.ID,
+		doc.UpdatedAt.Format(time.RFC3339Nano),
+		c.contentHash(doc),
+		strings.Join(pageTags(doc, opts), ","),
+	}, "\x00")
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*renderEntry).content
+	}
+	c.mu.Unlock()
+
+	content := render()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.order.PushFront(&renderEntry{key: key, content: content})
+		for c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*renderEntry).key)
+		}
+	}
+	return content
+}
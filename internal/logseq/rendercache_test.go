@@ -0,0 +1,105 @@
+package logseq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type RenderCacheSuite struct {
+	suite.Suite
+	cache  *RenderCache
+	hashes map[string]string // Content hash by document ID
+}
+
+func TestRenderCacheSuite(t *testing.T) {
+	suite.Run(t, new(RenderCacheSuite))
+}
+
+func (s *RenderCacheSuite) SetupTest() {
+	s.hashes = map[string]string{"doc-1": "v1", "doc-2": "v1", "doc-3": "v1"}
+	s.cache = NewRenderCache(2, func(doc *granola.Document) string { return s.hashes[doc.ID] })
+}
+
+func (s *RenderCacheSuite) doc(id string) *granola.Document {
+	return &granola.Document{ID: id, Title: "Planning", UpdatedAt: time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)}
+}
+
+// renders renders doc through the cache and returns how many times it rendered
+func (s *RenderCacheSuite) renders(template string, doc *granola.Document, opts FormatOptions) int {
+	count := 0
+	s.cache.render(template, doc, opts, func() string {
+		count++
+		return "page"
+	})
+	return count
+}
+
+func (s *RenderCacheSuite) TestReusesRendering() {
+	doc := s.doc("doc-1")
+	s.Equal(1, s.renders("t", doc, FormatOptions{}))
+	s.Equal(0, s.renders("t", doc, FormatOptions{}))
+	s.Equal(0, s.renders("t", s.doc("doc-1"), FormatOptions{}), "a fresh parse of the same meeting")
+}
+
+func (s *RenderCacheSuite) TestKey() {
+	doc := s.doc("doc-1")
+	s.Equal(1, s.renders("t", doc, FormatOptions{}))
+
+	s.Equal(1, s.renders("other", doc, FormatOptions{}), "different template")
+	s.Equal(1, s.renders("t", doc, FormatOptions{PropertiesStyle: PropertiesFrontmatter}), "different properties style")
+
+	s.hashes["doc-1"] = "v2"
+	s.Equal(1, s.renders("t", doc, FormatOptions{}), "content changed")
+
+	doc.UpdatedAt = doc.UpdatedAt.Add(time.Minute)
+	s.Equal(1, s.renders("t", doc, FormatOptions{}), "updated in Granola")
+}
+
+func (s *RenderCacheSuite) TestTitleTagsInKey() {
+	tagger := NewTitleTagger(false, 2)
+	opts := FormatOptions{TitleTags: tagger}
+	doc := s.doc("doc-1")
+	s.Equal(1, s.renders("t", doc, opts))
+
+	// A second Planning meeting gives both the title tag
+	tagger.Count([]*granola.Document{doc, s.doc("doc-2")})
+	s.Equal(1, s.renders("t", doc, opts))
+}
+
+func (s *RenderCacheSuite) TestDropsLeastRecentlyUsed() {
+	s.renders("t", s.doc("doc-1"), FormatOptions{})
+	s.renders("t", s.doc("doc-2"), FormatOptions{})
+	s.renders("t", s.doc("doc-1"), FormatOptions{})
+	s.renders("t", s.doc("doc-3"), FormatOptions{})
+
+	s.Equal(0, s.renders("t", s.doc("doc-1"), FormatOptions{}))
+	s.Equal(1, s.renders("t", s.doc("doc-2"), FormatOptions{}))
+}
+
+func (s *RenderCacheSuite) TestNilCacheAlwaysRenders() {
+	var cache *RenderCache
+	count := 0
+	for range 2 {
+		cache.render("t", s.doc("doc-1"), FormatOptions{}, func() string {
+			count++
+			return "page"
+		})
+	}
+	s.Equal(2, count)
+}
+
+func (s *RenderCacheSuite) TestWritersShareRenderings() {
+	renders := NewRenderCache(10, func(doc *granola.Document) string { return "v1" })
+	page := NewWriter(s.T().TempDir(), "Test User", FormatOptions{})
+	page.SetRenderCache(renders, "t")
+	journal := NewWriter(s.T().TempDir(), "Test User", FormatOptions{})
+	journal.SetRenderCache(renders, "t")
+
+	doc := s.doc("doc-1")
+	s.Equal(page.formatPage(doc), journal.formatJournalMeeting(doc))
+	s.Equal(1, renders.order.Len(), "bullet pages and journal blocks render the same")
+}
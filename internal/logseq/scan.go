@@ -0,0 +1,166 @@
+package logseq
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+var (
+	// granolaIDRe matches the granola-id property written on meeting pages,
+	// either as a granola-id:: line or a (possibly quoted) frontmatter key
+	granolaIDRe = regexp.MustCompile(`(?m)^\s*granola-id::? *"?([^"\s]+)"?\s*$`)
+	// optOutRe matches the granola-sync:: ignore property a user adds to a page to
+	// stop sync updating it, as a property line or a frontmatter key
+	optOutRe = regexp.MustCompile(`(?mi)^\s*granola-sync::? *"?ignore"?\s*$`)
+	// nonWordRe matches runs of characters ignored when comparing titles
+	nonWordRe = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// Page is a markdown page read from the graph's pages directory
+type Page struct {
+	Path    string
+	Name    string
+	Content string
+}
+
+// GranolaID returns the page's granola-id:: property, or empty string if it has none
+func (p *Page) GranolaID() string {
+	if m := granolaIDRe.FindStringSubmatch(p.Content); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// IsOptedOut reports whether page content carries the granola-sync:: ignore
+// property, which tells sync to leave the page alone
+func IsOptedOut(content string) bool {
+	return optOutRe.MatchString(content)
+}
+
+// ScanPages reads every markdown page in the graph's pages directory through fsys
+func ScanPages(fsys FS, basePath string) ([]*Page, error) {
+	pagesDir := filepath.Join(basePath, "pages")
+	entries, err := fsys.ReadDir(pagesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading pages directory: %w", err)
+	}
+
+	var pages []*Page
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		pagePath := filepath.Join(pagesDir, entry.Name())
+		content, err := fsys.ReadFile(pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("reading page %s: %w", entry.Name(), err)
+		}
+		pages = append(pages, &Page{
+			Path:    pagePath,
+			Name:    pageNameFromFilename(entry.Name()),
+			Content: string(content),
+		})
+	}
+
+	return pages, nil
+}
+
+// ScanGranolaIDs scans the graph's pages directory for meeting pages and returns
+// a map of granola-id to page path. Pages written by another host (or before the
+// state store was lost) are found this way even though local state knows nothing
+// about them.
+func ScanGranolaIDs(fsys FS, basePath string) (map[string]string, error) {
+	pages, err := ScanPages(fsys, basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string)
+	for _, page := range pages {
+		if id := page.GranolaID(); id != "" {
+			ids[id] = page.Path
+		}
+	}
+
+	return ids, nil
+}
+
+// MatchMeetingPage finds a hand-made page for a meeting: one without a granola-id
+// whose name contains the meeting title and whose name or content mentions the
+// meeting date. Returns nil if no page matches.
+func MatchMeetingPage(pages []*Page, doc *granola.Document) *Page {
+	title := normalizeTitle(doc.Title)
+	if title == "" {
+		return nil
+	}
+	dates := dateForms(doc)
+
+	var matches []*Page
+	for _, page := range pages {
+		if page.GranolaID() != "" {
+			continue
+		}
+		if !strings.Contains(normalizeTitle(page.Name), title) {
+			continue
+		}
+		for _, date := range dates {
+			if strings.Contains(page.Name, date) || strings.Contains(page.Content, date) {
+				matches = append(matches, page)
+				break
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// Prefer the closest name match
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i].Name) != len(matches[j].Name) {
+			return len(matches[i].Name) < len(matches[j].Name)
+		}
+		return matches[i].Path < matches[j].Path
+	})
+	return matches[0]
+}
+
+// normalizeTitle lowercases a title and collapses punctuation to single spaces
+func normalizeTitle(title string) string {
+	return strings.TrimSpace(nonWordRe.ReplaceAllString(strings.ToLower(title), " "))
+}
+
+// dateForms returns the ways a hand-made page commonly references the meeting date
+func dateForms(doc *granola.Document) []string {
+	date := doc.GetMeetingDate()
+	return []string{
+		date.Format("2006-01-02"),
+		date.Format("2006_01_02"),
+		date.Format("2006/01/02"),
+		date.Format("Jan ") + ordinal(date.Day()) + date.Format(", 2006"), // Logseq's default journal title
+	}
+}
+
+// ordinal formats a day of month with its English suffix (1st, 2nd, 3rd, 4th...)
+func ordinal(day int) string {
+	suffix := "th"
+	switch {
+	case day%100 >= 11 && day%100 <= 13:
+	case day%10 == 1:
+		suffix = "st"
+	case day%10 == 2:
+		suffix = "nd"
+	case day%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
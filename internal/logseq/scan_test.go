@@ -0,0 +1,138 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type ScanSuite struct {
+	suite.Suite
+	basePath string
+}
+
+func TestScanSuite(t *testing.T) {
+	suite.Run(t, new(ScanSuite))
+}
+
+func (s *ScanSuite) SetupTest() {
+	s.basePath = s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.basePath, "pages"), 0o755))
+}
+
+func (s *ScanSuite) writePage(name, content string) string {
+	path := filepath.Join(s.basePath, "pages", name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func (s *ScanSuite) TestScanGranolaIDs() {
+	meeting := s.writePage("meetings___2025-01-28___Standup.md",
+		"- Standup\n  meeting-date:: [[2025-01-28]]\n  granola-id:: doc-123\n  tags:: [[Granola Notes]]\n")
+	s.writePage("Unrelated.md", "- Just a page\n")
+	s.writePage("notes.txt", "granola-id:: ignored\n")
+
+	ids, err := ScanGranolaIDs(OSFS, s.basePath)
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"doc-123": meeting}, ids)
+}
+
+func (s *ScanSuite) TestIsOptedOut() {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"page property", "granola-sync:: ignore\n\n- Planning\n", true},
+		{"bullet property", "- Planning\n  granola-id:: doc-1\n  granola-sync:: ignore\n", true},
+		{"frontmatter", "---\ngranola-id: doc-1\ngranola-sync: \"ignore\"\n---\n- Planning\n", true},
+		{"other value", "granola-sync:: manual\n", false},
+		{"mentioned in notes", "- Add granola-sync:: ignore to pages you edit\n", false},
+		{"no property", "- Planning\n", false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, IsOptedOut(tt.content))
+		})
+	}
+}
+
+func (s *ScanSuite) TestScanMissingPagesDir() {
+	ids, err := ScanGranolaIDs(OSFS, filepath.Join(s.basePath, "missing"))
+	s.NoError(err)
+	s.Empty(ids)
+}
+
+func (s *ScanSuite) TestMatchMeetingPage() {
+	doc := &granola.Document{
+		ID:        "doc-1",
+		Title:     "Roadmap Review",
+		CreatedAt: time.Date(2025, 1, 28, 10, 0, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		name  string
+		pages map[string]string // filename -> content
+		want  string            // matched filename, empty for no match
+	}{
+		{
+			name:  "date in page name",
+			pages: map[string]string{"2025-01-28 roadmap review.md": "- notes\n"},
+			want:  "2025-01-28 roadmap review.md",
+		},
+		{
+			name:  "date as journal link in content",
+			pages: map[string]string{"Roadmap Review.md": "- [[Jan 28th, 2025]]\n- notes\n"},
+			want:  "Roadmap Review.md",
+		},
+		{
+			name:  "wrong date",
+			pages: map[string]string{"Roadmap Review.md": "- [[Jan 29th, 2025]]\n"},
+			want:  "",
+		},
+		{
+			name:  "generated page is not adopted again",
+			pages: map[string]string{"meetings___2025-01-28___Roadmap Review.md": "- Roadmap Review\n  granola-id:: other\n"},
+			want:  "",
+		},
+		{
+			name: "prefers closest name",
+			pages: map[string]string{
+				"Roadmap Review 2025-01-28.md":           "",
+				"Roadmap Review follow-up 2025-01-28.md": "",
+			},
+			want: "Roadmap Review 2025-01-28.md",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			for name, content := range tt.pages {
+				s.writePage(name, content)
+			}
+			pages, err := ScanPages(OSFS, s.basePath)
+			s.Require().NoError(err)
+
+			match := MatchMeetingPage(pages, doc)
+			if tt.want == "" {
+				s.Nil(match)
+				return
+			}
+			s.Require().NotNil(match)
+			s.Equal(filepath.Join(s.basePath, "pages", tt.want), match.Path)
+		})
+	}
+}
+
+func (s *ScanSuite) TestOrdinal() {
+	for day, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 21: "21st", 22: "22nd", 31: "31st"} {
+		s.Equal(want, ordinal(day))
+	}
+}
@@ -0,0 +1,62 @@
+package logseq
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// Sidecars are JSON files next to meeting pages holding the meeting's structured
+// data (the same record as export --format json), so scripts can use it without
+// parsing Granola's cache.
+
+// SidecarPath returns the JSON sidecar file for a meeting page
+func SidecarPath(pagePath string) string {
+	return strings.TrimSuffix(pagePath, ".md") + ".json"
+}
+
+// SetSidecars makes the writer write a JSON sidecar next to each meeting page
+func (w *Writer) SetSidecars(enabled bool) {
+	w.sidecars = enabled
+}
+
+// writeSidecar writes a meeting's sidecar next to its page
+func (w *Writer) writeSidecar(doc *granola.Document, pagePath string) error {
+	data, err := w.SidecarJSON(doc)
+	if err != nil {
+		return err
+	}
+	if err := w.fs.WriteFile(SidecarPath(pagePath), data, 0o644); err != nil {
+		return fmt.Errorf("writing sidecar: %w", err)
+	}
+	return nil
+}
+
+// SidecarJSON returns a meeting's sidecar content, with attendees pseudonymized
+// like the page's
+func (w *Writer) SidecarJSON(doc *granola.Document) ([]byte, error) {
+	m := export.NewMeeting(doc)
+	for i, a := range m.Attendees {
+		anon := w.opts.Anonymizer.Attendee(granola.MeetingAttendee{Name: a.Name, Email: a.Email})
+		m.Attendees[i] = export.Attendee{Name: anon.Name, Email: anon.Email}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding sidecar: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// moveSidecar moves a renamed page's sidecar along with it, if it has one
+func (w *Writer) moveSidecar(oldPagePath, newPagePath string) error {
+	err := w.fs.Rename(SidecarPath(oldPagePath), SidecarPath(newPagePath))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("renaming sidecar: %w", err)
+	}
+	return nil
+}
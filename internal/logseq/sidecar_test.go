@@ -0,0 +1,100 @@
+package logseq
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type SidecarSuite struct {
+	suite.Suite
+	graphDir string
+}
+
+func TestSidecarSuite(t *testing.T) {
+	suite.Run(t, new(SidecarSuite))
+}
+
+func (s *SidecarSuite) SetupTest() {
+	s.graphDir = s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.graphDir, "pages"), 0o755))
+}
+
+func (s *SidecarSuite) meeting(title string) *granola.Document {
+	return &granola.Document{
+		ID:    "doc-1",
+		Title: title,
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Start:     &granola.EventTime{DateTime: "2025-01-28T10:00:00Z"},
+			Attendees: []granola.Attendee{{Email: "alice@example.com", DisplayName: "Alice"}},
+		},
+	}
+}
+
+// readSidecar decodes the sidecar of a page
+func (s *SidecarSuite) readSidecar(pagePath string) export.Meeting {
+	data, err := os.ReadFile(SidecarPath(pagePath))
+	s.Require().NoError(err)
+	var m export.Meeting
+	s.Require().NoError(json.Unmarshal(data, &m))
+	return m
+}
+
+func (s *SidecarSuite) TestSidecarPath() {
+	s.Equal("/g/pages/meetings___2025-01-28___Planning.json", SidecarPath("/g/pages/meetings___2025-01-28___Planning.md"))
+}
+
+func (s *SidecarSuite) TestWrite() {
+	w := NewWriter(s.graphDir, "", FormatOptions{})
+	w.SetSidecars(true)
+
+	pagePath, err := w.WriteMeetingPage(s.meeting("Planning"))
+	s.Require().NoError(err)
+
+	m := s.readSidecar(pagePath)
+	s.Equal("doc-1", m.ID)
+	s.Equal("Planning", m.Title)
+	s.Equal([]export.Attendee{{Name: "Alice", Email: "alice@example.com"}}, m.Attendees)
+}
+
+func (s *SidecarSuite) TestDisabled() {
+	pagePath, err := NewWriter(s.graphDir, "", FormatOptions{}).WriteMeetingPage(s.meeting("Planning"))
+	s.Require().NoError(err)
+	s.NoFileExists(SidecarPath(pagePath))
+}
+
+func (s *SidecarSuite) TestAnonymized() {
+	w := NewWriter(s.graphDir, "", FormatOptions{Anonymizer: NewAnonymizer("key", "")})
+	w.SetSidecars(true)
+
+	pagePath, err := w.WriteMeetingPage(s.meeting("Planning"))
+	s.Require().NoError(err)
+
+	m := s.readSidecar(pagePath)
+	s.Require().Len(m.Attendees, 1)
+	s.NotEqual("Alice", m.Attendees[0].Name)
+	s.NotEqual("alice@example.com", m.Attendees[0].Email)
+}
+
+func (s *SidecarSuite) TestMovedOnRename() {
+	w := NewWriter(s.graphDir, "", FormatOptions{})
+	w.SetSidecars(true)
+
+	oldPath, err := w.WriteMeetingPage(s.meeting("Planning"))
+	s.Require().NoError(err)
+
+	renamed := s.meeting("Roadmap Planning")
+	moved, err := w.RenameMeetingPage(oldPath, renamed)
+	s.Require().NoError(err)
+	s.True(moved)
+
+	newPath := filepath.Join(s.graphDir, "pages", GetPageFilename(renamed, ""))
+	s.NoFileExists(SidecarPath(oldPath))
+	s.FileExists(SidecarPath(newPath))
+}
@@ -0,0 +1,38 @@
+package logseq
+
+import (
+	"log/slog"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/spotlight"
+)
+
+// SetSpotlight makes the writer set Spotlight metadata (title, date, attendees
+// and tags as keywords) on meeting pages, so macOS search finds meetings by who
+// was in them
+func (w *Writer) SetSpotlight(enabled bool) {
+	w.spotlight = enabled
+}
+
+// SpotlightMetadata returns the Spotlight metadata for a meeting's page, with
+// attendees pseudonymized like the page's
+func (w *Writer) SpotlightMetadata(doc *granola.Document) spotlight.Metadata {
+	return spotlight.Metadata{
+		Title:     doc.Title,
+		Date:      doc.GetMeetingDate(),
+		Attendees: attendeeNames(doc, w.opts),
+		Tags:      pageTags(doc, w.opts),
+	}
+}
+
+// writeSpotlight sets a meeting page's Spotlight metadata. Only pages on the
+// local disk have it, and a filesystem without extended attributes only costs
+// the metadata, not the sync.
+func (w *Writer) writeSpotlight(doc *granola.Document, pagePath string) {
+	if !w.spotlight || w.fs != OSFS {
+		return
+	}
+	if err := spotlight.Write(pagePath, w.SpotlightMetadata(doc)); err != nil {
+		slog.Warn("setting Spotlight metadata failed", "path", pagePath, "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type SpotlightSuite struct {
+	suite.Suite
+	graphDir string
+}
+
+func TestSpotlightSuite(t *testing.T) {
+	suite.Run(t, new(SpotlightSuite))
+}
+
+func (s *SpotlightSuite) SetupTest() {
+	s.graphDir = s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.graphDir, "pages"), 0o755))
+}
+
+func (s *SpotlightSuite) meeting() *granola.Document {
+	return &granola.Document{
+		ID:    "doc-1",
+		Title: "Planning",
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Start:     &granola.EventTime{DateTime: "2025-01-28T10:00:00Z"},
+			Attendees: []granola.Attendee{{Email: "alice@example.com", DisplayName: "Alice"}},
+		},
+	}
+}
+
+func (s *SpotlightSuite) TestMetadata() {
+	w := NewWriter(s.graphDir, "", FormatOptions{})
+
+	m := w.SpotlightMetadata(s.meeting())
+
+	s.Equal("Planning", m.Title)
+	s.True(m.Date.Equal(time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)))
+	s.Equal([]string{"Alice"}, m.Attendees)
+	s.Equal(w.PageTags(s.meeting()), m.Tags)
+}
+
+func (s *SpotlightSuite) TestMetadataAnonymized() {
+	w := NewWriter(s.graphDir, "", FormatOptions{Anonymizer: NewAnonymizer("key", "")})
+
+	m := w.SpotlightMetadata(s.meeting())
+
+	s.Require().Len(m.Attendees, 1)
+	s.NotEqual("Alice", m.Attendees[0])
+}
+
+func (s *SpotlightSuite) TestWriteMeetingPage() {
+	w := NewWriter(s.graphDir, "", FormatOptions{})
+	w.SetSpotlight(true)
+
+	pagePath, err := w.WriteMeetingPage(s.meeting())
+	s.Require().NoError(err)
+
+	s.FileExists(pagePath)
+}
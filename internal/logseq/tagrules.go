@@ -0,0 +1,75 @@
+package logseq
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// TagRule adds Tags to meetings with an attendee from one of Domains (or a
+// subdomain), or whose title matches one of Titles
+type TagRule struct {
+	Tags    []string
+	Domains []string
+	Titles  []*regexp.Regexp
+}
+
+// TagRules tags meeting pages from configured rules, on top of the derived
+// title tag. Every matching rule applies. A nil TagRules adds no tags.
+type TagRules struct {
+	rules []TagRule
+}
+
+// NewTagRules creates tag rules
+func NewTagRules(rules []TagRule) *TagRules {
+	return &TagRules{rules: rules}
+}
+
+// Tags returns the tags of every rule a meeting matches, in rule order
+func (r *TagRules) Tags(doc *granola.Document) []string {
+	if r == nil {
+		return nil
+	}
+
+	var domains []string
+	for _, a := range doc.GetAttendees() {
+		if _, domain, ok := strings.Cut(a.Email, "@"); ok {
+			domains = append(domains, strings.ToLower(domain))
+		}
+	}
+
+	var tags []string
+	for _, rule := range r.rules {
+		if matchesDomain(domains, rule.Domains) || matchesTitle(doc.Title, rule.Titles) {
+			tags = append(tags, rule.Tags...)
+		}
+	}
+	return tags
+}
+
+// matchesTitle reports whether title matches any of patterns
+func matchesTitle(title string, patterns []*regexp.Regexp) bool {
+	for _, p := range patterns {
+		if p.MatchString(title) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendTags appends the tags not already in list, compared case-insensitively
+// like Logseq page names
+func appendTags(list []string, tags ...string) []string {
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !slices.ContainsFunc(list, func(have string) bool { return strings.EqualFold(have, t) }) {
+			list = append(list, t)
+		}
+	}
+	return list
+}
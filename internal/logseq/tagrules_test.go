@@ -0,0 +1,70 @@
+package logseq
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type TagRulesSuite struct {
+	suite.Suite
+}
+
+func TestTagRulesSuite(t *testing.T) {
+	suite.Run(t, new(TagRulesSuite))
+}
+
+func (s *TagRulesSuite) rules() *TagRules {
+	return NewTagRules([]TagRule{
+		{Tags: []string{"Engineering", "Sprint"}, Titles: []*regexp.Regexp{regexp.MustCompile("(?i)^sprint")}},
+		{Tags: []string{"Acme"}, Domains: []string{"acme.com"}},
+	})
+}
+
+func (s *TagRulesSuite) meeting(title, email string) *granola.Document {
+	return &granola.Document{
+		ID:    "doc-1",
+		Title: title,
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			Attendees: []granola.Attendee{{Email: email}},
+		},
+	}
+}
+
+func (s *TagRulesSuite) TestTags() {
+	tests := []struct {
+		name  string
+		rules *TagRules
+		doc   *granola.Document
+		want  []string
+	}{
+		{"nil adds none", nil, s.meeting("Sprint Planning", "bob@acme.com"), nil},
+		{"title match", s.rules(), s.meeting("Sprint Planning", "bob@example.com"), []string{"Engineering", "Sprint"}},
+		{"subdomain match", s.rules(), s.meeting("Intro", "bob@eu.acme.com"), []string{"Acme"}},
+		{"every matching rule", s.rules(), s.meeting("sprint review", "bob@acme.com"), []string{"Engineering", "Sprint", "Acme"}},
+		{"no match", s.rules(), s.meeting("Retro", "bob@example.com"), nil},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.rules.Tags(tt.doc))
+		})
+	}
+}
+
+func (s *TagRulesSuite) TestFormatMeetingPage() {
+	opts := FormatOptions{
+		TagRules: NewTagRules([]TagRule{
+			{Tags: []string{"Sprint Planning", "Engineering"}, Titles: []*regexp.Regexp{regexp.MustCompile("(?i)^sprint")}},
+		}),
+		Tags: NewTagFilter(nil, []string{"Engineering"}),
+	}
+
+	content := FormatMeetingPage(s.meeting("Sprint Planning", "bob@example.com"), opts)
+
+	// Tags already on the page aren't repeated, and tag filters still apply
+	s.Contains(content, "tags:: [[Granola Notes]], [[Sprint Planning]]\n")
+}
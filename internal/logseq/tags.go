@@ -0,0 +1,63 @@
+package logseq
+
+import "strings"
+
+// defaultTag is the tag on every meeting page. Tag filters never remove it.
+const defaultTag = "Granola Notes"
+
+// CalendarBlockTag is the tag on pages of focus time and other calendar blocks
+// when they are tagged rather than synced like meetings
+const CalendarBlockTag = "calendar-block"
+
+// TagFilter limits which tags meeting pages get, so stray tags don't create
+// pages in the graph. Tags are compared case-insensitively, since Logseq page
+// names are. A nil TagFilter allows every tag.
+type TagFilter struct {
+	allow map[string]bool // nil allows any tag not denied
+	deny  map[string]bool
+}
+
+// NewTagFilter creates a filter that allows only the tags in allow (or any tag
+// if allow is empty), except those in deny
+func NewTagFilter(allow, deny []string) *TagFilter {
+	f := &TagFilter{deny: tagSet(deny)}
+	if len(allow) > 0 {
+		f.allow = tagSet(allow)
+	}
+	return f
+}
+
+// tagSet returns a set of lowercased tags
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		set[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+	return set
+}
+
+// Allowed reports whether pages may be tagged with tag
+func (f *TagFilter) Allowed(tag string) bool {
+	if f == nil || strings.EqualFold(tag, defaultTag) {
+		return true
+	}
+	key := strings.ToLower(tag)
+	if f.deny[key] {
+		return false
+	}
+	return f.allow == nil || f.allow[key]
+}
+
+// Filter returns the allowed tags, in order
+func (f *TagFilter) Filter(tags []string) []string {
+	if f == nil {
+		return tags
+	}
+	allowed := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if f.Allowed(t) {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,48 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type TagFilterSuite struct {
+	suite.Suite
+}
+
+func TestTagFilterSuite(t *testing.T) {
+	suite.Run(t, new(TagFilterSuite))
+}
+
+func (s *TagFilterSuite) TestAllowed() {
+	tests := []struct {
+		name   string
+		filter *TagFilter
+		tag    string
+		want   bool
+	}{
+		{"nil allows all", nil, "Anything", true},
+		{"allowlisted", NewTagFilter([]string{"project review"}, nil), "Project Review", true},
+		{"not allowlisted", NewTagFilter([]string{"Project Review"}, nil), "Projet Review", false},
+		{"denylisted", NewTagFilter(nil, []string{"Lunch"}), "lunch", false},
+		{"deny wins over allow", NewTagFilter([]string{"Lunch"}, []string{"Lunch"}), "Lunch", false},
+		{"default tag always allowed", NewTagFilter([]string{"Standup"}, []string{"Granola Notes"}), "Granola Notes", true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.filter.Allowed(tt.tag))
+		})
+	}
+}
+
+func (s *TagFilterSuite) TestFormatMeetingPage() {
+	doc := &granola.Document{ID: "doc-1", Title: "Projet Review"}
+	opts := FormatOptions{Tags: NewTagFilter([]string{"Project Review"}, nil)}
+
+	content := FormatMeetingPage(doc, opts)
+	s.Contains(content, "tags:: [[Granola Notes]]\n")
+	s.NotContains(content, "[[Projet Review]]")
+}
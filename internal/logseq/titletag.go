@@ -0,0 +1,48 @@
+package logseq
+
+import "github.com/philrhinehart/granola-sync/internal/granola"
+
+// TitleTagger decides which meetings get a tag derived from their title (e.g.
+// [[Project Review]]). A nil TitleTagger tags every meeting.
+type TitleTagger struct {
+	disabled    bool
+	minMeetings int            // Only tag titles shared by at least this many meetings
+	counts      map[string]int // Meetings per derived tag, from the last Count
+}
+
+// NewTitleTagger creates a tagger that tags no meetings if disabled, or else only
+// meetings whose derived tag is shared by at least minMeetings meetings (0 or 1
+// tags every meeting)
+func NewTitleTagger(disabled bool, minMeetings int) *TitleTagger {
+	return &TitleTagger{disabled: disabled, minMeetings: minMeetings}
+}
+
+// Count records how many meetings share each derived tag. Until it is called, a
+// tagger with a minimum tags nothing.
+func (t *TitleTagger) Count(docs []*granola.Document) {
+	if t == nil || t.disabled || t.minMeetings <= 1 {
+		return
+	}
+	t.counts = make(map[string]int)
+	for _, doc := range docs {
+		if doc.IsDeleted() {
+			continue
+		}
+		if tag := meetingTag(doc.Title); tag != "" {
+			t.counts[tag]++
+		}
+	}
+}
+
+// Tag returns the tag derived from a meeting title, or empty string if the
+// meeting shouldn't get one
+func (t *TitleTagger) Tag(title string) string {
+	tag := meetingTag(title)
+	if t == nil || tag == "" {
+		return tag
+	}
+	if t.disabled || (t.minMeetings > 1 && t.counts[tag] < t.minMeetings) {
+		return ""
+	}
+	return tag
+}
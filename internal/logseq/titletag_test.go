@@ -0,0 +1,54 @@
+package logseq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+type TitleTaggerSuite struct {
+	suite.Suite
+}
+
+func TestTitleTaggerSuite(t *testing.T) {
+	suite.Run(t, new(TitleTaggerSuite))
+}
+
+func (s *TitleTaggerSuite) TestTag() {
+	docs := []*granola.Document{
+		{ID: "1", Title: "Project Review 2025-01-27"},
+		{ID: "2", Title: "Project Review 2025-01-28"},
+		{ID: "3", Title: "Project Review 2025-01-29"},
+		{ID: "4", Title: "Vendor Intro"},
+	}
+
+	tests := []struct {
+		name   string
+		tagger *TitleTagger
+		title  string
+		want   string
+	}{
+		{"nil tags every meeting", nil, "Vendor Intro", "Vendor Intro"},
+		{"disabled", NewTitleTagger(true, 0), "Project Review 2025-01-27", ""},
+		{"recurring meets minimum", NewTitleTagger(false, 3), "Project Review 2025-02-03", "Project Review"},
+		{"one-off below minimum", NewTitleTagger(false, 3), "Vendor Intro", ""},
+		{"minimum of one tags every meeting", NewTitleTagger(false, 1), "Vendor Intro", "Vendor Intro"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.tagger.Count(docs)
+			s.Equal(tt.want, tt.tagger.Tag(tt.title))
+		})
+	}
+}
+
+func (s *TitleTaggerSuite) TestFormatMeetingPage() {
+	doc := &granola.Document{ID: "doc-1", Title: "Vendor Intro"}
+
+	content := FormatMeetingPage(doc, FormatOptions{TitleTags: NewTitleTagger(true, 0)})
+	s.Contains(content, "tags:: [[Granola Notes]]\n")
+	s.NotContains(content, "[[Vendor Intro]]")
+}
@@ -0,0 +1,94 @@
+package logseq
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Kinds of links HostResolver can unfurl
+const (
+	LinkGoogleDocs = "google_docs"
+	LinkFigma      = "figma"
+	LinkJira       = "jira"
+)
+
+var (
+	markdownLinkRe = regexp.MustCompile(`\[([^\[\]]+)\]\((https?://[^)\s]+)\)`)
+	jiraIssueRe    = regexp.MustCompile(`^/browse/([A-Z][A-Z0-9_]*-\d+)`)
+)
+
+// LinkResolver turns links in meeting notes into Logseq page references
+type LinkResolver interface {
+	// Resolve returns the page name a link should point at, or false to keep
+	// the markdown link
+	Resolve(text string, u *url.URL) (string, bool)
+}
+
+// UnfurlLinks replaces markdown links the resolver recognizes with [[page]]
+// references. A nil resolver leaves the content unchanged.
+func UnfurlLinks(content string, r LinkResolver) string {
+	if r == nil {
+		return content
+	}
+	return markdownLinkRe.ReplaceAllStringFunc(content, func(link string) string {
+		m := markdownLinkRe.FindStringSubmatch(link)
+		u, err := url.Parse(m[2])
+		if err != nil {
+			return link
+		}
+		page, ok := r.Resolve(m[1], u)
+		page = strings.TrimSpace(strings.NewReplacer("[", "", "]", "").Replace(page))
+		if !ok || page == "" {
+			return link
+		}
+		return "[[" + page + "]]"
+	})
+}
+
+// HostResolver unfurls links to well-known hosts without network access, naming
+// the page after the link text or the title or key embedded in the URL
+type HostResolver struct {
+	kinds map[string]bool
+}
+
+// NewHostResolver creates a resolver for the given link kinds (LinkGoogleDocs,
+// LinkFigma, LinkJira)
+func NewHostResolver(kinds []string) *HostResolver {
+	r := &HostResolver{kinds: make(map[string]bool)}
+	for _, k := range kinds {
+		r.kinds[k] = true
+	}
+	return r
+}
+
+// Resolve implements LinkResolver
+func (r *HostResolver) Resolve(text string, u *url.URL) (string, bool) {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	// Link text that is just the URL says nothing about the document
+	if text == u.String() {
+		text = ""
+	}
+
+	switch {
+	case host == "docs.google.com" && r.kinds[LinkGoogleDocs]:
+		return text, text != ""
+	case host == "figma.com" && r.kinds[LinkFigma]:
+		if text != "" {
+			return text, true
+		}
+		// figma.com/file/<key>/<File-Name>
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) >= 3 {
+			return strings.ReplaceAll(parts[2], "-", " "), true
+		}
+	case strings.HasSuffix(host, ".atlassian.net") && r.kinds[LinkJira]:
+		if m := jiraIssueRe.FindStringSubmatch(u.Path); m != nil {
+			return m[1], true
+		}
+		if issue := u.Query().Get("selectedIssue"); issue != "" {
+			return issue, true
+		}
+	}
+	return "", false
+}
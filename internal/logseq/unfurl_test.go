@@ -0,0 +1,82 @@
+package logseq
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UnfurlSuite struct {
+	suite.Suite
+}
+
+func TestUnfurlSuite(t *testing.T) {
+	suite.Run(t, new(UnfurlSuite))
+}
+
+func (s *UnfurlSuite) TestUnfurlLinks() {
+	resolver := NewHostResolver([]string{LinkGoogleDocs, LinkFigma, LinkJira})
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "google_doc_uses_link_text",
+			content:  "- Read [Q3 Plan](https://docs.google.com/document/d/abc/edit) first",
+			expected: "- Read [[Q3 Plan]] first",
+		},
+		{
+			name:     "google_doc_without_title_is_kept",
+			content:  "- [https://docs.google.com/document/d/abc](https://docs.google.com/document/d/abc)",
+			expected: "- [https://docs.google.com/document/d/abc](https://docs.google.com/document/d/abc)",
+		},
+		{
+			name:     "figma_name_from_url",
+			content:  "- [https://www.figma.com/file/XyZ/Checkout-Redesign](https://www.figma.com/file/XyZ/Checkout-Redesign)",
+			expected: "- [[Checkout Redesign]]",
+		},
+		{
+			name:     "jira_issue_key",
+			content:  "- Tracked in [this ticket](https://acme.atlassian.net/browse/PAY-142) and [board](https://acme.atlassian.net/jira/software/projects/PAY/boards/1?selectedIssue=PAY-7)",
+			expected: "- Tracked in [[PAY-142]] and [[PAY-7]]",
+		},
+		{
+			name:     "unknown_host_is_kept",
+			content:  "- [Blog](https://example.com/post)",
+			expected: "- [Blog](https://example.com/post)",
+		},
+		{
+			name:     "nested_brackets_are_kept",
+			content:  "- [Plan [v2]](https://docs.google.com/document/d/abc)",
+			expected: "- [Plan [v2]](https://docs.google.com/document/d/abc)",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, UnfurlLinks(tt.content, resolver))
+		})
+	}
+}
+
+func (s *UnfurlSuite) TestOnlyConfiguredKinds() {
+	content := "- [Spec](https://docs.google.com/document/d/abc) [PAY-1](https://acme.atlassian.net/browse/PAY-1)"
+	s.Equal("- [Spec](https://docs.google.com/document/d/abc) [[PAY-1]]", UnfurlLinks(content, NewHostResolver([]string{LinkJira})))
+	s.Equal(content, UnfurlLinks(content, nil))
+}
+
+// titleResolver is a custom resolver, as a plugin would provide
+type titleResolver map[string]string
+
+func (r titleResolver) Resolve(text string, u *url.URL) (string, bool) {
+	title, ok := r[u.String()]
+	return title, ok
+}
+
+func (s *UnfurlSuite) TestCustomResolver() {
+	r := titleResolver{"https://notion.so/abc": "Team Wiki"}
+	s.Equal("- [[Team Wiki]] and [other](https://notion.so/def)",
+		UnfurlLinks("- [wiki](https://notion.so/abc) and [other](https://notion.so/def)", r))
+}
@@ -11,93 +11,377 @@ import (
 
 // Writer handles writing Logseq pages and journal entries
 type Writer struct {
-	basePath string
-	userName string
+	fs        FS
+	basePath  string
+	userName  string
+	opts      FormatOptions
+	backups   *Backups // nil disables page backups
+	archive   *Archive // nil disables the markdown archive
+	sidecars  bool     // Write a JSON sidecar next to each meeting page
+	spotlight bool     // Set Spotlight metadata on meeting pages
+
+	// renders, if set, is shared with other writers; template identifies this
+	// writer's formatting options in it
+	renders  *RenderCache
+	template string
 }
 
 // NewWriter creates a new Logseq writer
-func NewWriter(basePath, userName string) *Writer {
-	return &Writer{basePath: basePath, userName: userName}
+func NewWriter(basePath, userName string, opts FormatOptions) *Writer {
+	return &Writer{fs: OSFS, basePath: basePath, userName: userName, opts: opts}
+}
+
+// SetFS makes the writer read and write the graph through fsys instead of the
+// local disk. Backups and the archive stay on the local disk.
+func (w *Writer) SetFS(fsys FS) {
+	w.fs = fsys
+}
+
+// SetBackups makes the writer back up meeting pages before overwriting them
+func (w *Writer) SetBackups(b *Backups) {
+	w.backups = b
+}
+
+// SetArchive makes the writer mirror meeting pages into a markdown archive
+func (w *Writer) SetArchive(a *Archive) {
+	w.archive = a
+}
+
+// CountTitleTags updates the meeting counts that decide which meetings get a tag
+// derived from their title
+func (w *Writer) CountTitleTags(docs []*granola.Document) {
+	w.opts.TitleTags.Count(docs)
+}
+
+// SetRenderCache shares rendered meetings with other writers through renders.
+// template must identify everything about the writer's options and user that
+// changes how a meeting renders, apart from the properties style.
+func (w *Writer) SetRenderCache(renders *RenderCache, template string) {
+	w.renders = renders
+	w.template = template
+}
+
+// SetIssueLinker makes the writer link action items to the issues filed for them
+func (w *Writer) SetIssueLinker(l IssueLinker) {
+	w.opts.Issues = l
 }
 
 // WriteMeetingPage creates or updates a meeting page
 func (w *Writer) WriteMeetingPage(doc *granola.Document) (string, error) {
-	filename := GetPageFilename(doc)
+	filename := GetPageFilename(doc, w.opts.PageNameTemplate)
 	pagePath := filepath.Join(w.basePath, "pages", filename)
 
-	content := FormatMeetingPage(doc)
-	content = MarkUserTodos(content, w.userName)
+	content := w.formatPage(doc)
 
-	if err := os.WriteFile(pagePath, []byte(content), 0o644); err != nil {
+	if err := w.backups.Save(w.fs, pagePath, content); err != nil {
+		return "", fmt.Errorf("backing up meeting page: %w", err)
+	}
+	if err := w.fs.WriteFile(pagePath, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("writing meeting page: %w", err)
 	}
+	if w.sidecars {
+		if err := w.writeSidecar(doc, pagePath); err != nil {
+			return "", err
+		}
+	}
+	// Replacing the page drops its attributes, so they're set on every write
+	w.writeSpotlight(doc, pagePath)
+	if err := w.saveArchive(doc, pagePath); err != nil {
+		return "", fmt.Errorf("archiving meeting page: %w", err)
+	}
 
 	return pagePath, nil
 }
 
+// saveArchive mirrors a meeting into the archive, if there is one. pagePath is
+// the page to hard-link, or empty if there is none.
+func (w *Writer) saveArchive(doc *granola.Document, pagePath string) error {
+	if w.archive == nil {
+		return nil
+	}
+	// Only a page on the local disk can be hard-linked
+	if w.fs != OSFS {
+		pagePath = ""
+	}
+	return w.archive.Save(doc, pagePath, w.ArchiveContent(doc))
+}
+
+// ArchiveContent renders a meeting for copies outside the graph, with its
+// properties as YAML frontmatter, which markdown tools understand
+func (w *Writer) ArchiveContent(doc *granola.Document) string {
+	opts := w.opts
+	opts.PropertiesStyle = PropertiesFrontmatter
+	return w.render(doc, opts)
+}
+
 // AppendJournalEntry adds a meeting reference to the journal
 // Returns true if an entry was added, false if it already existed
 func (w *Writer) AppendJournalEntry(doc *granola.Document) (bool, error) {
-	filename := GetJournalFilename(doc)
-	journalPath := filepath.Join(w.basePath, "journals", filename)
+	journalPath := w.JournalPath(doc)
 
 	// Read existing content
-	existingContent, err := os.ReadFile(journalPath)
+	existingContent, err := w.fs.ReadFile(journalPath)
 	if err != nil && !os.IsNotExist(err) {
 		return false, fmt.Errorf("reading journal: %w", err)
 	}
 
 	// Check if entry already exists
-	pageName := GetPageName(doc)
+	pageName := GetPageName(doc, w.opts.PageNameTemplate)
 	if strings.Contains(string(existingContent), pageName) {
 		return false, nil // Entry already exists
 	}
 
 	// Format new entry
-	entry := FormatJournalEntry(doc)
-
-	// Append to file
-	var newContent string
-	if len(existingContent) == 0 {
-		newContent = entry
-	} else {
-		// Add newline if needed
-		if !strings.HasSuffix(string(existingContent), "\n") {
-			newContent = string(existingContent) + "\n" + entry
-		} else {
-			newContent = string(existingContent) + entry
+	entry := FormatJournalEntry(doc, w.opts)
+
+	// Append to file, under the journal heading if there is one
+	newContent := appendUnderHeading(string(existingContent), w.opts.JournalHeading, entry)
+
+	if err := w.fs.WriteFile(journalPath, []byte(newContent), 0o644); err != nil {
+		return false, fmt.Errorf("writing journal: %w", err)
+	}
+
+	return true, nil
+}
+
+// RenameMeetingPage moves a previously written meeting page to the path for the
+// document's current title and rewrites journal links that pointed at the old page.
+// Returns true if a page was moved, or an error if another page already has the
+// new path.
+func (w *Writer) RenameMeetingPage(oldPath string, doc *granola.Document) (bool, error) {
+	newPath := filepath.Join(w.basePath, "pages", GetPageFilename(doc, w.opts.PageNameTemplate))
+	if oldPath == "" || oldPath == newPath {
+		return false, nil
+	}
+
+	if _, err := w.fs.Stat(oldPath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // Old page is gone, nothing to move
 		}
+		return false, fmt.Errorf("checking old page: %w", err)
 	}
 
-	if err := os.WriteFile(journalPath, []byte(newContent), 0o644); err != nil {
-		return false, fmt.Errorf("writing journal: %w", err)
+	// A rename that only changes case finds the old page itself on a
+	// case-insensitive disk
+	if !strings.EqualFold(oldPath, newPath) {
+		if _, err := w.fs.Stat(newPath); err == nil {
+			return false, fmt.Errorf("page %s already exists", newPath)
+		} else if !os.IsNotExist(err) {
+			return false, fmt.Errorf("checking new page: %w", err)
+		}
+	}
+
+	if err := w.fs.Rename(oldPath, newPath); err != nil {
+		return false, fmt.Errorf("renaming meeting page: %w", err)
+	}
+	if err := w.moveSidecar(oldPath, newPath); err != nil {
+		return true, err
+	}
+
+	oldName := pageNameFromFilename(filepath.Base(oldPath))
+	newName := GetPageName(doc, w.opts.PageNameTemplate)
+
+	// The meeting date may have moved along with the title, so check both journals
+	journals := []string{GetJournalFilename(doc, w.opts.Journal)}
+	if oldJournal := journalFilenameFromPageName(oldName, w.opts.Journal); oldJournal != "" && oldJournal != journals[0] {
+		journals = append(journals, oldJournal)
+	}
+	var linkPaths []string
+	for _, filename := range journals {
+		linkPaths = append(linkPaths, filepath.Join(w.basePath, "journals", filename))
+	}
+
+	// Person pages may also carry backlinks to the old page
+	for _, name := range attendeeNames(doc, w.opts) {
+		linkPaths = append(linkPaths, filepath.Join(w.basePath, "pages", GetPersonPageFilename(name)))
+	}
+
+	for _, path := range linkPaths {
+		if err := w.rewritePageLinks(path, oldName, newName); err != nil {
+			return true, err
+		}
 	}
 
 	return true, nil
 }
 
+// rewritePageLinks replaces links to oldName with links to newName in a journal or page file
+func (w *Writer) rewritePageLinks(path, oldName, newName string) error {
+	content, err := w.fs.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	oldLink := "[[" + oldName + "]]"
+	if !strings.Contains(string(content), oldLink) {
+		return nil
+	}
+
+	updated := strings.ReplaceAll(string(content), oldLink, "[["+newName+"]]")
+	if err := w.fs.WriteFile(path, []byte(updated), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// AppendPersonBacklinks adds a dated "met in" bullet to each attendee's person page.
+// The user's own page is skipped. Returns the number of pages that were updated.
+func (w *Writer) AppendPersonBacklinks(doc *granola.Document) (int, error) {
+	updated := 0
+	for _, name := range w.personPageNames(doc) {
+		added, err := w.appendPersonBacklink(name, doc)
+		if err != nil {
+			return updated, fmt.Errorf("updating person page for %s: %w", name, err)
+		}
+		if added {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (w *Writer) appendPersonBacklink(name string, doc *granola.Document) (bool, error) {
+	personPath := filepath.Join(w.basePath, "pages", GetPersonPageFilename(name))
+
+	existingContent, err := w.fs.ReadFile(personPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("reading person page: %w", err)
+	}
+
+	// Check if this meeting is already logged on the page
+	if strings.Contains(string(existingContent), "[["+GetPageName(doc, w.opts.PageNameTemplate)+"]]") {
+		return false, nil
+	}
+
+	content := string(existingContent)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += FormatPersonBacklink(doc, w.opts)
+
+	if err := w.fs.WriteFile(personPath, []byte(content), 0o644); err != nil {
+		return false, fmt.Errorf("writing person page: %w", err)
+	}
+	return true, nil
+}
+
+// personPageNames returns the attendees that should get a person page backlink
+func (w *Writer) personPageNames(doc *granola.Document) []string {
+	var names []string
+	for _, name := range attendeeNames(doc, w.opts) {
+		if name == w.userName {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// AttendeeNames returns a meeting's attendees as named on its page
+func (w *Writer) AttendeeNames(doc *granola.Document) []string {
+	return attendeeNames(doc, w.opts)
+}
+
+// PageTags returns the tags on a meeting's page
+func (w *Writer) PageTags(doc *granola.Document) []string {
+	return pageTags(doc, w.opts)
+}
+
+// UserTodos returns the user's action items on a meeting's page
+func (w *Writer) UserTodos(doc *granola.Document) []string {
+	return ExtractUserTodos(w.formatPage(doc), w.userName)
+}
+
+// ActionItems returns every action item on a meeting's page
+func (w *Writer) ActionItems(doc *granola.Document) []ActionItem {
+	return ExtractActionItems(w.formatPage(doc), noteLanguage(doc, w.opts))
+}
+
+// ActionItemTexts returns the text of every action item on a meeting's page,
+// owner included
+func (w *Writer) ActionItemTexts(doc *granola.Document) []string {
+	return ExtractActionItemTexts(w.formatPage(doc), noteLanguage(doc, w.opts))
+}
+
+// LabeledBullets returns the bullets on a meeting's page that start with one
+// of labels
+func (w *Writer) LabeledBullets(doc *granola.Document, labels []string) []LabeledBullet {
+	return ExtractLabeledBullets(w.formatPage(doc), labels)
+}
+
+// Highlights returns the sections of a meeting's notes
+func (w *Writer) Highlights(doc *granola.Document) []Highlight {
+	switch {
+	case doc.NotesMarkdown != nil && *doc.NotesMarkdown != "":
+		return ExtractHighlights(w.opts.Glyphs.Normalize(*doc.NotesMarkdown))
+	case doc.NotesPlain != nil && *doc.NotesPlain != "":
+		return ExtractHighlights(w.opts.Glyphs.Normalize(convertPlainTextToLogseq(*doc.NotesPlain)))
+	}
+	return nil
+}
+
 // DryRunMeetingPage returns what would be written for a meeting page
 func (w *Writer) DryRunMeetingPage(doc *granola.Document) (path, content string) {
-	filename := GetPageFilename(doc)
+	filename := GetPageFilename(doc, w.opts.PageNameTemplate)
 	pagePath := filepath.Join(w.basePath, "pages", filename)
-	content = FormatMeetingPage(doc)
-	content = MarkUserTodos(content, w.userName)
-	return pagePath, content
+	return pagePath, w.formatPage(doc)
+}
+
+// formatPage renders a meeting page with the user's action items marked
+func (w *Writer) formatPage(doc *granola.Document) string {
+	return w.render(doc, w.opts)
+}
+
+// render renders a meeting with opts and the user's action items marked, then
+// transformed by any plugins, reusing an earlier rendering from the render cache
+func (w *Writer) render(doc *granola.Document, opts FormatOptions) string {
+	if opts.PropertiesStyle == "" {
+		opts.PropertiesStyle = PropertiesBullet
+	}
+	return w.renders.render(w.template, doc, opts, func() string {
+		content := MarkUserTodos(FormatMeetingPage(doc, opts), w.userName, noteLanguage(doc, opts))
+		return transformPage(doc, linkIssues(doc, content, opts), opts)
+	})
+}
+
+// DryRunPersonBacklinks returns the person pages that would get a backlink for a meeting
+func (w *Writer) DryRunPersonBacklinks(doc *granola.Document) []string {
+	dry, overlay := w.dryRun()
+	// A page that can't be read would fail the real sync too; list the rest
+	_, _ = dry.AppendPersonBacklinks(doc)
+	return overlay.Changed()
+}
+
+// dryRun returns a copy of the writer that writes to an overlay of its
+// filesystem, so dry runs take the same path as real writes without changing
+// anything. Backups, the archive, sidecars and Spotlight metadata are left out.
+func (w *Writer) dryRun() (*Writer, *OverlayFS) {
+	overlay := NewOverlayFS(w.fs)
+	dry := *w
+	dry.fs = overlay
+	dry.backups = nil
+	dry.archive = nil
+	dry.sidecars = false
+	dry.spotlight = false
+	return &dry, overlay
 }
 
 // DryRunJournalEntry returns what would be appended to a journal
 func (w *Writer) DryRunJournalEntry(doc *granola.Document) (path, content string, wouldAdd bool) {
-	filename := GetJournalFilename(doc)
+	filename := GetJournalFilename(doc, w.opts.Journal)
 	journalPath := filepath.Join(w.basePath, "journals", filename)
 
 	// Check if entry already exists
-	existingContent, err := os.ReadFile(journalPath)
+	existingContent, err := w.fs.ReadFile(journalPath)
 	if err == nil {
-		if strings.Contains(string(existingContent), GetPageName(doc)) {
+		if strings.Contains(string(existingContent), GetPageName(doc, w.opts.PageNameTemplate)) {
 			return journalPath, "", false
 		}
 	}
 
-	entry := FormatJournalEntry(doc)
+	entry := FormatJournalEntry(doc, w.opts)
 	return journalPath, entry, true
 }
@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+)
+
+// defaultLimit is how many meetings list and search return unless asked
+const defaultLimit = 20
+
+// snippetRadius is how much note text either side of a search match is shown
+const snippetRadius = 80
+
+// Meetings is where the tools read meetings from.
+type Meetings interface {
+	// List returns every meeting, with notes as found in the Granola cache
+	List(ctx context.Context) ([]export.Meeting, error)
+	// Get returns one meeting with its notes, fetched if the cache lacks them
+	Get(ctx context.Context, id string) (*export.Meeting, error)
+	// PagePath returns where the meeting was synced in Logseq, or empty
+	PagePath(id string) (string, error)
+}
+
+// NewMeetingServer creates a server with tools to list, read and search
+// meetings and their attendees.
+func NewMeetingServer(meetings Meetings, version string) *Server {
+	s := NewServer("granola-sync", version)
+	t := &meetingTools{meetings: meetings}
+	s.AddTool(Tool{
+		Name:        "list_meetings",
+		Description: "List meetings, newest first, with their time and attendees. Use get_meeting for notes.",
+		InputSchema: objectSchema(map[string]any{
+			"since": stringProperty("Only meetings on or after this date (YYYY-MM-DD)"),
+			"until": stringProperty("Only meetings before this date (YYYY-MM-DD)"),
+			"limit": integerProperty(fmt.Sprintf("Maximum number of meetings (default %d)", defaultLimit)),
+		}),
+		Call: t.list,
+	})
+	s.AddTool(Tool{
+		Name:        "get_meeting",
+		Description: "Get a meeting's notes (markdown), attendees, action items and Logseq page by ID.",
+		InputSchema: objectSchema(map[string]any{
+			"id": stringProperty("The meeting ID, from list_meetings or search_meetings"),
+		}, "id"),
+		Call: t.get,
+	})
+	s.AddTool(Tool{
+		Name:        "search_meetings",
+		Description: "Search meeting titles, notes and attendees. Every word must match (case-insensitive). Returns newest first with a snippet of the notes.",
+		InputSchema: objectSchema(map[string]any{
+			"query": stringProperty("Words to search for"),
+			"limit": integerProperty(fmt.Sprintf("Maximum number of meetings (default %d)", defaultLimit)),
+		}, "query"),
+		Call: t.search,
+	})
+	s.AddTool(Tool{
+		Name:        "list_attendees",
+		Description: "List the people in meetings with how many meetings each attended and when they were last met, most frequent first.",
+		InputSchema: objectSchema(map[string]any{
+			"query": stringProperty("Only people whose name or email contains this"),
+			"limit": integerProperty("Maximum number of people (default 50)"),
+		}),
+		Call: t.attendees,
+	})
+	return s
+}
+
+type meetingTools struct {
+	meetings Meetings
+}
+
+// meetingSummary is a meeting in list and search results
+type meetingSummary struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Start     time.Time  `json:"start"`
+	End       *time.Time `json:"end,omitempty"`
+	Attendees []string   `json:"attendees"`
+	Snippet   string     `json:"snippet,omitempty"`
+}
+
+func summarize(m export.Meeting) meetingSummary {
+	summary := meetingSummary{ID: m.ID, Title: m.Title, Start: m.Start, End: m.End, Attendees: []string{}}
+	for _, a := range m.Attendees {
+		summary.Attendees = append(summary.Attendees, cmp.Or(a.Name, a.Email))
+	}
+	return summary
+}
+
+func (t *meetingTools) list(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Since string `json:"since"`
+		Until string `json:"until"`
+		Limit int    `json:"limit"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	since, err := parseDate("since", args.Since)
+	if err != nil {
+		return "", err
+	}
+	until, err := parseDate("until", args.Until)
+	if err != nil {
+		return "", err
+	}
+
+	meetings, err := t.newestFirst(ctx)
+	if err != nil {
+		return "", err
+	}
+	summaries := []meetingSummary{}
+	for _, m := range meetings {
+		if (!since.IsZero() && m.Start.Before(since)) || (!until.IsZero() && !m.Start.Before(until)) {
+			continue
+		}
+		summaries = append(summaries, summarize(m))
+		if len(summaries) == limit(args.Limit, defaultLimit) {
+			break
+		}
+	}
+	return toJSON(summaries)
+}
+
+func (t *meetingTools) get(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.ID == "" {
+		return "", fmt.Errorf("id is required")
+	}
+
+	m, err := t.meetings.Get(ctx, args.ID)
+	if err != nil {
+		return "", err
+	}
+	pagePath, err := t.meetings.PagePath(args.ID)
+	if err != nil {
+		return "", err
+	}
+	return toJSON(struct {
+		*export.Meeting
+		LogseqPage string `json:"logseq_page,omitempty"`
+	}{m, pagePath})
+}
+
+func (t *meetingTools) search(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	words := strings.Fields(strings.ToLower(args.Query))
+	if len(words) == 0 {
+		return "", fmt.Errorf("query is required")
+	}
+
+	meetings, err := t.newestFirst(ctx)
+	if err != nil {
+		return "", err
+	}
+	results := []meetingSummary{}
+	for _, m := range meetings {
+		if !matchesAll(m, words) {
+			continue
+		}
+		summary := summarize(m)
+		summary.Snippet = snippet(m.Notes, words)
+		results = append(results, summary)
+		if len(results) == limit(args.Limit, defaultLimit) {
+			break
+		}
+	}
+	return toJSON(results)
+}
+
+// attendeeSummary is a person in list_attendees results
+type attendeeSummary struct {
+	Name     string    `json:"name"`
+	Email    string    `json:"email,omitempty"`
+	Meetings int       `json:"meetings"`
+	LastMet  time.Time `json:"last_met"`
+}
+
+func (t *meetingTools) attendees(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	query := strings.ToLower(args.Query)
+
+	meetings, err := t.meetings.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	// People are matched by email when they have one, else by name
+	byKey := make(map[string]*attendeeSummary)
+	for _, m := range meetings {
+		for _, a := range m.Attendees {
+			key := strings.ToLower(cmp.Or(a.Email, a.Name))
+			if key == "" || !strings.Contains(strings.ToLower(a.Name+" "+a.Email), query) {
+				continue
+			}
+			person, ok := byKey[key]
+			if !ok {
+				person = &attendeeSummary{Name: a.Name, Email: a.Email}
+				byKey[key] = person
+			}
+			person.Meetings++
+			if m.Start.After(person.LastMet) {
+				person.LastMet = m.Start
+				person.Name = cmp.Or(a.Name, person.Name)
+			}
+		}
+	}
+
+	people := make([]attendeeSummary, 0, len(byKey))
+	for _, p := range byKey {
+		people = append(people, *p)
+	}
+	slices.SortFunc(people, func(a, b attendeeSummary) int {
+		return cmp.Or(cmp.Compare(b.Meetings, a.Meetings), b.LastMet.Compare(a.LastMet), cmp.Compare(a.Name, b.Name))
+	})
+	if n := limit(args.Limit, 50); len(people) > n {
+		people = people[:n]
+	}
+	return toJSON(people)
+}
+
+// newestFirst lists the meetings, most recent first
+func (t *meetingTools) newestFirst(ctx context.Context) ([]export.Meeting, error) {
+	meetings, err := t.meetings.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(meetings, func(a, b export.Meeting) int {
+		return b.Start.Compare(a.Start)
+	})
+	return meetings, nil
+}
+
+// matchesAll reports whether every word is in the meeting's title, notes or
+// attendees
+func matchesAll(m export.Meeting, words []string) bool {
+	var sb strings.Builder
+	sb.WriteString(m.Title + "\n" + m.Notes)
+	for _, a := range m.Attendees {
+		sb.WriteString("\n" + a.Name + " " + a.Email)
+	}
+	text := strings.ToLower(sb.String())
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// snippet returns the notes around the first word found in them
+func snippet(notes string, words []string) string {
+	lower := strings.ToLower(notes)
+	for _, w := range words {
+		i := strings.Index(lower, w)
+		if i < 0 {
+			continue
+		}
+		start, end := max(0, i-snippetRadius), min(len(notes), i+len(w)+snippetRadius)
+		// Don't cut a character in half
+		for start > 0 && !isRuneStart(notes[start]) {
+			start--
+		}
+		for end < len(notes) && !isRuneStart(notes[end]) {
+			end++
+		}
+		text := strings.Join(strings.Fields(notes[start:end]), " ")
+		if start > 0 {
+			text = "…" + text
+		}
+		if end < len(notes) {
+			text += "…"
+		}
+		return text
+	}
+	return ""
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// limit returns n if positive, else the default
+func limit(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
+
+func parseDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date like 2025-01-28", name)
+	}
+	return t, nil
+}
+
+func toJSON(v any) (string, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding result: %w", err)
+	}
+	return string(out), nil
+}
+
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProperty(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func integerProperty(description string) map[string]any {
+	return map[string]any{"type": "integer", "description": description}
+}
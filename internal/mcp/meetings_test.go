@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+)
+
+// fakeMeetings serves fixed meetings
+type fakeMeetings struct {
+	meetings []export.Meeting
+	pages    map[string]string
+}
+
+func (f *fakeMeetings) List(ctx context.Context) ([]export.Meeting, error) {
+	return append([]export.Meeting(nil), f.meetings...), nil
+}
+
+func (f *fakeMeetings) Get(ctx context.Context, id string) (*export.Meeting, error) {
+	for _, m := range f.meetings {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("meeting %s not found", id)
+}
+
+func (f *fakeMeetings) PagePath(id string) (string, error) {
+	return f.pages[id], nil
+}
+
+type MeetingsSuite struct {
+	suite.Suite
+	tools *meetingTools
+}
+
+func TestMeetingsSuite(t *testing.T) {
+	suite.Run(t, new(MeetingsSuite))
+}
+
+func (s *MeetingsSuite) SetupTest() {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.Local) }
+	alice := export.Attendee{Name: "Alice Smith", Email: "alice@example.com"}
+	bob := export.Attendee{Name: "Bob", Email: "bob@example.com"}
+	s.tools = &meetingTools{meetings: &fakeMeetings{
+		meetings: []export.Meeting{
+			{ID: "m1", Title: "Roadmap", Start: day(10), Attendees: []export.Attendee{alice}, Notes: "- Ship the beta in March"},
+			{ID: "m3", Title: "Standup", Start: day(28), Attendees: []export.Attendee{alice, bob}, Notes: "- Beta is blocked on review"},
+			{ID: "m2", Title: "Hiring", Start: day(20), Attendees: []export.Attendee{bob}, Notes: "- Interview loop"},
+		},
+		pages: map[string]string{"m1": "/graph/pages/Roadmap.md"},
+	}}
+}
+
+// call runs a tool and decodes its JSON result into v
+func (s *MeetingsSuite) call(tool func(context.Context, json.RawMessage) (string, error), args string, v any) {
+	out, err := tool(context.Background(), json.RawMessage(args))
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal([]byte(out), v))
+}
+
+func (s *MeetingsSuite) TestList() {
+	var meetings []meetingSummary
+	s.call(s.tools.list, `{}`, &meetings)
+	s.Require().Len(meetings, 3)
+	s.Equal([]string{"m3", "m2", "m1"}, []string{meetings[0].ID, meetings[1].ID, meetings[2].ID})
+	s.Equal([]string{"Alice Smith", "Bob"}, meetings[0].Attendees)
+
+	s.call(s.tools.list, `{"since": "2025-01-15", "until": "2025-01-28", "limit": 5}`, &meetings)
+	s.Require().Len(meetings, 1)
+	s.Equal("m2", meetings[0].ID)
+
+	s.call(s.tools.list, `{"limit": 1}`, &meetings)
+	s.Len(meetings, 1)
+
+	_, err := s.tools.list(context.Background(), json.RawMessage(`{"since": "last week"}`))
+	s.ErrorContains(err, "since")
+}
+
+func (s *MeetingsSuite) TestGet() {
+	var meeting struct {
+		export.Meeting
+		LogseqPage string `json:"logseq_page"`
+	}
+	s.call(s.tools.get, `{"id": "m1"}`, &meeting)
+	s.Equal("Roadmap", meeting.Title)
+	s.Equal("- Ship the beta in March", meeting.Notes)
+	s.Equal("/graph/pages/Roadmap.md", meeting.LogseqPage)
+
+	_, err := s.tools.get(context.Background(), json.RawMessage(`{"id": "nope"}`))
+	s.Error(err)
+}
+
+func (s *MeetingsSuite) TestSearch() {
+	var results []meetingSummary
+	s.call(s.tools.search, `{"query": "BETA"}`, &results)
+	s.Require().Len(results, 2)
+	s.Equal("m3", results[0].ID)
+	s.Equal("- Beta is blocked on review", results[0].Snippet)
+
+	s.call(s.tools.search, `{"query": "beta bob"}`, &results)
+	s.Require().Len(results, 1, "every word must match, attendees included")
+	s.Equal("m3", results[0].ID)
+
+	_, err := s.tools.search(context.Background(), json.RawMessage(`{"query": " "}`))
+	s.ErrorContains(err, "query")
+}
+
+func (s *MeetingsSuite) TestSnippetTrimsLongNotes() {
+	notes := "Start " + strings.Repeat("filler ", 30) + "target " + strings.Repeat("tail ", 30)
+	got := snippet(notes, []string{"target"})
+	s.Contains(got, "target")
+	s.Less(len(got), len(notes))
+	s.True(strings.HasPrefix(got, "…") && strings.HasSuffix(got, "…"))
+}
+
+func (s *MeetingsSuite) TestAttendees() {
+	var people []attendeeSummary
+	s.call(s.tools.attendees, `{}`, &people)
+	s.Require().Len(people, 2)
+	s.Equal("Alice Smith", people[0].Name, "ties are by last met, then name")
+	s.Equal(2, people[0].Meetings)
+	s.Equal(2, people[1].Meetings)
+
+	s.call(s.tools.attendees, `{"query": "alice"}`, &people)
+	s.Require().Len(people, 1)
+	s.Equal("alice@example.com", people[0].Email)
+	s.Equal(28, people[0].LastMet.Day())
+}
+
+func (s *MeetingsSuite) TestNewMeetingServerListsTools() {
+	server := NewMeetingServer(s.tools.meetings, "dev")
+	var names []string
+	for _, t := range server.tools {
+		names = append(names, t.Name)
+	}
+	s.Equal([]string{"list_meetings", "get_meeting", "search_meetings", "list_attendees"}, names)
+}
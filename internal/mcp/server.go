@@ -0,0 +1,205 @@
+// Package mcp serves meetings to LLM clients as Model Context Protocol tools,
+// over JSON-RPC on stdin and stdout.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+)
+
+// protocolVersion is the newest MCP revision the server speaks
+const protocolVersion = "2025-06-18"
+
+// supportedVersions are the MCP revisions the server accepts from clients
+var supportedVersions = []string{"2024-11-05", "2025-03-26", protocolVersion}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a function the client's model can call.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON schema of the arguments
+	InputSchema map[string]any
+	// Call runs the tool with its JSON arguments and returns text for the
+	// model. An error is reported to the model rather than failing the request.
+	Call func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// Server is an MCP server offering tools.
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+}
+
+// NewServer creates a server that introduces itself with name and version.
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version}
+}
+
+// AddTool offers a tool to clients.
+func (s *Server) AddTool(t Tool) {
+	s.tools = append(s.tools, t)
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Serve answers newline-delimited JSON-RPC messages from r on w until r ends
+// or ctx is done. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("writing response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading requests: %w", err)
+	}
+	return nil
+}
+
+// handle answers one message, returning nil for notifications
+func (s *Server) handle(ctx context.Context, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}}
+	}
+	if req.ID == nil {
+		// Notifications, such as notifications/initialized, need no answer
+		slog.Debug("mcp notification", "method", req.Method)
+		return nil
+	}
+
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" {
+		resp.Error = &rpcError{Code: codeInvalidRequest, Message: "jsonrpc must be 2.0"}
+		return resp
+	}
+	result, err := s.dispatch(ctx, req)
+	var rpcErr *rpcError
+	switch {
+	case errors.As(err, &rpcErr):
+		resp.Error = rpcErr
+	case err != nil:
+		resp.Error = &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	default:
+		resp.Result = result
+	}
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid initialize params: %w", err)
+		}
+		// Agree to the client's revision if known, else offer ours
+		version := protocolVersion
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		tools := make([]map[string]any, len(s.tools))
+		for i, t := range s.tools {
+			tools[i] = map[string]any{"name": t.Name, "description": t.Description, "inputSchema": t.InputSchema}
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+// callTool runs a tool. Its failures are results the model can see and act on,
+// not protocol errors.
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (any, error) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("invalid tools/call params: %w", err)
+	}
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == params.Name })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown tool: %s", params.Name)
+	}
+	if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+		params.Arguments = json.RawMessage("{}")
+	}
+
+	text, err := s.tools[i].Call(ctx, params.Arguments)
+	if err != nil {
+		slog.Warn("mcp tool failed", "tool", params.Name, "error", err)
+		return toolResult(err.Error(), true), nil
+	}
+	return toolResult(text, false), nil
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	result := map[string]any{"content": []map[string]any{{"type": "text", "text": text}}}
+	if isError {
+		result["isError"] = true
+	}
+	return result
+}
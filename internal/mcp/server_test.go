@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerSuite struct {
+	suite.Suite
+	server *Server
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(ServerSuite))
+}
+
+func (s *ServerSuite) SetupTest() {
+	s.server = NewServer("test", "1.0")
+	s.server.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the text",
+		InputSchema: objectSchema(map[string]any{"text": stringProperty("Text")}, "text"),
+		Call: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ Text string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			if a.Text == "" {
+				return "", errors.New("text is required")
+			}
+			return a.Text, nil
+		},
+	})
+}
+
+// exchange sends messages and returns the decoded responses
+func (s *ServerSuite) exchange(messages ...string) []map[string]any {
+	var out bytes.Buffer
+	s.Require().NoError(s.server.Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out))
+
+	var responses []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp map[string]any
+		s.Require().NoError(dec.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func (s *ServerSuite) TestInitialize() {
+	responses := s.exchange(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"client","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	)
+	s.Require().Len(responses, 3, "no response to the notification")
+
+	result := responses[0]["result"].(map[string]any)
+	s.Equal(float64(1), responses[0]["id"])
+	s.Equal("2024-11-05", result["protocolVersion"])
+	s.Equal(map[string]any{"name": "test", "version": "1.0"}, result["serverInfo"])
+	s.Contains(result["capabilities"], "tools")
+
+	s.Equal(protocolVersion, responses[1]["result"].(map[string]any)["protocolVersion"], "unknown revisions get ours")
+	s.Equal(map[string]any{}, responses[2]["result"])
+}
+
+func (s *ServerSuite) TestTools() {
+	responses := s.exchange(
+		`{"jsonrpc":"2.0","id":"a","method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":"b","method":"tools/call","params":{"name":"echo","arguments":{"text":"hello"}}}`,
+		`{"jsonrpc":"2.0","id":"c","method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":"d","method":"tools/call","params":{"name":"missing"}}`,
+	)
+	s.Require().Len(responses, 4)
+
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	s.Require().Len(tools, 1)
+	s.Equal("echo", tools[0].(map[string]any)["name"])
+	s.Contains(tools[0].(map[string]any), "inputSchema")
+
+	s.Equal(map[string]any{"content": []any{map[string]any{"type": "text", "text": "hello"}}}, responses[1]["result"])
+
+	failed := responses[2]["result"].(map[string]any)
+	s.Equal(true, failed["isError"], "tool failures are results for the model")
+	s.Equal("text is required", failed["content"].([]any)[0].(map[string]any)["text"])
+
+	s.Equal(float64(codeInvalidParams), responses[3]["error"].(map[string]any)["code"])
+}
+
+func (s *ServerSuite) TestErrors() {
+	responses := s.exchange(
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"1.0","id":2,"method":"ping"}`,
+	)
+	s.Require().Len(responses, 3)
+
+	s.Nil(responses[0]["id"])
+	s.Equal(float64(codeParseError), responses[0]["error"].(map[string]any)["code"])
+	s.Equal(float64(codeMethodNotFound), responses[1]["error"].(map[string]any)["code"])
+	s.Equal(float64(codeInvalidRequest), responses[2]["error"].(map[string]any)["code"])
+}
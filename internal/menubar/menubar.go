@@ -0,0 +1,87 @@
+// Package menubar shows watch mode as a macOS menu bar item with its last sync
+// and Sync Now, Pause and Open Logs actions, for running granola-sync as an app
+// rather than a headless daemon.
+package menubar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/control"
+)
+
+// Menu bar titles
+const (
+	titleIdle    = "GS"
+	titleSyncing = "GS ↻"
+	titlePaused  = "GS ⏸"
+	titleError   = "GS ⚠"
+)
+
+// view is what the menu shows. render builds it from the daemon's status, so
+// the menu can be checked without a menu bar.
+type view struct {
+	title  string // Shown in the menu bar
+	status string // The disabled first menu item
+	result string // What the last Sync Now did, if anything
+	paused bool   // Offer Resume instead of Pause
+}
+
+// render describes the daemon's status as of now. result is the outcome of the
+// last Sync Now, or empty.
+func render(st *control.Status, err error, result string, now time.Time) view {
+	if err != nil {
+		return view{title: titleError, status: fmt.Sprintf("Can't read status: %v", err)}
+	}
+
+	v := view{title: titleIdle, result: result, paused: st.PausedAt != nil}
+	switch last := st.Health.LastSync; {
+	case st.Syncing:
+		v.status = "Syncing…"
+	case last.IsZero():
+		v.status = "Not synced yet"
+	default:
+		v.status = "Last sync " + ago(last, now)
+	}
+
+	switch {
+	case st.Syncing:
+		v.title = titleSyncing
+	case v.paused:
+		v.title = titlePaused
+		v.status += ", paused"
+	case st.Health.Status != "ok":
+		v.title = titleError
+		if st.Health.LastError != "" {
+			v.status += ": " + st.Health.LastError
+		}
+	}
+	return v
+}
+
+// ago describes how long before now t was
+func ago(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return t.Local().Format("Jan 2 15:04")
+	}
+}
+
+// summarize describes what a Sync Now did
+func summarize(result *control.SyncResult, err error) string {
+	if err != nil {
+		return fmt.Sprintf("Sync failed: %v", err)
+	}
+	s := fmt.Sprintf("Synced %d new, %d updated", result.NewMeetings, result.UpdatedMeetings)
+	if n := len(result.Errors); n > 0 {
+		s += fmt.Sprintf(", %d errors", n)
+	}
+	return s
+}
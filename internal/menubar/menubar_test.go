@@ -0,0 +1,85 @@
+package menubar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/philrhinehart/granola-sync/internal/control"
+	"github.com/philrhinehart/granola-sync/internal/health"
+)
+
+type MenubarSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestMenubarSuite(t *testing.T) {
+	suite.Run(t, new(MenubarSuite))
+}
+
+func (s *MenubarSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 28, 15, 0, 0, 0, time.UTC)
+}
+
+func (s *MenubarSuite) TestRender() {
+	paused := s.now.Add(-time.Hour)
+	tests := []struct {
+		name   string
+		status *control.Status
+		err    error
+		want   view
+	}{
+		{
+			name:   "not synced yet",
+			status: &control.Status{Health: health.Report{Status: "ok"}},
+			want:   view{title: titleIdle, status: "Not synced yet"},
+		},
+		{
+			name:   "synced",
+			status: &control.Status{Health: health.Report{Status: "ok", LastSync: s.now.Add(-3 * time.Minute)}},
+			want:   view{title: titleIdle, status: "Last sync 3m ago"},
+		},
+		{
+			name:   "syncing",
+			status: &control.Status{Syncing: true, Health: health.Report{Status: "ok"}},
+			want:   view{title: titleSyncing, status: "Syncing…"},
+		},
+		{
+			name:   "paused",
+			status: &control.Status{PausedAt: &paused, Health: health.Report{Status: "ok", LastSync: s.now.Add(-2 * time.Hour)}},
+			want:   view{title: titlePaused, status: "Last sync 2h ago, paused", paused: true},
+		},
+		{
+			name: "failing",
+			status: &control.Status{Health: health.Report{
+				Status: "error", LastSync: s.now.Add(-10 * time.Second), LastError: "cache not found",
+			}},
+			want: view{title: titleError, status: "Last sync just now: cache not found"},
+		},
+		{
+			name: "no status",
+			err:  errors.New("boom"),
+			want: view{title: titleError, status: "Can't read status: boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, render(tt.status, tt.err, "", s.now))
+		})
+	}
+}
+
+func (s *MenubarSuite) TestRenderResult() {
+	v := render(&control.Status{Health: health.Report{Status: "ok"}}, nil, "Synced 1 new, 0 updated", s.now)
+	s.Equal("Synced 1 new, 0 updated", v.result)
+}
+
+func (s *MenubarSuite) TestSummarize() {
+	s.Equal("Synced 2 new, 1 updated", summarize(&control.SyncResult{NewMeetings: 2, UpdatedMeetings: 1}, nil))
+	s.Equal("Synced 0 new, 1 updated, 1 errors", summarize(&control.SyncResult{UpdatedMeetings: 1, Errors: []string{"x"}}, nil))
+	s.Equal("Sync failed: boom", summarize(nil, errors.New("boom")))
+}
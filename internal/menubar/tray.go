@@ -0,0 +1,109 @@
+package menubar
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+
+	"github.com/philrhinehart/granola-sync/internal/control"
+)
+
+// refreshInterval is how often the menu picks up syncs run by the watcher and
+// schedule
+const refreshInterval = 5 * time.Second
+
+// Run shows the menu bar item for d until Quit is chosen or called. It must be
+// called from the main goroutine, which macOS runs the menu bar on.
+func Run(d control.Daemon, openLogs func() error) {
+	systray.Run(func() { onReady(d, openLogs) }, nil)
+}
+
+// Quit removes the menu bar item, making Run return
+func Quit() {
+	systray.Quit()
+}
+
+func onReady(d control.Daemon, openLogs func() error) {
+	systray.SetTitle(titleIdle)
+	systray.SetTooltip("granola-sync")
+
+	status := systray.AddMenuItem("Starting…", "")
+	status.Disable()
+	result := systray.AddMenuItem("", "")
+	result.Disable()
+	result.Hide()
+	systray.AddSeparator()
+	syncNow := systray.AddMenuItem("Sync Now", "Sync every new and updated meeting now")
+	pause := systray.AddMenuItem("Pause Syncing", "Stop syncing until resumed")
+	logs := systray.AddMenuItem("Open Logs", "Open the log file in Console")
+	systray.AddSeparator()
+	quit := systray.AddMenuItem("Quit", "Stop syncing and quit")
+
+	// lastResult is what the last Sync Now did, set from its goroutine
+	var mu sync.Mutex
+	var lastResult string
+
+	refresh := func() {
+		st, err := d.Status()
+		mu.Lock()
+		v := render(st, err, lastResult, time.Now())
+		mu.Unlock()
+
+		systray.SetTitle(v.title)
+		status.SetTitle(v.status)
+		if v.result != "" {
+			result.SetTitle(v.result)
+			result.Show()
+		}
+		if v.paused {
+			pause.SetTitle("Resume Syncing")
+		} else {
+			pause.SetTitle("Pause Syncing")
+		}
+	}
+	refresh()
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-syncNow.ClickedCh:
+				syncNow.Disable()
+				go func() {
+					defer syncNow.Enable()
+					summary := summarize(d.Sync(context.Background()))
+					mu.Lock()
+					lastResult = summary
+					mu.Unlock()
+					refresh()
+				}()
+			case <-pause.ClickedCh:
+				st, err := d.Status()
+				switch {
+				case err != nil:
+					slog.Error("reading status failed", "error", err)
+				case st.PausedAt != nil:
+					err = d.Resume()
+				default:
+					err = d.Pause()
+				}
+				if err != nil {
+					slog.Error("pausing or resuming failed", "error", err)
+				}
+			case <-logs.ClickedCh:
+				if err := openLogs(); err != nil {
+					slog.Error("opening logs failed", "error", err)
+				}
+			case <-quit.ClickedCh:
+				systray.Quit()
+				return
+			}
+			refresh()
+		}
+	}()
+}
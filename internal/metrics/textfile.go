@@ -0,0 +1,122 @@
+// Package metrics exports sync statistics for monitoring.
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunStats describes the outcome of a single sync run
+type RunStats struct {
+	Start           time.Time
+	Duration        time.Duration
+	Success         bool // The run completed without errors
+	NewMeetings     int
+	UpdatedMeetings int
+	AdoptedMeetings int
+	NewJournals     int
+	Errors          int
+}
+
+// Textfile writes metrics in the Prometheus text exposition format for the
+// node_exporter textfile collector. Counters accumulate for the life of the process.
+type Textfile struct {
+	path string
+
+	mu       sync.Mutex
+	runs     int
+	failures int
+	meetings int
+	journals int
+	errors   int
+	lastOK   time.Time
+}
+
+// NewTextfile creates a textfile writer for the given .prom path
+func NewTextfile(path string) *Textfile {
+	return &Textfile{path: path}
+}
+
+// Record adds a sync run to the counters and rewrites the metrics file
+func (t *Textfile) Record(run RunStats) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.runs++
+	if !run.Success {
+		t.failures++
+	} else {
+		t.lastOK = run.Start.Add(run.Duration)
+	}
+	t.meetings += run.NewMeetings + run.UpdatedMeetings + run.AdoptedMeetings
+	t.journals += run.NewJournals
+	t.errors += run.Errors
+
+	return writeAtomic(t.path, t.render(run))
+}
+
+// render formats the current counters and the last run as exposition text
+func (t *Textfile) render(run RunStats) string {
+	var sb strings.Builder
+	metric := func(name, typ, help string, value float64) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, typ, name, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+
+	metric("granola_sync_runs_total", "counter", "Sync runs since the process started.", float64(t.runs))
+	metric("granola_sync_run_failures_total", "counter", "Sync runs that failed or had errors.", float64(t.failures))
+	metric("granola_sync_meetings_written_total", "counter", "Meeting pages created, updated, or adopted.", float64(t.meetings))
+	metric("granola_sync_journal_entries_total", "counter", "Journal entries added.", float64(t.journals))
+	metric("granola_sync_errors_total", "counter", "Documents that failed to sync.", float64(t.errors))
+
+	metric("granola_sync_last_run_timestamp_seconds", "gauge", "Start time of the last sync run.", float64(run.Start.Unix()))
+	metric("granola_sync_last_run_duration_seconds", "gauge", "Duration of the last sync run.", run.Duration.Seconds())
+	metric("granola_sync_last_run_success", "gauge", "Whether the last sync run completed without errors.", boolValue(run.Success))
+	if !t.lastOK.IsZero() {
+		metric("granola_sync_last_success_timestamp_seconds", "gauge", "End time of the last successful sync run.", float64(t.lastOK.Unix()))
+	}
+
+	fmt.Fprintf(&sb, "# HELP granola_sync_last_run_meetings Meetings processed in the last sync run.\n")
+	fmt.Fprintf(&sb, "# TYPE granola_sync_last_run_meetings gauge\n")
+	fmt.Fprintf(&sb, "granola_sync_last_run_meetings{result=\"new\"} %d\n", run.NewMeetings)
+	fmt.Fprintf(&sb, "granola_sync_last_run_meetings{result=\"updated\"} %d\n", run.UpdatedMeetings)
+	fmt.Fprintf(&sb, "granola_sync_last_run_meetings{result=\"adopted\"} %d\n", run.AdoptedMeetings)
+	fmt.Fprintf(&sb, "granola_sync_last_run_meetings{result=\"error\"} %d\n", run.Errors)
+
+	return sb.String()
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writeAtomic writes via a temp file and rename so the collector never reads a partial file
+func writeAtomic(path, content string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp metrics file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing metrics file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("setting metrics file mode: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing metrics file: %w", err)
+	}
+	return nil
+}
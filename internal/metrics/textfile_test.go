@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TextfileSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestTextfileSuite(t *testing.T) {
+	suite.Run(t, new(TextfileSuite))
+}
+
+func (s *TextfileSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "granola_sync.prom")
+}
+
+func (s *TextfileSuite) read() string {
+	raw, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	return string(raw)
+}
+
+func (s *TextfileSuite) TestRecordWritesLastRun() {
+	start := time.Unix(1738058400, 0)
+	tf := NewTextfile(s.path)
+
+	s.Require().NoError(tf.Record(RunStats{
+		Start:       start,
+		Duration:    1500 * time.Millisecond,
+		Success:     true,
+		NewMeetings: 2,
+		NewJournals: 2,
+	}))
+
+	got := s.read()
+	s.Contains(got, "# TYPE granola_sync_runs_total counter\ngranola_sync_runs_total 1\n")
+	s.Contains(got, "granola_sync_last_run_timestamp_seconds 1738058400\n")
+	s.Contains(got, "granola_sync_last_run_duration_seconds 1.5\n")
+	s.Contains(got, "granola_sync_last_run_success 1\n")
+	s.Contains(got, "granola_sync_last_run_meetings{result=\"new\"} 2\n")
+	s.Contains(got, "granola_sync_journal_entries_total 2\n")
+}
+
+func (s *TextfileSuite) TestCountersAccumulate() {
+	start := time.Unix(1738058400, 0)
+	tf := NewTextfile(s.path)
+
+	s.Require().NoError(tf.Record(RunStats{Start: start, Success: true, NewMeetings: 3}))
+	s.Require().NoError(tf.Record(RunStats{Start: start.Add(time.Minute), Errors: 1, UpdatedMeetings: 1}))
+
+	got := s.read()
+	s.Contains(got, "granola_sync_runs_total 2\n")
+	s.Contains(got, "granola_sync_run_failures_total 1\n")
+	s.Contains(got, "granola_sync_meetings_written_total 4\n")
+	s.Contains(got, "granola_sync_last_run_success 0\n")
+	s.Contains(got, "granola_sync_last_success_timestamp_seconds 1738058400\n")
+	s.Contains(got, "granola_sync_last_run_meetings{result=\"new\"} 0\n")
+}
+
+func (s *TextfileSuite) TestRecordMissingDirectory() {
+	tf := NewTextfile(filepath.Join(s.T().TempDir(), "missing", "granola_sync.prom"))
+	s.Error(tf.Record(RunStats{Start: time.Now(), Success: true}))
+}
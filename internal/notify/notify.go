@@ -0,0 +1,52 @@
+// Package notify shows macOS desktop notifications, through terminal-notifier if
+// it is installed and osascript otherwise.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// notifyScript shows a notification with the title and message given as the
+// first and second arguments, so no quoting is needed
+const notifyScript = `on run argv
+	display notification (item 2 of argv) with title (item 1 of argv)
+end run`
+
+// group lets terminal-notifier replace granola-sync's previous notification
+// rather than stacking them
+const group = "granola-sync"
+
+// Notifier shows desktop notifications.
+type Notifier struct {
+	// run executes a command; replaced in tests
+	run func(ctx context.Context, name string, args ...string) ([]byte, error)
+	// lookPath finds a command on the PATH; replaced in tests
+	lookPath func(file string) (string, error)
+}
+
+// New creates a notifier.
+func New() *Notifier {
+	return &Notifier{run: runCommand, lookPath: exec.LookPath}
+}
+
+// Send shows a notification.
+func (n *Notifier) Send(ctx context.Context, title, message string) error {
+	var output []byte
+	var err error
+	if path, lookErr := n.lookPath("terminal-notifier"); lookErr == nil {
+		output, err = n.run(ctx, path, "-title", title, "-message", message, "-group", group)
+	} else {
+		output, err = n.run(ctx, "osascript", "-e", notifyScript, title, message)
+	}
+	if err != nil {
+		return fmt.Errorf("showing notification: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
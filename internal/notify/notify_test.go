@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NotifierSuite struct {
+	suite.Suite
+}
+
+func TestNotifierSuite(t *testing.T) {
+	suite.Run(t, new(NotifierSuite))
+}
+
+func (s *NotifierSuite) TestSendWithOsascript() {
+	n := New()
+	n.lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	n.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		s.Equal("osascript", name)
+		s.Equal([]string{"-e", notifyScript, "granola-sync", `Synced "Planning"`}, args)
+		return nil, nil
+	}
+
+	s.NoError(n.Send(context.Background(), "granola-sync", `Synced "Planning"`))
+}
+
+func (s *NotifierSuite) TestSendWithTerminalNotifier() {
+	n := New()
+	n.lookPath = func(file string) (string, error) { return "/opt/homebrew/bin/" + file, nil }
+	n.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		s.Equal("/opt/homebrew/bin/terminal-notifier", name)
+		s.Equal([]string{"-title", "granola-sync", "-message", "Synced", "-group", group}, args)
+		return nil, nil
+	}
+
+	s.NoError(n.Send(context.Background(), "granola-sync", "Synced"))
+}
+
+func (s *NotifierSuite) TestSendError() {
+	n := New()
+	n.lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	n.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("execution error: Not authorized\n"), errors.New("exit status 1")
+	}
+
+	err := n.Send(context.Background(), "granola-sync", "Synced")
+	s.Error(err)
+	s.Contains(err.Error(), "Not authorized")
+}
@@ -0,0 +1,81 @@
+// Package omnifocus creates tasks in OmniFocus through osascript.
+package omnifocus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/osascript"
+)
+
+// createScript makes a task in the project named by the first argument, or the
+// inbox if it is empty, tags it with the comma-separated tags in the fourth
+// (creating missing tags), and prints the new task's ID. Values are passed as
+// arguments rather than interpolated so no quoting is needed.
+const createScript = `on run argv
+	set projectName to item 1 of argv
+	set AppleScript's text item delimiters to ","
+	set tagNames to text items of (item 4 of argv)
+	set AppleScript's text item delimiters to ""
+	tell application "OmniFocus"
+		tell default document
+			set taskProps to {name:(item 2 of argv), note:(item 3 of argv)}
+			if projectName is "" then
+				set newTask to make new inbox task with properties taskProps
+			else
+				set matches to flattened projects whose name is projectName
+				if matches is {} then error "no OmniFocus project named " & projectName
+				set newTask to make new task at end of tasks of (item 1 of matches) with properties taskProps
+			end if
+			repeat with tagItem in tagNames
+				set tagName to tagItem as text
+				if tagName is not "" then
+					set matches to flattened tags whose name is tagName
+					if matches is {} then
+						set theTag to make new tag with properties {name:tagName}
+					else
+						set theTag to item 1 of matches
+					end if
+					add theTag to tags of newTask
+				end if
+			end repeat
+			return id of newTask
+		end tell
+	end tell
+end run`
+
+// Client creates tasks in one project.
+type Client struct {
+	project string
+	tags    []string
+	// run is osascript.Run; replaced in tests
+	run func(ctx context.Context, script string, args ...string) ([]byte, error)
+}
+
+// NewClient creates a client for the named project, or the inbox if project is
+// empty, tagging each task with tags.
+func NewClient(project string, tags []string) *Client {
+	return &Client{project: project, tags: tags, run: osascript.Run}
+}
+
+// Task is a task to create.
+type Task struct {
+	Title string
+	Note  string
+}
+
+// Create adds a task and returns its OmniFocus ID.
+func (c *Client) Create(ctx context.Context, task Task) (string, error) {
+	output, err := c.run(ctx, createScript, c.project, task.Title, task.Note, strings.Join(c.tags, ","))
+	if err != nil {
+		return "", fmt.Errorf("creating task: %w", err)
+	}
+
+	id := strings.TrimSpace(string(output))
+	if id == "" {
+		return "", errors.New("osascript returned no task id")
+	}
+	return id, nil
+}
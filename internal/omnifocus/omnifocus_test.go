@@ -0,0 +1,45 @@
+package omnifocus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreate() {
+	client := NewClient("Work", []string{"meetings"})
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		s.Equal(createScript, script)
+		s.Equal([]string{"Work", `Send the "deck"`, "From Roadmap\nlogseq://graph/notes?page=Roadmap", "meetings"}, args)
+		return []byte("kL3m9xQ2aB1\n"), nil
+	}
+
+	id, err := client.Create(context.Background(), Task{
+		Title: `Send the "deck"`,
+		Note:  "From Roadmap\nlogseq://graph/notes?page=Roadmap",
+	})
+
+	s.NoError(err)
+	s.Equal("kL3m9xQ2aB1", id)
+}
+
+func (s *ClientSuite) TestCreateError() {
+	client := NewClient("Wrok", nil)
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		return nil, errors.New("execution error: no OmniFocus project named Wrok (-2700): exit status 1")
+	}
+
+	_, err := client.Create(context.Background(), Task{Title: "x"})
+	s.Error(err)
+	s.Contains(err.Error(), "no OmniFocus project named Wrok")
+}
@@ -0,0 +1,25 @@
+// Package osascript runs AppleScript with the macOS osascript command.
+package osascript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run runs script with arguments, which the script receives as argv, and returns
+// what it printed. When the script fails, the error carries what osascript wrote
+// to stderr (e.g. "execution error: ... (-1743)").
+func Run(ctx context.Context, script string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "osascript", append([]string{"-e", script}, args...)...)
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return output, fmt.Errorf("%s: %w", stderr, err)
+		}
+	}
+	return output, err
+}
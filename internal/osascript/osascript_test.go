@@ -0,0 +1,42 @@
+package osascript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OsascriptSuite struct {
+	suite.Suite
+}
+
+func TestOsascriptSuite(t *testing.T) {
+	suite.Run(t, new(OsascriptSuite))
+}
+
+// fakeOsascript puts a shell script named osascript first on the PATH
+func (s *OsascriptSuite) fakeOsascript(body string) {
+	dir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "osascript"), []byte("#!/bin/sh\n"+body), 0o755))
+	s.T().Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func (s *OsascriptSuite) TestRunReturnsStdout() {
+	s.fakeOsascript(`shift 2; echo "$@"; echo "warning" >&2`)
+
+	output, err := Run(context.Background(), "on run argv", "Work", "Follow up")
+	s.Require().NoError(err)
+	s.Equal("Work Follow up\n", string(output))
+}
+
+func (s *OsascriptSuite) TestRunErrorHasStderr() {
+	s.fakeOsascript(`echo "execution error: Not authorized to send Apple events to Reminders. (-1743)" >&2; exit 1`)
+
+	_, err := Run(context.Background(), "on run argv")
+	s.Require().Error(err)
+	s.Contains(err.Error(), "Not authorized to send Apple events")
+	s.Contains(err.Error(), "exit status 1")
+}
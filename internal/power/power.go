@@ -0,0 +1,45 @@
+// Package power reads whether the Mac is running on battery or in Low Power
+// Mode, so bulk work can wait until it's plugged in.
+package power
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// lowPowerRe matches the Low Power Mode setting in "pmset -g" output. Newer
+// Macs report it as powermode (1 is low power, 2 high power).
+var lowPowerRe = regexp.MustCompile(`(?m)^\s*(lowpowermode|powermode)\s+1\s*$`)
+
+// Status is the Mac's power source and mode
+type Status struct {
+	OnBattery    bool
+	LowPowerMode bool
+}
+
+// Read asks pmset for the power status. It fails where there is no pmset,
+// i.e. anywhere but macOS.
+func Read() (Status, error) {
+	batt, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return Status{}, fmt.Errorf("reading power source: %w", err)
+	}
+	settings, err := exec.Command("pmset", "-g").Output()
+	if err != nil {
+		return Status{}, fmt.Errorf("reading power settings: %w", err)
+	}
+	return Status{OnBattery: onBattery(string(batt)), LowPowerMode: lowPowerMode(string(settings))}, nil
+}
+
+// onBattery reports whether "pmset -g batt" output says the Mac is drawing
+// from its battery
+func onBattery(out string) bool {
+	return strings.Contains(out, "'Battery Power'")
+}
+
+// lowPowerMode reports whether "pmset -g" output has Low Power Mode on
+func lowPowerMode(out string) bool {
+	return lowPowerRe.MatchString(out)
+}
@@ -0,0 +1,32 @@
+package power
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PowerSuite struct {
+	suite.Suite
+}
+
+func TestPowerSuite(t *testing.T) {
+	suite.Run(t, new(PowerSuite))
+}
+
+func (s *PowerSuite) TestOnBattery() {
+	s.True(onBattery("Now drawing from 'Battery Power'\n -InternalBattery-0 (id=1234)\t82%; discharging; 5:12 remaining present: true\n"))
+	s.False(onBattery("Now drawing from 'AC Power'\n -InternalBattery-0 (id=1234)\t100%; charged; 0:00 remaining present: true\n"))
+	// Desktop Macs have no battery
+	s.False(onBattery("Now drawing from 'AC Power'\n"))
+}
+
+func (s *PowerSuite) TestLowPowerMode() {
+	settings := "System-wide power settings:\nCurrently in use:\n standby              1\n lowpowermode         %s\n sleep                1\n"
+	s.True(lowPowerMode(fmt.Sprintf(settings, "1")))
+	s.False(lowPowerMode(fmt.Sprintf(settings, "0")))
+	s.True(lowPowerMode("Currently in use:\n powermode            1\n"))
+	s.False(lowPowerMode("Currently in use:\n powermode            2\n"))
+	s.False(lowPowerMode("Currently in use:\n sleep                1\n"))
+}
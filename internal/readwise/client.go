@@ -0,0 +1,99 @@
+// Package readwise creates highlights through the Readwise API.
+package readwise
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultBaseURL = "https://readwise.io/api/v2"
+
+// ErrUnauthorized is returned when Readwise rejects the access token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Client communicates with the Readwise API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewClient creates a new Readwise API client.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		baseURL: baseURL,
+		token:   token,
+	}
+}
+
+// Highlight is a highlight to create. Readwise groups highlights into a book by
+// Title and skips a highlight whose text is already in the book.
+type Highlight struct {
+	Text          string     `json:"text"`
+	Title         string     `json:"title"`
+	Author        string     `json:"author,omitempty"`
+	SourceType    string     `json:"source_type,omitempty"`
+	Category      string     `json:"category,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	HighlightedAt *time.Time `json:"highlighted_at,omitempty"`
+	SourceURL     string     `json:"source_url,omitempty"`
+}
+
+// bookResponse is the part of a book with created highlights we use.
+type bookResponse struct {
+	ID int64 `json:"id"`
+}
+
+// CreateHighlights creates highlights and returns the Readwise ID of the book
+// the first one went into.
+func (c *Client) CreateHighlights(ctx context.Context, highlights []Highlight) (string, error) {
+	body, err := json.Marshal(map[string][]Highlight{"highlights": highlights})
+	if err != nil {
+		return "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/highlights/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Token "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var books []bookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&books); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if len(books) == 0 || books[0].ID == 0 {
+		return "", errors.New("API response has no book id")
+	}
+
+	return strconv.FormatInt(books[0].ID, 10), nil
+}
@@ -0,0 +1,76 @@
+package readwise
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreateHighlights() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("/highlights/", r.URL.Path)
+		s.Equal("Token test-token", r.Header.Get("Authorization"))
+		s.Equal("application/json", r.Header.Get("Content-Type"))
+
+		var body struct {
+			Highlights []Highlight `json:"highlights"`
+		}
+		s.NoError(json.NewDecoder(r.Body).Decode(&body))
+		s.Require().Len(body.Highlights, 1)
+		s.Equal("Ship the beta in March", body.Highlights[0].Text)
+		s.Equal("Roadmap", body.Highlights[0].Title)
+		s.Equal("Decisions", body.Highlights[0].Note)
+		s.Equal("2025-01-28T10:00:00Z", body.Highlights[0].HighlightedAt.Format(time.RFC3339))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 42, "title": "Roadmap", "modified_highlights": [7]}]`))
+	}))
+	defer server.Close()
+
+	at := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+	id, err := NewClient(server.URL, "test-token").CreateHighlights(context.Background(), []Highlight{{
+		Text:          "Ship the beta in March",
+		Title:         "Roadmap",
+		Note:          "Decisions",
+		HighlightedAt: &at,
+	}})
+
+	s.NoError(err)
+	s.Equal("42", id)
+}
+
+func (s *ClientSuite) TestCreateHighlightsUnauthorized() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "bad-token").CreateHighlights(context.Background(), []Highlight{{Text: "x", Title: "y"}})
+	s.True(errors.Is(err, ErrUnauthorized))
+}
+
+func (s *ClientSuite) TestCreateHighlightsServerError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"text": ["required"]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-token").CreateHighlights(context.Background(), []Highlight{{Title: "y"}})
+	s.ErrorContains(err, `API returned 400: {"text": ["required"]}`)
+}
@@ -0,0 +1,95 @@
+// Package reflectapp creates notes through the Reflect API.
+package reflectapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultBaseURL = "https://reflect.app/api"
+
+// ErrUnauthorized is returned when Reflect rejects the access token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Client communicates with the Reflect API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	graphID string
+	token   string
+}
+
+// NewClient creates a new Reflect API client for a graph.
+func NewClient(baseURL, graphID, token string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		baseURL: baseURL,
+		graphID: graphID,
+		token:   token,
+	}
+}
+
+// Note is a note to create.
+type Note struct {
+	Subject         string `json:"subject"`
+	ContentMarkdown string `json:"content_markdown"`
+	Pinned          bool   `json:"pinned"`
+}
+
+// noteResponse is the part of the created note we use.
+type noteResponse struct {
+	ID string `json:"id"`
+}
+
+// CreateNote creates a note and returns its Reflect ID.
+func (c *Client) CreateNote(ctx context.Context, note Note) (string, error) {
+	body, err := json.Marshal(note)
+	if err != nil {
+		return "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/graphs/" + url.PathEscape(c.graphID) + "/notes"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var created noteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if created.ID == "" {
+		return "", errors.New("API response has no note id")
+	}
+
+	return created.ID, nil
+}
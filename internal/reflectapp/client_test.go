@@ -0,0 +1,67 @@
+package reflectapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreateNote() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("/graphs/graph-1/notes", r.URL.Path)
+		s.Equal("Bearer test-token", r.Header.Get("Authorization"))
+		s.Equal("application/json", r.Header.Get("Content-Type"))
+
+		var note Note
+		s.NoError(json.NewDecoder(r.Body).Decode(&note))
+		s.Equal("Roadmap", note.Subject)
+		s.Equal("## Decisions\n- Ship the beta in March\n", note.ContentMarkdown)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "note-42", "subject": "Roadmap"}`))
+	}))
+	defer server.Close()
+
+	id, err := NewClient(server.URL, "graph-1", "test-token").CreateNote(context.Background(), Note{
+		Subject:         "Roadmap",
+		ContentMarkdown: "## Decisions\n- Ship the beta in March\n",
+	})
+
+	s.NoError(err)
+	s.Equal("note-42", id)
+}
+
+func (s *ClientSuite) TestCreateNoteUnauthorized() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "graph-1", "bad-token").CreateNote(context.Background(), Note{Subject: "x"})
+	s.True(errors.Is(err, ErrUnauthorized))
+}
+
+func (s *ClientSuite) TestCreateNoteServerError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "graph-1", "test-token").CreateNote(context.Background(), Note{Subject: "x"})
+	s.ErrorContains(err, "API returned 500: boom")
+}
@@ -0,0 +1,57 @@
+// Package reminders creates reminders in Apple Reminders through osascript.
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/osascript"
+)
+
+// createScript makes a reminder in the list named by the first argument,
+// creating the list if needed, and prints the new reminder's ID. Values are
+// passed as arguments rather than interpolated so no quoting is needed.
+const createScript = `on run argv
+	set listName to item 1 of argv
+	tell application "Reminders"
+		if not (exists list listName) then
+			make new list with properties {name:listName}
+		end if
+		set newReminder to make new reminder at end of list listName with properties {name:(item 2 of argv), body:(item 3 of argv)}
+		return id of newReminder
+	end tell
+end run`
+
+// Client creates reminders in one list.
+type Client struct {
+	list string
+	// run is osascript.Run; replaced in tests
+	run func(ctx context.Context, script string, args ...string) ([]byte, error)
+}
+
+// NewClient creates a client for the named Reminders list.
+func NewClient(list string) *Client {
+	return &Client{list: list, run: osascript.Run}
+}
+
+// Reminder is a reminder to create.
+type Reminder struct {
+	Title string
+	Notes string
+}
+
+// Create adds a reminder to the list and returns its Reminders ID.
+func (c *Client) Create(ctx context.Context, reminder Reminder) (string, error) {
+	output, err := c.run(ctx, createScript, c.list, reminder.Title, reminder.Notes)
+	if err != nil {
+		return "", fmt.Errorf("creating reminder: %w", err)
+	}
+
+	id := strings.TrimSpace(string(output))
+	if id == "" {
+		return "", errors.New("osascript returned no reminder id")
+	}
+	return id, nil
+}
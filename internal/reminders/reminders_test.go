@@ -0,0 +1,45 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreate() {
+	client := NewClient("Meetings")
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		s.Equal(createScript, script)
+		s.Equal([]string{"Meetings", `Send the "deck"`, "From Roadmap\nlogseq://graph/notes?page=Roadmap"}, args)
+		return []byte("x-apple-reminder://ABC-123\n"), nil
+	}
+
+	id, err := client.Create(context.Background(), Reminder{
+		Title: `Send the "deck"`,
+		Notes: "From Roadmap\nlogseq://graph/notes?page=Roadmap",
+	})
+
+	s.NoError(err)
+	s.Equal("x-apple-reminder://ABC-123", id)
+}
+
+func (s *ClientSuite) TestCreateError() {
+	client := NewClient("Meetings")
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		return nil, errors.New("execution error: Not authorized to send Apple events to Reminders. (-1743): exit status 1")
+	}
+
+	_, err := client.Create(context.Background(), Reminder{Title: "x"})
+	s.Error(err)
+	s.Contains(err.Error(), "Not authorized")
+}
@@ -0,0 +1,103 @@
+// Package remote reaches Logseq graphs kept on another machine, such as a NAS,
+// over WebDAV or SFTP. Files are replaced atomically like on the local disk:
+// written to a temporary file next to the target, then moved over it.
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/keychain"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+)
+
+// Graph is the filesystem of a remote graph
+type Graph interface {
+	logseq.FS
+	Close() error
+}
+
+// lookupPassword returns the Keychain password for user@host; tests replace it
+var lookupPassword = keychain.Password
+
+// Open returns the filesystem and graph directory for a remote_graph URL:
+// webdav://host/path or webdavs://host/path for WebDAV over HTTP or HTTPS, or
+// sftp://host/path. A user in the URL (user@host) logs in with the password
+// stored in the Keychain for user@host; SFTP tries the SSH agent and the
+// user's keys first. Nothing is connected until the graph is used.
+func Open(rawURL string) (Graph, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing remote graph URL: %w", err)
+	}
+	if u.Host == "" {
+		return nil, "", errors.New("remote graph URL needs a host")
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, "", fmt.Errorf("remote graph URL can't hold a password; store it in the Keychain under service %s", keychain.Service)
+	}
+	dir := path.Clean("/" + u.Path)
+
+	switch u.Scheme {
+	case "webdav":
+		return newWebDAV("http", u), dir, nil
+	case "webdavs":
+		return newWebDAV("https", u), dir, nil
+	case "sftp":
+		return newSFTP(u), dir, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported remote graph scheme %q (must be webdav, webdavs or sftp)", u.Scheme)
+	}
+}
+
+// tempName returns the temporary file a write to name goes through
+func tempName(name string) string {
+	return path.Join(path.Dir(name), fmt.Sprintf(".%s.tmp-%d", path.Base(name), time.Now().UnixNano()))
+}
+
+// fileInfo describes a remote file
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i fileInfo) ModTime() time.Time { return i.modTime }
+func (i fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i fileInfo) Sys() any           { return nil }
+
+// notExist returns the error for a missing file
+func notExist(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// renameError returns the error for a failed rename, keeping a missing source
+// recognizable to os.IsNotExist
+func renameError(oldname, newname string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		err = fs.ErrNotExist
+	}
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+}
+
+// dirEntries converts a remote directory listing, sorted by name like
+// os.ReadDir
+func dirEntries(infos []fs.FileInfo) []fs.DirEntry {
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries
+}
@@ -0,0 +1,280 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sftpGraph is a graph on an SFTP server. It connects on first use and again
+// after the connection drops. Host keys are checked against ~/.ssh/known_hosts.
+type sftpGraph struct {
+	addr string // host:port
+	user string
+
+	mu     sync.Mutex // Serializes requests
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+func newSFTP(u *url.URL) *sftpGraph {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+	name := u.User.Username()
+	if name == "" {
+		if current, err := user.Current(); err == nil {
+			name = current.Username
+		}
+	}
+	return &sftpGraph{addr: addr, user: name}
+}
+
+func (g *sftpGraph) ReadFile(name string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := g.with(func(c *sftp.Client) error {
+		f, err := c.Open(name)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = f.WriteTo(&buf)
+		return err
+	})
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *sftpGraph) Stat(name string) (fs.FileInfo, error) {
+	var info fs.FileInfo
+	err := g.with(func(c *sftp.Client) error {
+		var err error
+		info, err = c.Stat(name)
+		return err
+	})
+	if err != nil {
+		return nil, pathError("stat", name, err)
+	}
+	return info, nil
+}
+
+func (g *sftpGraph) ReadDir(name string) ([]fs.DirEntry, error) {
+	var infos []fs.FileInfo
+	err := g.with(func(c *sftp.Client) error {
+		var err error
+		infos, err = c.ReadDir(name)
+		return err
+	})
+	if err != nil {
+		return nil, pathError("readdir", name, err)
+	}
+	return dirEntries(infos), nil
+}
+
+// WriteFile writes data to a temporary file next to name and renames it into
+// place. Servers without OpenSSH's posix-rename can't rename over a file, so
+// there the old file is removed first and readers may briefly find none.
+func (g *sftpGraph) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	err := g.with(func(c *sftp.Client) error {
+		if info, err := c.Stat(name); err == nil {
+			perm = info.Mode().Perm()
+		}
+
+		tmp := tempName(name)
+		f, err := c.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+		if errors.Is(err, fs.ErrNotExist) {
+			if err := c.MkdirAll(path.Dir(name)); err != nil {
+				return err
+			}
+			f, err = c.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeAll(c, f, data, perm); err != nil {
+			_ = f.Close()
+			_ = c.Remove(tmp)
+			return err
+		}
+		if err := f.Close(); err != nil {
+			_ = c.Remove(tmp)
+			return err
+		}
+
+		rename := c.PosixRename
+		if _, ok := c.HasExtension("posix-rename@openssh.com"); !ok {
+			if err := c.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				_ = c.Remove(tmp)
+				return err
+			}
+			rename = c.Rename
+		}
+		if err := rename(tmp, name); err != nil {
+			_ = c.Remove(tmp)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return pathError("write", name, err)
+	}
+	return nil
+}
+
+func (g *sftpGraph) Rename(oldname, newname string) error {
+	err := g.with(func(c *sftp.Client) error {
+		return c.Rename(oldname, newname)
+	})
+	if err != nil {
+		return renameError(oldname, newname, err)
+	}
+	return nil
+}
+
+// Close disconnects from the server. The graph reconnects if it is used again.
+func (g *sftpGraph) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.disconnect()
+}
+
+// with runs fn with a connected client. A lost connection is dropped so the
+// next request reconnects.
+func (g *sftpGraph) with(fn func(c *sftp.Client) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.client == nil {
+		if err := g.connect(); err != nil {
+			return err
+		}
+	}
+	err := fn(g.client)
+	if errors.Is(err, sftp.ErrSSHFxConnectionLost) {
+		_ = g.disconnect()
+	}
+	return err
+}
+
+// connect logs in and starts the SFTP subsystem
+func (g *sftpGraph) connect() error {
+	config, closeAgent, err := g.clientConfig()
+	if err != nil {
+		return err
+	}
+	// The agent is only needed to log in
+	defer closeAgent()
+
+	conn, err := ssh.Dial("tcp", g.addr, config)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", g.addr, err)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("starting SFTP: %w", err)
+	}
+	g.conn, g.client = conn, client
+	return nil
+}
+
+func (g *sftpGraph) disconnect() error {
+	if g.client == nil {
+		return nil
+	}
+	_ = g.client.Close()
+	var err error
+	if g.conn != nil {
+		err = g.conn.Close()
+	}
+	g.conn, g.client = nil, nil
+	return err
+}
+
+// clientConfig authenticates with the SSH agent and the user's unencrypted
+// keys, then the Keychain password. closeAgent disconnects from the agent once
+// logged in.
+func (g *sftpGraph) clientConfig() (config *ssh.ClientConfig, closeAgent func(), err error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding home directory: %w", err)
+	}
+	hostKeys, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading known hosts: %w", err)
+	}
+
+	closeAgent = func() {}
+	var auth []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			closeAgent = func() { _ = conn.Close() }
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	var signers []ssh.Signer
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		key, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if err != nil {
+			continue
+		}
+		if signer, err := ssh.ParsePrivateKey(key); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		auth = append(auth, ssh.PublicKeys(signers...))
+	}
+	host, _, _ := net.SplitHostPort(g.addr)
+	auth = append(auth, ssh.PasswordCallback(func() (string, error) {
+		return lookupPassword(g.user + "@" + host)
+	}))
+
+	return &ssh.ClientConfig{
+		User:            g.user,
+		Auth:            auth,
+		HostKeyCallback: hostKeys,
+		Timeout:         30 * time.Second,
+	}, closeAgent, nil
+}
+
+// writeAll writes data to a new file with the given permissions and flushes
+// it to disk where the server supports that
+func writeAll(c *sftp.Client, f *sftp.File, data []byte, perm fs.FileMode) error {
+	if err := f.Chmod(perm); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if _, ok := c.HasExtension("fsync@openssh.com"); !ok {
+		return nil
+	}
+	return f.Sync()
+}
+
+// pathError wraps an error for a file, keeping a missing file recognizable to
+// os.IsNotExist
+func pathError(op, name string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/suite"
+)
+
+// pipeConn joins the ends of two pipes into one connection
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+type SFTPSuite struct {
+	suite.Suite
+	root  string // The server's disk
+	graph *sftpGraph
+	close func()
+}
+
+func TestSFTPSuite(t *testing.T) {
+	suite.Run(t, new(SFTPSuite))
+}
+
+func (s *SFTPSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.close = nil
+}
+
+func (s *SFTPSuite) TearDownTest() {
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// connect starts a session with an SFTP server on the test's temp directory
+func (s *SFTPSuite) connect() {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	server, err := sftp.NewServer(pipeConn{serverR, serverW})
+	s.Require().NoError(err)
+	go func() { _ = server.Serve() }()
+
+	client, err := sftp.NewClientPipe(clientR, clientW)
+	s.Require().NoError(err)
+	s.graph = &sftpGraph{addr: "nas:22", user: "alice", client: client}
+	s.close = func() {
+		_ = server.Close()
+		_ = client.Close()
+	}
+}
+
+func (s *SFTPSuite) TestWriteAndRead() {
+	s.connect()
+	page := filepath.Join(s.root, "graph", "pages", "Planning.md")
+
+	// Large enough to take several packets
+	content := strings.Repeat("- a line of meeting notes\n", 3000)
+	s.Require().NoError(s.graph.WriteFile(page, []byte(content), 0o600))
+	s.DirExists(filepath.Dir(page), "missing directories are created")
+
+	read, err := s.graph.ReadFile(page)
+	s.Require().NoError(err)
+	s.Equal(content, string(read))
+
+	// Rewriting keeps the file's permissions
+	s.Require().NoError(s.graph.WriteFile(page, []byte("- short\n"), 0o644))
+	info, err := s.graph.Stat(page)
+	s.Require().NoError(err)
+	s.Equal(int64(8), info.Size())
+	s.Equal(os.FileMode(0o600), info.Mode())
+
+	entries, err := s.graph.ReadDir(filepath.Dir(page))
+	s.Require().NoError(err)
+	s.Require().Len(entries, 1, "no temp files are left behind")
+	s.Equal("Planning.md", entries[0].Name())
+}
+
+func (s *SFTPSuite) TestWriteWithoutPosixRename() {
+	s.Require().NoError(sftp.SetSFTPExtensions("hardlink@openssh.com", "statvfs@openssh.com"))
+	defer func() {
+		_ = sftp.SetSFTPExtensions("hardlink@openssh.com", "posix-rename@openssh.com", "statvfs@openssh.com")
+	}()
+	s.connect()
+	page := filepath.Join(s.root, "Planning.md")
+
+	s.Require().NoError(s.graph.WriteFile(page, []byte("- v1\n"), 0o644))
+	s.Require().NoError(s.graph.WriteFile(page, []byte("- v2\n"), 0o644))
+
+	read, err := s.graph.ReadFile(page)
+	s.Require().NoError(err)
+	s.Equal("- v2\n", string(read))
+}
+
+func (s *SFTPSuite) TestMissingFiles() {
+	s.connect()
+	missing := filepath.Join(s.root, "missing.md")
+
+	_, err := s.graph.ReadFile(missing)
+	s.True(os.IsNotExist(err))
+	_, err = s.graph.Stat(missing)
+	s.True(os.IsNotExist(err))
+	_, err = s.graph.ReadDir(filepath.Join(s.root, "pages"))
+	s.True(os.IsNotExist(err))
+	s.True(os.IsNotExist(s.graph.Rename(missing, filepath.Join(s.root, "other.md"))))
+	s.NotNil(s.graph.client, "an error status keeps the connection")
+}
@@ -0,0 +1,266 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webDAV is a graph on a WebDAV server
+type webDAV struct {
+	scheme string
+	host   string
+	user   string // Empty for anonymous access
+	client *http.Client
+
+	mu       sync.Mutex
+	password string // Read from the Keychain on first use
+}
+
+func newWebDAV(scheme string, u *url.URL) *webDAV {
+	return &webDAV{
+		scheme: scheme,
+		host:   u.Host,
+		user:   u.User.Username(),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (w *webDAV) ReadFile(name string) ([]byte, error) {
+	resp, err := w.do(http.MethodGet, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, notExist("open", name)
+	default:
+		return nil, statusError(resp)
+	}
+}
+
+func (w *webDAV) Stat(name string) (fs.FileInfo, error) {
+	resp, err := w.do(http.MethodHead, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+		return fileInfo{name: path.Base(name), size: resp.ContentLength, mode: 0o644, modTime: modTime}, nil
+	case http.StatusNotFound:
+		return nil, notExist("stat", name)
+	default:
+		return nil, statusError(resp)
+	}
+}
+
+// propfindBody asks for what ReadDir reports about each entry
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/><D:getcontentlength/><D:getlastmodified/></D:prop></D:propfind>`
+
+// multistatus is a PROPFIND response
+type multistatus struct {
+	Responses []struct {
+		Href string `xml:"href"`
+		Prop struct {
+			ResourceType struct {
+				Collection *struct{} `xml:"collection"`
+			} `xml:"resourcetype"`
+			ContentLength int64  `xml:"getcontentlength"`
+			LastModified  string `xml:"getlastmodified"`
+		} `xml:"propstat>prop"`
+	} `xml:"response"`
+}
+
+func (w *webDAV) ReadDir(name string) ([]fs.DirEntry, error) {
+	header := map[string]string{"Depth": "1", "Content-Type": "application/xml"}
+	resp, err := w.do("PROPFIND", strings.TrimSuffix(name, "/")+"/", []byte(propfindBody), header)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusMultiStatus:
+	case http.StatusNotFound:
+		return nil, notExist("readdir", name)
+	default:
+		return nil, statusError(resp)
+	}
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("PROPFIND %s: %w", name, err)
+	}
+
+	var infos []fs.FileInfo
+	for _, r := range ms.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			continue
+		}
+		// The listing includes the collection itself
+		entry := strings.TrimSuffix(href.Path, "/")
+		if entry == strings.TrimSuffix(name, "/") {
+			continue
+		}
+		info := fileInfo{name: path.Base(entry), size: r.Prop.ContentLength, mode: 0o644}
+		info.modTime, _ = http.ParseTime(r.Prop.LastModified)
+		if r.Prop.ResourceType.Collection != nil {
+			info.mode = fs.ModeDir | 0o755
+		}
+		infos = append(infos, info)
+	}
+	return dirEntries(infos), nil
+}
+
+// WriteFile uploads data next to name and moves it into place, so the old
+// content is replaced in one step. WebDAV has no permissions, so perm is unused.
+func (w *webDAV) WriteFile(name string, data []byte, _ fs.FileMode) error {
+	tmp := tempName(name)
+	resp, err := w.do(http.MethodPut, tmp, data, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	// The server reports a missing parent collection as a conflict
+	if resp.StatusCode == http.StatusConflict {
+		if err := w.mkdirAll(path.Dir(name)); err != nil {
+			return err
+		}
+		if resp, err = w.do(http.MethodPut, tmp, data, nil); err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+	}
+	if !success(resp) {
+		return statusError(resp)
+	}
+
+	if err := w.move(tmp, name); err != nil {
+		if resp, derr := w.do(http.MethodDelete, tmp, nil, nil); derr == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+func (w *webDAV) Rename(oldname, newname string) error {
+	if err := w.move(oldname, newname); err != nil {
+		return renameError(oldname, newname, err)
+	}
+	return nil
+}
+
+// Close does nothing; every request makes its own connection
+func (w *webDAV) Close() error {
+	return nil
+}
+
+// move moves a file over whatever is at newname
+func (w *webDAV) move(oldname, newname string) error {
+	header := map[string]string{"Destination": w.url(newname), "Overwrite": "T"}
+	resp, err := w.do("MOVE", oldname, nil, header)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return notExist("move", oldname)
+	}
+	if !success(resp) {
+		return statusError(resp)
+	}
+	return nil
+}
+
+// mkdirAll creates a collection and any missing parents
+func (w *webDAV) mkdirAll(dir string) error {
+	current := "/"
+	for _, part := range strings.Split(strings.Trim(dir, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		current = path.Join(current, part)
+		resp, err := w.do("MKCOL", current+"/", nil, nil)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		// Method Not Allowed means the collection already exists
+		if !success(resp) && resp.StatusCode != http.StatusMethodNotAllowed {
+			return statusError(resp)
+		}
+	}
+	return nil
+}
+
+// url returns the URL of a path on the server
+func (w *webDAV) url(name string) string {
+	u := url.URL{Scheme: w.scheme, Host: w.host, Path: name}
+	return u.String()
+}
+
+// do sends a request for a path on the server
+func (w *webDAV) do(method, name string, body []byte, header map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, w.url(name), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating %s request: %w", method, err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if w.user != "" {
+		password, err := w.credentials()
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(w.user, password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, name, err)
+	}
+	return resp, nil
+}
+
+// credentials returns the user's password, reading it from the Keychain once
+func (w *webDAV) credentials() (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.password == "" {
+		password, err := lookupPassword(w.user + "@" + w.host)
+		if err != nil {
+			return "", err
+		}
+		w.password = password
+	}
+	return w.password, nil
+}
+
+// success reports whether a response has a 2xx status
+func success(resp *http.Response) bool {
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// statusError describes an unexpected response
+func statusError(resp *http.Response) error {
+	return fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+}
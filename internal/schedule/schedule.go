@@ -0,0 +1,192 @@
+// Package schedule parses sync schedules, either a fixed interval or a cron
+// expression, and runs a function on them.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule reports when a scheduled sync is next due.
+type Schedule interface {
+	// Next returns the first time strictly after t that the schedule fires.
+	Next(t time.Time) time.Time
+}
+
+// Parse parses a schedule: a Go duration of at least a minute such as "15m",
+// or a five-field cron expression (minute hour day-of-month month day-of-week)
+// such as "*/15 9-18 * * 1-5". Cron fields accept *, numbers, ranges, lists and
+// steps, and are matched in local time.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty schedule")
+	}
+	if fields := strings.Fields(spec); len(fields) == 5 {
+		c, err := parseCron(fields)
+		if err != nil {
+			return nil, err
+		}
+		if c.Next(time.Now()).IsZero() {
+			return nil, fmt.Errorf("cron expression %q never fires", spec)
+		}
+		return c, nil
+	}
+	d, err := time.ParseDuration(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a duration nor a five-field cron expression", spec)
+	}
+	if d < time.Minute {
+		return nil, fmt.Errorf("interval %s is shorter than a minute", d)
+	}
+	return interval(d), nil
+}
+
+// Run calls fn each time s fires until ctx is done. A run that overlaps the
+// next firing time delays it rather than running twice.
+func Run(ctx context.Context, s Schedule, fn func()) {
+	for {
+		next := s.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			fn()
+		}
+	}
+}
+
+// interval fires every fixed duration
+type interval time.Duration
+
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// cron fires at the minutes matching all of its fields. As in cron, when both
+// day fields are restricted a day matches if either does.
+type cron struct {
+	minute, hour, dom, month, dow uint64 // Bit n set when value n matches
+	domAny, dowAny                bool
+}
+
+// cronFields are the bounds of each field in order
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+func parseCron(fields []string) (*cron, error) {
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron %s field: %w", cronFields[i].name, err)
+		}
+		bits[i] = b
+	}
+	c := &cron{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+// parseField parses a comma-separated list of *, n, a-b, */s, n/s or a-b/s
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				hi = max // n/s runs from n to the end
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Any valid expression matches within a few years (29 February on a Monday
+	// within 28); give up after that rather than loop forever on 30 February
+	limit := t.AddDate(30, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(c.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(c.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+	switch {
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
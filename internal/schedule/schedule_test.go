@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleSuite struct {
+	suite.Suite
+}
+
+func TestScheduleSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleSuite))
+}
+
+// at is a time on Monday 15 January 2024 in UTC
+func at(hour, minute int) time.Time {
+	return time.Date(2024, 1, 15, hour, minute, 0, 0, time.UTC)
+}
+
+func (s *ScheduleSuite) TestInterval() {
+	sched, err := Parse("15m")
+	s.Require().NoError(err)
+
+	s.Equal(at(10, 22), sched.Next(at(10, 7)))
+}
+
+func (s *ScheduleSuite) TestCronNext() {
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every 15 minutes",
+			spec: "*/15 * * * *",
+			from: at(10, 7),
+			want: at(10, 15),
+		},
+		{
+			name: "strictly after",
+			spec: "*/15 * * * *",
+			from: at(10, 15),
+			want: at(10, 30),
+		},
+		{
+			name: "working hours roll over to the next day",
+			spec: "0 9-17 * * 1-5",
+			from: at(17, 30),
+			want: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays skip the weekend",
+			spec: "30 8 * * 1-5",
+			from: time.Date(2024, 1, 19, 9, 0, 0, 0, time.UTC), // Friday
+			want: time.Date(2024, 1, 22, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "7 is Sunday",
+			spec: "0 12 * * 7",
+			from: at(0, 0),
+			want: time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "lists",
+			spec: "0,45 6,18 * * *",
+			from: at(6, 1),
+			want: at(6, 45),
+		},
+		{
+			name: "restricted day fields match either",
+			spec: "0 0 1 * 3",
+			from: at(0, 0),
+			want: time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC), // Wednesday before the 1st
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: at(0, 0),
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			sched, err := Parse(tt.spec)
+			s.Require().NoError(err)
+			s.Equal(tt.want, sched.Next(tt.from))
+		})
+	}
+}
+
+func (s *ScheduleSuite) TestParseErrors() {
+	for _, spec := range []string{
+		"",
+		"soon",
+		"30s",
+		"* * * *",
+		"60 * * * *",
+		"* 9-5 * * *",
+		"*/0 * * * *",
+		"0 0 30 2 *",
+	} {
+		_, err := Parse(spec)
+		s.Error(err, spec)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -18,39 +19,68 @@ const (
 	PlistName    = "com.granola-sync.plist"
 )
 
-// plistPath returns the path to the plist file in LaunchAgents.
-func plistPath() (string, error) {
+// Label returns the launchd label of a profile's service: ServiceLabel for
+// the default profile, com.granola-sync.<profile> for others.
+func Label(profile string) string {
+	if profile == "" {
+		return ServiceLabel
+	}
+	return ServiceLabel + "." + profile
+}
+
+// plistPath returns the path to a profile's plist file in LaunchAgents.
+func plistPath(profile string) (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("getting home directory: %w", err)
 	}
-	return filepath.Join(home, "Library", "LaunchAgents", PlistName), nil
+	return filepath.Join(home, "Library", "LaunchAgents", Label(profile)+".plist"), nil
 }
 
-// LogPath returns the path to the service stderr log file.
-func LogPath() (string, error) {
+// logName returns the name of a profile's stdout or stderr log, e.g.
+// stderr.log or stderr.work.log
+func logName(stream, profile string) string {
+	if profile == "" {
+		return stream + ".log"
+	}
+	return stream + "." + profile + ".log"
+}
+
+// LogPath returns the path to a profile's service stderr log file.
+func LogPath(profile string) (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("getting home directory: %w", err)
 	}
-	return filepath.Join(home, ".config", "granola-sync", "stderr.log"), nil
+	return filepath.Join(home, ".config", "granola-sync", logName("stderr", profile)), nil
 }
 
-// Install generates the plist, copies it to LaunchAgents, and loads the service.
-func Install() error {
-	// Get binary path
+// findBinary returns the path of the installed granola-sync binary, from PATH
+// or GOPATH/bin.
+func findBinary() (string, error) {
 	binaryPath, err := exec.LookPath("granola-sync")
+	if err == nil {
+		return binaryPath, nil
+	}
+	// Try GOPATH/bin
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, _ := os.UserHomeDir()
+		gopath = filepath.Join(home, "go")
+	}
+	binaryPath = filepath.Join(gopath, "bin", "granola-sync")
+	if _, err := os.Stat(binaryPath); err != nil {
+		return "", fmt.Errorf("granola-sync binary not found in PATH or GOPATH/bin")
+	}
+	return binaryPath, nil
+}
+
+// Install generates a profile's plist, copies it to LaunchAgents, and loads
+// the service.
+func Install(profile string) error {
+	binaryPath, err := findBinary()
 	if err != nil {
-		// Try GOPATH/bin
-		gopath := os.Getenv("GOPATH")
-		if gopath == "" {
-			home, _ := os.UserHomeDir()
-			gopath = filepath.Join(home, "go")
-		}
-		binaryPath = filepath.Join(gopath, "bin", "granola-sync")
-		if _, err := os.Stat(binaryPath); err != nil {
-			return fmt.Errorf("granola-sync binary not found in PATH or GOPATH/bin")
-		}
+		return err
 	}
 
 	home, err := os.UserHomeDir()
@@ -65,7 +95,17 @@ func Install() error {
 	}
 
 	// Generate plist content
-	plistContent := strings.ReplaceAll(plistTemplate, "__BINARY_PATH__", binaryPath)
+	var profileArgs string
+	if profile != "" {
+		profileArgs = "        <string>--profile</string>\n        <string>" + profile + "</string>\n"
+	}
+	plistContent := strings.NewReplacer(
+		"__LABEL__", Label(profile),
+		"__BINARY_PATH__", binaryPath,
+		"__PROFILE_ARGS__", profileArgs,
+		"__STDOUT_PATH__", "~/.config/granola-sync/"+logName("stdout", profile),
+		"__STDERR_PATH__", "~/.config/granola-sync/"+logName("stderr", profile),
+	).Replace(plistTemplate)
 	plistContent = strings.ReplaceAll(plistContent, "~", home)
 
 	// Ensure LaunchAgents directory exists
@@ -75,10 +115,10 @@ func Install() error {
 	}
 
 	// Unload if already loaded
-	_ = Unload()
+	_ = Unload(profile)
 
 	// Write plist file
-	plistFile, err := plistPath()
+	plistFile, err := plistPath(profile)
 	if err != nil {
 		return err
 	}
@@ -95,9 +135,9 @@ func Install() error {
 	return nil
 }
 
-// Unload stops the service and removes the plist file.
-func Unload() error {
-	plistFile, err := plistPath()
+// Unload stops a profile's service and removes its plist file.
+func Unload(profile string) error {
+	plistFile, err := plistPath(profile)
 	if err != nil {
 		return err
 	}
@@ -114,6 +154,30 @@ func Unload() error {
 	return nil
 }
 
+// programRe matches the binary path, the first ProgramArguments entry, in the plist.
+var programRe = regexp.MustCompile(`<key>ProgramArguments</key>\s*<array>\s*<string>([^<]*)</string>`)
+
+// InstalledBinaryPath returns the binary path recorded in a profile's installed
+// plist, or "" if the service isn't installed.
+func InstalledBinaryPath(profile string) (string, error) {
+	plistFile, err := plistPath(profile)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(plistFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading plist file: %w", err)
+	}
+	m := programRe.FindSubmatch(data)
+	if m == nil {
+		return "", fmt.Errorf("no ProgramArguments in %s", plistFile)
+	}
+	return string(m[1]), nil
+}
+
 // Status represents the service status.
 type Status struct {
 	Running bool
@@ -121,27 +185,28 @@ type Status struct {
 	Label   string
 }
 
-// GetStatus returns the current service status.
-func GetStatus() (*Status, error) {
+// GetStatus returns the current status of a profile's service.
+func GetStatus(profile string) (*Status, error) {
 	cmd := exec.Command("launchctl", "list")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("listing services: %w", err)
 	}
 
+	label := Label(profile)
 	for _, line := range strings.Split(string(output), "\n") {
-		if strings.Contains(line, ServiceLabel) {
-			fields := strings.Fields(line)
-			if len(fields) >= 3 {
-				status := &Status{
-					Label:   ServiceLabel,
-					Running: fields[0] != "-",
-				}
-				if status.Running {
-					_, _ = fmt.Sscanf(fields[0], "%d", &status.PID)
-				}
-				return status, nil
+		// Lines are PID, last exit status and label; other profiles' labels
+		// start with this one, so match it whole
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[2] == label {
+			status := &Status{
+				Label:   label,
+				Running: fields[0] != "-",
+			}
+			if status.Running {
+				_, _ = fmt.Sscanf(fields[0], "%d", &status.PID)
 			}
+			return status, nil
 		}
 	}
 
@@ -0,0 +1,83 @@
+package service
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed urlhandler.applescript.tmpl
+var urlHandlerTemplate string
+
+const (
+	// URLScheme is the scheme the URL handler app opens, e.g. granola-sync://sync
+	URLScheme = "granola-sync"
+
+	urlHandlerBundleID = "com.granola-sync.url-handler"
+	urlHandlerAppName  = "Granola Sync URL Handler.app"
+	lsregisterPath     = "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+)
+
+// URLHandlerPath returns where the URL handler app is installed.
+func URLHandlerPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, "Applications", urlHandlerAppName), nil
+}
+
+// InstallURLHandler builds a small AppleScript app in ~/Applications that
+// handles granola-sync:// URLs by running "granola-sync shortcuts open", and
+// registers it with Launch Services. It replaces any earlier install.
+func InstallURLHandler() (string, error) {
+	binaryPath, err := findBinary()
+	if err != nil {
+		return "", err
+	}
+	appPath, err := URLHandlerPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(appPath), 0o755); err != nil {
+		return "", fmt.Errorf("creating Applications directory: %w", err)
+	}
+	if err := os.RemoveAll(appPath); err != nil {
+		return "", fmt.Errorf("removing old URL handler: %w", err)
+	}
+
+	script, err := os.CreateTemp("", "granola-sync-url-handler-*.applescript")
+	if err != nil {
+		return "", fmt.Errorf("creating script file: %w", err)
+	}
+	defer func() { _ = os.Remove(script.Name()) }()
+	source := strings.ReplaceAll(urlHandlerTemplate, "__BINARY_PATH__", binaryPath)
+	if _, err := script.WriteString(source); err != nil {
+		_ = script.Close()
+		return "", fmt.Errorf("writing script file: %w", err)
+	}
+	if err := script.Close(); err != nil {
+		return "", fmt.Errorf("writing script file: %w", err)
+	}
+
+	infoPlist := filepath.Join(appPath, "Contents", "Info.plist")
+	urlTypes := fmt.Sprintf(`[{"CFBundleURLName":%q,"CFBundleURLSchemes":[%q]}]`, urlHandlerBundleID, URLScheme)
+	steps := [][]string{
+		{"osacompile", "-o", appPath, script.Name()},
+		{"plutil", "-replace", "CFBundleIdentifier", "-string", urlHandlerBundleID, infoPlist},
+		{"plutil", "-replace", "CFBundleURLTypes", "-json", urlTypes, infoPlist},
+		// No Dock icon while a URL is handled
+		{"plutil", "-replace", "LSUIElement", "-bool", "true", infoPlist},
+		{lsregisterPath, "-f", appPath},
+	}
+	for _, step := range steps {
+		if output, err := exec.Command(step[0], step[1:]...).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("running %s: %s: %w", filepath.Base(step[0]), strings.TrimSpace(string(output)), err)
+		}
+	}
+
+	return appPath, nil
+}
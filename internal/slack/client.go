@@ -0,0 +1,150 @@
+// Package slack posts messages and canvases through the Slack Web API.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultBaseURL = "https://slack.com/api"
+
+// ErrUnauthorized is returned when Slack rejects the bot token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Client communicates with the Slack Web API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewClient creates a new Slack API client with a bot token.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		baseURL: baseURL,
+		token:   token,
+	}
+}
+
+// response is the envelope of every Web API response, with the fields of the
+// methods we call.
+type response struct {
+	OK       bool   `json:"ok"`
+	Error    string `json:"error"`
+	TS       string `json:"ts"`
+	CanvasID string `json:"canvas_id"`
+}
+
+// documentContent is a canvas body in Slack's markdown.
+type documentContent struct {
+	Type     string `json:"type"`
+	Markdown string `json:"markdown"`
+}
+
+// PostMessage posts text to a channel and returns the message's timestamp,
+// which identifies it for UpdateMessage.
+func (c *Client) PostMessage(ctx context.Context, channel, text string) (string, error) {
+	resp, err := c.call(ctx, "chat.postMessage", map[string]any{
+		"channel":      channel,
+		"text":         text,
+		"unfurl_links": false,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.TS == "" {
+		return "", errors.New("API response has no message ts")
+	}
+	return resp.TS, nil
+}
+
+// UpdateMessage replaces the text of the message posted at ts.
+func (c *Client) UpdateMessage(ctx context.Context, channel, ts, text string) error {
+	_, err := c.call(ctx, "chat.update", map[string]any{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+	})
+	return err
+}
+
+// CreateChannelCanvas creates a canvas in a channel from markdown and returns
+// its ID.
+func (c *Client) CreateChannelCanvas(ctx context.Context, channel, title, markdown string) (string, error) {
+	resp, err := c.call(ctx, "conversations.canvases.create", map[string]any{
+		"channel_id":       channel,
+		"title":            title,
+		"document_content": documentContent{Type: "markdown", Markdown: markdown},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.CanvasID == "" {
+		return "", errors.New("API response has no canvas id")
+	}
+	return resp.CanvasID, nil
+}
+
+// EditCanvas replaces the whole content of a canvas with markdown.
+func (c *Client) EditCanvas(ctx context.Context, canvasID, markdown string) error {
+	_, err := c.call(ctx, "canvases.edit", map[string]any{
+		"canvas_id": canvasID,
+		"changes": []map[string]any{{
+			"operation":        "replace",
+			"document_content": documentContent{Type: "markdown", Markdown: markdown},
+		}},
+	})
+	return err
+}
+
+// call invokes a Web API method. Slack reports most failures with a 200 and
+// ok: false, so both are checked.
+func (c *Client) call(ctx context.Context, method string, args map[string]any) (*response, error) {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if !result.OK {
+		switch result.Error {
+		case "not_authed", "invalid_auth", "token_revoked", "token_expired", "account_inactive":
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("%s: %s", method, result.Error)
+	}
+	return &result, nil
+}
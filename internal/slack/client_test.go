@@ -0,0 +1,117 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestPostAndUpdateMessage() {
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("Bearer test-token", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		s.NoError(json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			_, _ = w.Write([]byte(`{"ok": true, "channel": "C0123", "ts": "1706.0001"}`))
+		case "/chat.update":
+			_, _ = w.Write([]byte(`{"ok": true, "channel": "C0123", "ts": "1706.0001"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	ts, err := client.PostMessage(context.Background(), "C0123", "*Standup*")
+	s.Require().NoError(err)
+	s.Equal("1706.0001", ts)
+	s.Require().NoError(client.UpdateMessage(context.Background(), "C0123", ts, "*Standup* edited"))
+
+	s.Require().Len(bodies, 2)
+	s.Equal("C0123", bodies[0]["channel"])
+	s.Equal("*Standup*", bodies[0]["text"])
+	s.Equal("1706.0001", bodies[1]["ts"])
+	s.Equal("*Standup* edited", bodies[1]["text"])
+}
+
+func (s *ClientSuite) TestCreateAndEditCanvas() {
+	var paths []string
+	var edit struct {
+		CanvasID string `json:"canvas_id"`
+		Changes  []struct {
+			Operation       string          `json:"operation"`
+			DocumentContent documentContent `json:"document_content"`
+		} `json:"changes"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/conversations.canvases.create":
+			var body struct {
+				ChannelID       string          `json:"channel_id"`
+				Title           string          `json:"title"`
+				DocumentContent documentContent `json:"document_content"`
+			}
+			s.NoError(json.NewDecoder(r.Body).Decode(&body))
+			s.Equal("C0456", body.ChannelID)
+			s.Equal("Roadmap", body.Title)
+			s.Equal(documentContent{Type: "markdown", Markdown: "## Decisions\n"}, body.DocumentContent)
+			_, _ = w.Write([]byte(`{"ok": true, "canvas_id": "F0789"}`))
+		case "/canvases.edit":
+			s.NoError(json.NewDecoder(r.Body).Decode(&edit))
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	id, err := client.CreateChannelCanvas(context.Background(), "C0456", "Roadmap", "## Decisions\n")
+	s.Require().NoError(err)
+	s.Equal("F0789", id)
+	s.Require().NoError(client.EditCanvas(context.Background(), id, "## Decisions\n- Ship\n"))
+
+	s.Equal([]string{"/conversations.canvases.create", "/canvases.edit"}, paths)
+	s.Equal("F0789", edit.CanvasID)
+	s.Require().Len(edit.Changes, 1)
+	s.Equal("replace", edit.Changes[0].Operation)
+	s.Equal("## Decisions\n- Ship\n", edit.Changes[0].DocumentContent.Markdown)
+}
+
+func (s *ClientSuite) TestUnauthorized() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "bad-token").PostMessage(context.Background(), "C0123", "x")
+	s.True(errors.Is(err, ErrUnauthorized))
+}
+
+func (s *ClientSuite) TestAPIError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "test-token").UpdateMessage(context.Background(), "C0123", "1.2", "x")
+	s.ErrorContains(err, "chat.update: channel_not_found")
+}
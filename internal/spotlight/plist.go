@@ -0,0 +1,134 @@
+package spotlight
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+// plistEpoch is where binary property list dates count seconds from
+var plistEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// encodePlist encodes v, a string, []string or time.Time, as a binary property
+// list (bplist00), the format Spotlight reads metadata attributes in
+func encodePlist(v any) ([]byte, error) {
+	// Objects in order, the top one first; an array's elements follow it
+	var objects []any
+	switch v := v.(type) {
+	case string, time.Time:
+		objects = []any{v}
+	case []string:
+		objects = []any{v}
+		for _, s := range v {
+			objects = append(objects, s)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported plist value %T", v)
+	}
+	refSize := 1
+	if len(objects) > math.MaxUint8 {
+		refSize = 2
+	}
+	if len(objects) > math.MaxUint16 {
+		return nil, fmt.Errorf("too many plist objects: %d", len(objects))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("bplist00")
+	offsets := make([]uint64, len(objects))
+	for i, obj := range objects {
+		offsets[i] = uint64(buf.Len())
+		switch obj := obj.(type) {
+		case string:
+			writePlistString(&buf, obj)
+		case time.Time:
+			buf.WriteByte(0x33)
+			seconds := obj.Sub(plistEpoch).Seconds()
+			_ = binary.Write(&buf, binary.BigEndian, math.Float64bits(seconds))
+		case []string:
+			writePlistMarker(&buf, 0xA0, len(obj))
+			for j := range obj {
+				writePlistUint(&buf, uint64(i+1+j), refSize)
+			}
+		}
+	}
+
+	tableOffset := uint64(buf.Len())
+	offsetSize := plistUintSize(tableOffset)
+	for _, off := range offsets {
+		writePlistUint(&buf, off, offsetSize)
+	}
+
+	// Trailer: 6 unused bytes, the offset and reference sizes, the object
+	// count, the top object and where the offset table starts
+	buf.Write(make([]byte, 6))
+	buf.WriteByte(byte(offsetSize))
+	buf.WriteByte(byte(refSize))
+	writePlistUint(&buf, uint64(len(objects)), 8)
+	writePlistUint(&buf, 0, 8)
+	writePlistUint(&buf, tableOffset, 8)
+	return buf.Bytes(), nil
+}
+
+// writePlistString writes s as ASCII if it can be, or as UTF-16
+func writePlistString(buf *bytes.Buffer, s string) {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		writePlistMarker(buf, 0x50, len(s))
+		buf.WriteString(s)
+		return
+	}
+	units := utf16.Encode([]rune(s))
+	writePlistMarker(buf, 0x60, len(units))
+	for _, u := range units {
+		_ = binary.Write(buf, binary.BigEndian, u)
+	}
+}
+
+// writePlistMarker writes an object's type marker with its length, which
+// follows as an integer object when it doesn't fit in the marker
+func writePlistMarker(buf *bytes.Buffer, marker byte, n int) {
+	if n < 0xF {
+		buf.WriteByte(marker | byte(n))
+		return
+	}
+	buf.WriteByte(marker | 0xF)
+	size := plistUintSize(uint64(n))
+	// Integer objects are 2^k bytes long, with k in the marker's low bits
+	var k byte
+	for 1<<k < size {
+		k++
+	}
+	buf.WriteByte(0x10 | k)
+	writePlistUint(buf, uint64(n), size)
+}
+
+// plistUintSize returns how many bytes (1, 2, 4 or 8) n needs
+func plistUintSize(n uint64) int {
+	switch {
+	case n <= math.MaxUint8:
+		return 1
+	case n <= math.MaxUint16:
+		return 2
+	case n <= math.MaxUint32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// writePlistUint writes n big-endian in size bytes
+func writePlistUint(buf *bytes.Buffer, n uint64, size int) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	buf.Write(b[8-size:])
+}
@@ -0,0 +1,80 @@
+// Package spotlight writes metadata extended attributes on files so macOS
+// Spotlight can find them by title and by the people in a meeting, not just by
+// their text.
+package spotlight
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// attrPrefix is the extended attribute namespace Spotlight imports kMDItem
+// attributes from
+const attrPrefix = "com.apple.metadata:"
+
+// Metadata describes a meeting page to Spotlight
+type Metadata struct {
+	Title     string
+	Date      time.Time
+	Attendees []string
+	Tags      []string
+}
+
+// Attributes returns the extended attributes for m, each a binary property list
+// keyed by attribute name. Attendees and tags become keywords, and attendees are
+// listed in the Finder comment too, which Spotlight searches as text.
+func Attributes(m Metadata) (map[string][]byte, error) {
+	attrs := map[string]any{
+		"kMDItemKeywords": keywords(m),
+	}
+	if m.Title != "" {
+		attrs["kMDItemTitle"] = m.Title
+	}
+	if !m.Date.IsZero() {
+		attrs["kMDItemContentCreationDate"] = m.Date
+	}
+	if len(m.Attendees) > 0 {
+		attrs["kMDItemFinderComment"] = "Attendees: " + strings.Join(m.Attendees, ", ")
+	}
+
+	encoded := make(map[string][]byte, len(attrs))
+	for name, v := range attrs {
+		data, err := encodePlist(v)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", name, err)
+		}
+		encoded[attrPrefix+name] = data
+	}
+	return encoded, nil
+}
+
+// keywords returns m's attendees then tags, without blanks or repeats
+func keywords(m Metadata) []string {
+	seen := make(map[string]bool)
+	list := []string{}
+	for _, k := range append(append([]string{}, m.Attendees...), m.Tags...) {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[strings.ToLower(k)] {
+			continue
+		}
+		seen[strings.ToLower(k)] = true
+		list = append(list, k)
+	}
+	return list
+}
+
+// Write sets m's attributes on the file at path. It does nothing on systems
+// other than macOS.
+func Write(path string, m Metadata) error {
+	attrs, err := Attributes(m)
+	if err != nil {
+		return err
+	}
+	for name, data := range attrs {
+		if err := setxattr(path, name, data); err != nil {
+			return fmt.Errorf("setting %s: %w", name, err)
+		}
+	}
+	return nil
+}
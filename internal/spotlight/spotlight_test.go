@@ -0,0 +1,102 @@
+package spotlight
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SpotlightSuite struct {
+	suite.Suite
+}
+
+func TestSpotlightSuite(t *testing.T) {
+	suite.Run(t, new(SpotlightSuite))
+}
+
+// trailer returns a bplist00 trailer with one-byte offsets and references
+func trailer(objects, tableOffset byte) []byte {
+	return []byte{
+		0, 0, 0, 0, 0, 0, 1, 1,
+		0, 0, 0, 0, 0, 0, 0, objects,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, tableOffset,
+	}
+}
+
+func (s *SpotlightSuite) TestEncodePlistString() {
+	data, err := encodePlist("Hi")
+	s.Require().NoError(err)
+
+	want := append([]byte("bplist00\x52Hi\x08"), trailer(1, 11)...)
+	s.Equal(want, data)
+}
+
+func (s *SpotlightSuite) TestEncodePlistLongString() {
+	title := strings.Repeat("x", 20)
+	data, err := encodePlist(title)
+	s.Require().NoError(err)
+
+	// Lengths of 15 and up follow the marker as an integer object
+	want := append([]byte("bplist00\x5f\x10\x14"+title+"\x08"), trailer(1, 31)...)
+	s.Equal(want, data)
+}
+
+func (s *SpotlightSuite) TestEncodePlistStringArray() {
+	data, err := encodePlist([]string{"a", "é"})
+	s.Require().NoError(err)
+
+	want := []byte("bplist00")
+	want = append(want, 0xA2, 1, 2) // Array referencing objects 1 and 2
+	want = append(want, 0x51, 'a')
+	want = append(want, 0x61, 0x00, 0xE9) // Non-ASCII strings are UTF-16
+	want = append(want, 8, 11, 13)        // Offset table
+	want = append(want, trailer(3, 16)...)
+	s.Equal(want, data)
+}
+
+func (s *SpotlightSuite) TestEncodePlistDate() {
+	data, err := encodePlist(time.Date(2001, 1, 1, 0, 0, 1, 0, time.UTC))
+	s.Require().NoError(err)
+
+	want := []byte("bplist00\x33\x3f\xf0\x00\x00\x00\x00\x00\x00\x08")
+	want = append(want, trailer(1, 17)...)
+	s.Equal(want, data)
+}
+
+func (s *SpotlightSuite) TestEncodePlistUnsupported() {
+	_, err := encodePlist(42)
+	s.Error(err)
+}
+
+func (s *SpotlightSuite) TestAttributes() {
+	attrs, err := Attributes(Metadata{
+		Title:     "Roadmap",
+		Date:      time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC),
+		Attendees: []string{"Alice Smith", "Bob"},
+		Tags:      []string{"meeting", "bob"},
+	})
+	s.Require().NoError(err)
+
+	s.Len(attrs, 4)
+	keywords, err := encodePlist([]string{"Alice Smith", "Bob", "meeting"})
+	s.Require().NoError(err)
+	s.Equal(keywords, attrs["com.apple.metadata:kMDItemKeywords"])
+	title, err := encodePlist("Roadmap")
+	s.Require().NoError(err)
+	s.Equal(title, attrs["com.apple.metadata:kMDItemTitle"])
+	comment, err := encodePlist("Attendees: Alice Smith, Bob")
+	s.Require().NoError(err)
+	s.Equal(comment, attrs["com.apple.metadata:kMDItemFinderComment"])
+	s.Contains(attrs, "com.apple.metadata:kMDItemContentCreationDate")
+}
+
+func (s *SpotlightSuite) TestAttributesWithoutAttendees() {
+	attrs, err := Attributes(Metadata{Tags: []string{"meeting"}})
+	s.Require().NoError(err)
+
+	s.Len(attrs, 1)
+	s.Contains(attrs, "com.apple.metadata:kMDItemKeywords")
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+
+package spotlight
+
+import "golang.org/x/sys/unix"
+
+func setxattr(path, name string, data []byte) error {
+	return unix.Setxattr(path, name, data, 0)
+}
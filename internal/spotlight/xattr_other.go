@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package spotlight
+
+// Spotlight only exists on macOS; elsewhere the attributes are skipped
+func setxattr(path, name string, data []byte) error {
+	return nil
+}
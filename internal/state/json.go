@@ -0,0 +1,340 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jsonStoreVersion is the on-disk format version of the JSON state file
+const jsonStoreVersion = 1
+
+// JSONStore manages the sync state in a human-readable JSON file.
+// The whole file is loaded on open and rewritten on every change.
+type JSONStore struct {
+	path string
+	mu   sync.Mutex
+	data jsonStoreData
+	// panels is kept in memory only: persisting every meeting's notes would make
+	// the file (rewritten on every change) large
+	panels map[string]CachedPanel
+}
+
+// jsonStoreData is the on-disk layout of the JSON state file
+type jsonStoreData struct {
+	Version   int                        `json:"version"`
+	Documents map[string]*SyncedDocument `json:"documents"`
+	Values    map[string]string          `json:"values,omitempty"`
+	// ExportedTasks maps "target/document/task key" to the external task ID
+	ExportedTasks map[string]string `json:"exported_tasks,omitempty"`
+	// SyncRuns is the sync history, oldest first
+	SyncRuns []SyncRun `json:"sync_runs,omitempty"`
+}
+
+// NewJSONStore opens (or creates on first write) a JSON-file state store
+func NewJSONStore(path string) (*JSONStore, error) {
+	store := &JSONStore{
+		path: path,
+		data: jsonStoreData{
+			Version:   jsonStoreVersion,
+			Documents: make(map[string]*SyncedDocument),
+		},
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &store.data); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+	if store.data.Documents == nil {
+		store.data.Documents = make(map[string]*SyncedDocument)
+	}
+
+	return store, nil
+}
+
+// Close is a no-op since every change is flushed immediately
+func (s *JSONStore) Close() error {
+	return nil
+}
+
+// GetSyncedDocument retrieves a synced document by ID
+func (s *JSONStore) GetSyncedDocument(id string) (*SyncedDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.data.Documents[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *doc
+	return &copied, nil
+}
+
+// MarkSynced records that a document has been synced
+func (s *JSONStore) MarkSynced(doc *SyncedDocument) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *doc
+	copied.Attendees = slices.Clone(doc.Attendees)
+	copied.Tags = slices.Clone(doc.Tags)
+	s.data.Documents[doc.ID] = &copied
+	return s.save()
+}
+
+// NeedsUpdate checks if a document needs to be re-synced
+func (s *JSONStore) NeedsUpdate(id string, currentUpdatedAt time.Time, contentHash string) (bool, error) {
+	doc, err := s.GetSyncedDocument(id)
+	if err != nil {
+		return false, err
+	}
+	return needsUpdate(doc, currentUpdatedAt, contentHash), nil
+}
+
+// SyncLatencies returns the recorded sync latencies of documents synced at or after since
+func (s *JSONStore) SyncLatencies(since time.Time) ([]time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latencies []time.Duration
+	for _, doc := range s.data.Documents {
+		if doc.SyncLatency > 0 && !doc.SyncedAt.Before(since) {
+			latencies = append(latencies, doc.SyncLatency)
+		}
+	}
+	return latencies, nil
+}
+
+// ListSynced returns synced documents last updated in Granola within [since, until), newest first
+func (s *JSONStore) ListSynced(since, until *time.Time) ([]SyncedDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var docs []SyncedDocument
+	for _, doc := range s.data.Documents {
+		updatedAt := doc.GranolaUpdatedAt
+		if (since != nil || until != nil) && updatedAt == nil {
+			continue
+		}
+		if since != nil && updatedAt.Before(*since) {
+			continue
+		}
+		if until != nil && !updatedAt.Before(*until) {
+			continue
+		}
+		docs = append(docs, *doc)
+	}
+	sortNewestFirst(docs)
+	return docs, nil
+}
+
+// CountByDay counts synced documents by the local day they were last updated in Granola
+func (s *JSONStore) CountByDay(since time.Time) (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, doc := range s.data.Documents {
+		if doc.GranolaUpdatedAt != nil && !doc.GranolaUpdatedAt.Before(since) {
+			counts[doc.GranolaUpdatedAt.Local().Format("2006-01-02")]++
+		}
+	}
+	return counts, nil
+}
+
+// sortNewestFirst orders documents like the SQL store: by Granola update time,
+// newest first, with unknown times last and ties broken by ID
+func sortNewestFirst(docs []SyncedDocument) {
+	sort.Slice(docs, func(i, j int) bool {
+		a, b := docs[i].GranolaUpdatedAt, docs[j].GranolaUpdatedAt
+		switch {
+		case a == nil || b == nil:
+			if (a == nil) != (b == nil) {
+				return b == nil
+			}
+		case !a.Equal(*b):
+			return a.After(*b)
+		}
+		return docs[i].ID < docs[j].ID
+	})
+}
+
+// exportedTaskKey builds the ExportedTasks map key
+func exportedTaskKey(target, docID, taskKey string) string {
+	return target + "/" + docID + "/" + taskKey
+}
+
+// GetExportedTask returns the external ID of an exported action item, or "" if it hasn't been
+func (s *JSONStore) GetExportedTask(target, docID, taskKey string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ExportedTasks[exportedTaskKey(target, docID, taskKey)], nil
+}
+
+// MarkTaskExported records that an action item was exported to target
+func (s *JSONStore) MarkTaskExported(target, docID, taskKey, externalID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.ExportedTasks == nil {
+		s.data.ExportedTasks = make(map[string]string)
+	}
+	s.data.ExportedTasks[exportedTaskKey(target, docID, taskKey)] = externalID
+	return s.save()
+}
+
+// ListExportedTasks returns the external IDs of a document's action items
+// exported to target, keyed by task key
+func (s *JSONStore) ListExportedTasks(target, docID string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := exportedTaskKey(target, docID, "")
+	tasks := make(map[string]string)
+	for key, externalID := range s.data.ExportedTasks {
+		if taskKey, ok := strings.CutPrefix(key, prefix); ok {
+			tasks[taskKey] = externalID
+		}
+	}
+	return tasks, nil
+}
+
+// LoadPanelCache returns the document panels cached since the store was opened
+func (s *JSONStore) LoadPanelCache() (map[string]CachedPanel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	panels := make(map[string]CachedPanel, len(s.panels))
+	for id, panel := range s.panels {
+		panels[id] = panel
+	}
+	return panels, nil
+}
+
+// SavePanelCache caches document panels for the life of the store
+func (s *JSONStore) SavePanelCache(panels map[string]CachedPanel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.panels == nil {
+		s.panels = make(map[string]CachedPanel)
+	}
+	for id, panel := range panels {
+		s.panels[id] = panel
+	}
+	return nil
+}
+
+// GetValue returns a stored sync metadata value, or "" if it was never set
+func (s *JSONStore) GetValue(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Values[key], nil
+}
+
+// SetValue stores a sync metadata value; setting "" clears it
+func (s *JSONStore) SetValue(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if value == "" {
+		delete(s.data.Values, key)
+	} else {
+		if s.data.Values == nil {
+			s.data.Values = make(map[string]string)
+		}
+		s.data.Values[key] = value
+	}
+	return s.save()
+}
+
+// RecordSyncRun records the summary of a finished sync run and drops the oldest
+// runs beyond maxSyncRuns
+func (s *JSONStore) RecordSyncRun(run *SyncRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.SyncRuns = append(s.data.SyncRuns, *run)
+	if extra := len(s.data.SyncRuns) - maxSyncRuns; extra > 0 {
+		s.data.SyncRuns = s.data.SyncRuns[extra:]
+	}
+	return s.save()
+}
+
+// RecentSyncRuns returns up to limit of the most recent sync runs, newest first
+func (s *JSONStore) RecentSyncRuns(limit int) ([]SyncRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var runs []SyncRun
+	for i := len(s.data.SyncRuns) - 1; i >= 0 && len(runs) < limit; i-- {
+		runs = append(runs, s.data.SyncRuns[i])
+	}
+	return runs, nil
+}
+
+// Reset forgets documents synced before the given time, or clears the whole store if before is nil
+func (s *JSONStore) Reset(before *time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if before == nil {
+		n := len(s.data.Documents)
+		s.data = jsonStoreData{
+			Version:   jsonStoreVersion,
+			Documents: make(map[string]*SyncedDocument),
+		}
+		s.panels = nil
+		return n, s.save()
+	}
+
+	n := 0
+	for id, doc := range s.data.Documents {
+		if doc.SyncedAt.Before(*before) {
+			delete(s.data.Documents, id)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, s.save()
+}
+
+// save writes the state file via a temp file and rename so a crash can't truncate it.
+// Callers must hold s.mu.
+func (s *JSONStore) save() error {
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp state file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONStoreFileSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestJSONStoreFileSuite(t *testing.T) {
+	suite.Run(t, new(JSONStoreFileSuite))
+}
+
+func (s *JSONStoreFileSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "state.json")
+}
+
+func (s *JSONStoreFileSuite) TestPersistsAcrossReopen() {
+	updatedAt := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+
+	store, err := NewJSONStore(s.path)
+	s.Require().NoError(err)
+	s.Require().NoError(store.MarkSynced(&SyncedDocument{
+		ID:               "doc-1",
+		Title:            "Standup",
+		SyncedAt:         updatedAt,
+		GranolaUpdatedAt: &updatedAt,
+		ContentHash:      "abc",
+	}))
+	s.Require().NoError(store.Close())
+
+	reopened, err := NewJSONStore(s.path)
+	s.Require().NoError(err)
+	doc, err := reopened.GetSyncedDocument("doc-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(doc)
+	s.Equal("Standup", doc.Title)
+
+	needs, err := reopened.NeedsUpdate("doc-1", updatedAt, "abc")
+	s.NoError(err)
+	s.False(needs)
+}
+
+func (s *JSONStoreFileSuite) TestInvalidFile() {
+	s.Require().NoError(os.WriteFile(s.path, []byte("{not json"), 0o644))
+
+	_, err := NewJSONStore(s.path)
+	s.Error(err)
+	s.Contains(err.Error(), "parsing state file")
+}
+
+func (s *JSONStoreFileSuite) TestOpenUnknownBackend() {
+	_, err := Open("bolt", s.path, "laptop")
+	s.Error(err)
+	s.Contains(err.Error(), "unknown state backend")
+}
@@ -0,0 +1,25 @@
+package state
+
+// machineStore keeps one machine's sync metadata apart from that of other
+// machines sharing the store. Synced documents and everything else are shared.
+type machineStore struct {
+	Store
+	prefix string
+}
+
+// ForMachine returns a view of store whose GetValue and SetValue only see
+// machineID's metadata, so one machine's pause, backfill checkpoint, output
+// breakers and cache fingerprint don't apply to another.
+func ForMachine(store Store, machineID string) Store {
+	return &machineStore{Store: store, prefix: machineID + "/"}
+}
+
+// GetValue returns this machine's value for key, or "" if it was never set
+func (s *machineStore) GetValue(key string) (string, error) {
+	return s.Store.GetValue(s.prefix + key)
+}
+
+// SetValue stores this machine's value for key; setting "" clears it
+func (s *machineStore) SetValue(key, value string) error {
+	return s.Store.SetValue(s.prefix+key, value)
+}
@@ -0,0 +1,679 @@
+package state
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// SQL dialects supported by SQLStore
+const (
+	dialectSQLite   = "sqlite"
+	dialectPostgres = "postgres"
+)
+
+// SQLStore manages the sync state in a SQL database (SQLite locally, or a
+// shared Postgres server so several machines agree on what's already synced)
+type SQLStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// NewStore creates a new SQLite-backed state store
+func NewStore(dbPath string) (*SQLStore, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	return newSQLStore(db, dialectSQLite)
+}
+
+// NewPostgresStore creates a state store backed by a Postgres server
+func NewPostgresStore(dsn string) (*SQLStore, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	return newSQLStore(db, dialectPostgres)
+}
+
+func newSQLStore(db *sql.DB, dialect string) (*SQLStore, error) {
+	store := &SQLStore{db: db, dialect: dialect}
+	if err := store.migrate(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrating database: %w", err)
+	}
+
+	return store, nil
+}
+
+// Close closes the database connection
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// documentColumns are the synced_documents columns scanDocument reads, in order
+const documentColumns = `id, title, synced_at, granola_updated_at, logseq_page_path, content_hash, machine_id, sync_latency_ms, opted_out, adopted, meeting_date, attendees, tags`
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanDocument reads a synced document selected with documentColumns
+func scanDocument(row rowScanner) (*SyncedDocument, error) {
+	var doc SyncedDocument
+	var granolaUpdatedAt, meetingDate sql.NullTime
+	var latencyMS int64
+	var attendees, tags string
+
+	err := row.Scan(&doc.ID, &doc.Title, &doc.SyncedAt, &granolaUpdatedAt, &doc.LogseqPagePath, &doc.ContentHash, &doc.MachineID, &latencyMS, &doc.OptedOut, &doc.Adopted, &meetingDate, &attendees, &tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if granolaUpdatedAt.Valid {
+		doc.GranolaUpdatedAt = &granolaUpdatedAt.Time
+	}
+	if meetingDate.Valid {
+		doc.MeetingDate = &meetingDate.Time
+	}
+	doc.SyncLatency = time.Duration(latencyMS) * time.Millisecond
+	if doc.Attendees, err = decodeList(attendees); err != nil {
+		return nil, fmt.Errorf("decoding attendees: %w", err)
+	}
+	if doc.Tags, err = decodeList(tags); err != nil {
+		return nil, fmt.Errorf("decoding tags: %w", err)
+	}
+	return &doc, nil
+}
+
+// encodeList stores a list of names in a TEXT column as a JSON array, or "" if empty
+func encodeList(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(list)
+	return string(data)
+}
+
+// decodeList reads a list stored by encodeList
+func decodeList(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// utc returns a time in UTC, or nil. Times are stored in UTC because SQLite
+// keeps them as text, which only compares in time order at the same offset.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// GetSyncedDocument retrieves a synced document by ID
+func (s *SQLStore) GetSyncedDocument(id string) (*SyncedDocument, error) {
+	doc, err := scanDocument(s.db.QueryRow(s.rebind(`SELECT `+documentColumns+` FROM synced_documents WHERE id = ?`), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// queryDocuments runs a query selecting documentColumns
+func (s *SQLStore) queryDocuments(query string, args ...any) ([]SyncedDocument, error) {
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var docs []SyncedDocument
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, rows.Err()
+}
+
+// newestFirst orders documents by Granola update time, newest first with
+// unknown times last (SQLite and Postgres disagree on where NULLs sort)
+const newestFirst = ` ORDER BY granola_updated_at IS NULL, granola_updated_at DESC, id`
+
+// ListSynced returns synced documents last updated in Granola within [since, until), newest first
+func (s *SQLStore) ListSynced(since, until *time.Time) ([]SyncedDocument, error) {
+	var conds []string
+	var args []any
+	if since != nil {
+		conds = append(conds, "granola_updated_at >= ?")
+		args = append(args, since.UTC())
+	}
+	if until != nil {
+		conds = append(conds, "granola_updated_at < ?")
+		args = append(args, until.UTC())
+	}
+
+	query := `SELECT ` + documentColumns + ` FROM synced_documents`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return s.queryDocuments(query+newestFirst, args...)
+}
+
+// CountByDay counts synced documents by the local day they were last updated in
+// Granola. Only the indexed column is read; days are bucketed here since SQLite
+// and Postgres store time zones differently.
+func (s *SQLStore) CountByDay(since time.Time) (map[string]int, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT granola_updated_at FROM synced_documents WHERE granola_updated_at >= ?
+	`), since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var updatedAt time.Time
+		if err := rows.Scan(&updatedAt); err != nil {
+			return nil, err
+		}
+		counts[updatedAt.Local().Format("2006-01-02")]++
+	}
+	return counts, rows.Err()
+}
+
+// MarkSynced records that a document has been synced
+func (s *SQLStore) MarkSynced(doc *SyncedDocument) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO synced_documents (id, title, synced_at, granola_updated_at, logseq_page_path, content_hash, machine_id, sync_latency_ms, opted_out, adopted, meeting_date, attendees, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title,
+			synced_at = excluded.synced_at,
+			granola_updated_at = excluded.granola_updated_at,
+			logseq_page_path = excluded.logseq_page_path,
+			content_hash = excluded.content_hash,
+			machine_id = excluded.machine_id,
+			sync_latency_ms = excluded.sync_latency_ms,
+			opted_out = excluded.opted_out,
+			adopted = excluded.adopted,
+			meeting_date = excluded.meeting_date,
+			attendees = excluded.attendees,
+			tags = excluded.tags
+	`), doc.ID, doc.Title, doc.SyncedAt.UTC(), utc(doc.GranolaUpdatedAt), doc.LogseqPagePath, doc.ContentHash, doc.MachineID, doc.SyncLatency.Milliseconds(), doc.OptedOut, doc.Adopted,
+		utc(doc.MeetingDate), encodeList(doc.Attendees), encodeList(doc.Tags))
+	return err
+}
+
+// NeedsUpdate checks if a document needs to be re-synced
+func (s *SQLStore) NeedsUpdate(id string, currentUpdatedAt time.Time, contentHash string) (bool, error) {
+	doc, err := s.GetSyncedDocument(id)
+	if err != nil {
+		return false, err
+	}
+	return needsUpdate(doc, currentUpdatedAt, contentHash), nil
+}
+
+// SyncLatencies returns the recorded sync latencies of documents synced at or after since
+func (s *SQLStore) SyncLatencies(since time.Time) ([]time.Duration, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT sync_latency_ms FROM synced_documents
+		WHERE sync_latency_ms > 0 AND synced_at >= ?
+	`), since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var latencies []time.Duration
+	for rows.Next() {
+		var ms int64
+		if err := rows.Scan(&ms); err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, time.Duration(ms)*time.Millisecond)
+	}
+	return latencies, rows.Err()
+}
+
+// GetExportedTask returns the external ID of an exported action item, or "" if it hasn't been
+func (s *SQLStore) GetExportedTask(target, docID, taskKey string) (string, error) {
+	var externalID string
+	err := s.db.QueryRow(s.rebind(`
+		SELECT external_id FROM exported_tasks
+		WHERE target = ? AND document_id = ? AND task_key = ?
+	`), target, docID, taskKey).Scan(&externalID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return externalID, err
+}
+
+// MarkTaskExported records that an action item was exported to target
+func (s *SQLStore) MarkTaskExported(target, docID, taskKey, externalID string) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO exported_tasks (target, document_id, task_key, external_id, exported_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(target, document_id, task_key) DO UPDATE SET
+			external_id = excluded.external_id,
+			exported_at = excluded.exported_at
+	`), target, docID, taskKey, externalID, time.Now().UTC())
+	return err
+}
+
+// ListExportedTasks returns the external IDs of a document's action items
+// exported to target, keyed by task key
+func (s *SQLStore) ListExportedTasks(target, docID string) (map[string]string, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT task_key, external_id FROM exported_tasks
+		WHERE target = ? AND document_id = ?
+	`), target, docID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks := make(map[string]string)
+	for rows.Next() {
+		var taskKey, externalID string
+		if err := rows.Scan(&taskKey, &externalID); err != nil {
+			return nil, err
+		}
+		tasks[taskKey] = externalID
+	}
+	return tasks, rows.Err()
+}
+
+// LoadPanelCache returns the cached markdown of document panels, keyed by panel ID
+func (s *SQLStore) LoadPanelCache() (map[string]CachedPanel, error) {
+	rows, err := s.db.Query(`SELECT panel_id, content_updated_at, markdown FROM panel_cache`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	panels := make(map[string]CachedPanel)
+	for rows.Next() {
+		var id string
+		var panel CachedPanel
+		if err := rows.Scan(&id, &panel.ContentUpdatedAt, &panel.Markdown); err != nil {
+			return nil, err
+		}
+		panels[id] = panel
+	}
+	return panels, rows.Err()
+}
+
+// SavePanelCache stores markdown extracted from document panels in one transaction
+func (s *SQLStore) SavePanelCache(panels map[string]CachedPanel) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(s.rebind(`
+		INSERT INTO panel_cache (panel_id, content_updated_at, markdown)
+		VALUES (?, ?, ?)
+		ON CONFLICT(panel_id) DO UPDATE SET
+			content_updated_at = excluded.content_updated_at,
+			markdown = excluded.markdown
+	`))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for id, panel := range panels {
+		if _, err := stmt.Exec(id, panel.ContentUpdatedAt, panel.Markdown); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetValue returns a stored sync metadata value, or "" if it was never set
+func (s *SQLStore) GetValue(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow(s.rebind(`SELECT value FROM sync_meta WHERE key = ?`), key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// SetValue stores a sync metadata value; setting "" clears it
+func (s *SQLStore) SetValue(key, value string) error {
+	if value == "" {
+		_, err := s.db.Exec(s.rebind(`DELETE FROM sync_meta WHERE key = ?`), key)
+		return err
+	}
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO sync_meta (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`), key, value)
+	return err
+}
+
+// RecordSyncRun records the summary of a finished sync run and drops the oldest
+// runs beyond maxSyncRuns
+func (s *SQLStore) RecordSyncRun(run *SyncRun) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO sync_runs (started_at, run_trigger, duration_ms, new_meetings, updated_meetings, new_journals, errors, failed)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`), run.StartedAt.UTC(), run.Trigger, run.Duration.Milliseconds(), run.NewMeetings, run.UpdatedMeetings, run.NewJournals, run.Errors, run.Failed)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(s.rebind(`
+		DELETE FROM sync_runs WHERE started_at < (
+			SELECT MIN(started_at) FROM (
+				SELECT started_at FROM sync_runs ORDER BY started_at DESC LIMIT ?
+			) AS recent
+		)
+	`), maxSyncRuns)
+	return err
+}
+
+// RecentSyncRuns returns up to limit of the most recent sync runs, newest first
+func (s *SQLStore) RecentSyncRuns(limit int) ([]SyncRun, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT started_at, run_trigger, duration_ms, new_meetings, updated_meetings, new_journals, errors, failed
+		FROM sync_runs ORDER BY started_at DESC LIMIT ?
+	`), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []SyncRun
+	for rows.Next() {
+		var run SyncRun
+		var durationMS int64
+		if err := rows.Scan(&run.StartedAt, &run.Trigger, &durationMS, &run.NewMeetings, &run.UpdatedMeetings, &run.NewJournals, &run.Errors, &run.Failed); err != nil {
+			return nil, err
+		}
+		run.Duration = time.Duration(durationMS) * time.Millisecond
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// Reset forgets documents synced before the given time, or clears every table if before is nil
+func (s *SQLStore) Reset(before *time.Time) (int, error) {
+	if before != nil {
+		res, err := s.db.Exec(s.rebind(`DELETE FROM synced_documents WHERE synced_at < ?`), before.UTC())
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		return int(n), err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`DELETE FROM synced_documents`)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	for _, table := range []string{"exported_tasks", "panel_cache", "sync_runs", "sync_meta"} {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return 0, err
+		}
+	}
+	return int(n), tx.Commit()
+}
+
+func (s *SQLStore) migrate() error {
+	_, err := s.db.Exec(s.schema(`
+		CREATE TABLE IF NOT EXISTS synced_documents (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			synced_at TIMESTAMP NOT NULL,
+			granola_updated_at TIMESTAMP,
+			logseq_page_path TEXT,
+			content_hash TEXT
+		)
+	`))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS sync_meta (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(s.schema(`
+		CREATE TABLE IF NOT EXISTS exported_tasks (
+			target TEXT NOT NULL,
+			document_id TEXT NOT NULL,
+			task_key TEXT NOT NULL,
+			external_id TEXT NOT NULL,
+			exported_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (target, document_id, task_key)
+		)
+	`))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS panel_cache (
+			panel_id TEXT PRIMARY KEY,
+			content_updated_at TEXT NOT NULL,
+			markdown TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(s.schema(`
+		CREATE TABLE IF NOT EXISTS sync_runs (
+			started_at TIMESTAMP NOT NULL,
+			duration_ms INTEGER NOT NULL,
+			new_meetings INTEGER NOT NULL,
+			updated_meetings INTEGER NOT NULL,
+			new_journals INTEGER NOT NULL,
+			errors INTEGER NOT NULL,
+			failed BOOLEAN NOT NULL
+		)
+	`))
+	if err != nil {
+		return err
+	}
+
+	// Columns added after the initial schema
+	if err := s.addColumn("synced_documents", "machine_id", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumn("synced_documents", "sync_latency_ms", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumn("synced_documents", "opted_out", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+		return err
+	}
+	if err := s.addColumn("synced_documents", "adopted", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+		return err
+	}
+	if err := s.addColumn("synced_documents", "meeting_date", "TIMESTAMP"); err != nil {
+		return err
+	}
+	// JSON arrays of names, "" when empty
+	if err := s.addColumn("synced_documents", "attendees", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumn("synced_documents", "tags", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	// "trigger" is an SQL keyword
+	if err := s.addColumn("sync_runs", "run_trigger", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Index for ListSynced and CountByDay
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS synced_documents_granola_updated_at ON synced_documents (granola_updated_at)`); err != nil {
+		return err
+	}
+
+	return s.convertTimesToUTC()
+}
+
+// utcColumns are the time columns queries compare or sort by, by table
+var utcColumns = map[string][]string{
+	"synced_documents": {"synced_at", "granola_updated_at", "meeting_date"},
+	"sync_runs":        {"started_at"},
+}
+
+// convertTimesToUTC rewrites SQLite times stored with another offset (by
+// earlier versions) in UTC. Postgres stores times with their zone already.
+func (s *SQLStore) convertTimesToUTC() error {
+	if s.dialect == dialectPostgres {
+		return nil
+	}
+	for table, columns := range utcColumns {
+		for _, column := range columns {
+			if err := s.convertColumnToUTC(table, column); err != nil {
+				return fmt.Errorf("converting %s.%s to UTC: %w", table, column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// convertColumnToUTC rewrites one column's times that aren't stored in UTC
+func (s *SQLStore) convertColumnToUTC(table, column string) error {
+	rows, err := s.db.Query(fmt.Sprintf(`SELECT rowid, %[1]s FROM %[2]s WHERE %[1]s NOT LIKE '%%+00:00'`, column, table))
+	if err != nil {
+		return err
+	}
+	type stored struct {
+		rowID int64
+		at    time.Time
+	}
+	var times []stored
+	for rows.Next() {
+		var row stored
+		if err := rows.Scan(&row.rowID, &row.at); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		times = append(times, row)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, row := range times {
+		if _, err := s.db.Exec(fmt.Sprintf(`UPDATE %s SET %s = ? WHERE rowid = ?`, table, column), row.at.UTC(), row.rowID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds a column to an existing table unless it is already there
+func (s *SQLStore) addColumn(table, column, definition string) error {
+	if s.dialect == dialectPostgres {
+		_, err := s.db.Exec(s.schema(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, column, definition)))
+		return err
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// rebind converts ? placeholders to the dialect's placeholder syntax
+func (s *SQLStore) rebind(query string) string {
+	if s.dialect != dialectPostgres {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// schema adapts DDL to the dialect. Postgres gets timezone-aware timestamps so
+// machines in different zones compare granola_updated_at correctly.
+func (s *SQLStore) schema(ddl string) string {
+	if s.dialect != dialectPostgres {
+		return ddl
+	}
+	return strings.ReplaceAll(ddl, "TIMESTAMP", "TIMESTAMPTZ")
+}
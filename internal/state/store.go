@@ -1,123 +1,154 @@
 package state
 
 import (
-	"database/sql"
 	"fmt"
 	"time"
+)
 
-	_ "modernc.org/sqlite"
+// Backend names accepted by Open
+const (
+	BackendSQLite   = "sqlite"
+	BackendJSON     = "json"
+	BackendPostgres = "postgres"
 )
 
-// Store manages the sync state in SQLite
-type Store struct {
-	db *sql.DB
+// Store persists which Granola documents have been synced
+type Store interface {
+	// GetSyncedDocument retrieves a synced document by ID, or nil if it was never synced
+	GetSyncedDocument(id string) (*SyncedDocument, error)
+	// MarkSynced records that a document has been synced
+	MarkSynced(doc *SyncedDocument) error
+	// NeedsUpdate checks if a document needs to be re-synced
+	NeedsUpdate(id string, currentUpdatedAt time.Time, contentHash string) (bool, error)
+	// SyncLatencies returns the recorded sync latencies of documents synced at or after since
+	SyncLatencies(since time.Time) ([]time.Duration, error)
+	// ListSynced returns the synced documents last updated in Granola at or after
+	// since and before until, newest first. Nil bounds are open; documents with no
+	// Granola update time are only listed when both are nil, last.
+	ListSynced(since, until *time.Time) ([]SyncedDocument, error)
+	// CountByDay counts the synced documents last updated in Granola at or after
+	// since by local day ("2006-01-02")
+	CountByDay(since time.Time) (map[string]int, error)
+	// GetExportedTask returns the external ID of an action item already exported
+	// to target, or "" if it hasn't been
+	GetExportedTask(target, docID, taskKey string) (string, error)
+	// MarkTaskExported records that an action item was exported to target
+	MarkTaskExported(target, docID, taskKey, externalID string) error
+	// ListExportedTasks returns the external IDs of a document's action items
+	// exported to target, keyed by task key
+	ListExportedTasks(target, docID string) (map[string]string, error)
+	// LoadPanelCache returns the cached markdown of document panels, keyed by panel ID
+	LoadPanelCache() (map[string]CachedPanel, error)
+	// SavePanelCache stores markdown extracted from document panels, keyed by panel ID
+	SavePanelCache(panels map[string]CachedPanel) error
+	// GetValue returns a stored sync metadata value, or "" if it was never set
+	GetValue(key string) (string, error)
+	// SetValue stores a sync metadata value; setting "" clears it
+	SetValue(key, value string) error
+	// RecordSyncRun records the summary of a finished sync run, keeping the most
+	// recent maxSyncRuns
+	RecordSyncRun(run *SyncRun) error
+	// RecentSyncRuns returns up to limit of the most recent sync runs, newest first
+	RecentSyncRuns(limit int) ([]SyncRun, error)
+	// Reset forgets documents synced before the given time so the next sync
+	// rewrites them. A nil before clears the whole store: synced documents,
+	// exported action items, cached panels, sync history and metadata. Returns
+	// the number of documents forgotten.
+	Reset(before *time.Time) (int, error)
+	// Close releases the underlying storage
+	Close() error
 }
 
 // SyncedDocument represents a synced document record
 type SyncedDocument struct {
-	ID               string
-	Title            string
-	SyncedAt         time.Time
-	GranolaUpdatedAt *time.Time
-	LogseqPagePath   string
-	ContentHash      string
+	ID               string     `json:"id"`
+	Title            string     `json:"title"`
+	SyncedAt         time.Time  `json:"synced_at"`
+	GranolaUpdatedAt *time.Time `json:"granola_updated_at,omitempty"`
+	LogseqPagePath   string     `json:"logseq_page_path"`
+	ContentHash      string     `json:"content_hash"`
+	MachineID        string     `json:"machine_id,omitempty"` // Host that wrote (or adopted) the page
+	// SyncLatency is the time from Granola's updated_at to the sync, or 0 if not recorded
+	SyncLatency time.Duration `json:"sync_latency,omitempty"`
+	// OptedOut is set when the page carries granola-sync:: ignore, so sync leaves it alone
+	OptedOut bool `json:"opted_out,omitempty"`
+	// Adopted is set on hand-made pages linked by "granola-sync adopt" without
+	// --upgrade, which sync leaves as they are
+	Adopted bool `json:"adopted,omitempty"`
+	// MeetingDate, Attendees and Tags mirror the page so commands can read them
+	// without parsing the cache; unset on records written before they were stored
+	MeetingDate *time.Time `json:"meeting_date,omitempty"`
+	Attendees   []string   `json:"attendees,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
 }
 
-// NewStore creates a new state store
-func NewStore(dbPath string) (*Store, error) {
-	db, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
-	}
+// maxSyncRuns is how many sync runs the store keeps; older ones are dropped
+const maxSyncRuns = 1000
 
-	store := &Store{db: db}
-	if err := store.migrate(); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("migrating database: %w", err)
-	}
+// What started a sync run
+const (
+	TriggerWatch    = "watch"    // A change to the Granola cache (or the first sync) in watch mode
+	TriggerBackfill = "backfill" // run --backfill
+	TriggerManual   = "manual"   // The sync command, for specific meetings
+)
 
-	return store, nil
+// SyncRun summarizes one sync run
+type SyncRun struct {
+	StartedAt       time.Time     `json:"started_at"`
+	Trigger         string        `json:"trigger,omitempty"` // One of the Trigger constants
+	Duration        time.Duration `json:"duration"`
+	NewMeetings     int           `json:"new_meetings"`
+	UpdatedMeetings int           `json:"updated_meetings"`
+	NewJournals     int           `json:"new_journals"`
+	Errors          int           `json:"errors"`
+	// Failed is set when the run couldn't sync at all, e.g. the cache was unreadable
+	Failed bool `json:"failed,omitempty"`
 }
 
-// Close closes the database connection
-func (s *Store) Close() error {
-	return s.db.Close()
+// CachedPanel is markdown extracted from a document panel. It is valid while
+// the panel's content_updated_at, stamped with the extractor version, is unchanged.
+type CachedPanel struct {
+	ContentUpdatedAt string // content_updated_at@extractor version
+	Markdown         string
 }
 
-// GetSyncedDocument retrieves a synced document by ID
-func (s *Store) GetSyncedDocument(id string) (*SyncedDocument, error) {
-	var doc SyncedDocument
-	var granolaUpdatedAt sql.NullTime
-
-	err := s.db.QueryRow(`
-		SELECT id, title, synced_at, granola_updated_at, logseq_page_path, content_hash
-		FROM synced_documents WHERE id = ?
-	`, id).Scan(&doc.ID, &doc.Title, &doc.SyncedAt, &granolaUpdatedAt, &doc.LogseqPagePath, &doc.ContentHash)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	if granolaUpdatedAt.Valid {
-		doc.GranolaUpdatedAt = &granolaUpdatedAt.Time
+// Open opens the state store for the given backend. The location is a file
+// path for sqlite and json, or a connection string for postgres.
+// An empty backend defaults to SQLite. A postgres store is shared with other
+// machines, so it keeps this machine's sync metadata under its machineID.
+func Open(backend, location, machineID string) (Store, error) {
+	switch backend {
+	case "", BackendSQLite:
+		return NewStore(location)
+	case BackendJSON:
+		return NewJSONStore(location)
+	case BackendPostgres:
+		store, err := NewPostgresStore(location)
+		if err != nil {
+			return nil, err
+		}
+		return ForMachine(store, machineID), nil
+	default:
+		return nil, fmt.Errorf("unknown state backend: %s", backend)
 	}
-
-	return &doc, nil
-}
-
-// MarkSynced records that a document has been synced
-func (s *Store) MarkSynced(doc *SyncedDocument) error {
-	_, err := s.db.Exec(`
-		INSERT INTO synced_documents (id, title, synced_at, granola_updated_at, logseq_page_path, content_hash)
-		VALUES (?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			title = excluded.title,
-			synced_at = excluded.synced_at,
-			granola_updated_at = excluded.granola_updated_at,
-			logseq_page_path = excluded.logseq_page_path,
-			content_hash = excluded.content_hash
-	`, doc.ID, doc.Title, doc.SyncedAt, doc.GranolaUpdatedAt, doc.LogseqPagePath, doc.ContentHash)
-	return err
 }
 
-// NeedsUpdate checks if a document needs to be re-synced
-func (s *Store) NeedsUpdate(id string, currentUpdatedAt time.Time, contentHash string) (bool, error) {
-	doc, err := s.GetSyncedDocument(id)
-	if err != nil {
-		return false, err
-	}
-
+// needsUpdate compares a stored record against the current document state
+func needsUpdate(doc *SyncedDocument, currentUpdatedAt time.Time, contentHash string) bool {
 	// New document
 	if doc == nil {
-		return true, nil
+		return true
 	}
 
 	// Check if content changed via hash
 	if doc.ContentHash != contentHash {
-		return true, nil
+		return true
 	}
 
 	// Check if Granola's updated_at changed
 	if doc.GranolaUpdatedAt == nil || !doc.GranolaUpdatedAt.Equal(currentUpdatedAt) {
-		return true, nil
+		return true
 	}
 
-	return false, nil
-}
-
-func (s *Store) migrate() error {
-	_, err := s.db.Exec(`
-		CREATE TABLE IF NOT EXISTS synced_documents (
-			id TEXT PRIMARY KEY,
-			title TEXT NOT NULL,
-			synced_at TIMESTAMP NOT NULL,
-			granola_updated_at TIMESTAMP,
-			logseq_page_path TEXT,
-			content_hash TEXT
-		)
-	`)
-	return err
+	return false
 }
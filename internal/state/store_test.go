@@ -1,24 +1,59 @@
 package state
 
 import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
+// StoreSuite runs the same behavioral tests against every Store backend
 type StoreSuite struct {
 	suite.Suite
-	store *Store
+	newStore func() (Store, error)
+	store    Store
 }
 
 func TestStoreSuite(t *testing.T) {
-	suite.Run(t, new(StoreSuite))
+	suite.Run(t, &StoreSuite{newStore: func() (Store, error) {
+		return NewStore(":memory:")
+	}})
+}
+
+func TestJSONStoreSuite(t *testing.T) {
+	suite.Run(t, &StoreSuite{newStore: func() (Store, error) {
+		return NewJSONStore(filepath.Join(t.TempDir(), "state.json"))
+	}})
+}
+
+// TestPostgresStoreSuite runs against a real server when
+// GRANOLA_SYNC_TEST_POSTGRES_DSN is set (the table is truncated between stores).
+func TestPostgresStoreSuite(t *testing.T) {
+	dsn := os.Getenv("GRANOLA_SYNC_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("GRANOLA_SYNC_TEST_POSTGRES_DSN not set")
+	}
+	suite.Run(t, &StoreSuite{newStore: func() (Store, error) {
+		store, err := NewPostgresStore(dsn)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := store.db.Exec("TRUNCATE synced_documents, sync_meta, exported_tasks, panel_cache, sync_runs"); err != nil {
+			return nil, err
+		}
+		return store, nil
+	}})
 }
 
 func (s *StoreSuite) SetupTest() {
 	var err error
-	s.store, err = NewStore(":memory:")
+	s.store, err = s.newStore()
 	s.Require().NoError(err)
 }
 
@@ -30,7 +65,7 @@ func (s *StoreSuite) TearDownTest() {
 
 func (s *StoreSuite) TestNewStore() {
 	// Already tested in SetupTest, but let's verify tables exist
-	store, err := NewStore(":memory:")
+	store, err := s.newStore()
 	s.NoError(err)
 	s.NotNil(store)
 	defer func() { _ = store.Close() }()
@@ -43,6 +78,7 @@ func (s *StoreSuite) TestNewStore() {
 func (s *StoreSuite) TestMarkSyncedAndGetSyncedDocument() {
 	now := time.Now().Truncate(time.Second)
 	updatedAt := now.Add(-time.Hour)
+	meetingDate := now.Add(-2 * time.Hour)
 
 	doc := &SyncedDocument{
 		ID:               "test-doc-1",
@@ -51,6 +87,12 @@ func (s *StoreSuite) TestMarkSyncedAndGetSyncedDocument() {
 		GranolaUpdatedAt: &updatedAt,
 		LogseqPagePath:   "/pages/test-meeting.md",
 		ContentHash:      "abc123",
+		SyncLatency:      90 * time.Second,
+		OptedOut:         true,
+		Adopted:          true,
+		MeetingDate:      &meetingDate,
+		Attendees:        []string{"Alice Smith", "Bob Jones"},
+		Tags:             []string{"granola", "standup"},
 	}
 
 	// Insert
@@ -65,7 +107,107 @@ func (s *StoreSuite) TestMarkSyncedAndGetSyncedDocument() {
 	s.Equal(doc.Title, retrieved.Title)
 	s.Equal(doc.LogseqPagePath, retrieved.LogseqPagePath)
 	s.Equal(doc.ContentHash, retrieved.ContentHash)
+	s.Equal(doc.SyncLatency, retrieved.SyncLatency)
+	s.True(retrieved.OptedOut)
+	s.True(retrieved.Adopted)
 	s.NotNil(retrieved.GranolaUpdatedAt)
+	s.Require().NotNil(retrieved.MeetingDate)
+	s.True(meetingDate.Equal(*retrieved.MeetingDate))
+	s.Equal(doc.Attendees, retrieved.Attendees)
+	s.Equal(doc.Tags, retrieved.Tags)
+}
+
+func (s *StoreSuite) TestSyncLatencies() {
+	now := time.Now().Truncate(time.Second)
+	docs := []*SyncedDocument{
+		{ID: "recent", Title: "Recent", SyncedAt: now, SyncLatency: 2 * time.Minute},
+		{ID: "old", Title: "Old", SyncedAt: now.AddDate(0, 0, -40), SyncLatency: time.Hour},
+		{ID: "backfilled", Title: "Backfilled", SyncedAt: now},
+	}
+	for _, doc := range docs {
+		s.Require().NoError(s.store.MarkSynced(doc))
+	}
+
+	latencies, err := s.store.SyncLatencies(now.AddDate(0, 0, -30))
+	s.NoError(err)
+	s.Equal([]time.Duration{2 * time.Minute}, latencies)
+}
+
+// markUpdated records a synced document last updated in Granola at updatedAt
+func (s *StoreSuite) markUpdated(id, title string, updatedAt *time.Time) {
+	s.Require().NoError(s.store.MarkSynced(&SyncedDocument{ID: id, Title: title, SyncedAt: time.Now(), GranolaUpdatedAt: updatedAt}))
+}
+
+// ids returns the IDs of documents in order
+func ids(docs []SyncedDocument) []string {
+	var out []string
+	for _, doc := range docs {
+		out = append(out, doc.ID)
+	}
+	return out
+}
+
+func (s *StoreSuite) TestListSynced() {
+	day := time.Date(2025, 1, 28, 12, 0, 0, 0, time.Local)
+	older, newer := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+	s.markUpdated("day", "Planning", &day)
+	s.markUpdated("older", "Standup", &older)
+	s.markUpdated("newer", "Standup", &newer)
+	s.markUpdated("unknown", "Adopted", nil)
+
+	docs, err := s.store.ListSynced(nil, nil)
+	s.Require().NoError(err)
+	s.Equal([]string{"newer", "day", "older", "unknown"}, ids(docs))
+
+	docs, err = s.store.ListSynced(&day, nil)
+	s.Require().NoError(err)
+	s.Equal([]string{"newer", "day"}, ids(docs))
+
+	docs, err = s.store.ListSynced(&older, &newer)
+	s.Require().NoError(err)
+	s.Equal([]string{"day", "older"}, ids(docs), "until is exclusive")
+}
+
+func (s *StoreSuite) TestTimeBoundsInOtherZones() {
+	pacific := time.FixedZone("PST", -8*60*60)
+	tokyo := time.FixedZone("JST", 9*60*60)
+	updatedAt := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+	s.markUpdated("utc", "Planning", &updatedAt)
+	inTokyo := updatedAt.Add(2 * time.Hour).In(tokyo) // 12:00Z, written at +09:00
+	s.markUpdated("tokyo", "Standup", &inTokyo)
+
+	// 03:00 in California is 11:00Z, after the first meeting's update
+	since := time.Date(2025, 1, 28, 3, 0, 0, 0, pacific)
+	docs, err := s.store.ListSynced(&since, nil)
+	s.Require().NoError(err)
+	s.Equal([]string{"tokyo"}, ids(docs))
+
+	docs, err = s.store.ListSynced(nil, &since)
+	s.Require().NoError(err)
+	s.Equal([]string{"utc"}, ids(docs))
+
+	counts, err := s.store.CountByDay(since)
+	s.Require().NoError(err)
+	s.Equal(map[string]int{inTokyo.Local().Format("2006-01-02"): 1}, counts)
+
+	s.Require().NoError(s.store.MarkSynced(&SyncedDocument{ID: "latency", Title: "Retro", SyncedAt: updatedAt, SyncLatency: time.Minute}))
+	latencies, err := s.store.SyncLatencies(since)
+	s.Require().NoError(err)
+	s.Empty(latencies)
+}
+
+func (s *StoreSuite) TestCountByDay() {
+	morning := time.Date(2025, 1, 28, 9, 0, 0, 0, time.Local)
+	afternoon := morning.Add(6 * time.Hour)
+	earlier := morning.AddDate(0, 0, -3)
+	s.markUpdated("a", "Planning", &morning)
+	s.markUpdated("b", "Standup", &afternoon)
+	s.markUpdated("c", "Standup", &earlier)
+	s.markUpdated("d", "Adopted", nil)
+
+	counts, err := s.store.CountByDay(morning.AddDate(0, 0, -1))
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"2025-01-28": 2}, counts)
 }
 
 func (s *StoreSuite) TestMarkSyncedUpsert() {
@@ -98,6 +240,179 @@ func (s *StoreSuite) TestGetSyncedDocumentNotFound() {
 	s.Nil(doc)
 }
 
+func (s *StoreSuite) TestGetSetValue() {
+	value, err := s.store.GetValue("checkpoint")
+	s.NoError(err)
+	s.Empty(value)
+
+	s.Require().NoError(s.store.SetValue("checkpoint", "2025-01-01"))
+	s.Require().NoError(s.store.SetValue("checkpoint", "2025-02-01"))
+	value, err = s.store.GetValue("checkpoint")
+	s.NoError(err)
+	s.Equal("2025-02-01", value)
+
+	s.Require().NoError(s.store.SetValue("checkpoint", ""))
+	value, err = s.store.GetValue("checkpoint")
+	s.NoError(err)
+	s.Empty(value)
+}
+
+func (s *StoreSuite) TestForMachine() {
+	laptop := ForMachine(s.store, "laptop")
+	desktop := ForMachine(s.store, "desktop")
+
+	s.Require().NoError(laptop.SetValue("paused_at", "2025-01-01T00:00:00Z"))
+	value, err := desktop.GetValue("paused_at")
+	s.NoError(err)
+	s.Empty(value, "Expected another machine's value to stay hidden")
+	value, err = laptop.GetValue("paused_at")
+	s.NoError(err)
+	s.Equal("2025-01-01T00:00:00Z", value)
+
+	s.Require().NoError(laptop.MarkSynced(&SyncedDocument{ID: "doc-1", Title: "Shared", SyncedAt: time.Now()}))
+	doc, err := desktop.GetSyncedDocument("doc-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(doc)
+	s.Equal("Shared", doc.Title)
+}
+
+func (s *StoreSuite) TestExportedTasks() {
+	id, err := s.store.GetExportedTask("todoist", "doc-1", "task-a")
+	s.NoError(err)
+	s.Empty(id)
+
+	s.Require().NoError(s.store.MarkTaskExported("todoist", "doc-1", "task-a", "123"))
+
+	id, err = s.store.GetExportedTask("todoist", "doc-1", "task-a")
+	s.NoError(err)
+	s.Equal("123", id)
+
+	// Targets and documents are tracked separately
+	id, err = s.store.GetExportedTask("reminders", "doc-1", "task-a")
+	s.NoError(err)
+	s.Empty(id)
+	id, err = s.store.GetExportedTask("todoist", "doc-2", "task-a")
+	s.NoError(err)
+	s.Empty(id)
+
+	s.Require().NoError(s.store.MarkTaskExported("todoist", "doc-1", "task-b", "456"))
+	s.Require().NoError(s.store.MarkTaskExported("reminders", "doc-1", "task-c", "x-1"))
+	tasks, err := s.store.ListExportedTasks("todoist", "doc-1")
+	s.NoError(err)
+	s.Equal(map[string]string{"task-a": "123", "task-b": "456"}, tasks)
+	tasks, err = s.store.ListExportedTasks("todoist", "doc-2")
+	s.NoError(err)
+	s.Empty(tasks)
+}
+
+func (s *StoreSuite) TestPanelCache() {
+	panels, err := s.store.LoadPanelCache()
+	s.NoError(err)
+	s.Empty(panels)
+
+	s.Require().NoError(s.store.SavePanelCache(map[string]CachedPanel{
+		"panel-1": {ContentUpdatedAt: "2025-01-01T00:00:00Z", Markdown: "- Old"},
+		"panel-2": {ContentUpdatedAt: "2025-01-01T00:00:00Z", Markdown: "- Other"},
+	}))
+	s.Require().NoError(s.store.SavePanelCache(map[string]CachedPanel{
+		"panel-1": {ContentUpdatedAt: "2025-01-02T00:00:00Z", Markdown: "- New"},
+	}))
+
+	panels, err = s.store.LoadPanelCache()
+	s.NoError(err)
+	s.Equal(map[string]CachedPanel{
+		"panel-1": {ContentUpdatedAt: "2025-01-02T00:00:00Z", Markdown: "- New"},
+		"panel-2": {ContentUpdatedAt: "2025-01-01T00:00:00Z", Markdown: "- Other"},
+	}, panels)
+}
+
+func (s *StoreSuite) TestSyncRuns() {
+	runs, err := s.store.RecentSyncRuns(10)
+	s.NoError(err)
+	s.Empty(runs)
+
+	start := time.Now().Truncate(time.Second)
+	for i := range 3 {
+		s.Require().NoError(s.store.RecordSyncRun(&SyncRun{
+			StartedAt:   start.Add(time.Duration(i) * time.Minute),
+			Trigger:     TriggerWatch,
+			Duration:    1500 * time.Millisecond,
+			NewMeetings: i,
+			Errors:      1,
+		}))
+	}
+	s.Require().NoError(s.store.RecordSyncRun(&SyncRun{StartedAt: start.Add(time.Hour), Trigger: TriggerBackfill, Failed: true}))
+
+	runs, err = s.store.RecentSyncRuns(2)
+	s.NoError(err)
+	s.Require().Len(runs, 2)
+	s.True(runs[0].Failed)
+	s.Equal(TriggerBackfill, runs[0].Trigger)
+	s.Equal(TriggerWatch, runs[1].Trigger)
+	s.True(start.Add(time.Hour).Equal(runs[0].StartedAt))
+	s.Equal(2, runs[1].NewMeetings)
+	s.Equal(1, runs[1].Errors)
+	s.Equal(1500*time.Millisecond, runs[1].Duration)
+	s.False(runs[1].Failed)
+}
+
+func (s *StoreSuite) TestResetBefore() {
+	now := time.Now().Truncate(time.Second)
+	s.Require().NoError(s.store.MarkSynced(&SyncedDocument{ID: "old", Title: "Old", SyncedAt: now.AddDate(0, 0, -10)}))
+	s.Require().NoError(s.store.MarkSynced(&SyncedDocument{ID: "new", Title: "New", SyncedAt: now}))
+	s.Require().NoError(s.store.SetValue("checkpoint", "2025-01-01"))
+
+	cutoff := now.AddDate(0, 0, -1)
+	n, err := s.store.Reset(&cutoff)
+	s.NoError(err)
+	s.Equal(1, n)
+
+	doc, err := s.store.GetSyncedDocument("old")
+	s.NoError(err)
+	s.Nil(doc)
+	doc, err = s.store.GetSyncedDocument("new")
+	s.NoError(err)
+	s.NotNil(doc)
+
+	// Metadata is only cleared by a full reset
+	value, err := s.store.GetValue("checkpoint")
+	s.NoError(err)
+	s.Equal("2025-01-01", value)
+}
+
+func (s *StoreSuite) TestResetAll() {
+	now := time.Now().Truncate(time.Second)
+	s.Require().NoError(s.store.MarkSynced(&SyncedDocument{ID: "doc-1", Title: "One", SyncedAt: now}))
+	s.Require().NoError(s.store.MarkSynced(&SyncedDocument{ID: "doc-2", Title: "Two", SyncedAt: now}))
+	s.Require().NoError(s.store.SetValue("checkpoint", "2025-01-01"))
+	s.Require().NoError(s.store.MarkTaskExported("todoist", "doc-1", "task-a", "123"))
+	s.Require().NoError(s.store.SavePanelCache(map[string]CachedPanel{"panel-1": {Markdown: "- Notes"}}))
+	s.Require().NoError(s.store.RecordSyncRun(&SyncRun{StartedAt: now}))
+
+	n, err := s.store.Reset(nil)
+	s.NoError(err)
+	s.Equal(2, n)
+
+	doc, err := s.store.GetSyncedDocument("doc-1")
+	s.NoError(err)
+	s.Nil(doc)
+	value, err := s.store.GetValue("checkpoint")
+	s.NoError(err)
+	s.Empty(value)
+	id, err := s.store.GetExportedTask("todoist", "doc-1", "task-a")
+	s.NoError(err)
+	s.Empty(id)
+	panels, err := s.store.LoadPanelCache()
+	s.NoError(err)
+	s.Empty(panels)
+	runs, err := s.store.RecentSyncRuns(10)
+	s.NoError(err)
+	s.Empty(runs)
+
+	// The store is still usable afterwards
+	s.NoError(s.store.MarkSynced(&SyncedDocument{ID: "doc-1", Title: "One", SyncedAt: now}))
+}
+
 func (s *StoreSuite) TestNeedsUpdate() {
 	t1 := time.Now().Truncate(time.Second)
 	t2 := t1.Add(time.Hour)
@@ -159,7 +474,7 @@ func (s *StoreSuite) TestNeedsUpdate() {
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
 			// Fresh store for each test
-			store, err := NewStore(":memory:")
+			store, err := s.newStore()
 			s.Require().NoError(err)
 			defer func() { _ = store.Close() }()
 
@@ -179,3 +494,109 @@ func (s *StoreSuite) TestNeedsUpdate() {
 		})
 	}
 }
+
+func TestRebind(t *testing.T) {
+	sqlite := &SQLStore{dialect: dialectSQLite}
+	postgres := &SQLStore{dialect: dialectPostgres}
+	query := "SELECT * FROM t WHERE a = ? AND b = ?"
+
+	if got := sqlite.rebind(query); got != query {
+		t.Errorf("sqlite rebind changed query: %s", got)
+	}
+	if got := postgres.rebind(query); got != "SELECT * FROM t WHERE a = $1 AND b = $2" {
+		t.Errorf("postgres rebind = %s", got)
+	}
+}
+
+func TestSQLiteMigratesOldSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "state.db")
+
+	// Create a database with the original schema, before machine_id existed
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE synced_documents (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		synced_at TIMESTAMP NOT NULL,
+		granola_updated_at TIMESTAMP,
+		logseq_page_path TEXT,
+		content_hash TEXT
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO synced_documents (id, title, synced_at, logseq_page_path, content_hash)
+		VALUES ('old-doc', 'Old', CURRENT_TIMESTAMP, '/pages/old.md', 'hash')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err := NewStore(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	doc, err := store.GetSyncedDocument("old-doc")
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+	assert.Equal(t, "", doc.MachineID)
+	assert.Nil(t, doc.MeetingDate)
+	assert.Empty(t, doc.Attendees)
+	assert.Empty(t, doc.Tags)
+
+	doc.MachineID = "laptop"
+	require.NoError(t, store.MarkSynced(doc))
+	doc, err = store.GetSyncedDocument("old-doc")
+	require.NoError(t, err)
+	assert.Equal(t, "laptop", doc.MachineID)
+}
+
+func TestSQLiteConvertsTimesToUTC(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "state.db")
+	store, err := NewStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// Earlier versions stored times with the writer's offset
+	pacific := time.FixedZone("PST", -8*60*60)
+	early := time.Date(2025, 1, 28, 10, 0, 0, 0, time.UTC)
+	late := time.Date(2025, 1, 28, 3, 30, 0, 0, pacific) // 11:30Z
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	for id, at := range map[string]time.Time{"early": early, "late": late} {
+		_, err = db.Exec(`INSERT INTO synced_documents (id, title, synced_at, granola_updated_at, logseq_page_path, content_hash) VALUES (?, ?, ?, ?, '', '')`, id, id, at, at)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	store, err = NewStore(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	since := time.Date(2025, 1, 28, 11, 0, 0, 0, time.UTC)
+	docs, err := store.ListSynced(&since, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"late"}, ids(docs))
+	require.NotNil(t, docs[0].GranolaUpdatedAt)
+	assert.True(t, late.Equal(*docs[0].GranolaUpdatedAt))
+	assert.True(t, late.Equal(docs[0].SyncedAt))
+}
+
+func TestSQLiteQueriesUseIndexes(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	for query, index := range map[string]string{
+		`SELECT granola_updated_at FROM synced_documents WHERE granola_updated_at >= ?`:                   "synced_documents_granola_updated_at",
+		`SELECT ` + documentColumns + ` FROM synced_documents WHERE granola_updated_at < ?` + newestFirst: "synced_documents_granola_updated_at",
+	} {
+		rows, err := store.db.Query("EXPLAIN QUERY PLAN "+query, "x")
+		require.NoError(t, err)
+		var plan strings.Builder
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			require.NoError(t, rows.Scan(&id, &parent, &unused, &detail))
+			plan.WriteString(detail + "\n")
+		}
+		require.NoError(t, rows.Close())
+		assert.Contains(t, plan.String(), index, query)
+	}
+}
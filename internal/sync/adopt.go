@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+)
+
+// AdoptMatch is a hand-made page matched to a Granola meeting
+type AdoptMatch struct {
+	DocumentID string
+	Title      string
+	PagePath   string // The hand-made page that was matched
+	NewPath    string // Where the page lives after adoption (differs when upgraded)
+}
+
+// AdoptResult contains the result of an adopt operation
+type AdoptResult struct {
+	Matches []AdoptMatch
+	Errors  []error
+}
+
+// Adopt scans the graph for hand-made meeting pages that match meetings in the
+// Granola cache by title and date, and records them in state so sync doesn't
+// write duplicates. Sync leaves adopted pages alone. With upgrade, matched and
+// previously adopted pages are regenerated in the standard format (and moved to
+// the standard path, with journal links rewritten) and synced from then on.
+func (s *Syncer) Adopt(upgrade, dryRun bool) (*AdoptResult, error) {
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	main := s.graphs[0]
+	pages, err := logseq.ScanPages(main.fs, main.basePath)
+	if err != nil {
+		return nil, fmt.Errorf("scanning pages: %w", err)
+	}
+
+	result := &AdoptResult{}
+	for _, doc := range docs {
+		if doc.IsDeleted() || !doc.IsUserAttendee(s.cfg.UserEmail) {
+			continue
+		}
+
+		existing, err := s.graphs[0].store.GetSyncedDocument(doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting existing document: %w", err)
+		}
+		var pagePath string
+		switch {
+		case existing != nil && existing.Adopted && upgrade:
+			pagePath = existing.LogseqPagePath
+		case existing != nil:
+			continue
+		default:
+			page := logseq.MatchMeetingPage(pages, doc)
+			if page == nil {
+				continue
+			}
+			// Another meeting of the same name that day mustn't claim the page too
+			pages = slices.DeleteFunc(pages, func(p *logseq.Page) bool { return p == page })
+			pagePath = page.Path
+		}
+
+		match := AdoptMatch{DocumentID: doc.ID, Title: doc.Title, PagePath: pagePath, NewPath: pagePath}
+		if !dryRun {
+			newPath, err := s.adoptManualPage(doc, pagePath, upgrade)
+			if err != nil {
+				slog.Error("failed to adopt page", "id", doc.ID, "title", doc.Title, "error", err)
+				result.Errors = append(result.Errors, fmt.Errorf("doc %s: %w", doc.ID, err))
+				continue
+			}
+			match.NewPath = newPath
+		}
+		result.Matches = append(result.Matches, match)
+	}
+
+	return result, nil
+}
+
+// adoptManualPage records a matched page in state, regenerating it first if upgrading.
+// Returns the page path recorded in state.
+func (s *Syncer) adoptManualPage(doc *granola.Document, pagePath string, upgrade bool) (string, error) {
+	if upgrade {
+		if _, err := s.writer.RenameMeetingPage(pagePath, doc); err != nil {
+			return "", fmt.Errorf("moving page: %w", err)
+		}
+		newPath, err := s.writer.WriteMeetingPage(doc)
+		if err != nil {
+			return "", fmt.Errorf("writing meeting page: %w", err)
+		}
+		pagePath = newPath
+	}
+
+	syncedDoc := s.newSyncedDocument(s.writer, doc, pagePath, hashContent(doc))
+	syncedDoc.Adopted = !upgrade
+	if err := s.graphs[0].store.MarkSynced(syncedDoc); err != nil {
+		return "", fmt.Errorf("marking synced: %w", err)
+	}
+
+	slog.Info("adopted hand-made meeting page", "title", doc.Title, "path", pagePath, "upgraded", upgrade)
+	return pagePath, nil
+}
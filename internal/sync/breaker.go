@@ -0,0 +1,111 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// TargetPersonPages names person pages in the circuit breaker. Meeting pages
+// and journals are the core of a sync and are never disabled; the metrics
+// textfile is written once per run, so it has nothing to guard.
+const TargetPersonPages = "person_pages"
+
+// breakerStateKey is the state value holding the breaker states of the last run
+const breakerStateKey = "output_breakers"
+
+// BreakerState is the circuit-breaker state of one output target
+type BreakerState struct {
+	Target    string    `json:"target"`
+	Failures  int       `json:"failures"` // Consecutive failures
+	Open      bool      `json:"open"`     // Disabled for the rest of the run
+	LastError string    `json:"last_error,omitempty"`
+	OpenedAt  time.Time `json:"opened_at,omitzero"`
+}
+
+// breaker disables an output target for the rest of a run once it has failed
+// threshold times in a row, so a dead target doesn't slow every document
+type breaker struct {
+	threshold int // 0 never opens
+	states    map[string]*BreakerState
+}
+
+func newBreaker(threshold int) *breaker {
+	return &breaker{threshold: threshold, states: make(map[string]*BreakerState)}
+}
+
+// allow reports whether the target may be used
+func (b *breaker) allow(target string) bool {
+	st, ok := b.states[target]
+	return !ok || !st.Open
+}
+
+// record notes the outcome of using a target, opening the breaker when the
+// failure threshold is reached
+func (b *breaker) record(target string, err error) {
+	st, ok := b.states[target]
+	if !ok {
+		st = &BreakerState{Target: target}
+		b.states[target] = st
+	}
+
+	if err == nil {
+		st.Failures = 0
+		return
+	}
+
+	st.Failures++
+	st.LastError = err.Error()
+	if b.threshold > 0 && st.Failures >= b.threshold && !st.Open {
+		st.Open = true
+		st.OpenedAt = time.Now()
+		slog.Warn("disabling output target for the rest of the run", "target", target, "failures", st.Failures, "error", err)
+	}
+}
+
+// errTargetDisabled is returned for work skipped because its target's breaker is open
+func errTargetDisabled(target string) error {
+	return fmt.Errorf("%s disabled after repeated failures", target)
+}
+
+// snapshot returns the breaker states sorted by target
+func (b *breaker) snapshot() []BreakerState {
+	states := make([]BreakerState, 0, len(b.states))
+	for _, st := range b.states {
+		states = append(states, *st)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Target < states[j].Target })
+	return states
+}
+
+// saveBreakerStates persists the breaker states of the finished run for status
+func (s *Syncer) saveBreakerStates() {
+	raw, err := json.Marshal(s.breaker.snapshot())
+	if err != nil {
+		slog.Warn("failed to encode output target states", "error", err)
+		return
+	}
+	if err := s.store.SetValue(breakerStateKey, string(raw)); err != nil {
+		slog.Warn("failed to save output target states", "error", err)
+	}
+}
+
+// LoadBreakerStates returns the output target states recorded by the last sync run
+func LoadBreakerStates(store state.Store) ([]BreakerState, error) {
+	raw, err := store.GetValue(breakerStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading output target states: %w", err)
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	var states []BreakerState
+	if err := json.Unmarshal([]byte(raw), &states); err != nil {
+		return nil, fmt.Errorf("parsing output target states: %w", err)
+	}
+	return states, nil
+}
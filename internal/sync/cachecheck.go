@@ -0,0 +1,149 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// cacheFingerprintKey is the sync_meta key holding the fingerprint of the cache
+// file as of the last sync that left nothing to do. Empty when a sync may still
+// have work: errors, meetings too recent to sync, pages to re-render, or issues
+// to link.
+const cacheFingerprintKey = "cache_fingerprint"
+
+// cacheFingerprint identifies the cache file's contents and the config it was
+// synced with
+type cacheFingerprint struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	SHA256  string    `json:"sha256"`
+	Config  string    `json:"config"`
+}
+
+// ForgetCacheFingerprint makes the next sync parse the cache even if it hasn't
+// changed, e.g. after forgetting synced meetings
+func ForgetCacheFingerprint(store state.Store) error {
+	return store.SetValue(cacheFingerprintKey, "")
+}
+
+// skipsUnchangedCache reports whether a sync with opts may skip parsing an
+// unchanged cache: only a plain sync of every meeting
+func skipsUnchangedCache(opts SyncOptions) bool {
+	return !opts.DryRun && !opts.Backfill && !opts.Force && len(opts.IDs) == 0 && opts.Since == nil && opts.Until == nil
+}
+
+// cacheUnchanged reports whether the cache file is as it was after the last sync
+// that left nothing to do. Also returns the file's current fingerprint, or nil
+// if it couldn't be read.
+func (s *Syncer) cacheUnchanged() (bool, *cacheFingerprint) {
+	var previous *cacheFingerprint
+	if value, err := s.store.GetValue(cacheFingerprintKey); err == nil && value != "" {
+		if err := json.Unmarshal([]byte(value), &previous); err != nil {
+			previous = nil
+		}
+	}
+
+	current, err := s.readCacheFingerprint(previous)
+	if err != nil {
+		slog.Debug("could not fingerprint cache file", "error", err)
+		return false, nil
+	}
+	if previous == nil || current.Size != previous.Size || current.SHA256 != previous.SHA256 || current.Config != previous.Config {
+		return false, current
+	}
+	// Rewritten with the same contents: skip hashing it next time
+	if !current.ModTime.Equal(previous.ModTime) {
+		s.saveCacheFingerprint(current)
+	}
+	return true, current
+}
+
+// readCacheFingerprint fingerprints the cache file. The file is only hashed if
+// its size or modification time differ from previous (which may be nil).
+func (s *Syncer) readCacheFingerprint(previous *cacheFingerprint) (*cacheFingerprint, error) {
+	path, err := granola.FindCacheFile(s.cfg.GranolaDir)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &cacheFingerprint{Size: info.Size(), ModTime: info.ModTime().UTC(), Config: configHash(s.cfg)}
+	if previous != nil && previous.Size == fp.Size && previous.ModTime.Equal(fp.ModTime) {
+		fp.SHA256 = previous.SHA256
+		return fp, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("hashing cache file: %w", err)
+	}
+	fp.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return fp, nil
+}
+
+// configHash hashes the whole config, since most settings change what a sync does
+func configHash(cfg *config.Config) string {
+	raw, _ := json.Marshal(cfg)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// settleCacheFingerprint stores the cache fingerprint after a sync if the sync
+// left nothing to do, so the next one can skip parsing an unchanged cache, and
+// clears it otherwise
+func (s *Syncer) settleCacheFingerprint(fp *cacheFingerprint, docs []*granola.Document, result *SyncResult) {
+	// Filed issues are linked when their meetings are next synced
+	settled := fp != nil && len(result.Errors) == 0 && !s.issuesFiled
+	if settled {
+		if value, err := s.store.GetValue(rerenderBeforeKey); err != nil || value != "" {
+			settled = false
+		}
+	}
+	// Meetings too recent to sync become ready without the cache changing
+	minAge := time.Duration(s.cfg.MinAgeSeconds) * time.Second
+	for _, doc := range docs {
+		if !settled {
+			break
+		}
+		if s.clock.Now().Sub(doc.UpdatedAt) < minAge {
+			settled = false
+		}
+	}
+
+	if !settled {
+		if err := ForgetCacheFingerprint(s.store); err != nil {
+			slog.Warn("failed to clear cache fingerprint", "error", err)
+		}
+		return
+	}
+	s.saveCacheFingerprint(fp)
+}
+
+// saveCacheFingerprint stores fp as the fingerprint of a cache with nothing to sync
+func (s *Syncer) saveCacheFingerprint(fp *cacheFingerprint) {
+	raw, err := json.Marshal(fp)
+	if err == nil {
+		err = s.store.SetValue(cacheFingerprintKey, string(raw))
+	}
+	if err != nil {
+		slog.Warn("failed to store cache fingerprint", "error", err)
+	}
+}
@@ -0,0 +1,137 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// checkpointKey is the state value holding the start of the next backfill window
+const checkpointKey = "backfill_checkpoint"
+
+// checkpointLayout is the date format used for the stored checkpoint
+const checkpointLayout = "2006-01-02"
+
+// ChunkResult is the outcome of syncing one backfill window [Start, End)
+type ChunkResult struct {
+	Start  time.Time
+	End    time.Time
+	Result *SyncResult
+}
+
+// SyncChunked performs a backfill in windows of chunkDays days, oldest first.
+// After each window a checkpoint is stored so an interrupted backfill resumes
+// from the next window when no explicit since date is given. A window with
+// errors holds the checkpoint at its start, so its meetings are retried. Cancelling ctx
+// stops the backfill between windows. onChunk, if set, is called after each window.
+// A backfill that stops early is recorded as a failed run.
+func (s *Syncer) SyncChunked(ctx context.Context, opts SyncOptions, chunkDays int, onChunk func(ChunkResult)) (total *SyncResult, err error) {
+	if chunkDays <= 0 {
+		return nil, fmt.Errorf("chunk days must be positive, got %d", chunkDays)
+	}
+	if err := s.checkBackfillPower(opts); err != nil {
+		return nil, err
+	}
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+
+	runStart := s.beginRun()
+	defer func() {
+		if err != nil {
+			s.finishRun(runStart, nil, opts)
+		} else {
+			s.finishRun(runStart, total, opts)
+		}
+	}()
+	if err := s.runPreSyncHook(opts); err != nil {
+		return nil, err
+	}
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	total = &SyncResult{}
+	if len(docs) == 0 {
+		return total, nil
+	}
+
+	start := startOfDay(docs[0].GetMeetingDate())
+	if opts.Since != nil {
+		start = *opts.Since
+	} else if checkpoint, err := s.loadCheckpoint(); err != nil {
+		return nil, err
+	} else if checkpoint != nil {
+		slog.Info("resuming backfill from checkpoint", "date", checkpoint.Format(checkpointLayout))
+		start = *checkpoint
+	}
+
+	end := startOfDay(docs[len(docs)-1].GetMeetingDate()).AddDate(0, 0, 1)
+	if opts.Until != nil {
+		end = *opts.Until
+	}
+
+	apiClient := s.loadAPIClient()
+	failed := false
+	for chunkStart := start; chunkStart.Before(end); {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		chunkEnd := chunkStart.AddDate(0, 0, chunkDays)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		chunkOpts := opts
+		chunkOpts.Since = &chunkStart
+		chunkOpts.Until = &chunkEnd
+		result := s.syncDocuments(docs, chunkOpts, apiClient)
+		total.add(result)
+		failed = failed || len(result.Errors) > 0
+
+		if !opts.DryRun && !failed {
+			if err := s.store.SetValue(checkpointKey, chunkEnd.Format(checkpointLayout)); err != nil {
+				return total, fmt.Errorf("saving backfill checkpoint: %w", err)
+			}
+		}
+
+		if onChunk != nil {
+			onChunk(ChunkResult{Start: chunkStart, End: chunkEnd, Result: result})
+		}
+		chunkStart = chunkEnd
+	}
+
+	// The backfill finished, so the next one starts from scratch
+	if !opts.DryRun && !failed {
+		if err := s.store.SetValue(checkpointKey, ""); err != nil {
+			return total, fmt.Errorf("clearing backfill checkpoint: %w", err)
+		}
+	}
+
+	return total, nil
+}
+
+// loadCheckpoint returns the stored backfill checkpoint, or nil if there is none
+func (s *Syncer) loadCheckpoint() (*time.Time, error) {
+	value, err := s.store.GetValue(checkpointKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading backfill checkpoint: %w", err)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	checkpoint, err := time.ParseInLocation(checkpointLayout, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("parsing backfill checkpoint %q: %w", value, err)
+	}
+	return &checkpoint, nil
+}
+
+// startOfDay truncates t to local midnight
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,115 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// pausedKey is the sync_meta key holding when syncing was paused from the
+// control page or the pause command
+const pausedKey = "paused_at"
+
+// PausedSince returns when syncing was paused, or nil if it isn't paused
+func PausedSince(store state.Store) (*time.Time, error) {
+	value, err := store.GetValue(pausedKey)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pause time: %w", err)
+	}
+	return &t, nil
+}
+
+// Pause pauses watch mode syncing as of at, as the control page's pause
+// command does
+func Pause(store state.Store, at time.Time) error {
+	return store.SetValue(pausedKey, at.Format(time.RFC3339))
+}
+
+// Resume resumes watch mode syncing
+func Resume(store state.Store) error {
+	return store.SetValue(pausedKey, "")
+}
+
+// RunControlCommands runs the pending commands on the main graph's control page
+// and marks them done, with a note of what happened. Returns the number of
+// commands run.
+func (s *Syncer) RunControlCommands() (int, error) {
+	basePath := s.cfg.LogseqBasePath
+	content, err := logseq.ReadControlPage(basePath)
+	if err != nil {
+		return 0, err
+	}
+	cmds := logseq.PendingControlCommands(content)
+	if len(cmds) == 0 {
+		return 0, nil
+	}
+
+	results := make(map[string]logseq.ControlResult, len(cmds))
+	for _, cmd := range cmds {
+		if _, done := results[cmd]; done {
+			continue
+		}
+		result := s.runControlCommand(cmd)
+		slog.Info("ran control page command", "command", cmd, "ok", result.OK, "result", result.Note)
+		results[cmd] = result
+	}
+
+	// Commands can take a while, so apply the results to the page as it is now
+	content, err = logseq.ReadControlPage(basePath)
+	if err != nil {
+		return 0, err
+	}
+	if err := logseq.WriteControlPage(basePath, logseq.CompleteControlCommands(content, results)); err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
+
+// runControlCommand runs one control page command
+func (s *Syncer) runControlCommand(cmd string) logseq.ControlResult {
+	stamp := s.clock.Now().Format("2006-01-02 15:04")
+	fields := strings.Fields(cmd)
+	switch {
+	case len(fields) == 1 && fields[0] == "pause":
+		if err := Pause(s.store, s.clock.Now()); err != nil {
+			return logseq.ControlResult{Note: fmt.Sprintf("%s failed: %v", stamp, err)}
+		}
+		return logseq.ControlResult{OK: true, Note: stamp + " paused syncing"}
+
+	case len(fields) == 1 && fields[0] == "resume":
+		if err := Resume(s.store); err != nil {
+			return logseq.ControlResult{Note: fmt.Sprintf("%s failed: %v", stamp, err)}
+		}
+		return logseq.ControlResult{OK: true, Note: stamp + " resumed syncing"}
+
+	case len(fields) == 2 && fields[0] == "resync" && fields[1] == "all":
+		return resyncResult(s.Sync(SyncOptions{Backfill: true, Force: true}))
+
+	case len(fields) > 1 && fields[0] == "resync":
+		return resyncResult(s.Sync(SyncOptions{IDs: fields[1:], Force: true}))
+
+	default:
+		return logseq.ControlResult{Note: stamp + " unknown command; use resync <granola-id>, resync all, pause or resume"}
+	}
+}
+
+// resyncResult describes the outcome of a resync command
+func resyncResult(result *SyncResult, err error) logseq.ControlResult {
+	stamp := time.Now().Format("2006-01-02 15:04")
+	if err != nil {
+		return logseq.ControlResult{Note: fmt.Sprintf("%s failed: %v", stamp, err)}
+	}
+	note := fmt.Sprintf("%s %d new, %d updated", stamp, result.NewMeetings, result.UpdatedMeetings)
+	if len(result.Errors) > 0 {
+		return logseq.ControlResult{Note: fmt.Sprintf("%s, %d errors (see logs)", note, len(result.Errors))}
+	}
+	return logseq.ControlResult{OK: true, Note: note}
+}
@@ -0,0 +1,117 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// Estimate is what a sync would write, worked out without writing anything
+type Estimate struct {
+	NewPages       int
+	UpdatedPages   int
+	JournalEntries int   // Journal entries (or journal-only blocks) written
+	NewJournals    int   // Journal files created
+	Bytes          int64 // Size of the pages and journal entries written
+	// Duration is the expected sync time at the average per-meeting speed of
+	// past syncs, or 0 if none have written anything yet
+	Duration time.Duration
+}
+
+// Meetings returns the number of meetings the sync would write
+func (e *Estimate) Meetings() int {
+	return e.NewPages + e.UpdatedPages
+}
+
+// Estimate works out what a sync with opts would write, rendering each meeting
+// without writing it. Meetings too recently edited to sync yet are included,
+// since a backfill will reach them.
+func (s *Syncer) Estimate(opts SyncOptions) (*Estimate, error) {
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	est := &Estimate{}
+	newJournals := make(map[string]bool)
+	for _, doc := range docs {
+		if s.skipReason(doc, opts, 0) != "" {
+			continue
+		}
+
+		g := s.graphFor(doc)
+		needsUpdate, err := g.store.NeedsUpdate(doc.ID, doc.UpdatedAt, s.documentHash(doc))
+		if err != nil {
+			return nil, fmt.Errorf("checking update status of %s: %w", doc.ID, err)
+		}
+		if !needsUpdate && !opts.Force {
+			continue
+		}
+		existing, err := g.store.GetSyncedDocument(doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting synced document %s: %w", doc.ID, err)
+		}
+		if existing == nil {
+			est.NewPages++
+		} else {
+			est.UpdatedPages++
+		}
+
+		var journalPath, entry string
+		if s.cfg.JournalOnly {
+			journalPath, entry, _ = g.writer.DryRunJournalMeeting(doc)
+		} else {
+			_, content := g.writer.DryRunMeetingPage(doc)
+			est.Bytes += int64(len(content))
+			if existing == nil {
+				var wouldAdd bool
+				if journalPath, entry, wouldAdd = g.writer.DryRunJournalEntry(doc); !wouldAdd {
+					journalPath = ""
+				}
+			}
+		}
+		if journalPath == "" {
+			continue
+		}
+		est.JournalEntries++
+		est.Bytes += int64(len(entry))
+		if _, err := g.fs.Stat(journalPath); os.IsNotExist(err) && !newJournals[journalPath] {
+			newJournals[journalPath] = true
+			est.NewJournals++
+		}
+	}
+
+	perMeeting, err := syncTimePerMeeting(s.store)
+	if err != nil {
+		return nil, fmt.Errorf("reading sync history: %w", err)
+	}
+	est.Duration = perMeeting * time.Duration(est.Meetings())
+	return est, nil
+}
+
+// syncTimePerMeeting returns the average time past syncs took per meeting
+// written, or 0 if none have written anything
+func syncTimePerMeeting(store state.Store) (time.Duration, error) {
+	runs, err := store.RecentSyncRuns(100)
+	if err != nil {
+		return 0, err
+	}
+	var total time.Duration
+	var meetings int
+	for _, run := range runs {
+		if run.Failed || run.NewMeetings+run.UpdatedMeetings == 0 {
+			continue
+		}
+		total += run.Duration
+		meetings += run.NewMeetings + run.UpdatedMeetings
+	}
+	if meetings == 0 {
+		return 0, nil
+	}
+	return total / time.Duration(meetings), nil
+}
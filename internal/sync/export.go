@@ -0,0 +1,127 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// RenderMeetings renders the pages of every meeting Sync would write in the
+// date window, as archive files with paths relative to the graph. The graph
+// and state are not touched.
+func (s *Syncer) RenderMeetings(opts SyncOptions) ([]export.File, error) {
+	docs, err := s.exportDocuments(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []export.File
+	for _, doc := range docs {
+		pagePath, content := s.writer.DryRunMeetingPage(doc)
+		name, err := filepath.Rel(s.graphs[0].basePath, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("page path for %s: %w", doc.ID, err)
+		}
+		files = append(files, export.File{
+			Name:    filepath.ToSlash(name),
+			Content: []byte(content),
+			ModTime: doc.UpdatedAt,
+		})
+	}
+
+	return files, nil
+}
+
+// ExportMeetings returns normalized records for every meeting Sync would write
+// in the date window. Attendees are pseudonymized when anonymize_attendees is on.
+func (s *Syncer) ExportMeetings(opts SyncOptions) ([]export.Meeting, error) {
+	docs, err := s.exportDocuments(opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.meetingRecords(docs), nil
+}
+
+// CachedMeetings returns normalized records for every meeting in the Granola
+// cache that Sync would write, however recent. Unlike ExportMeetings it never
+// calls the API, so meetings without notes in the cache have none.
+func (s *Syncer) CachedMeetings() ([]export.Meeting, error) {
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+	var selected []*granola.Document
+	for _, doc := range docs {
+		if reason := s.skipReason(doc, SyncOptions{}, 0); reason == "" {
+			selected = append(selected, doc)
+		}
+	}
+	return s.meetingRecords(selected), nil
+}
+
+// SyncedPagePath returns where a meeting's page was written in whichever graph
+// synced it, or empty if none has
+func (s *Syncer) SyncedPagePath(id string) (string, error) {
+	if err := s.openGraphs(); err != nil {
+		return "", err
+	}
+	for _, g := range s.graphs {
+		synced, err := g.store.GetSyncedDocument(id)
+		if err != nil {
+			return "", fmt.Errorf("getting synced document: %w", err)
+		}
+		if synced != nil {
+			return synced.LogseqPagePath, nil
+		}
+	}
+	return "", nil
+}
+
+// meetingRecords builds the export records for docs
+func (s *Syncer) meetingRecords(docs []*granola.Document) []export.Meeting {
+	anonymizer := formatOptions(s.cfg).Anonymizer
+	meetings := make([]export.Meeting, 0, len(docs))
+	for _, doc := range docs {
+		m := export.NewMeeting(doc)
+		for i, a := range m.Attendees {
+			anon := anonymizer.Attendee(granola.MeetingAttendee{Name: a.Name, Email: a.Email})
+			m.Attendees[i] = export.Attendee{Name: anon.Name, Email: anon.Email}
+		}
+		for _, item := range s.writer.ActionItems(doc) {
+			m.ActionItems = append(m.ActionItems, export.ActionItem{
+				Owner: item.Owner,
+				Text:  item.Text,
+				Mine:  s.cfg.UserName != "" && item.Owner == s.cfg.UserName,
+			})
+		}
+		meetings = append(meetings, m)
+	}
+	return meetings
+}
+
+// exportDocuments returns the documents Sync would write in the date window,
+// however recent, with missing notes fetched from the API as in Sync
+func (s *Syncer) exportDocuments(opts SyncOptions) ([]*granola.Document, error) {
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := s.loadAPIClient()
+	var lastAPICall time.Time
+	var selected []*granola.Document
+	for _, doc := range docs {
+		if reason := s.skipReason(doc, opts, 0); reason != "" {
+			continue
+		}
+		if !doc.HasNotes() && apiClient != nil {
+			s.fetchAndPopulateNotes(context.Background(), doc, &apiClient, &lastAPICall)
+		}
+		selected = append(selected, doc)
+	}
+	return selected, nil
+}
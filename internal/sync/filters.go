@@ -0,0 +1,126 @@
+package sync
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// titlePattern is a configured title pattern with its case-insensitive regexp
+type titlePattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// titleFilter includes or excludes meetings by title
+type titleFilter struct {
+	include []titlePattern
+	exclude []titlePattern
+}
+
+// newTitleFilter compiles the title patterns in config. They were validated
+// when the config was loaded.
+func newTitleFilter(cfg *config.Config) *titleFilter {
+	return &titleFilter{
+		include: compileTitlePatterns(cfg.IncludeTitlePatterns),
+		exclude: compileTitlePatterns(cfg.ExcludeTitlePatterns),
+	}
+}
+
+// compileTitlePatterns compiles regular expressions to match case-insensitively
+func compileTitlePatterns(patterns []string) []titlePattern {
+	var compiled []titlePattern
+	for _, p := range patterns {
+		compiled = append(compiled, titlePattern{pattern: p, re: regexp.MustCompile("(?i)" + p)})
+	}
+	return compiled
+}
+
+// skipReason returns why a title is filtered out, or empty string if it isn't.
+// Exclusions win over inclusions.
+func (f *titleFilter) skipReason(title string) string {
+	for _, p := range f.exclude {
+		if p.re.MatchString(title) {
+			return fmt.Sprintf("title matches exclude pattern %q", p.pattern)
+		}
+	}
+	if len(f.include) == 0 {
+		return ""
+	}
+	for _, p := range f.include {
+		if p.re.MatchString(title) {
+			return ""
+		}
+	}
+	return "title matches no include pattern"
+}
+
+// domainFilter only includes meetings with someone from one of its domains
+type domainFilter struct {
+	domains     []string
+	byOrganizer bool // Only the organizer counts, not every attendee
+}
+
+// newDomainFilter creates the domain filter from config, or nil if include_domains is empty
+func newDomainFilter(cfg *config.Config) *domainFilter {
+	if len(cfg.IncludeDomains) == 0 {
+		return nil
+	}
+	return &domainFilter{
+		domains:     normalizeDomains(cfg.IncludeDomains),
+		byOrganizer: cfg.DomainFilterBy == "organizer",
+	}
+}
+
+// skipReason returns why a meeting is filtered out by domain, or empty string if it isn't
+func (f *domainFilter) skipReason(doc *granola.Document) string {
+	if f == nil {
+		return ""
+	}
+
+	organizer := doc.GetOrganizerEmail()
+	if f.byOrganizer {
+		if matchesDomain(organizer, f.domains) {
+			return ""
+		}
+		return "organizer not from an included domain"
+	}
+
+	if matchesDomain(organizer, f.domains) {
+		return ""
+	}
+	for _, a := range doc.GetAttendees() {
+		if matchesDomain(a.Email, f.domains) {
+			return ""
+		}
+	}
+	return "no attendee from an included domain"
+}
+
+// normalizeDomains lowercases domains and drops a leading @
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, len(domains))
+	for i, d := range domains {
+		normalized[i] = strings.ToLower(strings.TrimPrefix(d, "@"))
+	}
+	return normalized
+}
+
+// matchesDomain reports whether an email address is from one of the (normalized)
+// domains or a subdomain of one
+func matchesDomain(email string, domains []string) bool {
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return false
+	}
+	domain = strings.ToLower(domain)
+	for _, d := range domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
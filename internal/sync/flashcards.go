@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+)
+
+// definitionLabel marks bullets that become one card per term ("Definition:
+// ARR: annual recurring revenue") rather than a card per meeting
+const definitionLabel = "definition"
+
+// flashcardTag is on every card, so exported cards are easy to find in Anki
+const flashcardTag = "granola-sync"
+
+// Flashcards returns spaced-repetition cards for the bullets labeled with one
+// of flashcard_labels in every meeting Sync would write in the date window.
+// Definitions become a card per term; other labels a card per meeting listing
+// its bullets, e.g. the decisions made. With flashcard_tags set, only meetings
+// with one of those page tags are included.
+func (s *Syncer) Flashcards(opts SyncOptions) ([]export.Flashcard, error) {
+	docs, err := s.exportDocuments(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []export.Flashcard
+	for _, doc := range docs {
+		tags := s.writer.PageTags(doc)
+		if !s.wantsFlashcards(tags) {
+			continue
+		}
+		cards = append(cards, meetingFlashcards(doc, s.writer.LabeledBullets(doc, s.cfg.FlashcardLabels), tags)...)
+	}
+	return cards, nil
+}
+
+// wantsFlashcards reports whether a meeting with tags passes flashcard_tags
+func (s *Syncer) wantsFlashcards(tags []string) bool {
+	if len(s.cfg.FlashcardTags) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(tags, func(tag string) bool {
+		return slices.ContainsFunc(s.cfg.FlashcardTags, func(want string) bool {
+			return strings.EqualFold(tag, want)
+		})
+	})
+}
+
+// meetingFlashcards builds a meeting's cards from its labeled bullets
+func meetingFlashcards(doc *granola.Document, bullets []logseq.LabeledBullet, tags []string) []export.Flashcard {
+	source := fmt.Sprintf("%s (%s)", doc.Title, doc.GetMeetingDate().Format("2006-01-02"))
+
+	var cards []export.Flashcard
+	grouped := make(map[string][]string)
+	var labels []string // In order of first appearance
+	for _, b := range bullets {
+		cardTags := append(slices.Clone(tags), flashcardTag, strings.ToLower(b.Label))
+		if strings.EqualFold(b.Label, definitionLabel) {
+			if term, meaning, ok := splitDefinition(b.Text); ok {
+				cards = append(cards, export.Flashcard{Front: term, Back: meaning + "\n\n" + source, Tags: cardTags})
+				continue
+			}
+		}
+		if _, seen := grouped[b.Label]; !seen {
+			labels = append(labels, b.Label)
+		}
+		grouped[b.Label] = append(grouped[b.Label], "• "+b.Text)
+	}
+
+	for _, label := range labels {
+		cards = append(cards, export.Flashcard{
+			Front: fmt.Sprintf("%s: %s?", source, label),
+			Back:  strings.Join(grouped[label], "\n"),
+			Tags:  append(slices.Clone(tags), flashcardTag, strings.ToLower(label)),
+		})
+	}
+	return cards
+}
+
+// splitDefinition splits "term: meaning", "term — meaning" or "term - meaning"
+func splitDefinition(text string) (term, meaning string, ok bool) {
+	for _, sep := range []string{": ", " — ", " – ", " - ", " = "} {
+		if term, meaning, ok := strings.Cut(text, sep); ok && strings.TrimSpace(term) != "" && strings.TrimSpace(meaning) != "" {
+			return strings.TrimSpace(term), strings.TrimSpace(meaning), true
+		}
+	}
+	return "", "", false
+}
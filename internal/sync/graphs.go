@@ -0,0 +1,224 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/remote"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// graph is a Logseq graph meetings are written to, with its own sync state
+type graph struct {
+	name     string // Empty for the main graph
+	basePath string
+	writer   *logseq.Writer
+	store    state.Store
+	route    *graphRoute  // nil for the main graph, which takes unrouted meetings
+	remote   remote.Graph // nil unless the graph is written over WebDAV or SFTP
+	fs       logseq.FS    // The remote graph, or the local disk
+
+	// pageIndex maps granola-id to page path for pages already in the graph.
+	// Built lazily once per sync and only when adopting existing pages.
+	pageIndex map[string]string
+}
+
+// graphStore keeps a graph's page paths relative to the graph in its state
+// store, so machines sharing the store find each page in their own copy of
+// the graph. Paths read back are absolute; absolute paths stored by earlier
+// versions are returned as they are.
+type graphStore struct {
+	state.Store
+	basePath string
+}
+
+// GetSyncedDocument retrieves a synced document by ID, or nil if it was never synced
+func (s *graphStore) GetSyncedDocument(id string) (*state.SyncedDocument, error) {
+	doc, err := s.Store.GetSyncedDocument(id)
+	if doc != nil {
+		doc.LogseqPagePath = s.resolve(doc.LogseqPagePath)
+	}
+	return doc, err
+}
+
+// ListSynced returns the synced documents last updated in Granola within [since, until)
+func (s *graphStore) ListSynced(since, until *time.Time) ([]state.SyncedDocument, error) {
+	docs, err := s.Store.ListSynced(since, until)
+	for i := range docs {
+		docs[i].LogseqPagePath = s.resolve(docs[i].LogseqPagePath)
+	}
+	return docs, err
+}
+
+// MarkSynced records that a document has been synced, with its page path
+// relative to the graph
+func (s *graphStore) MarkSynced(doc *state.SyncedDocument) error {
+	stored := *doc
+	if filepath.IsAbs(doc.LogseqPagePath) {
+		rel, err := filepath.Rel(s.basePath, doc.LogseqPagePath)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			stored.LogseqPagePath = rel
+		}
+	}
+	return s.Store.MarkSynced(&stored)
+}
+
+// resolve returns the absolute path of a page path read from the store
+func (s *graphStore) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.basePath, path)
+}
+
+// graphRoute decides which meetings belong in an extra graph
+type graphRoute struct {
+	domains   []string
+	calendars []string
+	titles    []*regexp.Regexp
+}
+
+// newGraphRoute compiles a graph's routing rules. Title patterns were validated
+// when the config was loaded.
+func newGraphRoute(g config.GraphConfig) *graphRoute {
+	route := &graphRoute{domains: normalizeDomains(g.Domains), calendars: g.Calendars}
+	for _, pattern := range g.TitlePatterns {
+		route.titles = append(route.titles, regexp.MustCompile("(?i)"+pattern))
+	}
+	return route
+}
+
+// matches reports whether a meeting has an attendee from one of the route's
+// domains (or a subdomain), is on one of its calendars, or has a matching title
+func (r *graphRoute) matches(doc *granola.Document) bool {
+	for _, a := range doc.GetAttendees() {
+		if matchesDomain(a.Email, r.domains) {
+			return true
+		}
+	}
+
+	if calendar := doc.GetCalendar(); calendar != "" {
+		for _, c := range r.calendars {
+			if strings.EqualFold(calendar, c) {
+				return true
+			}
+		}
+	}
+
+	for _, re := range r.titles {
+		if re.MatchString(doc.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+// graphConfig returns a copy of cfg pointing at an extra graph, so per-graph
+// settings (journal format, backup and archive locations) are derived the same way
+func graphConfig(cfg *config.Config, g config.GraphConfig) *config.Config {
+	graphCfg := *cfg
+	graphCfg.LogseqBasePath = g.LogseqBasePath
+	graphCfg.RemoteGraph = ""
+	if cfg.BackupDir != "" {
+		graphCfg.BackupDir = filepath.Join(cfg.BackupDir, g.Name)
+	}
+	if cfg.ArchiveDir != "" {
+		graphCfg.ArchiveDir = filepath.Join(cfg.ArchiveDir, g.Name)
+	}
+	return &graphCfg
+}
+
+// newGraph creates the writer for a graph described by cfg
+func newGraph(name string, cfg *config.Config, store state.Store, route *graphRoute, renders *logseq.RenderCache) *graph {
+	basePath := cfg.LogseqBasePath
+	fsys := logseq.OSFS
+	var remoteGraph remote.Graph
+	if cfg.RemoteGraph != "" {
+		// The URL was checked when the config was loaded
+		var err error
+		if remoteGraph, basePath, err = remote.Open(cfg.RemoteGraph); err != nil {
+			slog.Error("can't open remote graph, writing to logseq_base_path", "error", err)
+			remoteGraph, basePath = nil, cfg.LogseqBasePath
+		} else {
+			fsys = remoteGraph
+		}
+	}
+	opts := formatOptions(cfg)
+	journal, err := logseq.ReadJournalFormat(fsys, basePath)
+	if err != nil {
+		slog.Warn("using default journal format", "error", err)
+	}
+	opts.Journal = journal
+	writer := logseq.NewWriter(basePath, cfg.UserName, opts)
+	writer.SetFS(fsys)
+	writer.SetRenderCache(renders, renderTemplate(cfg, opts.Journal))
+	if cfg.BackupRetention > 0 {
+		writer.SetBackups(logseq.NewBackups(backupDir(cfg), cfg.BackupRetention))
+	}
+	if cfg.ArchiveDir != "" {
+		writer.SetArchive(logseq.NewArchive(cfg.ArchiveDir, cfg.ArchiveMode == "hardlink"))
+	}
+	writer.SetSidecars(cfg.JSONSidecars)
+	writer.SetSpotlight(cfg.SpotlightMetadata)
+	store = &graphStore{Store: store, basePath: basePath}
+	return &graph{name: name, basePath: basePath, writer: writer, store: store, route: route, remote: remoteGraph, fs: fsys}
+}
+
+// openGraphs opens the state stores of the extra graphs in config. It is called
+// at the start of each sync and only opens them the first time.
+func (s *Syncer) openGraphs() error {
+	if len(s.graphs) > 1 || len(s.cfg.Graphs) == 0 {
+		return nil
+	}
+
+	for _, g := range s.cfg.Graphs {
+		store, err := state.Open(s.cfg.StateBackend, s.cfg.GraphStateLocation(g), s.machineID)
+		if err != nil {
+			_ = s.Close()
+			return fmt.Errorf("opening state store for graph %s: %w", g.Name, err)
+		}
+		extra := newGraph(g.Name, graphConfig(s.cfg, g), store, newGraphRoute(g), s.renders)
+		if s.issues != nil {
+			extra.writer.SetIssueLinker(s.issues)
+		}
+		s.graphs = append(s.graphs, extra)
+	}
+	return nil
+}
+
+// Close closes the state stores of the extra graphs and any connection to a
+// remote graph. The main store belongs to the caller and is left open.
+func (s *Syncer) Close() error {
+	var errs []error
+	if r := s.graphs[0].remote; r != nil {
+		if err := r.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing remote graph: %w", err))
+		}
+	}
+	for _, g := range s.graphs[1:] {
+		if err := g.store.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing state store for graph %s: %w", g.name, err))
+		}
+	}
+	s.graphs = s.graphs[:1]
+	return errors.Join(errs...)
+}
+
+// graphFor returns the graph a meeting is written to: the first extra graph whose
+// route matches, or the main graph
+func (s *Syncer) graphFor(doc *granola.Document) *graph {
+	for _, g := range s.graphs[1:] {
+		if g.route.matches(doc) {
+			return g
+		}
+	}
+	return s.graphs[0]
+}
@@ -0,0 +1,230 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/keychain"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/readwise"
+	"github.com/philrhinehart/granola-sync/internal/reflectapp"
+)
+
+// Output target names for highlight exporters
+const (
+	TargetReadwise = "readwise"
+	TargetReflect  = "reflect"
+)
+
+// lookupToken reads an API token from the Keychain; tests replace it
+var lookupToken = keychain.Password
+
+// meetingHighlights are the sections of a meeting's notes
+type meetingHighlights struct {
+	Title      string
+	Date       time.Time
+	PageURL    string // logseq:// link back to the meeting page
+	Highlights []logseq.Highlight
+}
+
+// name is what the meeting is called in the external app, e.g.
+// "Roadmap (2025-01-28)", so repeats of a meeting stay apart
+func (m meetingHighlights) name() string {
+	return fmt.Sprintf("%s (%s)", m.Title, m.Date.Format("2006-01-02"))
+}
+
+// highlightExporter sends a meeting's highlights to a reading or notes app
+type highlightExporter interface {
+	// target names the exporter in state and the circuit breaker
+	target() string
+	// key identifies what export would send for m; the meeting is sent again
+	// only when its key changes
+	key(m meetingHighlights) string
+	// export sends the highlights and returns their ID in the external app
+	export(ctx context.Context, m meetingHighlights) (string, error)
+}
+
+// highlightExporters builds the exporters enabled in config
+func (s *Syncer) highlightExporters() []highlightExporter {
+	var exporters []highlightExporter
+	if s.cfg.ReadwiseHighlights {
+		exporters = append(exporters, &readwiseExporter{token: keychainToken{account: TargetReadwise}})
+	}
+	if s.cfg.ReflectGraphID != "" {
+		exporters = append(exporters, &reflectExporter{graphID: s.cfg.ReflectGraphID, token: keychainToken{account: TargetReflect}})
+	}
+	return exporters
+}
+
+// meetingHighlights returns the sections of a meeting's notes
+func (s *Syncer) meetingHighlights(g *graph, doc *granola.Document) meetingHighlights {
+	pageName := logseq.GetPageName(doc, s.cfg.PageNameTemplate)
+	return meetingHighlights{
+		Title:      doc.Title,
+		Date:       doc.GetMeetingDate(),
+		PageURL:    logseq.PageURL(g.basePath, pageName),
+		Highlights: g.writer.Highlights(doc),
+	}
+}
+
+// exportHighlights sends a meeting's highlights to each enabled exporter,
+// skipping exporters that already have them so re-syncs don't duplicate them
+func (s *Syncer) exportHighlights(ctx context.Context, g *graph, doc *granola.Document) error {
+	if len(s.highlighters) == 0 {
+		return nil
+	}
+	m := s.meetingHighlights(g, doc)
+	if len(m.Highlights) == 0 {
+		return nil
+	}
+
+	for _, exp := range s.highlighters {
+		target := exp.target()
+		// Leave the document unsynced so the export is retried next run
+		if !s.breaker.allow(target) {
+			return errTargetDisabled(target)
+		}
+
+		key := exp.key(m)
+		existing, err := s.store.GetExportedTask(target, doc.ID, key)
+		if err != nil {
+			return fmt.Errorf("checking exported highlights: %w", err)
+		}
+		if existing != "" {
+			continue
+		}
+
+		externalID, err := exp.export(ctx, m)
+		s.breaker.record(target, err)
+		if err != nil {
+			return fmt.Errorf("exporting highlights to %s: %w", target, err)
+		}
+		if err := s.store.MarkTaskExported(target, doc.ID, key, externalID); err != nil {
+			return fmt.Errorf("recording exported highlights: %w", err)
+		}
+		slog.Info("exported highlights", "title", doc.Title, "target", target, "sections", len(m.Highlights))
+	}
+
+	return nil
+}
+
+// pendingHighlights lists the exporters that would be sent a meeting's
+// highlights, for dry runs
+func (s *Syncer) pendingHighlights(g *graph, doc *granola.Document) []string {
+	m := s.meetingHighlights(g, doc)
+	if len(m.Highlights) == 0 {
+		return nil
+	}
+	var pending []string
+	for _, exp := range s.highlighters {
+		existing, err := s.store.GetExportedTask(exp.target(), doc.ID, exp.key(m))
+		if err == nil && existing == "" {
+			pending = append(pending, exp.target())
+		}
+	}
+	return pending
+}
+
+// highlightsKey identifies the content of a meeting's highlights
+func highlightsKey(highlights []logseq.Highlight) string {
+	var sb strings.Builder
+	for _, h := range highlights {
+		sb.WriteString(h.Heading + "\n" + strings.Join(h.Bullets, "\n") + "\n")
+	}
+	return taskKey(sb.String())
+}
+
+// highlightsMarkdown renders highlights as a heading and bullets per section
+func highlightsMarkdown(highlights []logseq.Highlight) string {
+	var sb strings.Builder
+	for _, h := range highlights {
+		if h.Heading != "" {
+			sb.WriteString("## " + h.Heading + "\n")
+		}
+		for _, b := range h.Bullets {
+			sb.WriteString("- " + b + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// keychainToken reads an API token from the Keychain on first use
+type keychainToken struct {
+	account string // The Keychain account, e.g. readwise
+	token   string
+}
+
+func (t *keychainToken) get() (string, error) {
+	if t.token == "" {
+		token, err := lookupToken(t.account)
+		if err != nil {
+			return "", err
+		}
+		t.token = token
+	}
+	return t.token, nil
+}
+
+// readwiseExporter creates Readwise highlights, one per section, in a book
+// named after the meeting
+type readwiseExporter struct {
+	baseURL string // Empty for the Readwise API; set in tests
+	token   keychainToken
+}
+
+func (e *readwiseExporter) target() string { return TargetReadwise }
+
+// key changes whenever the notes do. Readwise skips highlights already in the
+// book, so resending an edited meeting only adds its changed sections.
+func (e *readwiseExporter) key(m meetingHighlights) string {
+	return highlightsKey(m.Highlights)
+}
+
+func (e *readwiseExporter) export(ctx context.Context, m meetingHighlights) (string, error) {
+	token, err := e.token.get()
+	if err != nil {
+		return "", err
+	}
+	date := m.Date
+	var highlights []readwise.Highlight
+	for _, h := range m.Highlights {
+		highlights = append(highlights, readwise.Highlight{
+			Text:          "- " + strings.Join(h.Bullets, "\n- "),
+			Title:         m.name(),
+			Author:        "Granola",
+			SourceType:    "granola-sync",
+			Category:      "articles",
+			Note:          h.Heading,
+			HighlightedAt: &date,
+		})
+	}
+	return readwise.NewClient(e.baseURL, token).CreateHighlights(ctx, highlights)
+}
+
+// reflectExporter creates a Reflect note per meeting. Reflect's API can't
+// update notes, so a meeting is only sent once.
+type reflectExporter struct {
+	baseURL string // Empty for the Reflect API; set in tests
+	graphID string
+	token   keychainToken
+}
+
+func (e *reflectExporter) target() string { return TargetReflect }
+
+func (e *reflectExporter) key(m meetingHighlights) string { return "note" }
+
+func (e *reflectExporter) export(ctx context.Context, m meetingHighlights) (string, error) {
+	token, err := e.token.get()
+	if err != nil {
+		return "", err
+	}
+	content := highlightsMarkdown(m.Highlights) + fmt.Sprintf("\nFrom [%s](%s)\n", m.Title, m.PageURL)
+	return reflectapp.NewClient(e.baseURL, e.graphID, token).CreateNote(ctx, reflectapp.Note{
+		Subject:         m.name(),
+		ContentMarkdown: content,
+	})
+}
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hookTimeout is how long a sync hook may run before it is killed
+const hookTimeout = 5 * time.Minute
+
+// runPreSyncHook runs the pre_sync_hook command, if any, before a sync. A
+// failing hook fails the sync, so a hook can stop it (e.g. when pulling the
+// graph hits a conflict).
+func (s *Syncer) runPreSyncHook(opts SyncOptions) error {
+	if s.cfg.PreSyncHook == "" || opts.DryRun {
+		return nil
+	}
+	env := []string{
+		"GRANOLA_SYNC_GRAPH=" + s.cfg.LogseqBasePath,
+		"GRANOLA_SYNC_TRIGGER=" + runTrigger(opts),
+	}
+	if err := runHook(s.cfg.PreSyncHook, s.cfg.LogseqBasePath, env); err != nil {
+		return fmt.Errorf("pre-sync hook: %w", err)
+	}
+	return nil
+}
+
+// runPostSyncHook runs the post_sync_hook command, if any, after a sync with
+// its outcome in the environment. A nil result is a failed sync. A failing
+// hook is only logged, since the sync itself is done.
+func (s *Syncer) runPostSyncHook(result *SyncResult, opts SyncOptions) {
+	if s.cfg.PostSyncHook == "" {
+		return
+	}
+	if err := runHook(s.cfg.PostSyncHook, s.cfg.LogseqBasePath, postSyncEnv(s.cfg.LogseqBasePath, result, opts)); err != nil {
+		slog.Warn("post-sync hook failed", "error", err)
+	}
+}
+
+// postSyncEnv describes a finished sync to the post-sync hook
+func postSyncEnv(graph string, result *SyncResult, opts SyncOptions) []string {
+	status := "ok"
+	switch {
+	case result == nil:
+		status = "failed"
+		result = &SyncResult{}
+	case len(result.Errors) > 0:
+		status = "errors"
+	}
+	return []string{
+		"GRANOLA_SYNC_GRAPH=" + graph,
+		"GRANOLA_SYNC_TRIGGER=" + runTrigger(opts),
+		"GRANOLA_SYNC_STATUS=" + status,
+		"GRANOLA_SYNC_NEW_MEETINGS=" + strconv.Itoa(result.NewMeetings),
+		"GRANOLA_SYNC_UPDATED_MEETINGS=" + strconv.Itoa(result.UpdatedMeetings),
+		"GRANOLA_SYNC_ADOPTED_MEETINGS=" + strconv.Itoa(result.AdoptedMeetings),
+		"GRANOLA_SYNC_NEW_JOURNALS=" + strconv.Itoa(result.NewJournals),
+		"GRANOLA_SYNC_ERRORS=" + strconv.Itoa(len(result.Errors)),
+		"GRANOLA_SYNC_CHANGED_PAGES=" + strings.Join(result.ChangedPages, "\n"),
+	}
+}
+
+// runHook runs a hook command with sh in dir, adding env to the environment
+func runHook(command, dir string, env []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		cmd.Dir = dir
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", command, strings.TrimSpace(string(output)), err)
+	}
+	if len(output) > 0 {
+		slog.Debug("sync hook output", "command", command, "output", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/jira"
+	"github.com/philrhinehart/granola-sync/internal/linear"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// Output target names for issue trackers
+const (
+	TargetJira   = "jira"
+	TargetLinear = "linear"
+)
+
+// issueRequest is an action item to file as an issue
+type issueRequest struct {
+	Title        string // The action item without the prefix or owner
+	Owner        string
+	MeetingTitle string
+	PageURL      string // logseq:// link back to the meeting page
+}
+
+// description is the issue's body, pointing back at the meeting
+func (r issueRequest) description() string {
+	var sb strings.Builder
+	if r.Owner != "" {
+		sb.WriteString("Owner: " + r.Owner + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("From %s\n%s", r.MeetingTitle, r.PageURL))
+	return sb.String()
+}
+
+// filedIssue is an issue created in a tracker
+type filedIssue struct {
+	Key string // e.g. ENG-12
+	URL string
+}
+
+// externalID is how an issue is recorded for its action item in state: its key
+// and URL, separated by a space
+func (i filedIssue) externalID() string {
+	return i.Key + " " + i.URL
+}
+
+// parseFiledIssue reads an issue recorded in state
+func parseFiledIssue(externalID string) filedIssue {
+	key, url, _ := strings.Cut(externalID, " ")
+	return filedIssue{Key: key, URL: url}
+}
+
+// issueFiler creates issues in a tracker
+type issueFiler interface {
+	// target names the tracker in state and the circuit breaker
+	target() string
+	// file creates an issue
+	file(ctx context.Context, req issueRequest) (filedIssue, error)
+}
+
+// issueTracker files action items that start with a prefix as issues and
+// links them to their issues when meetings are rendered
+type issueTracker struct {
+	prefix string
+	filer  issueFiler
+	store  state.Store
+}
+
+// newIssueTracker returns the tracker configured in issue_tracker, or nil
+func newIssueTracker(cfg *config.Config, store state.Store) *issueTracker {
+	it := cfg.IssueTracker
+	if it == nil {
+		return nil
+	}
+	t := &issueTracker{prefix: it.Prefix, store: store}
+	switch it.App {
+	case config.IssueAppJira:
+		t.filer = &jiraFiler{
+			siteURL:   it.URL,
+			email:     it.Email,
+			project:   it.Project,
+			issueType: it.IssueType,
+			token:     keychainToken{account: TargetJira},
+		}
+	case config.IssueAppLinear:
+		t.filer = &linearFiler{teamID: it.Project, token: keychainToken{account: TargetLinear}}
+	default:
+		return nil
+	}
+	return t
+}
+
+// match reports whether an action item's text (owner included) asks for an
+// issue, e.g. "FILE: Fix login" or "Alice: FILE: Fix login", and returns where
+// the prefix starts, the owner and the issue title
+func (t *issueTracker) match(text string) (at int, owner, title string, ok bool) {
+	rest := text
+	if !hasPrefixFold(rest, t.prefix) {
+		name, after, found := strings.Cut(text, ":")
+		if !found || strings.TrimSpace(name) == "" || len(strings.Fields(name)) > 3 {
+			return 0, "", "", false
+		}
+		owner, rest = strings.TrimSpace(name), strings.TrimSpace(after)
+		if !hasPrefixFold(rest, t.prefix) {
+			return 0, "", "", false
+		}
+	}
+	title = strings.TrimSpace(rest[len(t.prefix):])
+	if title == "" {
+		return 0, "", "", false
+	}
+	return len(text) - len(rest), owner, title, true
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// filed returns the issues filed for a meeting's action items, keyed by the
+// action item's taskKey
+func (t *issueTracker) filed(docID string) (map[string]filedIssue, error) {
+	tasks, err := t.store.ListExportedTasks(t.filer.target(), docID)
+	if err != nil {
+		return nil, err
+	}
+	issues := make(map[string]filedIssue, len(tasks))
+	for key, externalID := range tasks {
+		issues[key] = parseFiledIssue(externalID)
+	}
+	return issues, nil
+}
+
+// record saves the issue filed for an action item
+func (t *issueTracker) record(docID, key string, issue filedIssue) error {
+	return t.store.MarkTaskExported(t.filer.target(), docID, key, issue.externalID())
+}
+
+// hash folds the issues filed for a meeting into its content hash, so filing
+// one re-renders the meeting with the link on the next sync
+func (t *issueTracker) hash(contentHash, docID string) string {
+	tasks, err := t.store.ListExportedTasks(t.filer.target(), docID)
+	if err != nil || len(tasks) == 0 {
+		return contentHash
+	}
+	h := sha256.New()
+	h.Write([]byte(contentHash))
+	for _, key := range slices.Sorted(maps.Keys(tasks)) {
+		h.Write([]byte(key + "\x00" + tasks[key] + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LinkIssue replaces the prefix of an action item that was filed with a link to
+// its issue, e.g. "Alice: [ENG-12](https://...) Fix login"
+func (t *issueTracker) LinkIssue(docID, text string) string {
+	at, _, title, ok := t.match(text)
+	if !ok {
+		return ""
+	}
+	externalID, err := t.store.GetExportedTask(t.filer.target(), docID, taskKey(text))
+	if err != nil {
+		slog.Warn("can't link filed issue", "document", docID, "error", err)
+		return ""
+	}
+	if externalID == "" {
+		return ""
+	}
+	issue := parseFiledIssue(externalID)
+	return fmt.Sprintf("%s[%s](%s) %s", text[:at], issue.Key, issue.URL, title)
+}
+
+// fileIssues files a meeting's action items that start with the prefix as
+// issues, skipping those already filed so re-syncs don't create duplicates.
+// The page links them once it is next synced.
+func (s *Syncer) fileIssues(ctx context.Context, g *graph, doc *granola.Document) error {
+	if s.issues == nil {
+		return nil
+	}
+	var texts []string
+	for _, text := range g.writer.ActionItemTexts(doc) {
+		if _, _, _, ok := s.issues.match(text); ok {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+
+	target := s.issues.filer.target()
+	// Leave the document unsynced so the issues are filed next run
+	if !s.breaker.allow(target) {
+		return errTargetDisabled(target)
+	}
+	filed, err := s.issues.filed(doc.ID)
+	if err != nil {
+		return fmt.Errorf("checking filed issues: %w", err)
+	}
+
+	pageName := logseq.GetPageName(doc, s.cfg.PageNameTemplate)
+	created := 0
+	for _, text := range texts {
+		key := taskKey(text)
+		if _, ok := filed[key]; ok {
+			continue
+		}
+		_, owner, title, _ := s.issues.match(text)
+		issue, err := s.issues.filer.file(ctx, issueRequest{
+			Title:        title,
+			Owner:        owner,
+			MeetingTitle: doc.Title,
+			PageURL:      logseq.PageURL(g.basePath, pageName),
+		})
+		s.breaker.record(target, err)
+		if err != nil {
+			return fmt.Errorf("filing issue in %s: %w", target, err)
+		}
+		filed[key] = issue
+		if err := s.issues.record(doc.ID, key, issue); err != nil {
+			return fmt.Errorf("recording filed issue: %w", err)
+		}
+		created++
+	}
+	if created > 0 {
+		s.issuesFiled = true
+		slog.Info("filed issues", "title", doc.Title, "target", target, "count", created)
+	}
+
+	return nil
+}
+
+// pendingIssues counts the issues that would be filed for a meeting, for dry runs
+func (s *Syncer) pendingIssues(g *graph, doc *granola.Document) int {
+	if s.issues == nil {
+		return 0
+	}
+	filed, err := s.issues.filed(doc.ID)
+	if err != nil {
+		return 0
+	}
+	pending := 0
+	for _, text := range g.writer.ActionItemTexts(doc) {
+		if _, _, _, ok := s.issues.match(text); ok {
+			if _, ok := filed[taskKey(text)]; !ok {
+				pending++
+			}
+		}
+	}
+	return pending
+}
+
+// jiraFiler creates Jira issues
+type jiraFiler struct {
+	siteURL   string
+	email     string
+	project   string
+	issueType string
+	token     keychainToken
+}
+
+func (f *jiraFiler) target() string { return TargetJira }
+
+func (f *jiraFiler) file(ctx context.Context, req issueRequest) (filedIssue, error) {
+	token, err := f.token.get()
+	if err != nil {
+		return filedIssue{}, err
+	}
+	client := jira.NewClient(f.siteURL, f.email, token)
+	key, err := client.CreateIssue(ctx, jira.Issue{
+		Project:     f.project,
+		Type:        f.issueType,
+		Summary:     req.Title,
+		Description: req.description(),
+	})
+	if err != nil {
+		return filedIssue{}, err
+	}
+	return filedIssue{Key: key, URL: client.IssueURL(key)}, nil
+}
+
+// linearFiler creates Linear issues
+type linearFiler struct {
+	baseURL string // Empty for the Linear API; set in tests
+	teamID  string
+	token   keychainToken
+}
+
+func (f *linearFiler) target() string { return TargetLinear }
+
+func (f *linearFiler) file(ctx context.Context, req issueRequest) (filedIssue, error) {
+	token, err := f.token.get()
+	if err != nil {
+		return filedIssue{}, err
+	}
+	issue, err := linear.NewClient(f.baseURL, token).CreateIssue(ctx, linear.Issue{
+		TeamID:      f.teamID,
+		Title:       req.Title,
+		Description: req.description(),
+	})
+	if err != nil {
+		return filedIssue{}, err
+	}
+	return filedIssue{Key: issue.Identifier, URL: issue.URL}, nil
+}
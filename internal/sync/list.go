@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// Meeting sync statuses reported by List
+const (
+	StatusSynced   = "synced"
+	StatusUnsynced = "unsynced"
+	StatusSkipped  = "skipped"
+)
+
+// MeetingStatus describes whether a meeting in the Granola cache has been synced
+type MeetingStatus struct {
+	ID       string
+	Title    string
+	Date     time.Time
+	Status   string
+	Reason   string // Why the meeting is skipped or unsynced
+	PagePath string // Set once the meeting has been synced
+	PageURL  string // logseq:// link to the page, set once synced
+}
+
+// List cross-references the Granola cache with the state store and reports the
+// sync status of every meeting, using the same filters as Sync.
+func (s *Syncer) List(since *time.Time) ([]MeetingStatus, error) {
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	// One query per graph rather than a lookup per meeting
+	synced := make(map[*graph]map[string]*state.SyncedDocument, len(s.graphs))
+	for _, g := range s.graphs {
+		docs, err := g.store.ListSynced(nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing synced documents: %w", err)
+		}
+		byID := make(map[string]*state.SyncedDocument, len(docs))
+		for i := range docs {
+			byID[docs[i].ID] = &docs[i]
+		}
+		synced[g] = byID
+	}
+
+	minAge := time.Duration(s.cfg.MinAgeSeconds) * time.Second
+
+	var statuses []MeetingStatus
+	for _, doc := range docs {
+		status := MeetingStatus{
+			ID:    doc.ID,
+			Title: doc.Title,
+			Date:  doc.GetMeetingDate(),
+		}
+
+		g := s.graphFor(doc)
+		existing := synced[g][doc.ID]
+		if existing != nil && existing.LogseqPagePath != "" {
+			status.PagePath = existing.LogseqPagePath
+			status.PageURL = logseq.FileURL(g.basePath, existing.LogseqPagePath)
+		}
+
+		if reason := s.skipReason(doc, SyncOptions{Since: since}, minAge); reason != "" {
+			status.Status = StatusSkipped
+			status.Reason = reason
+		} else {
+			status.Status, status.Reason = syncStatus(doc, existing)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// syncStatus reports whether a meeting that passes the sync filters is up to
+// date in Logseq, and if not, why
+func syncStatus(doc *granola.Document, existing *state.SyncedDocument) (status, reason string) {
+	switch {
+	case existing != nil && existing.OptedOut:
+		return StatusSkipped, "opted out on page"
+	case existing != nil && existing.Adopted:
+		return StatusSkipped, "hand-made page adopted"
+	case existing == nil:
+		return StatusUnsynced, "not synced yet"
+	case existing.GranolaUpdatedAt == nil || !existing.GranolaUpdatedAt.Equal(doc.UpdatedAt):
+		// Compare timestamps only: the content hash may include notes
+		// fetched from the API, which List doesn't do
+		return StatusUnsynced, "changed since last sync"
+	default:
+		return StatusSynced, ""
+	}
+}
@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// notifyTimeout bounds how long showing a notification may take
+const notifyTimeout = 10 * time.Second
+
+// notificationTitle is the title of every notification
+const notificationTitle = "granola-sync"
+
+// notifier shows desktop notifications (see the notify package)
+type notifier interface {
+	Send(ctx context.Context, title, message string) error
+}
+
+// notifyRun shows a notification for a finished run when it synced new
+// meetings, or when it completes a streak of notify_after_failures failed runs.
+// Backfills don't notify of new meetings since they are run by hand.
+func (s *Syncer) notifyRun(result *SyncResult, opts SyncOptions) {
+	if s.notifier == nil {
+		return
+	}
+
+	if result != nil && result.NewMeetings > 0 && !opts.Backfill {
+		s.sendNotification(newMeetingsMessage(result.NewMeetings))
+	}
+
+	threshold := s.cfg.NotifyAfterFailures
+	if threshold <= 0 {
+		return
+	}
+	// One run past the threshold shows whether the streak just reached it, so
+	// only that run notifies rather than every one after it
+	runs, err := s.store.RecentSyncRuns(threshold + 1)
+	if err != nil {
+		slog.Warn("failed to load sync runs for notification", "error", err)
+		return
+	}
+	if failingStreak(runs) == threshold {
+		s.sendNotification(fmt.Sprintf("The last %d syncs failed. Run granola-sync logs for details.", threshold))
+	}
+}
+
+// failingStreak counts the runs, newest first, that failed or had errors before
+// the first that succeeded
+func failingStreak(runs []state.SyncRun) int {
+	for i, run := range runs {
+		if !run.Failed && run.Errors == 0 {
+			return i
+		}
+	}
+	return len(runs)
+}
+
+// newMeetingsMessage describes how many new meetings were synced
+func newMeetingsMessage(n int) string {
+	if n == 1 {
+		return "Synced 1 new meeting to Logseq"
+	}
+	return fmt.Sprintf("Synced %d new meetings to Logseq", n)
+}
+
+// sendNotification shows a notification, logging rather than failing the run
+// if it can't
+func (s *Syncer) sendNotification(message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if err := s.notifier.Send(ctx, notificationTitle, message); err != nil {
+		slog.Warn("failed to show notification", "error", err)
+	}
+}
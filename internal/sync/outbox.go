@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/export"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+)
+
+// Outbox event types
+const (
+	OutboxCreated = "created"
+	OutboxUpdated = "updated"
+)
+
+// OutboxEvent is the JSON written to the outbox for a synced meeting
+type OutboxEvent struct {
+	Event      string         `json:"event"` // created or updated
+	OccurredAt time.Time      `json:"occurred_at"`
+	PagePath   string         `json:"page_path"`
+	Meeting    export.Meeting `json:"meeting"`
+}
+
+// writeOutboxEvent drops an event file for a synced meeting in outbox_dir, if
+// set. Backfills write none, so automations only see meetings as they happen.
+func (s *Syncer) writeOutboxEvent(doc *granola.Document, pagePath string, isNew bool, opts SyncOptions) error {
+	if s.cfg.OutboxDir == "" || opts.Backfill {
+		return nil
+	}
+	event := OutboxEvent{
+		Event:      OutboxUpdated,
+		OccurredAt: s.clock.Now().UTC(),
+		PagePath:   pagePath,
+		Meeting:    s.meetingRecords([]*granola.Document{doc})[0],
+	}
+	if isNew {
+		event.Event = OutboxCreated
+	}
+	data, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding outbox event: %w", err)
+	}
+	// Timestamped names sort in the order events happened and keep an update
+	// from replacing an earlier event that hasn't been picked up yet
+	name := fmt.Sprintf("%s-%s-%s.json", event.OccurredAt.Format("20060102T150405.000000000Z"), doc.ID, event.Event)
+	if err := writeOutboxFile(s.cfg.OutboxDir, name, data); err != nil {
+		return fmt.Errorf("writing outbox event: %w", err)
+	}
+	return nil
+}
+
+// writeOutboxFile writes data to a hidden temp file and renames it into dir,
+// so folder watchers never pick up a half-written event
+func writeOutboxFile(dir, name string, data []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Removes the temp file if anything below fails; a no-op after the rename
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"log/slog"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// panelCache is the granola.PanelCache backed by the state store. Entries are
+// loaded once per Syncer and new extractions are written back after each parse.
+type panelCache struct {
+	entries map[string]state.CachedPanel
+	pending map[string]state.CachedPanel // Extracted since the last flush
+	hits    int
+	misses  int
+}
+
+// loadPanelCache reads the cached panels from the store. A store error only
+// costs re-extraction, so it's logged and an empty cache is used.
+func loadPanelCache(store state.Store) *panelCache {
+	entries, err := store.LoadPanelCache()
+	if err != nil {
+		slog.Warn("could not load panel cache, extracting all notes", "error", err)
+		entries = make(map[string]state.CachedPanel)
+	}
+	return &panelCache{entries: entries, pending: make(map[string]state.CachedPanel)}
+}
+
+func (c *panelCache) Markdown(panelID, stamp string) (string, bool) {
+	entry, ok := c.entries[panelID]
+	if !ok || entry.ContentUpdatedAt != stamp {
+		c.misses++
+		return "", false
+	}
+	c.hits++
+	return entry.Markdown, true
+}
+
+func (c *panelCache) SetMarkdown(panelID, stamp, markdown string) {
+	entry := state.CachedPanel{ContentUpdatedAt: stamp, Markdown: markdown}
+	c.entries[panelID] = entry
+	c.pending[panelID] = entry
+}
+
+// flush saves newly extracted panels to the store and resets the counters
+func (c *panelCache) flush(store state.Store) {
+	slog.Debug("panel cache", "hits", c.hits, "misses", c.misses)
+	c.hits, c.misses = 0, 0
+	if len(c.pending) == 0 {
+		return
+	}
+	if err := store.SavePanelCache(c.pending); err != nil {
+		slog.Warn("could not save panel cache", "error", err)
+		return
+	}
+	c.pending = make(map[string]state.CachedPanel)
+}
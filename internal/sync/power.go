@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/philrhinehart/granola-sync/internal/power"
+)
+
+// ErrDeferredOnBattery is returned by a backfill that defer_on_battery holds
+// off until the Mac is plugged in
+var ErrDeferredOnBattery = errors.New("deferred until on power")
+
+// readPower reads the Mac's power status; tests replace it
+var readPower = power.Read
+
+// deferReason says why defer_on_battery holds off bulk work right now, or
+// returns "" to go ahead. When the power status can't be read nothing is
+// deferred.
+func (s *Syncer) deferReason() string {
+	if s.cfg.DeferOnBattery == "" || s.cfg.DeferOnBattery == "off" {
+		return ""
+	}
+	st, err := readPower()
+	if err != nil {
+		slog.Debug("failed to read power status", "error", err)
+		return ""
+	}
+	switch {
+	case st.LowPowerMode:
+		return "Low Power Mode is on"
+	case st.OnBattery && s.cfg.DeferOnBattery == "battery":
+		return "running on battery"
+	}
+	return ""
+}
+
+// checkBackfillPower fails a backfill that defer_on_battery holds off
+func (s *Syncer) checkBackfillPower(opts SyncOptions) error {
+	if !opts.Backfill || opts.DryRun {
+		return nil
+	}
+	if reason := s.deferReason(); reason != "" {
+		return fmt.Errorf("backfill %w: %s (set defer_on_battery to off to run it anyway)", ErrDeferredOnBattery, reason)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"fmt"
+	"time"
+)
+
+// QueuedMeeting is a meeting waiting to be written to Logseq
+type QueuedMeeting struct {
+	ID      string
+	Title   string
+	Date    time.Time
+	Reason  string    // Why it needs writing: not synced yet or changed
+	ReadyAt time.Time // When it will be old enough to sync (see min_age_seconds)
+}
+
+// Queue returns the meetings the next syncs will write: those not synced yet
+// or changed since, including those still too recently edited to sync. Meetings
+// skipped for any other reason aren't queued.
+func (s *Syncer) Queue() ([]QueuedMeeting, error) {
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	minAge := time.Duration(s.cfg.MinAgeSeconds) * time.Second
+
+	var queue []QueuedMeeting
+	for _, doc := range docs {
+		// Age is reported as ReadyAt rather than filtered
+		if s.skipReason(doc, SyncOptions{}, 0) != "" {
+			continue
+		}
+
+		existing, err := s.graphFor(doc).store.GetSyncedDocument(doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting synced document %s: %w", doc.ID, err)
+		}
+		status, reason := syncStatus(doc, existing)
+		if status != StatusUnsynced {
+			continue
+		}
+
+		queue = append(queue, QueuedMeeting{
+			ID:      doc.ID,
+			Title:   doc.Title,
+			Date:    doc.GetMeetingDate(),
+			Reason:  reason,
+			ReadyAt: doc.UpdatedAt.Add(minAge),
+		})
+	}
+
+	return queue, nil
+}
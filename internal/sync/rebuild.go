@@ -0,0 +1,105 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+)
+
+// RebuildResult contains the result of a state rebuild
+type RebuildResult struct {
+	// Current counts pages identical to what sync would write now
+	Current int
+	// Stale counts pages that differ and will be rewritten (not re-created) on the next sync
+	Stale int
+	// Unknown counts pages not matched to a meeting in the Granola cache
+	Unknown int
+	// Existing counts meetings that were already in the state store
+	Existing int
+	Errors   []error
+}
+
+// RebuildState repopulates the state store from the meeting pages in the graph,
+// e.g. after the state database was lost. Each page's granola-id:: property ties
+// it to a meeting. A page that matches what sync would write now is recorded as
+// up to date; any other page is recorded with a hash of its on-disk content, so
+// the next sync updates it in place instead of treating the meeting as new
+// (which would also add a second journal entry).
+func (s *Syncer) RebuildState(dryRun bool) (*RebuildResult, error) {
+	// Journal-only meetings have no pages to scan
+	if s.cfg.JournalOnly {
+		return nil, errors.New("state rebuild doesn't support journal_only mode")
+	}
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[*graph]map[string]string, len(s.graphs))
+	pages := 0
+	for _, g := range s.graphs {
+		index, err := logseq.ScanGranolaIDs(g.fs, g.basePath)
+		if err != nil {
+			return nil, fmt.Errorf("scanning pages: %w", err)
+		}
+		indexes[g] = index
+		pages += len(index)
+	}
+
+	result := &RebuildResult{}
+	for _, doc := range docs {
+		g := s.graphFor(doc)
+		pagePath, ok := indexes[g][doc.ID]
+		if !ok {
+			continue
+		}
+		delete(indexes[g], doc.ID)
+
+		existing, err := g.store.GetSyncedDocument(doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting existing document: %w", err)
+		}
+		if existing != nil {
+			result.Existing++
+			continue
+		}
+
+		onDisk, err := g.fs.ReadFile(pagePath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("doc %s: reading page: %w", doc.ID, err))
+			continue
+		}
+		contentHash := s.documentHash(doc)
+		if wantPath, want := g.writer.DryRunMeetingPage(doc); wantPath == pagePath && want == string(onDisk) {
+			result.Current++
+		} else {
+			sum := sha256.Sum256(onDisk)
+			contentHash = hex.EncodeToString(sum[:])
+			result.Stale++
+		}
+		if dryRun {
+			continue
+		}
+
+		syncedDoc := s.newSyncedDocument(g.writer, doc, pagePath, contentHash)
+		syncedDoc.OptedOut = logseq.IsOptedOut(string(onDisk))
+		if err := g.store.MarkSynced(syncedDoc); err != nil {
+			return nil, fmt.Errorf("marking synced: %w", err)
+		}
+		slog.Debug("rebuilt state for meeting page", "title", doc.Title, "path", pagePath)
+	}
+
+	// Pages left in the indexes weren't matched to a meeting
+	for _, index := range indexes {
+		result.Unknown += len(index)
+	}
+	slog.Info("rebuilt state from pages", "pages", pages, "current", result.Current, "stale", result.Stale)
+	return result, nil
+}
@@ -0,0 +1,177 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+)
+
+// pageFormatVersion is part of the page format fingerprint. Bump it when a
+// change to the page layout should re-render existing pages.
+const pageFormatVersion = 1
+
+// Keys of the sync_meta values tracking page format changes
+const (
+	// formatFingerprintKey holds the fingerprint of the format pages were last written in
+	formatFingerprintKey = "format_fingerprint"
+	// rerenderBeforeKey holds when the format last changed; pages synced before
+	// then are re-rendered a batch at a time. Cleared once none are left.
+	rerenderBeforeKey = "rerender_before"
+)
+
+// formatFingerprint hashes the options that decide how meeting pages look
+func formatFingerprint(cfg *config.Config) string {
+	raw, _ := json.Marshal(struct {
+		Version               int
+		IncludeAttendeeEmails bool
+		IncludeTranscript     bool
+		IncludeMyNotes        bool
+		PageProperties        string
+		DetectLanguage        bool
+		PageNameTemplate      string
+		JournalHeading        string
+		CalendarBlocks        string
+		AnonymizeAttendees    bool
+		AnonymizeKey          string
+		ClassificationRules   []config.ClassificationRule
+		DefaultClassification string
+		NormalizeGlyphs       bool
+		GlyphReplacements     map[string]string
+		DeriveTitleTag        bool
+		TitleTagMinMeetings   int
+		TagAllowlist          []string
+		TagDenylist           []string
+		UnfurlLinks           []string
+		// Omitted when unset so adding it didn't change every fingerprint
+		PagePlugins              []string          `json:",omitempty"`
+		TagRules                 []config.TagRule  `json:",omitempty"`
+		AttendeeAliases          map[string]string `json:",omitempty"`
+		NormalizeAttendeeNames   bool              `json:",omitempty"`
+		ExcludeAttendeePatterns  []string          `json:",omitempty"`
+		ExcludeResourceAttendees bool              `json:",omitempty"`
+	}{
+		pageFormatVersion,
+		cfg.IncludeAttendeeEmails,
+		cfg.IncludeTranscript,
+		cfg.IncludeMyNotes,
+		cfg.PageProperties,
+		cfg.DetectLanguage,
+		cfg.PageNameTemplate,
+		cfg.JournalHeading,
+		cfg.CalendarBlocks,
+		cfg.AnonymizeAttendees,
+		cfg.AnonymizeKey,
+		cfg.ClassificationRules,
+		cfg.DefaultClassification,
+		cfg.NormalizeGlyphs,
+		cfg.GlyphReplacements,
+		cfg.DeriveTitleTag,
+		cfg.TitleTagMinMeetings,
+		cfg.TagAllowlist,
+		cfg.TagDenylist,
+		cfg.UnfurlLinks,
+		cfg.PagePlugins,
+		cfg.TagRules,
+		cfg.AttendeeAliases,
+		cfg.NormalizeAttendeeNames,
+		cfg.ExcludeAttendeePatterns,
+		cfg.ExcludeResourceAttendees,
+	})
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// renderTemplate identifies how a graph renders meetings in the render cache:
+// its page format, journal date format and user
+func renderTemplate(cfg *config.Config, journal logseq.JournalFormat) string {
+	return strings.Join([]string{formatFingerprint(cfg), journal.FileFormat, journal.TitleFormat, cfg.UserName}, "\x00")
+}
+
+// formatChangedAt notes a change of page format since the last sync, starting
+// a rolling re-render of the pages written before it. Returns when the current
+// re-render started, or nil if there is none.
+func (s *Syncer) formatChangedAt() (*time.Time, error) {
+	fingerprint := formatFingerprint(s.cfg)
+	stored, err := s.store.GetValue(formatFingerprintKey)
+	if err != nil {
+		return nil, err
+	}
+	if stored != fingerprint {
+		if err := s.store.SetValue(formatFingerprintKey, fingerprint); err != nil {
+			return nil, err
+		}
+		// Without a stored fingerprint the pages' format is unknown; assume current
+		if stored != "" {
+			slog.Info("page format changed, re-rendering existing pages", "per_sync", s.cfg.RerenderBatchSize)
+			if err := s.store.SetValue(rerenderBeforeKey, s.clock.Now().Format(time.RFC3339Nano)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	value, err := s.store.GetValue(rerenderBeforeKey)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	before, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", rerenderBeforeKey, err)
+	}
+	return &before, nil
+}
+
+// rerenderStalePages rewrites up to rerender_batch_size pages synced before the
+// page format changed, finishing the re-render when none are left. Errors are
+// added to result; failed pages are retried next sync.
+func (s *Syncer) rerenderStalePages(docs []*granola.Document, before time.Time, result *SyncResult) {
+	if s.cfg.RerenderBatchSize <= 0 {
+		return
+	}
+
+	ctx := context.Background()
+	minAge := time.Duration(s.cfg.MinAgeSeconds) * time.Second
+	stale, rendered := 0, 0
+	for _, doc := range docs {
+		if s.skipReason(doc, SyncOptions{}, minAge) != "" {
+			continue
+		}
+		g := s.graphFor(doc)
+		existing, err := g.store.GetSyncedDocument(doc.ID)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("doc %s: getting existing document: %w", doc.ID, err))
+			continue
+		}
+		if existing == nil || existing.OptedOut || existing.Adopted || !existing.SyncedAt.Before(before) {
+			continue
+		}
+
+		stale++
+		if rendered == s.cfg.RerenderBatchSize {
+			continue
+		}
+		rendered++
+		if err := s.syncDocument(ctx, g, doc, s.documentHash(doc), existing, SyncOptions{Backfill: true}, result); err != nil {
+			slog.Error("failed to re-render page", "id", doc.ID, "title", doc.Title, "error", err)
+			result.Errors = append(result.Errors, fmt.Errorf("doc %s: re-rendering: %w", doc.ID, err))
+		}
+	}
+
+	if rendered > 0 {
+		slog.Info("re-rendered pages in the new format", "count", rendered, "remaining", stale-rendered)
+	}
+	if stale == 0 {
+		slog.Info("all pages are in the current format")
+		if err := s.store.SetValue(rerenderBeforeKey, ""); err != nil {
+			slog.Warn("failed to finish page re-render", "error", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/s3"
+)
+
+// TargetS3 names the S3 archive in the circuit breaker
+const TargetS3 = "s3"
+
+// s3Archive uploads a copy of each synced meeting to an S3 bucket
+type s3Archive struct {
+	client   *s3.Client
+	bucket   string
+	prefix   string
+	sidecars bool
+}
+
+// newS3Archive creates the S3 archive in config, or returns nil if there is none
+func newS3Archive(cfg *config.Config) *s3Archive {
+	if cfg.S3Bucket == "" {
+		return nil
+	}
+	creds := s3.Credentials{
+		AccessKeyID:     cfg.S3AccessKeyID,
+		SecretAccessKey: cfg.S3SecretAccessKey,
+	}
+	if creds.AccessKeyID == "" {
+		creds = s3.Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	return &s3Archive{
+		client:   s3.NewClient(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, creds),
+		bucket:   cfg.S3Bucket,
+		prefix:   strings.Trim(cfg.S3Prefix, "/"),
+		sidecars: cfg.S3Sidecars,
+	}
+}
+
+// key returns a meeting's object key:
+// <prefix>/<year>/<month>/<date> <title> (<granola-id>).md
+func (a *s3Archive) key(doc *granola.Document) string {
+	return path.Join(a.prefix, doc.GetMeetingDate().Format("2006/01"), logseq.ArchiveFilename(doc))
+}
+
+// upload uploads a meeting's markdown, rendered by w, and its sidecar if enabled
+func (a *s3Archive) upload(ctx context.Context, w *logseq.Writer, doc *granola.Document) error {
+	key := a.key(doc)
+	if err := a.client.PutObject(ctx, key, []byte(w.ArchiveContent(doc)), "text/markdown; charset=utf-8"); err != nil {
+		return err
+	}
+	if !a.sidecars {
+		return nil
+	}
+	data, err := w.SidecarJSON(doc)
+	if err != nil {
+		return err
+	}
+	return a.client.PutObject(ctx, strings.TrimSuffix(key, ".md")+".json", data, "application/json")
+}
+
+// uploadToS3 uploads a synced meeting to the S3 archive, if there is one
+func (s *Syncer) uploadToS3(ctx context.Context, g *graph, doc *granola.Document) error {
+	if s.s3 == nil {
+		return nil
+	}
+	// Leave the document unsynced so the upload is retried next run
+	if !s.breaker.allow(TargetS3) {
+		return errTargetDisabled(TargetS3)
+	}
+	err := s.s3.upload(ctx, g.writer, doc)
+	s.breaker.record(TargetS3, err)
+	if err != nil {
+		return fmt.Errorf("uploading to S3: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// MeetingDetail is everything granola-sync knows about one meeting, for debugging
+type MeetingDetail struct {
+	Doc         *granola.Document
+	NotesSource string // Where the notes came from, or "none"
+	IsAttendee  bool
+	SkipReason  string // Why Sync would skip the meeting, or empty
+	Synced      *state.SyncedDocument
+	Graph       string // The extra graph the meeting is routed to, or empty
+	PagePath    string // Where the page is (or would be) written
+	Content     string // The rendered page
+}
+
+// Show gathers the parsed metadata, filter results, sync state, and rendered
+// page for the meeting with the given Granola ID. Missing notes are fetched
+// from the API the same way Sync does.
+func (s *Syncer) Show(id string) (*MeetingDetail, error) {
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+	docs, err := s.loadDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc *granola.Document
+	for _, d := range docs {
+		if d.ID == id {
+			doc = d
+			break
+		}
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("meeting %s not found in the Granola cache", id)
+	}
+
+	if !doc.HasNotes() {
+		if apiClient := s.loadAPIClient(); apiClient != nil {
+			var lastAPICall time.Time
+			s.fetchAndPopulateNotes(context.Background(), doc, &apiClient, &lastAPICall)
+		}
+	}
+
+	g := s.graphFor(doc)
+	synced, err := g.store.GetSyncedDocument(doc.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting synced document: %w", err)
+	}
+
+	minAge := time.Duration(s.cfg.MinAgeSeconds) * time.Second
+	detail := &MeetingDetail{
+		Doc:         doc,
+		NotesSource: notesSource(doc),
+		IsAttendee:  doc.IsUserAttendee(s.cfg.UserEmail),
+		SkipReason:  s.skipReason(doc, SyncOptions{}, minAge),
+		Synced:      synced,
+		Graph:       g.name,
+	}
+	detail.PagePath, detail.Content = g.writer.DryRunMeetingPage(doc)
+
+	return detail, nil
+}
+
+// notesSource describes where a document's notes come from
+func notesSource(doc *granola.Document) string {
+	switch {
+	case doc.NotesMarkdown != nil && *doc.NotesMarkdown != "":
+		return doc.NotesSource
+	case doc.NotesPlain != nil && *doc.NotesPlain != "":
+		return "plain text"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,176 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/slack"
+)
+
+// TargetSlack names Slack posts in state and the circuit breaker
+const TargetSlack = "slack"
+
+// slackPoster posts the notes of meetings matching slack_posts rules
+type slackPoster struct {
+	baseURL string // Empty for the Slack API; set in tests
+	posts   []config.SlackPost
+	token   keychainToken
+}
+
+// newSlackPoster returns a poster for the slack_posts rules, or nil if there
+// are none
+func newSlackPoster(cfg *config.Config) *slackPoster {
+	if len(cfg.SlackPosts) == 0 {
+		return nil
+	}
+	return &slackPoster{posts: cfg.SlackPosts, token: keychainToken{account: TargetSlack}}
+}
+
+// matching returns the rules whose tags a meeting with tags has
+func (p *slackPoster) matching(tags []string) []config.SlackPost {
+	var posts []config.SlackPost
+	for _, post := range p.posts {
+		if slices.ContainsFunc(tags, func(tag string) bool {
+			return slices.ContainsFunc(post.Tags, func(want string) bool {
+				return strings.EqualFold(tag, want)
+			})
+		}) {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+// slackPostKeys are the state keys of a meeting's post in a channel: id holds
+// the message ts or canvas ID, content marks the notes already posted
+func slackPostKeys(post config.SlackPost, m meetingHighlights) (id, content string) {
+	kind := "message"
+	if post.Canvas {
+		kind = "canvas"
+	}
+	prefix := post.Channel + "/" + kind
+	return prefix, prefix + "/" + highlightsKey(m.Highlights)
+}
+
+// postToSlack posts a meeting's notes to the channels whose rules match its
+// tags. A meeting already posted has its message or canvas updated when the
+// notes change, and is left alone otherwise.
+func (s *Syncer) postToSlack(ctx context.Context, g *graph, doc *granola.Document) error {
+	if s.slack == nil {
+		return nil
+	}
+	posts := s.slack.matching(g.writer.PageTags(doc))
+	if len(posts) == 0 {
+		return nil
+	}
+	m := s.meetingHighlights(g, doc)
+	if len(m.Highlights) == 0 {
+		return nil
+	}
+
+	for _, post := range posts {
+		// Leave the document unsynced so the post is retried next run
+		if !s.breaker.allow(TargetSlack) {
+			return errTargetDisabled(TargetSlack)
+		}
+
+		idKey, contentKey := slackPostKeys(post, m)
+		posted, err := s.store.GetExportedTask(TargetSlack, doc.ID, contentKey)
+		if err != nil {
+			return fmt.Errorf("checking Slack posts: %w", err)
+		}
+		if posted != "" {
+			continue
+		}
+		existing, err := s.store.GetExportedTask(TargetSlack, doc.ID, idKey)
+		if err != nil {
+			return fmt.Errorf("checking Slack posts: %w", err)
+		}
+
+		externalID, err := s.slack.send(ctx, post, m, existing)
+		s.breaker.record(TargetSlack, err)
+		if err != nil {
+			return fmt.Errorf("posting to Slack channel %s: %w", post.Channel, err)
+		}
+		if err := s.store.MarkTaskExported(TargetSlack, doc.ID, idKey, externalID); err != nil {
+			return fmt.Errorf("recording Slack post: %w", err)
+		}
+		if err := s.store.MarkTaskExported(TargetSlack, doc.ID, contentKey, externalID); err != nil {
+			return fmt.Errorf("recording Slack post: %w", err)
+		}
+		slog.Info("posted to Slack", "title", doc.Title, "channel", post.Channel, "canvas", post.Canvas, "updated", existing != "")
+	}
+
+	return nil
+}
+
+// pendingSlackPosts lists the channels a meeting's notes would be posted to,
+// for dry runs
+func (s *Syncer) pendingSlackPosts(g *graph, doc *granola.Document) []string {
+	if s.slack == nil {
+		return nil
+	}
+	posts := s.slack.matching(g.writer.PageTags(doc))
+	if len(posts) == 0 {
+		return nil
+	}
+	m := s.meetingHighlights(g, doc)
+	if len(m.Highlights) == 0 {
+		return nil
+	}
+	var pending []string
+	for _, post := range posts {
+		_, contentKey := slackPostKeys(post, m)
+		posted, err := s.store.GetExportedTask(TargetSlack, doc.ID, contentKey)
+		if err == nil && posted == "" {
+			pending = append(pending, post.Channel)
+		}
+	}
+	return pending
+}
+
+// send posts or, given the existing message ts or canvas ID, updates a
+// meeting's notes in a channel and returns the message ts or canvas ID
+func (p *slackPoster) send(ctx context.Context, post config.SlackPost, m meetingHighlights, existing string) (string, error) {
+	token, err := p.token.get()
+	if err != nil {
+		return "", err
+	}
+	client := slack.NewClient(p.baseURL, token)
+
+	if post.Canvas {
+		content := highlightsMarkdown(m.Highlights)
+		if existing != "" {
+			return existing, client.EditCanvas(ctx, existing, content)
+		}
+		return client.CreateChannelCanvas(ctx, post.Channel, m.name(), content)
+	}
+
+	text := slackMessage(m)
+	if existing != "" {
+		return existing, client.UpdateMessage(ctx, post.Channel, existing, text)
+	}
+	return client.PostMessage(ctx, post.Channel, text)
+}
+
+// slackMessage renders a meeting's notes in Slack's mrkdwn: the meeting name,
+// then each section's heading in bold over its bullets
+func slackMessage(m meetingHighlights) string {
+	var sb strings.Builder
+	sb.WriteString("*" + m.name() + "*\n")
+	for _, h := range m.Highlights {
+		sb.WriteString("\n")
+		if h.Heading != "" {
+			sb.WriteString("*" + h.Heading + "*\n")
+		}
+		for _, b := range h.Bullets {
+			sb.WriteString("• " + strings.ReplaceAll(b, "**", "*") + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,115 @@
+package sync
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/philrhinehart/granola-sync/internal/state"
+)
+
+// LatencyStats summarizes "time-to-notes": the delay between a meeting's last
+// edit in Granola and its page being written
+type LatencyStats struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+// LatencyReport computes latency percentiles over documents synced at or after since
+func LatencyReport(store state.Store, since time.Time) (*LatencyStats, error) {
+	latencies, err := store.SyncLatencies(since)
+	if err != nil {
+		return nil, fmt.Errorf("loading sync latencies: %w", err)
+	}
+
+	stats := &LatencyStats{Count: len(latencies)}
+	if len(latencies) == 0 {
+		return stats, nil
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.P50 = percentile(latencies, 50)
+	stats.P95 = percentile(latencies, 95)
+	stats.Max = latencies[len(latencies)-1]
+	return stats, nil
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// MeetingStats counts synced meetings by the local day of their last edit in Granola
+type MeetingStats struct {
+	Count       int
+	Days        int    // Days with at least one meeting
+	BusiestDay  string // "2006-01-02", the earliest if several tie
+	BusiestDays int    // Meetings on the busiest day
+}
+
+// MeetingReport counts synced meetings last edited in Granola at or after since
+func MeetingReport(store state.Store, since time.Time) (*MeetingStats, error) {
+	counts, err := store.CountByDay(since)
+	if err != nil {
+		return nil, fmt.Errorf("counting meetings by day: %w", err)
+	}
+
+	stats := &MeetingStats{Days: len(counts)}
+	for _, day := range slices.Sorted(maps.Keys(counts)) {
+		stats.Count += counts[day]
+		if counts[day] > stats.BusiestDays {
+			stats.BusiestDay, stats.BusiestDays = day, counts[day]
+		}
+	}
+	return stats, nil
+}
+
+// RunStats totals the most recent sync runs
+type RunStats struct {
+	Runs            int
+	FailedRuns      int // Runs that couldn't sync at all
+	NewMeetings     int
+	UpdatedMeetings int
+	NewJournals     int
+	Errors          int
+	ByTrigger       map[string]int // Number of runs by what started them
+	LastRun         *state.SyncRun
+}
+
+// PagesWritten returns how many meeting pages the runs wrote
+func (r *RunStats) PagesWritten() int {
+	return r.NewMeetings + r.UpdatedMeetings
+}
+
+// RunReport totals up to the last n sync runs
+func RunReport(store state.Store, n int) (*RunStats, error) {
+	runs, err := store.RecentSyncRuns(n)
+	if err != nil {
+		return nil, fmt.Errorf("loading sync runs: %w", err)
+	}
+
+	stats := &RunStats{Runs: len(runs), ByTrigger: make(map[string]int)}
+	for _, run := range runs {
+		stats.ByTrigger[run.Trigger]++
+		if run.Failed {
+			stats.FailedRuns++
+		}
+		stats.NewMeetings += run.NewMeetings
+		stats.UpdatedMeetings += run.UpdatedMeetings
+		stats.NewJournals += run.NewJournals
+		stats.Errors += run.Errors
+	}
+	if len(runs) > 0 {
+		stats.LastRun = &runs[0]
+	}
+	return stats, nil
+}
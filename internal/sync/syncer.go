@@ -7,73 +7,416 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/philrhinehart/granola-sync/internal/clock"
 	"github.com/philrhinehart/granola-sync/internal/config"
 	"github.com/philrhinehart/granola-sync/internal/granola"
 	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/metrics"
+	"github.com/philrhinehart/granola-sync/internal/notify"
 	"github.com/philrhinehart/granola-sync/internal/state"
 )
 
 // apiCallDelay is the minimum time between consecutive API calls.
 const apiCallDelay = 100 * time.Millisecond
 
+// parseRetryDelays are the waits before each retry of a cache that fails to
+// parse, usually because the watcher fired while Granola was still writing it
+var parseRetryDelays = []time.Duration{250 * time.Millisecond, time.Second, 3 * time.Second}
+
 // Syncer orchestrates syncing between Granola and Logseq
 type Syncer struct {
-	cfg    *config.Config
-	store  *state.Store
-	writer *logseq.Writer
+	cfg          *config.Config
+	store        state.Store
+	writer       *logseq.Writer
+	machineID    string
+	metrics      *metrics.Textfile   // nil unless metrics_textfile_path is set
+	notifier     notifier            // nil unless notifications are enabled
+	breaker      *breaker            // Reset at the start of each run
+	exporters    []taskExporter      // Action item exporters enabled in config
+	highlighters []highlightExporter // Highlight exporters enabled in config
+	issues       *issueTracker       // nil unless issue_tracker is set
+	issuesFiled  bool                // Set when a run files issues, which the next run links
+	slack        *slackPoster        // nil unless slack_posts is set
+	s3           *s3Archive          // nil unless s3_bucket is set
+	panels       *panelCache         // Loaded on first parse
+	titles       *titleFilter
+	domains      *domainFilter       // nil unless include_domains is set
+	renders      *logseq.RenderCache // Shared by the graphs' writers
+	clock        clock.Clock         // Decides recency (min_age_seconds), timestamps and waits
+	watchStart   time.Time           // When watch mode started; zero outside it
+
+	// graphs are the graphs meetings are routed to. The first is the main graph
+	// (writer and store); extra graphs from config are opened on first sync.
+	graphs []*graph
+}
+
+// renderCacheSize is how many rendered meetings the render cache keeps
+const renderCacheSize = 256
+
+// SyncOptions controls which documents a sync considers and whether it writes
+type SyncOptions struct {
+	Since  *time.Time // Only meetings on or after this date
+	Until  *time.Time // Only meetings before this date
+	DryRun bool
+
+	// PreviewLines limits the dry-run page preview to this many lines.
+	// 0 falls back to a fixed character limit.
+	PreviewLines int
+	// FullPreview prints whole pages in dry-run
+	FullPreview bool
+	// Check makes a dry run skip recently edited meetings like a real sync does,
+	// so it reports only what should already have been written
+	Check bool
+
+	// Backfill marks a bulk sync of historic meetings. Sync latency isn't
+	// recorded since it would measure how old the meetings are.
+	Backfill bool
+
+	// Force rewrites meetings even if the state store says they are up to date,
+	// e.g. to regenerate pages after changing formatting options
+	Force bool
+	// IDs, if set, limits the sync to these meetings (by Granola ID), however
+	// recently they were updated
+	IDs []string
 }
 
 // SyncResult contains the result of a sync operation
 type SyncResult struct {
 	NewMeetings     int
 	UpdatedMeetings int
+	AdoptedMeetings int
 	NewJournals     int
 	Errors          []error
+	// ChangedPages are the meeting pages and journals written, each once
+	ChangedPages []string
 }
 
 // NewSyncer creates a new syncer
-func NewSyncer(cfg *config.Config, store *state.Store) *Syncer {
-	return &Syncer{
-		cfg:    cfg,
-		store:  store,
-		writer: logseq.NewWriter(cfg.LogseqBasePath, cfg.UserName),
+func NewSyncer(cfg *config.Config, store state.Store) *Syncer {
+	s := &Syncer{
+		cfg:       cfg,
+		store:     store,
+		machineID: cfg.Machine(),
+		breaker:   newBreaker(cfg.OutputFailureThreshold),
+		titles:    newTitleFilter(cfg),
+		domains:   newDomainFilter(cfg),
+		clock:     clock.Real,
+	}
+	s.issues = newIssueTracker(cfg, store)
+	s.renders = logseq.NewRenderCache(renderCacheSize, s.documentHash)
+	main := newGraph("", cfg, store, nil, s.renders)
+	if s.issues != nil {
+		main.writer.SetIssueLinker(s.issues)
+	}
+	s.writer = main.writer
+	s.graphs = []*graph{main}
+	if cfg.MetricsTextfilePath != "" {
+		s.metrics = metrics.NewTextfile(cfg.MetricsTextfilePath)
+	}
+	if cfg.Notifications {
+		s.notifier = notify.New()
+	}
+	s.exporters = s.taskExporters()
+	s.highlighters = s.highlightExporters()
+	s.slack = newSlackPoster(cfg)
+	s.s3 = newS3Archive(cfg)
+	return s
+}
+
+// SetClock replaces the wall clock the syncer uses to judge how recently a
+// meeting was edited and to timestamp runs and synced meetings. Applications
+// embedding the syncer can use it to control recency deterministically.
+func (s *Syncer) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetWatchStart marks the syncer as driven by watch mode since start. Edits made
+// before then waited for the daemon rather than for sync, so no sync latency is
+// recorded for them.
+func (s *Syncer) SetWatchStart(start time.Time) {
+	s.watchStart = start
+}
+
+// backupDir returns where page backups go: backup_dir, or a hidden folder in the graph
+func backupDir(cfg *config.Config) string {
+	if cfg.BackupDir != "" {
+		return cfg.BackupDir
+	}
+	return filepath.Join(cfg.LogseqBasePath, ".granola-sync", "backups")
+}
+
+// formatOptions builds the page formatting options from config
+func formatOptions(cfg *config.Config) logseq.FormatOptions {
+	opts := logseq.FormatOptions{
+		IncludeAttendeeEmails: cfg.IncludeAttendeeEmails,
+		IncludeTranscript:     cfg.IncludeTranscript,
+		IncludeMyNotes:        cfg.IncludeMyNotes,
+		PropertiesStyle:       cfg.PageProperties,
+		DetectLanguage:        cfg.DetectLanguage,
+		PageNameTemplate:      cfg.PageNameTemplate,
+		JournalHeading:        cfg.JournalHeading,
+		TagCalendarBlocks:     cfg.CalendarBlocks == "tag",
+	}
+	if len(cfg.ExcludeAttendeePatterns) > 0 || cfg.ExcludeResourceAttendees {
+		// Patterns were validated when the config was loaded
+		var patterns []*regexp.Regexp
+		for _, p := range cfg.ExcludeAttendeePatterns {
+			patterns = append(patterns, regexp.MustCompile("(?i)"+p))
+		}
+		opts.ExcludeAttendees = logseq.NewAttendeeFilter(patterns, cfg.ExcludeResourceAttendees)
+	}
+	if len(cfg.AttendeeAliases) > 0 || cfg.NormalizeAttendeeNames {
+		opts.Aliases = logseq.NewAttendeeAliases(cfg.AttendeeAliases, cfg.NormalizeAttendeeNames)
+	}
+	if cfg.AnonymizeAttendees {
+		opts.Anonymizer = logseq.NewAnonymizer(cfg.AnonymizeKey, cfg.UserName)
+	}
+	if len(cfg.ClassificationRules) > 0 || cfg.DefaultClassification != "" {
+		rules := make([]logseq.ClassificationRule, len(cfg.ClassificationRules))
+		for i, r := range cfg.ClassificationRules {
+			rules[i] = logseq.ClassificationRule{Classification: r.Classification, Domains: r.Domains, Keywords: r.Keywords}
+		}
+		opts.Classifier = logseq.NewClassifier(rules, cfg.DefaultClassification)
+	}
+	if cfg.NormalizeGlyphs {
+		replacements := maps.Clone(logseq.DefaultGlyphReplacements)
+		maps.Copy(replacements, cfg.GlyphReplacements)
+		opts.Glyphs = logseq.NewGlyphNormalizer(replacements)
+	}
+	if !cfg.DeriveTitleTag || cfg.TitleTagMinMeetings > 1 {
+		opts.TitleTags = logseq.NewTitleTagger(!cfg.DeriveTitleTag, cfg.TitleTagMinMeetings)
+	}
+	if len(cfg.TagRules) > 0 {
+		// Title patterns were validated when the config was loaded
+		rules := make([]logseq.TagRule, len(cfg.TagRules))
+		for i, r := range cfg.TagRules {
+			rules[i] = logseq.TagRule{Tags: r.Tags, Domains: r.Domains}
+			for _, pattern := range r.TitlePatterns {
+				rules[i].Titles = append(rules[i].Titles, regexp.MustCompile("(?i)"+pattern))
+			}
+		}
+		opts.TagRules = logseq.NewTagRules(rules)
+	}
+	if len(cfg.TagAllowlist) > 0 || len(cfg.TagDenylist) > 0 {
+		opts.Tags = logseq.NewTagFilter(cfg.TagAllowlist, cfg.TagDenylist)
+	}
+	if len(cfg.UnfurlLinks) > 0 {
+		opts.LinkResolver = logseq.NewHostResolver(cfg.UnfurlLinks)
 	}
+	if len(cfg.PagePlugins) > 0 {
+		opts.Transformer = logseq.NewCommandPlugins(cfg.PagePlugins, cfg.PageNameTemplate)
+	}
+	return opts
 }
 
 // Sync performs a full sync of all documents
-func (s *Syncer) Sync(since *time.Time, dryRun bool) (*SyncResult, error) {
+func (s *Syncer) Sync(opts SyncOptions) (*SyncResult, error) {
+	if err := s.checkBackfillPower(opts); err != nil {
+		return nil, err
+	}
+	if err := s.openGraphs(); err != nil {
+		return nil, err
+	}
+	start := s.beginRun()
+	if err := s.runPreSyncHook(opts); err != nil {
+		s.finishRun(start, nil, opts)
+		return nil, err
+	}
+
+	// Decoding a large cache is slow; skip it when nothing could have changed
+	var fingerprint *cacheFingerprint
+	if skipsUnchangedCache(opts) {
+		var unchanged bool
+		if unchanged, fingerprint = s.cacheUnchanged(); unchanged {
+			slog.Debug("cache unchanged since last sync, nothing to do")
+			result := &SyncResult{}
+			s.finishRun(start, result, opts)
+			return result, nil
+		}
+	}
+
+	docs, err := s.loadDocuments()
+	if err != nil {
+		s.finishRun(start, nil, opts)
+		return nil, err
+	}
+	// Checked first so pages this sync writes count as current
+	var rerenderBefore *time.Time
+	if !opts.DryRun && len(opts.IDs) == 0 {
+		if rerenderBefore, err = s.formatChangedAt(); err != nil {
+			slog.Warn("failed to check for page format changes", "error", err)
+		}
+	}
 	// Load a fresh auth token each sync cycle
-	apiClient := s.loadAPIClient()
+	result := s.syncDocuments(docs, opts, s.loadAPIClient())
+	if rerenderBefore != nil {
+		if reason := s.deferReason(); reason != "" {
+			slog.Info("deferring page re-render until on power", "reason", reason)
+		} else {
+			s.rerenderStalePages(docs, *rerenderBefore, result)
+		}
+	}
+	if skipsUnchangedCache(opts) {
+		s.settleCacheFingerprint(fingerprint, docs, result)
+	}
+	s.finishRun(start, result, opts)
+	return result, nil
+}
+
+// beginRun resets per-run state and returns the run's start time
+func (s *Syncer) beginRun() time.Time {
+	s.breaker = newBreaker(s.cfg.OutputFailureThreshold)
+	s.issuesFiled = false
+	return s.clock.Now()
+}
+
+// finishRun records metrics and output target states for a finished run, shows
+// any notifications and runs the post-sync hook. A nil result records a failed run. Dry runs are not
+// recorded.
+func (s *Syncer) finishRun(start time.Time, result *SyncResult, opts SyncOptions) {
+	if opts.DryRun {
+		return
+	}
+	s.recordMetrics(start, result)
+	s.recordRun(start, result, opts)
+	s.saveBreakerStates()
+	s.notifyRun(result, opts)
+	s.runPostSyncHook(result, opts)
+}
+
+// recordRun adds a finished run to the sync history in the state store
+func (s *Syncer) recordRun(start time.Time, result *SyncResult, opts SyncOptions) {
+	run := &state.SyncRun{StartedAt: start, Trigger: runTrigger(opts), Duration: s.clock.Now().Sub(start), Failed: result == nil}
+	if result != nil {
+		run.NewMeetings = result.NewMeetings
+		run.UpdatedMeetings = result.UpdatedMeetings
+		run.NewJournals = result.NewJournals
+		run.Errors = len(result.Errors)
+	}
+	if err := s.store.RecordSyncRun(run); err != nil {
+		slog.Warn("failed to record sync run", "error", err)
+	}
+}
 
+// recordMetrics writes the metrics textfile for a finished run
+func (s *Syncer) recordMetrics(start time.Time, result *SyncResult) {
+	if s.metrics == nil {
+		return
+	}
+
+	run := metrics.RunStats{Start: start, Duration: s.clock.Now().Sub(start)}
+	if result != nil {
+		run.Success = len(result.Errors) == 0
+		run.NewMeetings = result.NewMeetings
+		run.UpdatedMeetings = result.UpdatedMeetings
+		run.AdoptedMeetings = result.AdoptedMeetings
+		run.NewJournals = result.NewJournals
+		run.Errors = len(result.Errors)
+	}
+	if err := s.metrics.Record(run); err != nil {
+		slog.Warn("failed to write metrics textfile", "path", s.cfg.MetricsTextfilePath, "error", err)
+	}
+}
+
+// runTrigger returns what started a run with the given options
+func runTrigger(opts SyncOptions) string {
+	switch {
+	case len(opts.IDs) > 0:
+		return state.TriggerManual
+	case opts.Backfill:
+		return state.TriggerBackfill
+	default:
+		return state.TriggerWatch
+	}
+}
+
+// loadDocuments parses the Granola cache and returns its documents sorted by
+// meeting date for consistent ordering
+func (s *Syncer) loadDocuments() ([]*granola.Document, error) {
 	cachePath, err := granola.FindCacheFile(s.cfg.GranolaDir)
 	if err != nil {
 		return nil, fmt.Errorf("finding cache file: %w", err)
 	}
-	docs, err := granola.ParseCache(cachePath)
+	if s.panels == nil {
+		s.panels = loadPanelCache(s.store)
+	}
+	docs, err := s.parseCache(cachePath)
 	if err != nil {
 		return nil, fmt.Errorf("parsing cache: %w", err)
 	}
+	s.panels.flush(s.store)
+	if kinds := granola.TakeUnknownNodeKinds(); len(kinds) > 0 {
+		slog.Warn("notes contain unsupported node types; passed their text through", "counts", kinds)
+	}
+	sorted := sortDocumentsByDate(docs)
+	for _, g := range s.graphs {
+		g.writer.CountTitleTags(sorted)
+	}
+	return sorted, nil
+}
+
+// parseCache parses the cache file, retrying with backoff while it fails to parse
+func (s *Syncer) parseCache(cachePath string) (map[string]*granola.Document, error) {
+	docs, err := granola.ParseCacheWith(cachePath, s.panels)
+	for _, delay := range parseRetryDelays {
+		if err == nil || errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		slog.Info("cache failed to parse, retrying", "error", err, "delay", delay)
+		s.clock.Sleep(delay)
+		docs, err = granola.ParseCacheWith(cachePath, s.panels)
+	}
+	return docs, err
+}
 
+// syncDocuments syncs the given (sorted) documents
+func (s *Syncer) syncDocuments(docs []*granola.Document, opts SyncOptions, apiClient *granola.APIClient) *SyncResult {
 	result := &SyncResult{}
+	for _, g := range s.graphs {
+		g.pageIndex = nil
+	}
 	minAge := time.Duration(s.cfg.MinAgeSeconds) * time.Second
 
-	// Sort documents by meeting date for consistent ordering
-	sortedDocs := sortDocumentsByDate(docs)
-
 	ctx := context.Background()
 	var lastAPICall time.Time
 
-	for _, doc := range sortedDocs {
-		if err := s.processDocument(ctx, doc, since, minAge, dryRun, &apiClient, &lastAPICall, result); err != nil {
+	for _, doc := range docs {
+		if err := s.processDocument(ctx, doc, opts, minAge, &apiClient, &lastAPICall, result); err != nil {
 			slog.Error("failed to process document", "id", doc.ID, "title", doc.Title, "error", err)
 			result.Errors = append(result.Errors, fmt.Errorf("doc %s: %w", doc.ID, err))
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// add accumulates another result into r
+func (r *SyncResult) add(other *SyncResult) {
+	r.NewMeetings += other.NewMeetings
+	r.UpdatedMeetings += other.UpdatedMeetings
+	r.AdoptedMeetings += other.AdoptedMeetings
+	r.NewJournals += other.NewJournals
+	r.Errors = append(r.Errors, other.Errors...)
+	for _, path := range other.ChangedPages {
+		r.addChangedPage(path)
+	}
+}
+
+// addChangedPage records a page the sync wrote
+func (r *SyncResult) addChangedPage(path string) {
+	if !slices.Contains(r.ChangedPages, path) {
+		r.ChangedPages = append(r.ChangedPages, path)
+	}
 }
 
 // loadAPIClient creates a fresh API client using the current auth token.
@@ -87,29 +430,10 @@ func (s *Syncer) loadAPIClient() *granola.APIClient {
 	return granola.NewAPIClient("", token)
 }
 
-func (s *Syncer) processDocument(ctx context.Context, doc *granola.Document, since *time.Time, minAge time.Duration, dryRun bool, apiClient **granola.APIClient, lastAPICall *time.Time, result *SyncResult) error {
-	// Skip deleted documents
-	if doc.IsDeleted() {
-		slog.Debug("skipping deleted document", "id", doc.ID, "title", doc.Title)
-		return nil
-	}
-
-	// Skip meetings the user wasn't invited to
-	if !doc.IsUserAttendee(s.cfg.UserEmail) {
-		slog.Debug("skipping meeting user wasn't invited to", "id", doc.ID, "title", doc.Title)
-		return nil
-	}
-
-	// Skip documents that are too new (might still be in progress)
-	if !dryRun && time.Since(doc.UpdatedAt) < minAge {
-		slog.Debug("skipping recent document", "id", doc.ID, "title", doc.Title, "age", time.Since(doc.UpdatedAt))
-		return nil
-	}
-
-	// Apply since filter
-	meetingDate := doc.GetMeetingDate()
-	if since != nil && meetingDate.Before(*since) {
-		slog.Debug("skipping document before since date", "id", doc.ID, "title", doc.Title, "date", meetingDate)
+func (s *Syncer) processDocument(ctx context.Context, doc *granola.Document, opts SyncOptions, minAge time.Duration, apiClient **granola.APIClient, lastAPICall *time.Time, result *SyncResult) error {
+	dryRun := opts.DryRun
+	if reason := s.skipReason(doc, opts, minAge); reason != "" {
+		slog.Debug("skipping document", "id", doc.ID, "title", doc.Title, "reason", reason)
 		return nil
 	}
 
@@ -119,37 +443,149 @@ func (s *Syncer) processDocument(ctx context.Context, doc *granola.Document, sin
 	}
 
 	// Calculate content hash for change detection
-	contentHash := hashContent(doc)
+	contentHash := s.documentHash(doc)
 
 	// Check if this document needs syncing
-	needsUpdate, err := s.store.NeedsUpdate(doc.ID, doc.UpdatedAt, contentHash)
+	g := s.graphFor(doc)
+	needsUpdate, err := g.store.NeedsUpdate(doc.ID, doc.UpdatedAt, contentHash)
 	if err != nil {
 		return fmt.Errorf("checking update status: %w", err)
 	}
 
-	if !needsUpdate {
+	if !needsUpdate && !opts.Force {
 		slog.Debug("document already synced", "id", doc.ID, "title", doc.Title)
 		return nil
 	}
 
 	// Check if this is new or updated
-	existing, err := s.store.GetSyncedDocument(doc.ID)
+	existing, err := g.store.GetSyncedDocument(doc.ID)
 	if err != nil {
 		return fmt.Errorf("getting existing document: %w", err)
 	}
 
-	isNew := existing == nil
+	// Hand-made pages adopted without --upgrade keep the user's own notes
+	if existing != nil && existing.Adopted {
+		slog.Debug("skipping adopted page", "id", doc.ID, "title", doc.Title)
+		return nil
+	}
+
+	// The user can stop sync touching a page by adding granola-sync:: ignore
+	if existing != nil && !s.cfg.JournalOnly {
+		optedOut, err := s.checkOptOut(g, doc, existing, dryRun)
+		if err != nil {
+			return fmt.Errorf("checking page opt-out: %w", err)
+		}
+		if optedOut {
+			return nil
+		}
+	}
+
+	// A page for this meeting may already exist, written by another host
+	if existing == nil && s.cfg.AdoptExistingPages {
+		adopted, err := s.adoptExistingPage(g, doc, contentHash, dryRun, result)
+		if err != nil {
+			return fmt.Errorf("adopting existing page: %w", err)
+		}
+		if adopted {
+			return nil
+		}
+	}
+
+	if dryRun {
+		return s.dryRunDocument(g, doc, existing, opts, result)
+	}
+
+	return s.syncDocument(ctx, g, doc, contentHash, existing, opts, result)
+}
+
+// checkOptOut reports whether a meeting's page carries granola-sync:: ignore,
+// recording the opt-out in state the first time it is seen. Removing the
+// property opts the page back in.
+func (s *Syncer) checkOptOut(g *graph, doc *granola.Document, existing *state.SyncedDocument, dryRun bool) (bool, error) {
+	content, err := g.fs.ReadFile(existing.LogseqPagePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !logseq.IsOptedOut(string(content)) {
+		return false, nil
+	}
 
 	if dryRun {
-		return s.dryRunDocument(doc, isNew, result)
+		fmt.Printf("\n[OPTED OUT] %s\n", doc.Title)
+		fmt.Printf("  Page: %s\n", existing.LogseqPagePath)
+		return true, nil
+	}
+	if !existing.OptedOut {
+		existing.OptedOut = true
+		if err := g.store.MarkSynced(existing); err != nil {
+			return false, fmt.Errorf("marking opted out: %w", err)
+		}
+		slog.Info("page opted out of sync", "title", doc.Title, "path", existing.LogseqPagePath)
+	}
+	slog.Debug("skipping opted-out page", "id", doc.ID, "title", doc.Title)
+	return true, nil
+}
+
+// skipReason returns why a document is not eligible for syncing, or empty string if it is
+func (s *Syncer) skipReason(doc *granola.Document, opts SyncOptions, minAge time.Duration) string {
+	if len(opts.IDs) > 0 && !slices.Contains(opts.IDs, doc.ID) {
+		return "not selected"
+	}
+
+	// Skip deleted documents
+	if doc.IsDeleted() {
+		return "deleted"
+	}
+
+	// Skip meetings the user wasn't invited to
+	if !doc.IsUserAttendee(s.cfg.UserEmail) {
+		return "not an attendee"
+	}
+	if s.cfg.SkipDeclined {
+		status := doc.GetUserResponseStatus(s.cfg.UserEmail)
+		if status != "" && slices.Contains(s.cfg.SkipResponseStatuses, status) {
+			return "response " + status
+		}
+	}
+	if s.cfg.CalendarBlocks == "skip" && doc.IsCalendarBlock() {
+		return "calendar block"
+	}
+	if reason := s.domains.skipReason(doc); reason != "" {
+		return reason
+	}
+
+	// Skip meetings filtered out by title
+	if reason := s.titles.skipReason(doc.Title); reason != "" {
+		return reason
+	}
+
+	// Skip documents that are too new (might still be in progress)
+	if age := s.clock.Now().Sub(doc.UpdatedAt); (!opts.DryRun || opts.Check) && len(opts.IDs) == 0 && age < minAge {
+		return fmt.Sprintf("too recent (updated %s ago)", age.Round(time.Second))
+	}
+
+	// Apply date window
+	if opts.Since != nil && doc.GetMeetingDate().Before(*opts.Since) {
+		return "before since date"
+	}
+	if opts.Until != nil && !doc.GetMeetingDate().Before(*opts.Until) {
+		return "after until date"
 	}
 
-	return s.syncDocument(doc, contentHash, isNew, result)
+	return ""
 }
 
-func (s *Syncer) dryRunDocument(doc *granola.Document, isNew bool, result *SyncResult) error {
-	pagePath, pageContent := s.writer.DryRunMeetingPage(doc)
-	journalPath, journalContent, wouldAddJournal := s.writer.DryRunJournalEntry(doc)
+func (s *Syncer) dryRunDocument(g *graph, doc *granola.Document, existing *state.SyncedDocument, opts SyncOptions, result *SyncResult) error {
+	if s.cfg.JournalOnly {
+		return s.dryRunJournalMeeting(g, doc, existing, opts, result)
+	}
+
+	isNew := existing == nil
+	pagePath, pageContent := g.writer.DryRunMeetingPage(doc)
+	journalPath, journalContent, wouldAddJournal := g.writer.DryRunJournalEntry(doc)
 
 	action := "UPDATE"
 	if isNew {
@@ -161,10 +597,47 @@ func (s *Syncer) dryRunDocument(doc *granola.Document, isNew bool, result *SyncR
 
 	fmt.Printf("\n[%s] %s\n", action, doc.Title)
 	fmt.Printf("  Meeting date: %s\n", doc.GetMeetingDate().Format("2006-01-02 15:04"))
+	if g.name != "" {
+		fmt.Printf("  Graph: %s\n", g.name)
+	}
 	fmt.Printf("  Page: %s\n", pagePath)
-	fmt.Printf("  Content preview:\n%s\n", truncate(pageContent, 500))
+	if !isNew && existing.LogseqPagePath != "" && existing.LogseqPagePath != pagePath {
+		fmt.Printf("  Renamed from: %s\n", existing.LogseqPagePath)
+	}
+	if isNew {
+		fmt.Printf("  Content preview:\n%s\n", preview(pageContent, opts))
+	} else {
+		printPageDiff(g.fs, existing.LogseqPagePath, pagePath, pageContent, opts)
+	}
 
-	if wouldAddJournal {
+	if s.cfg.PersonPages {
+		for _, personPath := range g.writer.DryRunPersonBacklinks(doc) {
+			fmt.Printf("  Person page: %s\n", personPath)
+		}
+	}
+
+	pending := s.pendingActionItems(g, doc)
+	for _, exp := range s.exporters {
+		if count := pending[exp.target()]; count > 0 {
+			fmt.Printf("  Action items for %s: %d\n", exp.target(), count)
+		}
+	}
+	if count := s.pendingIssues(g, doc); count > 0 {
+		fmt.Printf("  Issues for %s: %d\n", s.issues.filer.target(), count)
+	}
+	if s.s3 != nil {
+		fmt.Printf("  S3: s3://%s/%s\n", s.s3.bucket, s.s3.key(doc))
+	}
+	for _, target := range s.pendingHighlights(g, doc) {
+		fmt.Printf("  Highlights for %s\n", target)
+	}
+	for _, channel := range s.pendingSlackPosts(g, doc) {
+		fmt.Printf("  Slack: %s\n", channel)
+	}
+
+	if s.skipJournalEntry(doc) {
+		fmt.Printf("  Journal: (calendar block, no entry)\n")
+	} else if wouldAddJournal {
 		result.NewJournals++
 		fmt.Printf("  Journal: %s\n", journalPath)
 		fmt.Printf("  Entry: %s", journalContent)
@@ -175,50 +648,289 @@ func (s *Syncer) dryRunDocument(doc *granola.Document, isNew bool, result *SyncR
 	return nil
 }
 
-func (s *Syncer) syncDocument(doc *granola.Document, contentHash string, isNew bool, result *SyncResult) error {
-	// Write meeting page
-	pagePath, err := s.writer.WriteMeetingPage(doc)
-	if err != nil {
-		return fmt.Errorf("writing meeting page: %w", err)
-	}
+// dryRunJournalMeeting prints what journal-only mode would write for a meeting
+func (s *Syncer) dryRunJournalMeeting(g *graph, doc *granola.Document, existing *state.SyncedDocument, opts SyncOptions, result *SyncResult) error {
+	journalPath, content, exists := g.writer.DryRunJournalMeeting(doc)
 
-	if isNew {
+	action := "UPDATE"
+	if existing == nil {
+		action = "NEW"
 		result.NewMeetings++
-		slog.Info("created meeting page", "title", doc.Title, "path", pagePath)
 	} else {
 		result.UpdatedMeetings++
-		slog.Info("updated meeting page", "title", doc.Title, "path", pagePath)
+	}
+	if !exists {
+		result.NewJournals++
 	}
 
-	// Add journal entry if this is new
-	if isNew {
-		added, err := s.writer.AppendJournalEntry(doc)
+	fmt.Printf("\n[%s] %s\n", action, doc.Title)
+	fmt.Printf("  Meeting date: %s\n", doc.GetMeetingDate().Format("2006-01-02 15:04"))
+	if g.name != "" {
+		fmt.Printf("  Graph: %s\n", g.name)
+	}
+	fmt.Printf("  Journal: %s\n", journalPath)
+	if existing != nil && existing.LogseqPagePath != "" && existing.LogseqPagePath != journalPath {
+		fmt.Printf("  Moved from: %s\n", existing.LogseqPagePath)
+	}
+	if !exists {
+		fmt.Printf("  Content preview:\n%s\n", preview(content, opts))
+		return nil
+	}
+
+	before, after, err := g.writer.DryRunJournalContent(doc)
+	if err != nil {
+		return err
+	}
+	printChanges(journalPath, journalPath, before, after)
+	return nil
+}
+
+// printPageDiff prints the changes an update would make to a meeting page, or
+// a preview of the page if the current one can't be read. oldPath is where the
+// page was last written, if it was.
+func printPageDiff(fsys logseq.FS, oldPath, pagePath, content string, opts SyncOptions) {
+	if oldPath == "" {
+		oldPath = pagePath
+	}
+	current, err := fsys.ReadFile(oldPath)
+	if err != nil {
+		fmt.Printf("  Content preview:\n%s\n", preview(content, opts))
+		return
+	}
+	printChanges(oldPath, pagePath, string(current), content)
+}
+
+// printChanges prints a unified diff of a dry-run change
+func printChanges(oldPath, newPath, before, after string) {
+	diff := unifiedDiff(oldPath, newPath, before, after)
+	if diff == "" {
+		fmt.Printf("  Changes: (none)\n")
+		return
+	}
+	fmt.Printf("  Changes:\n%s", diff)
+}
+
+func (s *Syncer) syncDocument(ctx context.Context, g *graph, doc *granola.Document, contentHash string, existing *state.SyncedDocument, opts SyncOptions, result *SyncResult) error {
+	write := s.writeMeetingPage
+	if s.cfg.JournalOnly {
+		write = s.writeJournalMeeting
+	}
+	pagePath, err := write(g, doc, existing, result)
+	if err != nil {
+		return err
+	}
+	result.addChangedPage(pagePath)
+
+	// Log the meeting on each attendee's person page. Journal-only meetings have
+	// no page to link to.
+	if s.cfg.PersonPages && !s.cfg.JournalOnly {
+		// Leave the document unsynced so the backlinks are retried next run
+		if !s.breaker.allow(TargetPersonPages) {
+			return errTargetDisabled(TargetPersonPages)
+		}
+		updated, err := g.writer.AppendPersonBacklinks(doc)
+		s.breaker.record(TargetPersonPages, err)
 		if err != nil {
-			return fmt.Errorf("appending journal entry: %w", err)
+			return fmt.Errorf("updating person pages: %w", err)
 		}
-		if added {
-			result.NewJournals++
-			slog.Info("added journal entry", "title", doc.Title)
+		if updated > 0 {
+			slog.Info("updated person pages", "title", doc.Title, "count", updated)
 		}
 	}
 
+	// Send the user's action items to task apps
+	if err := s.exportActionItems(ctx, g, doc); err != nil {
+		return err
+	}
+
+	// File action items marked for the issue tracker
+	if err := s.fileIssues(ctx, g, doc); err != nil {
+		return err
+	}
+
+	if err := s.uploadToS3(ctx, g, doc); err != nil {
+		return err
+	}
+
+	// Send the notes' highlights to reading and notes apps
+	if err := s.exportHighlights(ctx, g, doc); err != nil {
+		return err
+	}
+
+	// Post the notes of tagged meetings to Slack
+	if err := s.postToSlack(ctx, g, doc); err != nil {
+		return err
+	}
+
+	// Tell outside automations about the meeting
+	if err := s.writeOutboxEvent(doc, pagePath, existing == nil, opts); err != nil {
+		return err
+	}
+
 	// Mark as synced
-	syncedDoc := &state.SyncedDocument{
+	syncedDoc := s.newSyncedDocument(g.writer, doc, pagePath, contentHash)
+	// Time-to-notes: how long after the last edit in Granola the page was written
+	if !opts.Backfill && !doc.UpdatedAt.Before(s.watchStart) {
+		syncedDoc.SyncLatency = syncedDoc.SyncedAt.Sub(doc.UpdatedAt)
+	}
+
+	if err := g.store.MarkSynced(syncedDoc); err != nil {
+		return fmt.Errorf("marking synced: %w", err)
+	}
+
+	return nil
+}
+
+// newSyncedDocument builds the state record for a meeting written to pagePath,
+// with the meeting details the writer puts on its page
+func (s *Syncer) newSyncedDocument(w *logseq.Writer, doc *granola.Document, pagePath, contentHash string) *state.SyncedDocument {
+	meetingDate := doc.GetMeetingDate()
+	return &state.SyncedDocument{
 		ID:               doc.ID,
 		Title:            doc.Title,
-		SyncedAt:         time.Now(),
+		SyncedAt:         s.clock.Now(),
 		GranolaUpdatedAt: &doc.UpdatedAt,
 		LogseqPagePath:   pagePath,
 		ContentHash:      contentHash,
+		MachineID:        s.machineID,
+		MeetingDate:      &meetingDate,
+		Attendees:        w.AttendeeNames(doc),
+		Tags:             w.PageTags(doc),
+	}
+}
+
+// writeMeetingPage writes a meeting's page and, for a new meeting, its journal
+// entry, in the configured write_order. Returns the page path.
+func (s *Syncer) writeMeetingPage(g *graph, doc *granola.Document, existing *state.SyncedDocument, result *SyncResult) (string, error) {
+	isNew := existing == nil
+	addJournal := isNew && !s.skipJournalEntry(doc)
+	journalFirst := s.cfg.WriteOrder == "journal_first"
+
+	if addJournal && journalFirst {
+		if err := s.appendJournalEntry(g, doc, result); err != nil {
+			return "", err
+		}
 	}
 
-	if err := s.store.MarkSynced(syncedDoc); err != nil {
-		return fmt.Errorf("marking synced: %w", err)
+	// Move the existing page if the title (and so the filename) changed
+	if !isNew {
+		renamed, err := g.writer.RenameMeetingPage(existing.LogseqPagePath, doc)
+		if err != nil {
+			return "", fmt.Errorf("renaming meeting page: %w", err)
+		}
+		if renamed {
+			slog.Info("renamed meeting page", "title", doc.Title, "from", existing.LogseqPagePath)
+		}
 	}
 
+	// Write meeting page
+	pagePath, err := g.writer.WriteMeetingPage(doc)
+	if err != nil {
+		return "", fmt.Errorf("writing meeting page: %w", err)
+	}
+
+	if isNew {
+		result.NewMeetings++
+		slog.Info("created meeting page", "title", doc.Title, "path", pagePath)
+	} else {
+		result.UpdatedMeetings++
+		slog.Info("updated meeting page", "title", doc.Title, "path", pagePath)
+	}
+
+	if addJournal && !journalFirst {
+		if err := s.appendJournalEntry(g, doc, result); err != nil {
+			return "", err
+		}
+	}
+
+	return pagePath, nil
+}
+
+// appendJournalEntry adds a new meeting's entry to its journal
+func (s *Syncer) appendJournalEntry(g *graph, doc *granola.Document, result *SyncResult) error {
+	added, err := g.writer.AppendJournalEntry(doc)
+	if err != nil {
+		return fmt.Errorf("appending journal entry: %w", err)
+	}
+	if added {
+		result.NewJournals++
+		result.addChangedPage(g.writer.JournalPath(doc))
+		slog.Info("added journal entry", "title", doc.Title)
+	}
 	return nil
 }
 
+// skipJournalEntry reports whether a meeting is left out of the journal: calendar
+// blocks are when they are tagged instead of synced like meetings
+func (s *Syncer) skipJournalEntry(doc *granola.Document) bool {
+	return s.cfg.CalendarBlocks == "tag" && doc.IsCalendarBlock()
+}
+
+// writeJournalMeeting writes a meeting's notes into its journal (journal-only
+// mode), moving the block if the meeting changed day. Returns the journal path.
+func (s *Syncer) writeJournalMeeting(g *graph, doc *granola.Document, existing *state.SyncedDocument, result *SyncResult) (string, error) {
+	journalPath, added, err := g.writer.WriteJournalMeeting(doc)
+	if err != nil {
+		return "", fmt.Errorf("writing journal meeting: %w", err)
+	}
+
+	if existing != nil && existing.LogseqPagePath != "" && existing.LogseqPagePath != journalPath {
+		removed, err := g.writer.RemoveJournalMeeting(existing.LogseqPagePath, doc.ID)
+		if err != nil {
+			return "", fmt.Errorf("removing meeting from old journal: %w", err)
+		}
+		if removed {
+			result.addChangedPage(existing.LogseqPagePath)
+		}
+	}
+
+	if existing == nil {
+		result.NewMeetings++
+	} else {
+		result.UpdatedMeetings++
+	}
+	if added {
+		result.NewJournals++
+		slog.Info("added meeting to journal", "title", doc.Title, "path", journalPath)
+	} else {
+		slog.Info("updated meeting in journal", "title", doc.Title, "path", journalPath)
+	}
+	return journalPath, nil
+}
+
+// adoptExistingPage records a meeting page that is already in the graph (e.g.
+// written by another host syncing the same graph) in local state instead of
+// rewriting it. Returns true if the document was adopted.
+func (s *Syncer) adoptExistingPage(g *graph, doc *granola.Document, contentHash string, dryRun bool, result *SyncResult) (bool, error) {
+	if g.pageIndex == nil {
+		index, err := logseq.ScanGranolaIDs(g.fs, g.basePath)
+		if err != nil {
+			return false, err
+		}
+		g.pageIndex = index
+	}
+
+	pagePath, ok := g.pageIndex[doc.ID]
+	if !ok {
+		return false, nil
+	}
+
+	result.AdoptedMeetings++
+	if dryRun {
+		fmt.Printf("\n[ADOPT] %s\n", doc.Title)
+		fmt.Printf("  Existing page: %s\n", pagePath)
+		return true, nil
+	}
+
+	syncedDoc := s.newSyncedDocument(g.writer, doc, pagePath, contentHash)
+	if err := g.store.MarkSynced(syncedDoc); err != nil {
+		return false, fmt.Errorf("marking synced: %w", err)
+	}
+
+	slog.Info("adopted existing meeting page", "title", doc.Title, "path", pagePath)
+	return true, nil
+}
+
 func (s *Syncer) fetchAndPopulateNotes(ctx context.Context, doc *granola.Document, apiClient **granola.APIClient, lastAPICall *time.Time) {
 	// Rate limit API calls
 	if elapsed := time.Since(*lastAPICall); elapsed < apiCallDelay {
@@ -239,6 +951,7 @@ func (s *Syncer) fetchAndPopulateNotes(ctx context.Context, doc *granola.Documen
 
 	if md := granola.BestSummaryFromPanels(panels); md != "" {
 		doc.NotesMarkdown = &md
+		doc.NotesSource = granola.NotesFromAPI
 		slog.Debug("populated notes from API", "id", doc.ID, "title", doc.Title)
 	}
 }
@@ -254,6 +967,48 @@ func sortDocumentsByDate(docs map[string]*granola.Document) []*granola.Document
 	return sorted
 }
 
+// hashTranscript folds the transcript into a content hash so transcript changes
+// trigger a re-sync when transcripts are rendered
+func hashTranscript(contentHash string, doc *granola.Document) string {
+	if len(doc.Transcript) == 0 {
+		return contentHash
+	}
+	h := sha256.New()
+	h.Write([]byte(contentHash))
+	for _, e := range doc.Transcript {
+		h.Write([]byte(e.Text))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashMyNotes folds the user's own notes into a content hash so edits to them
+// trigger a re-sync when they are rendered
+func hashMyNotes(contentHash string, doc *granola.Document) string {
+	if doc.MyNotes == "" {
+		return contentHash
+	}
+	h := sha256.New()
+	h.Write([]byte(contentHash))
+	h.Write([]byte(doc.MyNotes))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// documentHash returns the content hash used to detect changes to a meeting,
+// covering the parts of it that are rendered
+func (s *Syncer) documentHash(doc *granola.Document) string {
+	contentHash := hashContent(doc)
+	if s.cfg.IncludeTranscript {
+		contentHash = hashTranscript(contentHash, doc)
+	}
+	if s.cfg.IncludeMyNotes {
+		contentHash = hashMyNotes(contentHash, doc)
+	}
+	if s.issues != nil {
+		contentHash = s.issues.hash(contentHash, doc.ID)
+	}
+	return contentHash
+}
+
 func hashContent(doc *granola.Document) string {
 	h := sha256.New()
 	h.Write([]byte(doc.Title))
@@ -266,9 +1021,41 @@ func hashContent(doc *granola.Document) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// previewChars is the dry-run preview length when no line limit is given
+const previewChars = 500
+
+// preview shortens page content for dry-run output according to the options
+func preview(content string, opts SyncOptions) string {
+	switch {
+	case opts.FullPreview:
+		return content
+	case opts.PreviewLines > 0:
+		return truncateLines(content, opts.PreviewLines)
+	default:
+		return truncate(content, previewChars)
+	}
+}
+
+// truncate shortens s to at most max runes, never splitting a UTF-8 sequence
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := 0
+	for i := range s {
+		if runes == max {
+			return s[:i] + "..."
+		}
+		runes++
+	}
+	return s
+}
+
+// truncateLines keeps the first n lines of s and notes how many were dropped
+func truncateLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lines) <= n {
 		return s
 	}
-	return s[:max] + "..."
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n... (%d more lines)", len(lines)-n)
 }
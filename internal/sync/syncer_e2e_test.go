@@ -1,9 +1,15 @@
 package sync
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +17,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/keychain"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
 	"github.com/philrhinehart/granola-sync/internal/state"
 )
 
@@ -22,6 +30,7 @@ type testDoc struct {
 	Notes     string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Attendees []string // Extra attendee names besides the user
 }
 
 // makeDocument creates a test document with sensible defaults
@@ -56,6 +65,22 @@ func makeCache(docs []testDoc) string {
 
 		// Add calendar event with attendees so user email matching works
 		if doc.Email != "" {
+			attendees := []map[string]interface{}{
+				{
+					"email":          doc.Email,
+					"displayName":    "Test User",
+					"responseStatus": "accepted",
+					"self":           true,
+				},
+			}
+			for _, name := range doc.Attendees {
+				attendees = append(attendees, map[string]interface{}{
+					"email":          strings.ToLower(strings.ReplaceAll(name, " ", ".")) + "@example.com",
+					"displayName":    name,
+					"responseStatus": "accepted",
+				})
+			}
+
 			docMap["google_calendar_event"] = map[string]interface{}{
 				"id":      "event-" + doc.ID,
 				"summary": doc.Title,
@@ -67,14 +92,7 @@ func makeCache(docs []testDoc) string {
 					"dateTime": doc.CreatedAt.Add(time.Hour).Format(time.RFC3339),
 					"timeZone": "America/Los_Angeles",
 				},
-				"attendees": []map[string]interface{}{
-					{
-						"email":          doc.Email,
-						"displayName":    "Test User",
-						"responseStatus": "accepted",
-						"self":           true,
-					},
-				},
+				"attendees": attendees,
 			}
 		}
 
@@ -130,6 +148,51 @@ func writeCache(t *testing.T, path, content string) {
 	require.NoError(t, err)
 }
 
+// e2eEnv is a Logseq graph, Granola directory and state store in a temp
+// directory, for end-to-end tests
+type e2eEnv struct {
+	dir       string // The temp directory holding everything
+	logseqDir string
+	cachePath string
+	cfg       *config.Config
+	store     state.Store
+}
+
+// newE2EEnv creates a graph with empty pages and journals directories and opens
+// the state store, which is closed when the test ends. configure, if set,
+// adjusts the config before the store is opened.
+func newE2EEnv(t *testing.T, configure func(cfg *config.Config)) *e2eEnv {
+	t.Helper()
+	dir := t.TempDir()
+	logseqDir := filepath.Join(dir, "logseq")
+	require.NoError(t, os.MkdirAll(filepath.Join(logseqDir, "pages"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(logseqDir, "journals"), 0o755))
+	granolaDir := filepath.Join(dir, "granola")
+	require.NoError(t, os.MkdirAll(granolaDir, 0o755))
+
+	cfg := &config.Config{
+		GranolaDir:     granolaDir,
+		LogseqBasePath: logseqDir,
+		StateDBPath:    filepath.Join(dir, "state.db"),
+		UserEmail:      "test@example.com",
+		UserName:       "Test User",
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	store, err := state.NewStore(cfg.StateDBPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	return &e2eEnv{
+		dir:       dir,
+		logseqDir: logseqDir,
+		cachePath: filepath.Join(granolaDir, "cache-v4.json"),
+		cfg:       cfg,
+		store:     store,
+	}
+}
+
 func TestSyncE2E(t *testing.T) {
 	// Setup temp dirs
 	tmpDir := t.TempDir()
@@ -163,7 +226,7 @@ func TestSyncE2E(t *testing.T) {
 		defer func() { _ = store.Close() }()
 
 		syncer := NewSyncer(cfg, store)
-		result, err := syncer.Sync(nil, false)
+		result, err := syncer.Sync(SyncOptions{})
 		require.NoError(t, err)
 
 		assert.Equal(t, 1, result.NewMeetings)
@@ -187,7 +250,7 @@ func TestSyncE2E(t *testing.T) {
 		defer func() { _ = store.Close() }()
 
 		syncer := NewSyncer(cfg, store)
-		result, err := syncer.Sync(nil, false)
+		result, err := syncer.Sync(SyncOptions{})
 		require.NoError(t, err)
 
 		assert.Equal(t, 0, result.NewMeetings)
@@ -205,7 +268,7 @@ func TestSyncE2E(t *testing.T) {
 		defer func() { _ = store.Close() }()
 
 		syncer := NewSyncer(cfg, store)
-		result, err := syncer.Sync(nil, false)
+		result, err := syncer.Sync(SyncOptions{})
 		require.NoError(t, err)
 
 		assert.Equal(t, 0, result.NewMeetings)
@@ -229,7 +292,7 @@ func TestSyncE2E(t *testing.T) {
 		defer func() { _ = store.Close() }()
 
 		syncer := NewSyncer(cfg, store)
-		result, err := syncer.Sync(nil, false)
+		result, err := syncer.Sync(SyncOptions{})
 		require.NoError(t, err)
 
 		assert.Equal(t, 1, result.NewMeetings)
@@ -260,7 +323,7 @@ func TestSyncE2E(t *testing.T) {
 		defer func() { _ = store.Close() }()
 
 		syncer := NewSyncer(cfg, store)
-		result, err := syncer.Sync(nil, false)
+		result, err := syncer.Sync(SyncOptions{})
 		require.NoError(t, err)
 
 		// doc3 should be skipped because user email doesn't match
@@ -335,7 +398,7 @@ func TestSyncE2E_DeletedDocument(t *testing.T) {
 	defer func() { _ = store.Close() }()
 
 	syncer := NewSyncer(cfg, store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 	require.NoError(t, err)
 
 	// Deleted document should be skipped
@@ -377,7 +440,7 @@ func TestSyncE2E_DryRun(t *testing.T) {
 	defer func() { _ = store.Close() }()
 
 	syncer := NewSyncer(cfg, store)
-	result, err := syncer.Sync(nil, true) // dryRun = true
+	result, err := syncer.Sync(SyncOptions{DryRun: true}) // dryRun = true
 	require.NoError(t, err)
 
 	assert.Equal(t, 1, result.NewMeetings)
@@ -391,3 +454,1141 @@ func TestSyncE2E_DryRun(t *testing.T) {
 	_, err = os.Stat(journalPath)
 	assert.True(t, os.IsNotExist(err), "Expected NO journal to be created during dry run")
 }
+
+func TestSyncE2E_TitleRename(t *testing.T) {
+	env := newE2EEnv(t, nil)
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Untitled Meeting", "test@example.com", "Some notes"),
+	}))
+	_, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	// Rename the meeting in Granola
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap Review", "test@example.com", "Some notes"),
+	}))
+	result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+
+	oldPage := filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Untitled Meeting.md")
+	newPage := filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Roadmap Review.md")
+	_, err = os.Stat(oldPage)
+	assert.True(t, os.IsNotExist(err), "Expected old page to be moved")
+	_, err = os.Stat(newPage)
+	assert.NoError(t, err, "Expected page under the new title")
+
+	journalContent, err := os.ReadFile(filepath.Join(env.logseqDir, "journals", "2025_01_28.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(journalContent), "[[meetings/2025-01-28/Roadmap Review]]")
+	assert.NotContains(t, string(journalContent), "Untitled Meeting")
+
+	synced, err := env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("pages", "meetings___2025-01-28___Roadmap Review.md"), synced.LogseqPagePath, "Expected the path relative to the graph")
+}
+
+func TestSyncE2E_PersonPages(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.PersonPages = true
+	})
+
+	doc := makeDocument("doc1", "Project Sync", "test@example.com", "Notes")
+	doc.Attendees = []string{"Bob Smith"}
+	writeCache(t, env.cachePath, makeCache([]testDoc{doc}))
+
+	_, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	personPath := filepath.Join(env.logseqDir, "pages", "@Bob Smith.md")
+	content, err := os.ReadFile(personPath)
+	require.NoError(t, err)
+	assert.Equal(t, "- [[2025-01-28]] met in [[meetings/2025-01-28/Project Sync]]\n", string(content))
+
+	// The user doesn't get a person page for their own meetings
+	_, err = os.Stat(filepath.Join(env.logseqDir, "pages", "@Test User.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Updating the meeting doesn't duplicate the backlink
+	doc.Notes = "Updated notes"
+	writeCache(t, env.cachePath, makeCache([]testDoc{doc}))
+	result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+
+	content, err = os.ReadFile(personPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "met in"))
+}
+
+func TestSyncE2E_AdoptPagesFromOtherHost(t *testing.T) {
+	hostA := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.MachineID = "host-a"
+		cfg.AdoptExistingPages = true
+	})
+	writeCache(t, hostA.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Team Standup", "test@example.com", "Notes"),
+	}))
+
+	result, err := NewSyncer(hostA.cfg, hostA.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewMeetings)
+
+	// The second host shares the graph and Granola cache but keeps its own state
+	cfgB := *hostA.cfg
+	cfgB.StateDBPath = filepath.Join(hostA.dir, "host-b.db")
+	cfgB.MachineID = "host-b"
+	storeB, err := state.NewStore(cfgB.StateDBPath)
+	require.NoError(t, err)
+	defer func() { _ = storeB.Close() }()
+
+	// The second host finds the page host A wrote and adopts it
+	result, err = NewSyncer(&cfgB, storeB).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.NewMeetings)
+	assert.Equal(t, 1, result.AdoptedMeetings)
+	assert.Equal(t, 0, result.NewJournals)
+
+	adopted, err := storeB.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	require.NotNil(t, adopted)
+	assert.Equal(t, filepath.Join("pages", "meetings___2025-01-28___Team Standup.md"), adopted.LogseqPagePath)
+	assert.Equal(t, "host-b", adopted.MachineID)
+
+	// Once adopted, the document is treated as synced
+	result, err = NewSyncer(&cfgB, storeB).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.AdoptedMeetings)
+	assert.Equal(t, 0, result.UpdatedMeetings)
+
+	journalContent, err := os.ReadFile(filepath.Join(hostA.logseqDir, "journals", "2025_01_28.md"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(journalContent), "Team Standup"))
+}
+
+func TestSyncE2E_AdoptPagesOnRemoteGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+	granolaDir := filepath.Join(tmpDir, "granola")
+	require.NoError(t, os.MkdirAll(granolaDir, 0o755))
+	writeCache(t, filepath.Join(granolaDir, "cache-v4.json"), makeCache([]testDoc{
+		makeDocument("doc1", "Team Standup", "test@example.com", "Notes"),
+	}))
+
+	// Both hosts reach the graph through the same filesystem, never the local disk
+	graphFS := logseq.NewMemFS()
+	newHost := func(name string) *Syncer {
+		cfg := &config.Config{
+			GranolaDir:         granolaDir,
+			LogseqBasePath:     filepath.Join(tmpDir, "logseq"),
+			UserEmail:          "test@example.com",
+			UserName:           "Test User",
+			MachineID:          name,
+			AdoptExistingPages: true,
+		}
+		store, err := state.NewStore(":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		syncer := NewSyncer(cfg, store)
+		syncer.graphs[0].fs = graphFS
+		syncer.graphs[0].writer.SetFS(graphFS)
+		return syncer
+	}
+
+	result, err := newHost("host-a").Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewMeetings)
+
+	result, err = newHost("host-b").Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.NewMeetings)
+	assert.Equal(t, 1, result.AdoptedMeetings)
+	assert.NoDirExists(t, filepath.Join(tmpDir, "logseq"))
+}
+
+func TestSyncE2E_SharedStoreAcrossGraphPaths(t *testing.T) {
+	hostA := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.MachineID = "host-a"
+	})
+	writeCache(t, hostA.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Planning", "test@example.com", "Roadmap"),
+		makeDocument("doc2", "Retro", "test@example.com", "Went well"),
+	}))
+	_, err := NewSyncer(hostA.cfg, hostA.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	// The second host shares the state store, with its copy of the graph at another path
+	graphB := filepath.Join(t.TempDir(), "logseq")
+	require.NoError(t, os.CopyFS(graphB, os.DirFS(hostA.logseqDir)))
+	cfgB := *hostA.cfg
+	cfgB.LogseqBasePath = graphB
+	cfgB.MachineID = "host-b"
+
+	// There, the user takes one page over, and both meetings change in Granola
+	optedOut := filepath.Join(graphB, "pages", "meetings___2025-01-28___Planning.md")
+	edited := "granola-sync:: ignore\n\n- Planning, rewritten by hand\n"
+	require.NoError(t, os.WriteFile(optedOut, []byte(edited), 0o644))
+	planning := makeDocument("doc1", "Planning", "test@example.com", "Roadmap, revised")
+	planning.UpdatedAt = planning.UpdatedAt.Add(time.Hour)
+	retro := makeDocument("doc2", "Sprint Retro", "test@example.com", "Went well")
+	retro.UpdatedAt = retro.UpdatedAt.Add(time.Hour)
+	writeCache(t, hostA.cachePath, makeCache([]testDoc{planning, retro}))
+
+	result, err := NewSyncer(&cfgB, hostA.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+
+	page, err := os.ReadFile(optedOut)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(page), "Expected the opted-out page to be left alone")
+
+	// The renamed meeting's page is moved in host B's graph
+	assert.NoFileExists(t, filepath.Join(graphB, "pages", "meetings___2025-01-28___Retro.md"))
+	assert.FileExists(t, filepath.Join(graphB, "pages", "meetings___2025-01-28___Sprint Retro.md"))
+}
+
+func TestSyncE2E_AdoptHandMadePages(t *testing.T) {
+	for _, upgrade := range []bool{false, true} {
+		t.Run(fmt.Sprintf("upgrade=%t", upgrade), func(t *testing.T) {
+			env := newE2EEnv(t, nil)
+			writeCache(t, env.cachePath, makeCache([]testDoc{
+				makeDocument("doc1", "Team Standup", "test@example.com", "Granola notes"),
+			}))
+
+			handMade := filepath.Join(env.logseqDir, "pages", "team standup 2025-01-28.md")
+			require.NoError(t, os.WriteFile(handMade, []byte("- my own notes\n"), 0o644))
+
+			result, err := NewSyncer(env.cfg, env.store).Adopt(upgrade, false)
+			require.NoError(t, err)
+			require.Len(t, result.Matches, 1)
+			assert.Equal(t, "doc1", result.Matches[0].DocumentID)
+			assert.Equal(t, handMade, result.Matches[0].PagePath)
+
+			generated := filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Team Standup.md")
+			synced, err := env.store.GetSyncedDocument("doc1")
+			require.NoError(t, err)
+			require.NotNil(t, synced)
+			if upgrade {
+				assert.Equal(t, filepath.Join("pages", "meetings___2025-01-28___Team Standup.md"), synced.LogseqPagePath)
+				content, err := os.ReadFile(generated)
+				require.NoError(t, err)
+				assert.Contains(t, string(content), "granola-id:: doc1")
+				_, err = os.Stat(handMade)
+				assert.True(t, os.IsNotExist(err))
+			} else {
+				assert.Equal(t, filepath.Join("pages", "team standup 2025-01-28.md"), synced.LogseqPagePath)
+				content, err := os.ReadFile(handMade)
+				require.NoError(t, err)
+				assert.Equal(t, "- my own notes\n", string(content))
+			}
+
+			// Sync no longer creates a duplicate page
+			syncResult, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, 0, syncResult.NewMeetings)
+			assert.Equal(t, 0, syncResult.UpdatedMeetings)
+
+			// Editing the meeting leaves a page adopted without --upgrade alone
+			edited := makeDocument("doc1", "Team Standup", "test@example.com", "Edited notes")
+			edited.UpdatedAt = edited.UpdatedAt.Add(time.Hour)
+			writeCache(t, env.cachePath, makeCache([]testDoc{edited}))
+			syncResult, err = NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, 0, syncResult.NewMeetings)
+			if upgrade {
+				assert.Equal(t, 1, syncResult.UpdatedMeetings)
+				content, err := os.ReadFile(generated)
+				require.NoError(t, err)
+				assert.Contains(t, string(content), "Edited notes")
+			} else {
+				assert.Equal(t, 0, syncResult.UpdatedMeetings)
+				content, err := os.ReadFile(handMade)
+				require.NoError(t, err)
+				assert.Equal(t, "- my own notes\n", string(content))
+				_, err = os.Stat(generated)
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestSyncE2E_AdoptOnePagePerMeeting(t *testing.T) {
+	env := newE2EEnv(t, nil)
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Team Standup", "test@example.com", "Morning"),
+		makeDocument("doc2", "Team Standup", "test@example.com", "Afternoon"),
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(env.logseqDir, "pages", "team standup 2025-01-28.md"), []byte("- my own notes\n"), 0o644))
+
+	result, err := NewSyncer(env.cfg, env.store).Adopt(false, false)
+	require.NoError(t, err)
+	assert.Len(t, result.Matches, 1, "one hand-made page is adopted by one meeting")
+}
+
+func TestSyncE2E_ChunkedBackfill(t *testing.T) {
+	env := newE2EEnv(t, nil)
+
+	var docs []testDoc
+	for i, month := range []time.Month{time.January, time.March, time.May} {
+		doc := makeDocument(fmt.Sprintf("doc%d", i+1), fmt.Sprintf("Monthly Review %d", i+1), "test@example.com", "Notes")
+		doc.CreatedAt = time.Date(2025, month, 10, 10, 0, 0, 0, time.Local)
+		doc.UpdatedAt = doc.CreatedAt.Add(time.Hour)
+		docs = append(docs, doc)
+	}
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	// Interrupt after the first window
+	ctx, cancel := context.WithCancel(context.Background())
+	var chunks []ChunkResult
+	result, err := NewSyncer(env.cfg, env.store).SyncChunked(ctx, SyncOptions{}, 30, func(chunk ChunkResult) {
+		chunks = append(chunks, chunk)
+		cancel()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, 1, result.NewMeetings)
+
+	checkpoint, err := env.store.GetValue(checkpointKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02-09", checkpoint)
+
+	// Resuming picks up from the checkpoint and skips the finished window
+	chunks = nil
+	result, err = NewSyncer(env.cfg, env.store).SyncChunked(context.Background(), SyncOptions{}, 30, func(chunk ChunkResult) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NewMeetings)
+	assert.Equal(t, "2025-02-09", chunks[0].Start.Format("2006-01-02"))
+	assert.Len(t, chunks, 4)
+
+	for _, doc := range docs {
+		synced, err := env.store.GetSyncedDocument(doc.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, synced, "Expected %s to be synced", doc.ID)
+	}
+
+	checkpoint, err = env.store.GetValue(checkpointKey)
+	require.NoError(t, err)
+	assert.Empty(t, checkpoint, "Expected checkpoint to be cleared after a complete backfill")
+
+	runs, err := env.store.RecentSyncRuns(10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.True(t, runs[1].Failed, "Expected the interrupted backfill to be recorded as failed")
+	assert.False(t, runs[0].Failed)
+	assert.Equal(t, 2, runs[0].NewMeetings)
+}
+
+func TestSyncE2E_ChunkedBackfillCancelled(t *testing.T) {
+	env := newE2EEnv(t, nil)
+	writeCache(t, env.cachePath, makeCache([]testDoc{makeDocument("doc1", "Planning", "test@example.com", "Notes")}))
+
+	// Cancelled before the first window, the backfill stops without syncing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := NewSyncer(env.cfg, env.store).SyncChunked(ctx, SyncOptions{}, 30, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, result.NewMeetings)
+
+	synced, err := env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	assert.Nil(t, synced)
+
+	// The run is still recorded, as a failed one
+	runs, err := env.store.RecentSyncRuns(10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.True(t, runs[0].Failed)
+}
+
+func TestSyncE2E_ChunkedBackfillRetriesFailedWindow(t *testing.T) {
+	env := newE2EEnv(t, nil)
+
+	var docs []testDoc
+	for i, month := range []time.Month{time.January, time.March, time.May} {
+		doc := makeDocument(fmt.Sprintf("doc%d", i+1), fmt.Sprintf("Monthly Review %d", i+1), "test@example.com", "Notes")
+		doc.CreatedAt = time.Date(2025, month, 10, 10, 0, 0, 0, time.Local)
+		doc.UpdatedAt = doc.CreatedAt.Add(time.Hour)
+		docs = append(docs, doc)
+	}
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	// A directory where the March page goes fails its window
+	blocked := filepath.Join(env.logseqDir, "pages", "meetings___2025-03-10___Monthly Review 2.md")
+	require.NoError(t, os.MkdirAll(blocked, 0o755))
+
+	result, err := NewSyncer(env.cfg, env.store).SyncChunked(context.Background(), SyncOptions{}, 30, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NewMeetings)
+	assert.NotEmpty(t, result.Errors)
+
+	checkpoint, err := env.store.GetValue(checkpointKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02-09", checkpoint, "Expected the checkpoint to stay at the failed window")
+
+	// Resuming retries the failed window
+	require.NoError(t, os.Remove(blocked))
+	result, err = NewSyncer(env.cfg, env.store).SyncChunked(context.Background(), SyncOptions{}, 30, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewMeetings)
+	assert.Empty(t, result.Errors)
+
+	checkpoint, err = env.store.GetValue(checkpointKey)
+	require.NoError(t, err)
+	assert.Empty(t, checkpoint)
+}
+
+func TestSyncE2E_FailingOutputTargetIsDisabled(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.PersonPages = true
+		cfg.OutputFailureThreshold = 2
+	})
+
+	// A directory where the person page should be makes every backlink write fail
+	require.NoError(t, os.MkdirAll(filepath.Join(env.logseqDir, "pages", "@Bob Smith.md"), 0o755))
+
+	var docs []testDoc
+	for i := 1; i <= 3; i++ {
+		doc := makeDocument(fmt.Sprintf("doc%d", i), fmt.Sprintf("Sync %d", i), "test@example.com", "Notes")
+		doc.CreatedAt = doc.CreatedAt.Add(time.Duration(i) * time.Hour)
+		doc.Attendees = []string{"Bob Smith"}
+		docs = append(docs, doc)
+	}
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 3)
+	assert.Contains(t, result.Errors[2].Error(), "person_pages disabled after repeated failures")
+
+	states, err := LoadBreakerStates(env.store)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, TargetPersonPages, states[0].Target)
+	assert.True(t, states[0].Open)
+	assert.Equal(t, 2, states[0].Failures)
+
+	// Documents stay unsynced so the backlinks are retried next run
+	synced, err := env.store.GetSyncedDocument("doc3")
+	require.NoError(t, err)
+	assert.Nil(t, synced)
+}
+
+// fakeExporter records exported action items
+type fakeExporter struct {
+	items []actionItem
+	err   error
+}
+
+func (e *fakeExporter) target() string { return "fake" }
+
+func (e *fakeExporter) export(ctx context.Context, item actionItem) (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	e.items = append(e.items, item)
+	return fmt.Sprintf("task-%d", len(e.items)), nil
+}
+
+func TestSyncE2E_ExportActionItems(t *testing.T) {
+	env := newE2EEnv(t, nil)
+
+	notes := "**Action Items**\n- Test User: Send the deck\n- Bob: Book a room"
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", notes),
+	}))
+
+	// A failing export leaves the meeting unsynced so it's retried
+	failing := &fakeExporter{err: fmt.Errorf("service unavailable")}
+	syncer := NewSyncer(env.cfg, env.store)
+	syncer.exporters = []taskExporter{failing}
+	result, err := syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	synced, err := env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	assert.Nil(t, synced)
+
+	exporter := &fakeExporter{}
+	syncer = NewSyncer(env.cfg, env.store)
+	syncer.exporters = []taskExporter{exporter}
+	_, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exporter.items, 1, "only the user's action items are exported")
+	assert.Equal(t, "Send the deck", exporter.items[0].Text)
+	assert.Equal(t, "Roadmap", exporter.items[0].MeetingTitle)
+	assert.Equal(t, "logseq://graph/logseq?page=meetings%2F2025-01-28%2FRoadmap", exporter.items[0].PageURL)
+
+	// Re-syncing an edited meeting doesn't duplicate tasks, but new items are exported
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", notes+"\n- Test User: Review the budget"),
+	}))
+	syncer = NewSyncer(env.cfg, env.store)
+	syncer.exporters = []taskExporter{exporter}
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	require.Len(t, exporter.items, 2)
+	assert.Equal(t, "Review the budget", exporter.items[1].Text)
+}
+
+func TestSyncE2E_ExportHighlights(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.ReadwiseHighlights = true
+		cfg.ReflectGraphID = "graph-1"
+	})
+
+	lookupToken = func(account string) (string, error) { return account + "-token", nil }
+	defer func() { lookupToken = keychain.Password }()
+
+	var readwiseBodies, reflectBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/highlights/":
+			assert.Equal(t, "Token readwise-token", r.Header.Get("Authorization"))
+			readwiseBodies = append(readwiseBodies, string(body))
+			_, _ = w.Write([]byte(`[{"id": 7}]`))
+		case "/graphs/graph-1/notes":
+			assert.Equal(t, "Bearer reflect-token", r.Header.Get("Authorization"))
+			reflectBodies = append(reflectBodies, string(body))
+			_, _ = w.Write([]byte(`{"id": "note-1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	runSync := func() *SyncResult {
+		syncer := NewSyncer(env.cfg, env.store)
+		for _, exp := range syncer.highlighters {
+			switch exp := exp.(type) {
+			case *readwiseExporter:
+				exp.baseURL = server.URL
+			case *reflectExporter:
+				exp.baseURL = server.URL
+			}
+		}
+		result, err := syncer.Sync(SyncOptions{})
+		require.NoError(t, err)
+		return result
+	}
+
+	notes := "**Decisions**\n- Ship the beta in March\n- **Action Items**\n- Bob: Book a room"
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", notes),
+	}))
+	result := runSync()
+	require.Empty(t, result.Errors)
+
+	require.Len(t, readwiseBodies, 1)
+	var sent struct {
+		Highlights []struct {
+			Text  string `json:"text"`
+			Title string `json:"title"`
+			Note  string `json:"note"`
+		} `json:"highlights"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(readwiseBodies[0]), &sent))
+	require.Len(t, sent.Highlights, 2)
+	assert.Equal(t, "- Ship the beta in March", sent.Highlights[0].Text)
+	assert.Equal(t, "Roadmap (2025-01-28)", sent.Highlights[0].Title)
+	assert.Equal(t, "Decisions", sent.Highlights[0].Note)
+	assert.Equal(t, "Action Items", sent.Highlights[1].Note)
+
+	require.Len(t, reflectBodies, 1)
+	assert.Contains(t, reflectBodies[0], `"subject":"Roadmap (2025-01-28)"`)
+	assert.Contains(t, reflectBodies[0], `## Decisions\n- Ship the beta in March\n`)
+
+	// Edited notes are sent to Readwise again, which skips unchanged
+	// highlights; Reflect notes can't be updated so aren't sent twice
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", notes+"\n- Alice: Send the deck"),
+	}))
+	result = runSync()
+	require.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	assert.Len(t, readwiseBodies, 2)
+	assert.Contains(t, readwiseBodies[1], "Alice: Send the deck")
+	assert.Len(t, reflectBodies, 1)
+}
+
+func TestSyncE2E_FileIssues(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.IssueTracker = &config.IssueTrackerConfig{App: config.IssueAppLinear, Prefix: "FILE:", Project: "team-1"}
+	})
+
+	lookupToken = func(account string) (string, error) { return account + "-token", nil }
+	defer func() { lookupToken = keychain.Password }()
+
+	var titles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "linear-token", r.Header.Get("Authorization"))
+		var body struct {
+			Variables struct {
+				Input struct {
+					Title       string `json:"title"`
+					Description string `json:"description"`
+				} `json:"input"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		titles = append(titles, body.Variables.Input.Title)
+		assert.Contains(t, body.Variables.Input.Description, "From Roadmap\nlogseq://graph/")
+		id := fmt.Sprintf("ENG-%d", len(titles))
+		_, _ = fmt.Fprintf(w, `{"data": {"issueCreate": {"success": true, "issue": {"identifier": %q, "url": "https://linear.app/acme/issue/%s"}}}}`, id, id)
+	}))
+	defer server.Close()
+
+	runSync := func() *SyncResult {
+		syncer := NewSyncer(env.cfg, env.store)
+		syncer.issues.filer.(*linearFiler).baseURL = server.URL
+		result, err := syncer.Sync(SyncOptions{})
+		require.NoError(t, err)
+		return result
+	}
+	readPage := func() string {
+		content, err := os.ReadFile(filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Roadmap.md"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	notes := "**Action Items**\n- FILE: Fix the login page\n- Bob: file: Update the docs\n- Alice: Send the deck"
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", notes),
+	}))
+	result := runSync()
+	require.Empty(t, result.Errors)
+	assert.Equal(t, []string{"Fix the login page", "Update the docs"}, titles)
+	assert.Contains(t, readPage(), "- FILE: Fix the login page")
+
+	// Each action item records its own issue
+	filed, err := env.store.ListExportedTasks(TargetLinear, "doc1")
+	require.NoError(t, err)
+	assert.Len(t, filed, 2)
+	externalID, err := env.store.GetExportedTask(TargetLinear, "doc1", taskKey("FILE: Fix the login page"))
+	require.NoError(t, err)
+	assert.Equal(t, "ENG-1 https://linear.app/acme/issue/ENG-1", externalID)
+
+	// The next sync links the issues in place of the prefix
+	result = runSync()
+	require.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	page := readPage()
+	assert.Contains(t, page, "- [ENG-1](https://linear.app/acme/issue/ENG-1) Fix the login page")
+	assert.Contains(t, page, "- Bob: [ENG-2](https://linear.app/acme/issue/ENG-2) Update the docs")
+	assert.Contains(t, page, "- Alice: Send the deck")
+	assert.Len(t, titles, 2)
+
+	// After that the meeting is up to date
+	result = runSync()
+	require.Empty(t, result.Errors)
+	assert.Equal(t, 0, result.UpdatedMeetings)
+	assert.Len(t, titles, 2)
+}
+
+func TestSyncE2E_SlackPosts(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.DeriveTitleTag = true
+		cfg.SlackPosts = []config.SlackPost{
+			{Tags: []string{"standup"}, Channel: "C0123"},
+			{Tags: []string{"Standup"}, Channel: "C0456", Canvas: true},
+		}
+	})
+
+	lookupToken = func(account string) (string, error) { return account + "-token", nil }
+	defer func() { lookupToken = keychain.Password }()
+
+	var calls []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer slack-token", r.Header.Get("Authorization"))
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		calls = append(calls, r.URL.Path)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"ok": true, "ts": "1706.0001", "canvas_id": "F0789"}`))
+	}))
+	defer server.Close()
+
+	runSync := func() *SyncResult {
+		syncer := NewSyncer(env.cfg, env.store)
+		syncer.slack.baseURL = server.URL
+		result, err := syncer.Sync(SyncOptions{})
+		require.NoError(t, err)
+		return result
+	}
+
+	notes := "**Updates**\n- Shipped the **beta**"
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Standup", "test@example.com", notes),
+		makeDocument("doc2", "Roadmap", "test@example.com", notes),
+	}))
+	result := runSync()
+	require.Empty(t, result.Errors)
+
+	// Only the standup is posted, once as a message and once as a canvas
+	require.Equal(t, []string{"/chat.postMessage", "/conversations.canvases.create"}, calls)
+	assert.Equal(t, "C0123", bodies[0]["channel"])
+	assert.Equal(t, "*Standup (2025-01-28)*\n\n*Updates*\n• Shipped the *beta*\n", bodies[0]["text"])
+	assert.Equal(t, "C0456", bodies[1]["channel_id"])
+
+	// Edited notes update the message and canvas rather than posting again
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Standup", "test@example.com", notes+"\n- Fixed the login bug"),
+		makeDocument("doc2", "Roadmap", "test@example.com", notes),
+	}))
+	result = runSync()
+	require.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	require.Equal(t, []string{"/chat.postMessage", "/conversations.canvases.create", "/chat.update", "/canvases.edit"}, calls)
+	assert.Equal(t, "1706.0001", bodies[2]["ts"])
+	assert.Contains(t, bodies[2]["text"], "• Fixed the login bug")
+	assert.Equal(t, "F0789", bodies[3]["canvas_id"])
+}
+
+func TestSyncE2E_OutboxEvents(t *testing.T) {
+	outboxDir := filepath.Join(t.TempDir(), "outbox")
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.OutboxDir = outboxDir
+	})
+
+	readEvents := func() []OutboxEvent {
+		entries, err := os.ReadDir(outboxDir)
+		require.NoError(t, err)
+		var events []OutboxEvent
+		for _, e := range entries {
+			require.False(t, strings.HasPrefix(e.Name(), "."), "temp file left behind: %s", e.Name())
+			data, err := os.ReadFile(filepath.Join(outboxDir, e.Name()))
+			require.NoError(t, err)
+			var event OutboxEvent
+			require.NoError(t, json.Unmarshal(data, &event))
+			events = append(events, event)
+		}
+		return events
+	}
+
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", "- Ship the beta"),
+	}))
+	result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+
+	events := readEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, OutboxCreated, events[0].Event)
+	assert.Equal(t, "doc1", events[0].Meeting.ID)
+	assert.Equal(t, "Roadmap", events[0].Meeting.Title)
+	assert.Equal(t, filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Roadmap.md"), events[0].PagePath)
+
+	// An automation deletes what it has processed; edits add an updated event
+	require.NoError(t, os.RemoveAll(outboxDir))
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Roadmap", "test@example.com", "- Ship the beta in March"),
+	}))
+	result, err = NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.UpdatedMeetings)
+
+	events = readEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, OutboxUpdated, events[0].Event)
+	assert.Contains(t, events[0].Meeting.Notes, "Ship the beta in March")
+}
+
+func TestSyncE2E_MultiGraphRouting(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "pages"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "journals"), 0o755))
+	workStatePath := filepath.Join(t.TempDir(), "state-work.db")
+
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.UserEmail = ""
+		cfg.Graphs = []config.GraphConfig{{
+			Name:           "work",
+			LogseqBasePath: workDir,
+			StateDBPath:    workStatePath,
+			Domains:        []string{"acme.com"},
+			TitlePatterns:  []string{`\[work\]`},
+		}}
+	})
+
+	docs := []testDoc{
+		makeDocument("doc1", "Dentist", "test@example.com", "Cleaning"),
+		makeDocument("doc2", "Acme Planning", "test@acme.com", "Roadmap"),
+		makeDocument("doc3", "Standup [WORK]", "test@example.com", "Blockers"),
+	}
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	syncer := NewSyncer(env.cfg, env.store)
+	result, err := syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.NewMeetings)
+	assert.Empty(t, result.Errors)
+
+	// Each meeting's page and journal entry land in its graph
+	assert.FileExists(t, filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Dentist.md"))
+	assert.FileExists(t, filepath.Join(workDir, "pages", "meetings___2025-01-28___Acme Planning.md"))
+	assert.FileExists(t, filepath.Join(workDir, "pages", "meetings___2025-01-28___Standup [WORK].md"))
+	assert.NoFileExists(t, filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Acme Planning.md"))
+
+	journal, err := os.ReadFile(filepath.Join(workDir, "journals", "2025_01_28.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(journal), "Acme Planning")
+	assert.NotContains(t, string(journal), "Dentist")
+
+	// A second sync finds everything up to date in its graph's state
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, result.NewMeetings+result.UpdatedMeetings)
+	require.NoError(t, syncer.Close())
+
+	// Each graph tracks its own meetings
+	synced, err := env.store.GetSyncedDocument("doc2")
+	require.NoError(t, err)
+	assert.Nil(t, synced)
+
+	workStore, err := state.NewStore(workStatePath)
+	require.NoError(t, err)
+	defer func() { _ = workStore.Close() }()
+	synced, err = workStore.GetSyncedDocument("doc2")
+	require.NoError(t, err)
+	require.NotNil(t, synced)
+	assert.Equal(t, filepath.Join("pages", "meetings___2025-01-28___Acme Planning.md"), synced.LogseqPagePath)
+}
+
+func TestSyncE2E_JournalOnly(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.JournalOnly = true
+	})
+
+	journalPath := filepath.Join(env.logseqDir, "journals", "2025_01_28.md")
+	require.NoError(t, os.WriteFile(journalPath, []byte("- Morning thoughts\n"), 0o644))
+
+	docs := []testDoc{
+		makeDocument("doc1", "Planning", "test@example.com", "Roadmap draft"),
+		makeDocument("doc2", "Retro", "test@example.com", "Went well"),
+	}
+	docs[1].CreatedAt = docs[1].CreatedAt.Add(2 * time.Hour)
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	syncer := NewSyncer(env.cfg, env.store)
+	result, err := syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 2, result.NewMeetings)
+	assert.Equal(t, 2, result.NewJournals)
+
+	// Notes go into the journal and no pages are written
+	entries, err := os.ReadDir(filepath.Join(env.logseqDir, "pages"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	journal, err := os.ReadFile(journalPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(journal), "- Morning thoughts\n- Planning\n"))
+	assert.Contains(t, string(journal), "  granola-id:: doc1\n")
+	assert.Contains(t, string(journal), "Roadmap draft")
+	assert.Contains(t, string(journal), "Went well")
+
+	synced, err := env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	require.NotNil(t, synced)
+	assert.Equal(t, filepath.Join("journals", "2025_01_28.md"), synced.LogseqPagePath)
+
+	// Updated notes replace the meeting's block in place
+	docs[0].Notes = "Roadmap final"
+	docs[0].UpdatedAt = docs[0].UpdatedAt.Add(time.Hour)
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	assert.Zero(t, result.NewJournals)
+
+	journal, err = os.ReadFile(journalPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(journal), "Roadmap final")
+	assert.NotContains(t, string(journal), "Roadmap draft")
+	assert.Equal(t, 1, strings.Count(string(journal), "granola-id:: doc1"))
+	assert.Less(t, strings.Index(string(journal), "doc1"), strings.Index(string(journal), "doc2"))
+}
+
+func TestSyncE2E_ForceAndIDs(t *testing.T) {
+	env := newE2EEnv(t, nil)
+
+	docs := []testDoc{
+		makeDocument("doc1", "Planning", "test@example.com", "Roadmap"),
+		makeDocument("doc2", "Retro", "test@example.com", "Went well"),
+	}
+	writeCache(t, env.cachePath, makeCache(docs))
+
+	syncer := NewSyncer(env.cfg, env.store)
+	result, err := syncer.Sync(SyncOptions{IDs: []string{"doc2"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewMeetings)
+	assert.FileExists(t, filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Retro.md"))
+	assert.NoFileExists(t, filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Planning.md"))
+
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewMeetings)
+
+	// Up-to-date meetings are only rewritten when forced
+	pagePath := filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Planning.md")
+	require.NoError(t, os.WriteFile(pagePath, []byte("hand edited\n"), 0o644))
+
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, result.NewMeetings+result.UpdatedMeetings)
+
+	result, err = syncer.Sync(SyncOptions{Force: true, Backfill: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.UpdatedMeetings)
+	assert.Zero(t, result.NewJournals)
+
+	page, err := os.ReadFile(pagePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "granola-id:: doc1")
+}
+
+func TestSyncE2E_RebuildState(t *testing.T) {
+	env := newE2EEnv(t, nil)
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Planning", "test@example.com", "Roadmap"),
+		makeDocument("doc2", "Retro", "test@example.com", "Went well"),
+	}))
+
+	result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.NewMeetings)
+	require.NoError(t, env.store.Close())
+
+	// Lose the state, and edit one meeting's notes in Granola meanwhile
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Planning", "test@example.com", "Roadmap"),
+		makeDocument("doc2", "Retro", "test@example.com", "Went well, shipped"),
+	}))
+	store, err := state.NewStore(filepath.Join(env.dir, "state-new.db"))
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+	syncer := NewSyncer(env.cfg, store)
+
+	rebuilt, err := syncer.RebuildState(true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rebuilt.Current+rebuilt.Stale)
+	doc, err := store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	assert.Nil(t, doc)
+
+	rebuilt, err = syncer.RebuildState(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rebuilt.Current)
+	assert.Equal(t, 1, rebuilt.Stale)
+	assert.Empty(t, rebuilt.Errors)
+
+	// Only the edited meeting is rewritten, as an update without a new journal entry
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, result.NewMeetings)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	assert.Zero(t, result.NewJournals)
+
+	page, err := os.ReadFile(filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Retro.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "shipped")
+	journal, err := os.ReadFile(filepath.Join(env.logseqDir, "journals", "2025_01_28.md"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(journal), "Retro"))
+
+	rebuilt, err = syncer.RebuildState(false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rebuilt.Existing)
+}
+
+func TestSyncE2E_PageOptOut(t *testing.T) {
+	env := newE2EEnv(t, nil)
+	writeCache(t, env.cachePath, makeCache([]testDoc{makeDocument("doc1", "Planning", "test@example.com", "Roadmap")}))
+
+	syncer := NewSyncer(env.cfg, env.store)
+
+	_, err := syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	// The user takes the page over
+	pagePath := filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Planning.md")
+	edited := "granola-sync:: ignore\n\n- Planning, rewritten by hand\n"
+	require.NoError(t, os.WriteFile(pagePath, []byte(edited), 0o644))
+
+	doc := makeDocument("doc1", "Planning", "test@example.com", "Roadmap, revised")
+	doc.UpdatedAt = doc.UpdatedAt.Add(time.Hour)
+	writeCache(t, env.cachePath, makeCache([]testDoc{doc}))
+
+	result, err := syncer.Sync(SyncOptions{Force: true, Backfill: true})
+	require.NoError(t, err)
+	assert.Zero(t, result.UpdatedMeetings)
+	page, err := os.ReadFile(pagePath)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(page))
+
+	synced, err := env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	assert.True(t, synced.OptedOut)
+
+	statuses, err := syncer.List(nil)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, StatusSkipped, statuses[0].Status)
+	assert.Equal(t, "opted out on page", statuses[0].Reason)
+
+	// Removing the property opts the page back in
+	require.NoError(t, os.WriteFile(pagePath, []byte("- Planning\n"), 0o644))
+	result, err = syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedMeetings)
+	page, err = os.ReadFile(pagePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "revised")
+	synced, err = env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	assert.False(t, synced.OptedOut)
+}
+
+func TestSyncE2E_ControlPage(t *testing.T) {
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.ControlPage = true
+	})
+
+	writeCache(t, env.cachePath, makeCache([]testDoc{
+		makeDocument("doc1", "Planning", "test@example.com", "Roadmap"),
+	}))
+
+	syncer := NewSyncer(env.cfg, env.store)
+
+	_, err := syncer.Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, logseq.WriteControlPage(env.logseqDir, "- TODO pause\n- TODO resync doc1\n- TODO dance\n"))
+	n, err := syncer.RunControlCommands()
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	paused, err := PausedSince(env.store)
+	require.NoError(t, err)
+	assert.NotNil(t, paused)
+
+	content, err := logseq.ReadControlPage(env.logseqDir)
+	require.NoError(t, err)
+	assert.Contains(t, content, "- DONE pause\n")
+	assert.Regexp(t, `- DONE resync doc1\n\t- .* 0 new, 1 updated\n`, content)
+	assert.Contains(t, content, "- CANCELED dance\n\t- ")
+
+	// Finished commands don't run again
+	n, err = syncer.RunControlCommands()
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.NoError(t, logseq.WriteControlPage(env.logseqDir, content+"- TODO resume\n"))
+	_, err = syncer.RunControlCommands()
+	require.NoError(t, err)
+	paused, err = PausedSince(env.store)
+	require.NoError(t, err)
+	assert.Nil(t, paused)
+}
+
+func TestSyncE2E_WriteOrder(t *testing.T) {
+	for _, tt := range []struct {
+		order       string
+		pageWritten bool
+	}{
+		{"page_first", true},
+		{"journal_first", false},
+	} {
+		t.Run(tt.order, func(t *testing.T) {
+			env := newE2EEnv(t, func(cfg *config.Config) {
+				cfg.WriteOrder = tt.order
+			})
+			// A file in place of the journals directory makes journal writes fail
+			journalsDir := filepath.Join(env.logseqDir, "journals")
+			require.NoError(t, os.Remove(journalsDir))
+			require.NoError(t, os.WriteFile(journalsDir, nil, 0o644))
+
+			writeCache(t, env.cachePath,
+				makeCache([]testDoc{makeDocument("doc1", "Planning", "test@example.com", "Roadmap")}))
+
+			result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+			require.NoError(t, err)
+			assert.Len(t, result.Errors, 1)
+
+			_, err = os.Stat(filepath.Join(env.logseqDir, "pages", "meetings___2025-01-28___Planning.md"))
+			assert.Equal(t, tt.pageWritten, err == nil)
+		})
+	}
+}
+
+func TestSyncE2E_RecordsMeetingDetails(t *testing.T) {
+	env := newE2EEnv(t, nil)
+
+	doc := makeDocument("doc1", "Planning", "test@example.com", "Roadmap")
+	doc.Attendees = []string{"Alice Smith"}
+	writeCache(t, env.cachePath, makeCache([]testDoc{doc}))
+
+	_, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+
+	synced, err := env.store.GetSyncedDocument("doc1")
+	require.NoError(t, err)
+	require.NotNil(t, synced)
+	require.NotNil(t, synced.MeetingDate)
+	assert.True(t, doc.CreatedAt.Equal(*synced.MeetingDate))
+	assert.Contains(t, synced.Attendees, "Alice Smith")
+	assert.Equal(t, []string{"Granola Notes"}, synced.Tags)
+}
+
+func TestSyncE2E_UploadsToS3(t *testing.T) {
+	objects := make(map[string]string)
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		objects[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	env := newE2EEnv(t, func(cfg *config.Config) {
+		cfg.StateDBPath = ":memory:"
+		cfg.S3Bucket = "archive"
+		cfg.S3Region = "us-east-1"
+		cfg.S3Endpoint = server.URL
+		cfg.S3Prefix = "/granola/"
+		cfg.S3AccessKeyID = "AKID"
+		cfg.S3SecretAccessKey = "secret"
+		cfg.S3Sidecars = true
+	})
+
+	writeCache(t, env.cachePath, makeCache([]testDoc{makeDocument("doc1", "Project Sync", "test@example.com", "Notes")}))
+	result, err := NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewMeetings)
+
+	markdown := objects["/archive/granola/2025/01/2025-01-28 Project Sync (doc1).md"]
+	assert.True(t, strings.HasPrefix(markdown, "---\n"), "properties are YAML frontmatter")
+	assert.Contains(t, markdown, "Notes")
+	assert.Contains(t, objects["/archive/granola/2025/01/2025-01-28 Project Sync (doc1).json"], `"id": "doc1"`)
+
+	// A failed upload leaves the meeting to be retried
+	failing = true
+	doc := makeDocument("doc1", "Project Sync", "test@example.com", "Updated notes")
+	doc.UpdatedAt = doc.UpdatedAt.Add(time.Hour)
+	writeCache(t, env.cachePath, makeCache([]testDoc{doc}))
+	result, err = NewSyncer(env.cfg, env.store).Sync(SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "uploading to S3")
+}
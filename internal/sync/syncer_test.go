@@ -1,22 +1,30 @@
 package sync
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/philrhinehart/granola-sync/internal/clock"
 	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/export"
 	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/power"
 	"github.com/philrhinehart/granola-sync/internal/state"
 )
 
 type SyncerSuite struct {
 	suite.Suite
 	tempDir string
-	store   *state.Store
+	store   state.Store
 	cfg     *config.Config
 }
 
@@ -165,6 +173,8 @@ func (s *SyncerSuite) TestTruncate() {
 		{"exactly10!", 10, "exactly10!"},
 		{"this is longer than ten", 10, "this is lo..."},
 		{"", 10, ""},
+		{"naïve café ☕ notes", 12, "naïve café ☕..."},
+		{"☕☕☕", 3, "☕☕☕"},
 	}
 
 	for _, tt := range tests {
@@ -175,6 +185,56 @@ func (s *SyncerSuite) TestTruncate() {
 	}
 }
 
+func (s *SyncerSuite) TestPreview() {
+	content := "- Title\n\t- **Attendees**\n\t\t- [[@Bob]]\n\t- **Notes**\n"
+
+	s.Equal(content, preview(content, SyncOptions{FullPreview: true}))
+	s.Equal(content, preview(content, SyncOptions{PreviewLines: 10}))
+	s.Equal("- Title\n\t- **Attendees**\n... (2 more lines)", preview(content, SyncOptions{PreviewLines: 2}))
+	s.Equal(content, preview(content, SyncOptions{}), "short pages fit in the default preview")
+
+	long := strings.Repeat("é", 600)
+	s.Equal(strings.Repeat("é", 500)+"...", preview(long, SyncOptions{}))
+	s.Equal(long, preview(long, SyncOptions{FullPreview: true}))
+}
+
+func (s *SyncerSuite) TestUnifiedDiff() {
+	s.Equal("", unifiedDiff("a.md", "a.md", "same\n", "same\n"))
+
+	s.Equal("--- a.md\n+++ a.md\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n",
+		unifiedDiff("a.md", "a.md", "one\ntwo\nthree\n", "one\n2\nthree\n"))
+
+	s.Equal("--- a.md\n+++ b.md\n@@ -0,0 +1 @@\n+new\n",
+		unifiedDiff("a.md", "b.md", "", "new\n"), "renames show both paths")
+
+	// Changes far apart get separate hunks with three lines of context
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	before := strings.Join(lines, "\n") + "\n"
+	lines[1], lines[17] = "changed 2", "changed 18"
+	after := strings.Join(lines, "\n") + "\n"
+	s.Equal(`--- a.md
++++ a.md
+@@ -1,5 +1,5 @@
+ line 1
+-line 2
++changed 2
+ line 3
+ line 4
+ line 5
+@@ -15,6 +15,6 @@
+ line 15
+ line 16
+ line 17
+-line 18
++changed 18
+ line 19
+ line 20
+`, unifiedDiff("a.md", "a.md", before, after))
+}
+
 func (s *SyncerSuite) TestSyncWithEmptyCache() {
 	// Create empty cache file
 	cacheContent := `{"cache": "{\"state\":{\"documents\":{},\"documentPanels\":{}}}", "version": 3}`
@@ -182,7 +242,7 @@ func (s *SyncerSuite) TestSyncWithEmptyCache() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 
 	s.NoError(err)
 	s.NotNil(result)
@@ -203,7 +263,7 @@ func (s *SyncerSuite) TestSyncFilteringDeleted() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 
 	s.NoError(err)
 	s.Equal(0, result.NewMeetings) // Deleted doc should be skipped
@@ -221,7 +281,7 @@ func (s *SyncerSuite) TestSyncFilteringNonAttendee() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 
 	s.NoError(err)
 	s.Equal(0, result.NewMeetings) // Non-attendee doc should be skipped
@@ -239,10 +299,179 @@ func (s *SyncerSuite) TestSyncFilteringTooRecent() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 
 	s.NoError(err)
 	s.Equal(0, result.NewMeetings) // Too recent doc should be skipped
+
+	// Dry runs preview it, but checks skip it like a real sync
+	result, err = syncer.Sync(SyncOptions{DryRun: true})
+	s.NoError(err)
+	s.Equal(1, result.NewMeetings)
+	result, err = syncer.Sync(SyncOptions{DryRun: true, Check: true})
+	s.NoError(err)
+	s.Equal(0, result.NewMeetings)
+}
+
+func (s *SyncerSuite) TestSyncMinAgeFollowsClock() {
+	updated := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{\"doc\":{\"id\":\"doc\",\"title\":\"Standup\",\"created_at\":\"2024-01-15T09:30:00Z\",\"updated_at\":\"2024-01-15T10:00:00Z\",\"type\":\"meeting\"}},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	fake := clock.NewFake(updated.Add(30 * time.Second))
+	syncer := NewSyncer(s.cfg, s.store)
+	syncer.SetClock(fake)
+
+	result, err := syncer.Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Zero(result.NewMeetings, "updated 30s ago, within min_age_seconds")
+
+	fake.Advance(30 * time.Second)
+	result, err = syncer.Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(1, result.NewMeetings)
+
+	synced, err := s.store.GetSyncedDocument("doc")
+	s.Require().NoError(err)
+	s.Require().NotNil(synced)
+	s.True(synced.SyncedAt.Equal(fake.Now()))
+	s.Equal(time.Minute, synced.SyncLatency)
+}
+
+func (s *SyncerSuite) TestSyncSkipsLatencyFromBeforeWatchStart() {
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{\"doc\":{\"id\":\"doc\",\"title\":\"Standup\",\"created_at\":\"2024-01-15T09:30:00Z\",\"updated_at\":\"2024-01-15T10:00:00Z\",\"type\":\"meeting\"}},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	// Edited days before the daemon started, as on its initial sync
+	fake := clock.NewFake(time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC))
+	syncer := NewSyncer(s.cfg, s.store)
+	syncer.SetClock(fake)
+	syncer.SetWatchStart(fake.Now())
+
+	result, err := syncer.Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(1, result.NewMeetings)
+
+	synced, err := s.store.GetSyncedDocument("doc")
+	s.Require().NoError(err)
+	s.Require().NotNil(synced)
+	s.Zero(synced.SyncLatency)
+}
+
+func (s *SyncerSuite) TestSyncRunsHooks() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{\"doc\":{\"id\":\"doc\",\"title\":\"Planning\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"}},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	hookLog := filepath.Join(s.tempDir, "hooks.log")
+	s.cfg.PreSyncHook = `echo "pre $GRANOLA_SYNC_TRIGGER" >> ` + hookLog
+	s.cfg.PostSyncHook = `{ echo "post $GRANOLA_SYNC_STATUS $GRANOLA_SYNC_NEW_MEETINGS $GRANOLA_SYNC_NEW_JOURNALS"; echo "$GRANOLA_SYNC_CHANGED_PAGES"; } >> ` + hookLog
+
+	syncer := NewSyncer(s.cfg, s.store)
+	result, err := syncer.Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Require().Len(result.ChangedPages, 2, "the meeting page and its journal")
+
+	logged, err := os.ReadFile(hookLog)
+	s.Require().NoError(err)
+	s.Equal("pre watch\npost ok 1 1\n"+strings.Join(result.ChangedPages, "\n")+"\n", string(logged))
+
+	// A failing pre-sync hook stops the sync
+	s.cfg.PreSyncHook = "exit 3"
+	_, err = syncer.Sync(SyncOptions{})
+	s.ErrorContains(err, "pre-sync hook")
+
+	logged, err = os.ReadFile(hookLog)
+	s.Require().NoError(err)
+	s.True(strings.HasSuffix(string(logged), "post failed 0 0\n\n"))
+}
+
+func (s *SyncerSuite) TestMeetingFlashcards() {
+	doc := &granola.Document{
+		Title:     "Planning",
+		CreatedAt: time.Date(2025, 1, 28, 9, 0, 0, 0, time.Local),
+	}
+	bullets := []logseq.LabeledBullet{
+		{Label: "Decision", Text: "Ship the beta in March"},
+		{Label: "Definition", Text: "ARR: annual recurring revenue"},
+		{Label: "Decision", Text: "Hire a designer"},
+		{Label: "Definition", Text: "Undefined"},
+	}
+
+	cards := meetingFlashcards(doc, bullets, []string{"meeting"})
+	s.Equal([]export.Flashcard{
+		{
+			Front: "ARR",
+			Back:  "annual recurring revenue\n\nPlanning (2025-01-28)",
+			Tags:  []string{"meeting", "granola-sync", "definition"},
+		},
+		{
+			Front: "Planning (2025-01-28): Decision?",
+			Back:  "• Ship the beta in March\n• Hire a designer",
+			Tags:  []string{"meeting", "granola-sync", "decision"},
+		},
+		{
+			Front: "Planning (2025-01-28): Definition?",
+			Back:  "• Undefined",
+			Tags:  []string{"meeting", "granola-sync", "definition"},
+		},
+	}, cards)
+}
+
+func (s *SyncerSuite) TestRetriesPartiallyWrittenCache() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	cachePath := filepath.Join(s.cfg.GranolaDir, "cache-v4.json")
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{\"doc\":{\"id\":\"doc\",\"title\":\"Planning\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"}},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	partial := cacheContent[:len(cacheContent)/2]
+	s.Require().NoError(os.WriteFile(cachePath, []byte(partial), 0o644))
+
+	// Granola finishes writing during the second wait
+	var waits []time.Duration
+	fake := &sleepRecorder{Fake: clock.NewFake(time.Now()), onSleep: func(d time.Duration) {
+		waits = append(waits, d)
+		if len(waits) == 2 {
+			s.Require().NoError(os.WriteFile(cachePath, []byte(cacheContent), 0o644))
+		}
+	}}
+	syncer := NewSyncer(s.cfg, s.store)
+	syncer.SetClock(fake)
+	result, err := syncer.Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(1, result.NewMeetings)
+	s.Equal(parseRetryDelays[:2], waits)
+
+	// A cache that stays broken fails once the retries run out
+	waits = nil
+	fake.onSleep = func(d time.Duration) { waits = append(waits, d) }
+	s.Require().NoError(os.WriteFile(cachePath, []byte(partial), 0o644))
+	syncer = NewSyncer(s.cfg, s.store)
+	syncer.SetClock(fake)
+	_, err = syncer.Sync(SyncOptions{Backfill: true})
+	s.ErrorContains(err, "parsing cache")
+	s.Equal(parseRetryDelays, waits)
+}
+
+// sleepRecorder is a fake clock that reports each sleep
+type sleepRecorder struct {
+	*clock.Fake
+	onSleep func(d time.Duration)
+}
+
+func (c *sleepRecorder) Sleep(d time.Duration) {
+	c.onSleep(d)
+	c.Fake.Sleep(d)
 }
 
 func (s *SyncerSuite) TestSyncProcessesValidDoc() {
@@ -257,7 +486,7 @@ func (s *SyncerSuite) TestSyncProcessesValidDoc() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 
 	s.NoError(err)
 	s.Equal(1, result.NewMeetings)
@@ -278,7 +507,7 @@ func (s *SyncerSuite) TestSyncDryRun() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, true) // dry run = true
+	result, err := syncer.Sync(SyncOptions{DryRun: true}) // dry run = true
 
 	s.NoError(err)
 	s.Equal(1, result.NewMeetings)
@@ -302,12 +531,173 @@ func (s *SyncerSuite) TestSyncWithSinceFilter() {
 	s.Require().NoError(err)
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(&sinceTime, false)
+	result, err := syncer.Sync(SyncOptions{Since: &sinceTime})
 
 	s.NoError(err)
 	s.Equal(1, result.NewMeetings) // Only the recent one should be processed
 }
 
+func (s *SyncerSuite) TestSyncWithUntilFilter() {
+	oldTime := time.Now().Add(-48 * time.Hour)
+	recentTime := time.Now().Add(-2 * time.Hour)
+	untilTime := time.Now().Add(-24 * time.Hour)
+
+	// Two documents: one before until, one after
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{\"old-doc\":{\"id\":\"old-doc\",\"title\":\"Old Meeting\",\"created_at\":\"` + oldTime.Format(time.RFC3339) + `\",\"updated_at\":\"` + oldTime.Format(time.RFC3339) + `\",\"type\":\"meeting\"},\"recent-doc\":{\"id\":\"recent-doc\",\"title\":\"Recent Meeting\",\"created_at\":\"` + recentTime.Format(time.RFC3339) + `\",\"updated_at\":\"` + recentTime.Format(time.RFC3339) + `\",\"type\":\"meeting\"}},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	err := os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644)
+	s.Require().NoError(err)
+
+	syncer := NewSyncer(s.cfg, s.store)
+	result, err := syncer.Sync(SyncOptions{Until: &untilTime})
+
+	s.NoError(err)
+	s.Equal(1, result.NewMeetings) // Only the old one should be processed
+
+	synced, err := s.store.GetSyncedDocument("old-doc")
+	s.NoError(err)
+	s.NotNil(synced)
+}
+
+func (s *SyncerSuite) TestSkipReasonTitlePatterns() {
+	s.cfg.IncludeTitlePatterns = []string{"sync", "review"}
+	s.cfg.ExcludeTitlePatterns = []string{"^focus time$", "lunch"}
+	syncer := NewSyncer(s.cfg, s.store)
+
+	tests := []struct {
+		title  string
+		reason string
+	}{
+		{"Weekly Sync", ""},
+		{"Design Review", ""},
+		{"Focus Time", `title matches exclude pattern "^focus time$"`},
+		{"Team Lunch sync", `title matches exclude pattern "lunch"`},
+		{"1:1 with Bob", "title matches no include pattern"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.title, func() {
+			doc := &granola.Document{ID: "doc", Title: tt.title}
+			s.Equal(tt.reason, syncer.skipReason(doc, SyncOptions{}, 0))
+		})
+	}
+
+	// Without include patterns every title not excluded is synced
+	s.cfg.IncludeTitlePatterns = nil
+	syncer = NewSyncer(s.cfg, s.store)
+	s.Empty(syncer.skipReason(&granola.Document{ID: "doc", Title: "1:1 with Bob"}, SyncOptions{}, 0))
+}
+
+func (s *SyncerSuite) TestSkipReasonDomains() {
+	s.cfg.UserEmail = ""
+	event := func(attendees ...granola.Attendee) *granola.Document {
+		attendees = append(attendees, granola.Attendee{Email: "me@gmail.com", Self: true})
+		return &granola.Document{ID: "doc", GoogleCalendarEvent: &granola.GoogleCalendarEvent{Attendees: attendees}}
+	}
+	internal := event(granola.Attendee{Email: "boss@eng.mycompany.com", Organizer: true})
+	external := event(granola.Attendee{Email: "vendor@other.com", Organizer: true}, granola.Attendee{Email: "pm@MyCompany.com"})
+	personal := event(granola.Attendee{Email: "friend@gmail.com", Organizer: true})
+
+	tests := []struct {
+		name     string
+		filterBy string
+		doc      *granola.Document
+		reason   string
+	}{
+		{"attendee_organizer_matches", "attendee", internal, ""},
+		{"attendee_any_matches", "attendee", external, ""},
+		{"attendee_none_match", "attendee", personal, "no attendee from an included domain"},
+		{"organizer_matches", "organizer", internal, ""},
+		{"organizer_external", "organizer", external, "organizer not from an included domain"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.cfg.IncludeDomains = []string{"@mycompany.com"}
+			s.cfg.DomainFilterBy = tt.filterBy
+			syncer := NewSyncer(s.cfg, s.store)
+			s.Equal(tt.reason, syncer.skipReason(tt.doc, SyncOptions{}, 0))
+		})
+	}
+}
+
+func (s *SyncerSuite) TestSkipReasonDeclined() {
+	s.cfg.UserEmail = "me@example.com"
+	event := func(status string) *granola.Document {
+		return &granola.Document{ID: "doc", GoogleCalendarEvent: &granola.GoogleCalendarEvent{Attendees: []granola.Attendee{
+			{Email: "me@example.com", ResponseStatus: status},
+			{Email: "boss@example.com", Organizer: true, ResponseStatus: "accepted"},
+		}}}
+	}
+
+	tests := []struct {
+		name     string
+		skip     bool
+		statuses []string
+		doc      *granola.Document
+		reason   string
+	}{
+		{"disabled", false, []string{"declined"}, event("declined"), ""},
+		{"declined", true, []string{"declined"}, event("declined"), "response declined"},
+		{"accepted", true, []string{"declined"}, event("accepted"), ""},
+		{"needs_action_not_configured", true, []string{"declined"}, event("needsAction"), ""},
+		{"needs_action_configured", true, []string{"declined", "needsAction"}, event("needsAction"), "response needsAction"},
+		{"no_calendar_event", true, []string{"declined"}, &granola.Document{ID: "doc"}, ""},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.cfg.SkipDeclined = tt.skip
+			s.cfg.SkipResponseStatuses = tt.statuses
+			syncer := NewSyncer(s.cfg, s.store)
+			s.Equal(tt.reason, syncer.skipReason(tt.doc, SyncOptions{}, 0))
+		})
+	}
+}
+
+func (s *SyncerSuite) TestSkipReasonCalendarBlocks() {
+	focus := &granola.Document{ID: "focus", GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+		EventType: "focusTime",
+		Attendees: []granola.Attendee{{Email: "test@example.com", Self: true}},
+	}}
+
+	for mode, reason := range map[string]string{"sync": "", "tag": "", "skip": "calendar block"} {
+		s.Run(mode, func() {
+			s.cfg.CalendarBlocks = mode
+			syncer := NewSyncer(s.cfg, s.store)
+			s.Equal(reason, syncer.skipReason(focus, SyncOptions{}, 0))
+		})
+	}
+}
+
+func (s *SyncerSuite) TestCalendarBlockTaggedWithoutJournalEntry() {
+	s.cfg.CalendarBlocks = "tag"
+	syncer := NewSyncer(s.cfg, s.store)
+	doc := &granola.Document{
+		ID:        "ooo",
+		Title:     "Out of office",
+		CreatedAt: time.Date(2025, 1, 28, 9, 0, 0, 0, time.UTC),
+		GoogleCalendarEvent: &granola.GoogleCalendarEvent{
+			EventType: "outOfOffice",
+			Start:     &granola.EventTime{Date: "2025-01-28"},
+			Attendees: []granola.Attendee{{Email: "test@example.com", Self: true}},
+		},
+	}
+
+	result := &SyncResult{}
+	pagePath, err := syncer.writeMeetingPage(syncer.graphs[0], doc, nil, result)
+	s.Require().NoError(err)
+	s.Equal(1, result.NewMeetings)
+	s.Zero(result.NewJournals)
+
+	page, err := os.ReadFile(pagePath)
+	s.Require().NoError(err)
+	s.Contains(string(page), "[[calendar-block]]")
+	s.NoFileExists(filepath.Join(s.cfg.LogseqBasePath, "journals", "2025_01_28.md"))
+}
+
 func (s *SyncerSuite) TestSyncSkipsAlreadySynced() {
 	// Use a fixed time string to avoid nanosecond precision issues
 	oldTimeStr := "2024-01-15T10:00:00Z"
@@ -332,9 +722,547 @@ func (s *SyncerSuite) TestSyncSkipsAlreadySynced() {
 	s.Require().NoError(s.store.MarkSynced(syncedDoc))
 
 	syncer := NewSyncer(s.cfg, s.store)
-	result, err := syncer.Sync(nil, false)
+	result, err := syncer.Sync(SyncOptions{})
 
 	s.NoError(err)
 	s.Equal(0, result.NewMeetings)
 	s.Equal(0, result.UpdatedMeetings)
 }
+
+func (s *SyncerSuite) TestList() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	recentTime := time.Now().Format(time.RFC3339)
+
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"synced-doc\":{\"id\":\"synced-doc\",\"title\":\"Synced\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"},` +
+		`\"new-doc\":{\"id\":\"new-doc\",\"title\":\"New\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"},` +
+		`\"recent-doc\":{\"id\":\"recent-doc\",\"title\":\"Recent\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + recentTime + `\",\"type\":\"meeting\"},` +
+		`\"other-doc\":{\"id\":\"other-doc\",\"title\":\"Other\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"google_calendar_event\":{\"id\":\"cal-1\",\"attendees\":[{\"email\":\"other@example.com\"}]}}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	updatedAt, _ := time.Parse(time.RFC3339, oldTime)
+	s.Require().NoError(s.store.MarkSynced(&state.SyncedDocument{
+		ID:               "synced-doc",
+		Title:            "Synced",
+		SyncedAt:         time.Now(),
+		GranolaUpdatedAt: &updatedAt,
+		LogseqPagePath:   "/pages/synced.md",
+	}))
+
+	statuses, err := NewSyncer(s.cfg, s.store).List(nil)
+	s.Require().NoError(err)
+	s.Len(statuses, 4)
+
+	byID := make(map[string]MeetingStatus)
+	for _, st := range statuses {
+		byID[st.ID] = st
+	}
+
+	s.Equal(StatusSynced, byID["synced-doc"].Status)
+	s.Equal("/pages/synced.md", byID["synced-doc"].PagePath)
+	s.Equal("logseq://graph/"+filepath.Base(s.cfg.LogseqBasePath)+"?page=synced", byID["synced-doc"].PageURL)
+	s.Empty(byID["new-doc"].PageURL)
+	s.Equal(StatusUnsynced, byID["new-doc"].Status)
+	s.Equal(StatusSkipped, byID["recent-doc"].Status)
+	s.Contains(byID["recent-doc"].Reason, "too recent")
+	s.Equal(StatusSkipped, byID["other-doc"].Status)
+	s.Equal("not an attendee", byID["other-doc"].Reason)
+}
+
+func (s *SyncerSuite) TestQueue() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	recentTime := time.Now().Format(time.RFC3339)
+
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"synced-doc\":{\"id\":\"synced-doc\",\"title\":\"Synced\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"},` +
+		`\"changed-doc\":{\"id\":\"changed-doc\",\"title\":\"Changed\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"},` +
+		`\"recent-doc\":{\"id\":\"recent-doc\",\"title\":\"Recent\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + recentTime + `\",\"type\":\"meeting\"},` +
+		`\"other-doc\":{\"id\":\"other-doc\",\"title\":\"Other\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"google_calendar_event\":{\"id\":\"cal-1\",\"attendees\":[{\"email\":\"other@example.com\"}]}}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	updatedAt, _ := time.Parse(time.RFC3339, oldTime)
+	earlier := updatedAt.Add(-time.Hour)
+	s.Require().NoError(s.store.MarkSynced(&state.SyncedDocument{ID: "synced-doc", SyncedAt: time.Now(), GranolaUpdatedAt: &updatedAt}))
+	s.Require().NoError(s.store.MarkSynced(&state.SyncedDocument{ID: "changed-doc", SyncedAt: time.Now(), GranolaUpdatedAt: &earlier}))
+
+	queue, err := NewSyncer(s.cfg, s.store).Queue()
+	s.Require().NoError(err)
+
+	byID := make(map[string]QueuedMeeting)
+	for _, m := range queue {
+		byID[m.ID] = m
+	}
+	s.Len(byID, 2, "synced and non-attendee meetings aren't queued")
+
+	s.Equal("changed since last sync", byID["changed-doc"].Reason)
+	s.False(byID["changed-doc"].ReadyAt.After(time.Now()))
+	s.Equal("not synced yet", byID["recent-doc"].Reason)
+	s.True(byID["recent-doc"].ReadyAt.After(time.Now()), "waits for min_age_seconds")
+}
+
+func (s *SyncerSuite) TestEstimate() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-a\":{\"id\":\"doc-a\",\"title\":\"Planning\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Roadmap\"},` +
+		`\"doc-b\":{\"id\":\"doc-b\",\"title\":\"Standup\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Blockers\"},` +
+		`\"other-doc\":{\"id\":\"other-doc\",\"title\":\"Other\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"google_calendar_event\":{\"id\":\"cal-1\",\"attendees\":[{\"email\":\"other@example.com\"}]}}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	est, err := NewSyncer(s.cfg, s.store).Estimate(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(2, est.NewPages)
+	s.Equal(0, est.UpdatedPages)
+	s.Equal(2, est.JournalEntries)
+	s.Equal(1, est.NewJournals, "both meetings share a day")
+	s.Positive(est.Bytes)
+	s.Zero(est.Duration, "no sync history yet")
+
+	// Nothing was written
+	entries, err := os.ReadDir(filepath.Join(s.cfg.LogseqBasePath, "pages"))
+	s.NoError(err)
+	s.Empty(entries)
+
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+
+	est, err = NewSyncer(s.cfg, s.store).Estimate(SyncOptions{})
+	s.Require().NoError(err)
+	s.Zero(est.Meetings(), "everything is up to date")
+
+	s.Require().NoError(s.store.RecordSyncRun(&state.SyncRun{StartedAt: time.Now(), Duration: 10 * time.Second, NewMeetings: 5}))
+	est, err = NewSyncer(s.cfg, s.store).Estimate(SyncOptions{Force: true})
+	s.Require().NoError(err)
+	s.Equal(2, est.UpdatedPages)
+	s.Zero(est.JournalEntries, "updated meetings already have journal entries")
+	s.Greater(est.Duration, 2*time.Second)
+}
+
+func (s *SyncerSuite) TestRerenderAfterFormatChange() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+
+	var docs []string
+	for _, id := range []string{"doc-a", "doc-b", "doc-c"} {
+		docs = append(docs, `\"`+id+`\":{\"id\":\"`+id+`\",\"title\":\"Meeting `+id+`\",\"created_at\":\"`+oldTime+`\",\"updated_at\":\"`+oldTime+`\",\"type\":\"meeting\",\"notes_plain\":\"Notes\"}`)
+	}
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` + strings.Join(docs, ",") + `},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	result, err := NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(3, result.NewMeetings)
+
+	// Unchanged format: nothing to re-render
+	result, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Zero(result.UpdatedMeetings)
+
+	s.cfg.PageProperties = logseq.PropertiesFrontmatter
+	s.cfg.RerenderBatchSize = 2
+	frontmatterPages := func() int {
+		count := 0
+		for _, id := range []string{"doc-a", "doc-b", "doc-c"} {
+			synced, err := s.store.GetSyncedDocument(id)
+			s.Require().NoError(err)
+			content, err := os.ReadFile(filepath.Join(s.cfg.LogseqBasePath, synced.LogseqPagePath))
+			s.Require().NoError(err)
+			if strings.HasPrefix(string(content), "---\n") {
+				count++
+			}
+		}
+		return count
+	}
+
+	// Pages converge a batch per sync
+	result, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(2, result.UpdatedMeetings)
+	s.Equal(2, frontmatterPages())
+
+	result, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(1, result.UpdatedMeetings)
+	s.Equal(3, frontmatterPages())
+
+	result, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Zero(result.UpdatedMeetings)
+	before, err := s.store.GetValue(rerenderBeforeKey)
+	s.NoError(err)
+	s.Empty(before, "re-render finished")
+}
+
+func (s *SyncerSuite) TestDeferOnBattery() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-1\":{\"id\":\"doc-1\",\"title\":\"Planning\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Notes\"}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	status := power.Status{OnBattery: true}
+	readPower = func() (power.Status, error) { return status, nil }
+	defer func() { readPower = power.Read }()
+
+	// On battery alone only defers with "battery"
+	s.cfg.DeferOnBattery = "low_power"
+	result, err := NewSyncer(s.cfg, s.store).Sync(SyncOptions{Backfill: true})
+	s.Require().NoError(err)
+	s.Equal(1, result.NewMeetings)
+
+	s.cfg.DeferOnBattery = "battery"
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{Backfill: true, Force: true})
+	s.ErrorIs(err, ErrDeferredOnBattery)
+	_, err = NewSyncer(s.cfg, s.store).SyncChunked(context.Background(), SyncOptions{Backfill: true}, 7, nil)
+	s.ErrorIs(err, ErrDeferredOnBattery)
+
+	// Re-renders wait, but the format change isn't forgotten
+	s.cfg.PageProperties = logseq.PropertiesFrontmatter
+	s.cfg.RerenderBatchSize = 5
+	result, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Zero(result.UpdatedMeetings)
+
+	status = power.Status{}
+	result, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(1, result.UpdatedMeetings)
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{Backfill: true})
+	s.NoError(err)
+
+	// Low Power Mode defers with either setting
+	status = power.Status{LowPowerMode: true}
+	s.cfg.DeferOnBattery = "low_power"
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{Backfill: true})
+	s.ErrorIs(err, ErrDeferredOnBattery)
+}
+
+func (s *SyncerSuite) TestSkipsUnchangedCache() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	cachePath := filepath.Join(s.cfg.GranolaDir, "cache-v4.json")
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-1\":{\"id\":\"doc-1\",\"title\":\"Planning\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(cachePath, []byte(cacheContent), 0o644))
+	info, err := os.Stat(cachePath)
+	s.Require().NoError(err)
+
+	result, err := NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Equal(1, result.NewMeetings)
+
+	// Garbage with the same size and modification time isn't even read
+	s.Require().NoError(os.WriteFile(cachePath, []byte(strings.Repeat("x", len(cacheContent))), 0o644))
+	s.Require().NoError(os.Chtimes(cachePath, info.ModTime(), info.ModTime()))
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.NoError(err)
+
+	// Nor is the same content rewritten
+	s.Require().NoError(os.WriteFile(cachePath, []byte(cacheContent), 0o644))
+	s.Require().NoError(os.Chtimes(cachePath, info.ModTime().Add(time.Minute), info.ModTime().Add(time.Minute)))
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.NoError(err)
+	s.Require().NoError(os.WriteFile(cachePath, []byte(strings.Repeat("x", len(cacheContent))), 0o644))
+	s.Require().NoError(os.Chtimes(cachePath, info.ModTime().Add(time.Minute), info.ModTime().Add(time.Minute)))
+	_, err = NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.NoError(err, "the rewrite's modification time was remembered")
+
+	// Other syncs always parse, failing without waiting out the retries
+	parsing := func(cfg *config.Config) *Syncer {
+		syncer := NewSyncer(cfg, s.store)
+		syncer.SetClock(clock.NewFake(time.Now()))
+		return syncer
+	}
+	_, err = parsing(s.cfg).Sync(SyncOptions{Backfill: true})
+	s.Error(err)
+
+	// As does any sync after a config change or state reset
+	cfg := *s.cfg
+	cfg.DebounceSeconds++
+	_, err = parsing(&cfg).Sync(SyncOptions{})
+	s.Error(err)
+	s.Require().NoError(ForgetCacheFingerprint(s.store))
+	_, err = parsing(s.cfg).Sync(SyncOptions{})
+	s.Error(err)
+}
+
+func (s *SyncerSuite) TestParsesUnchangedCacheWhileMeetingsWait() {
+	recentTime := time.Now().Format(time.RFC3339)
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-1\":{\"id\":\"doc-1\",\"title\":\"Planning\",\"created_at\":\"` + recentTime + `\",\"updated_at\":\"` + recentTime + `\",\"type\":\"meeting\"}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	result, err := NewSyncer(s.cfg, s.store).Sync(SyncOptions{})
+	s.Require().NoError(err)
+	s.Zero(result.NewMeetings, "too recent")
+
+	// The meeting becomes ready without the cache changing
+	fingerprint, err := s.store.GetValue(cacheFingerprintKey)
+	s.NoError(err)
+	s.Empty(fingerprint)
+}
+
+// fakeNotifier records notification messages
+type fakeNotifier struct {
+	messages []string
+}
+
+func (n *fakeNotifier) Send(ctx context.Context, title, message string) error {
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func (s *SyncerSuite) TestNotifyRun() {
+	s.cfg.NotifyAfterFailures = 2
+	syncer := NewSyncer(s.cfg, s.store)
+	fake := &fakeNotifier{}
+	syncer.notifier = fake
+
+	syncer.notifyRun(&SyncResult{NewMeetings: 2}, SyncOptions{})
+	s.Equal([]string{"Synced 2 new meetings to Logseq"}, fake.messages)
+
+	syncer.notifyRun(&SyncResult{NewMeetings: 5}, SyncOptions{Backfill: true})
+	s.Len(fake.messages, 1, "backfills don't notify of new meetings")
+
+	// Notifies once when the failure streak reaches the threshold
+	for i, run := range []state.SyncRun{{}, {Failed: true}, {Errors: 1}, {Failed: true}} {
+		run.StartedAt = time.Now().Add(time.Duration(i) * time.Minute)
+		s.Require().NoError(s.store.RecordSyncRun(&run))
+		syncer.notifyRun(nil, SyncOptions{})
+	}
+	s.Equal([]string{
+		"Synced 2 new meetings to Logseq",
+		"The last 2 syncs failed. Run granola-sync logs for details.",
+	}, fake.messages)
+}
+
+func (s *SyncerSuite) TestBreaker() {
+	b := newBreaker(2)
+	s.True(b.allow(TargetPersonPages))
+
+	b.record(TargetPersonPages, errors.New("disk full"))
+	s.True(b.allow(TargetPersonPages), "one failure is below the threshold")
+
+	b.record(TargetPersonPages, nil)
+	b.record(TargetPersonPages, errors.New("disk full"))
+	s.True(b.allow(TargetPersonPages), "a success resets the failure count")
+
+	b.record(TargetPersonPages, errors.New("disk full"))
+	s.False(b.allow(TargetPersonPages))
+	s.True(b.allow(TargetS3), "other targets are unaffected")
+
+	states := b.snapshot()
+	s.Require().Len(states, 1)
+	s.True(states[0].Open)
+	s.Equal("disk full", states[0].LastError)
+
+	never := newBreaker(0)
+	for range 5 {
+		never.record(TargetPersonPages, errors.New("disk full"))
+	}
+	s.True(never.allow(TargetPersonPages), "a zero threshold never opens")
+}
+
+func (s *SyncerSuite) TestTaskManagerExporters() {
+	targets := func() []string {
+		var names []string
+		for _, exp := range NewSyncer(s.cfg, s.store).exporters {
+			names = append(names, exp.target())
+		}
+		return names
+	}
+	s.Empty(targets())
+
+	s.cfg.TodoistTasks = true
+	s.cfg.TaskManager = &config.TaskManagerConfig{App: config.TaskAppThings, List: "Work"}
+	s.Equal([]string{TargetTodoist, TargetThings}, targets())
+
+	s.cfg.TaskManager.App = config.TaskAppOmniFocus
+	s.Equal([]string{TargetTodoist, TargetOmniFocus}, targets())
+
+	s.cfg.TaskManager.App = config.TaskAppReminders
+	s.Equal([]string{TargetTodoist, TargetReminders}, targets())
+}
+
+func (s *SyncerSuite) TestLatencyReport() {
+	now := time.Now()
+	for i, minutes := range []int{1, 2, 3, 4, 20} {
+		s.Require().NoError(s.store.MarkSynced(&state.SyncedDocument{
+			ID:          fmt.Sprintf("doc-%d", i),
+			Title:       "Meeting",
+			SyncedAt:    now,
+			SyncLatency: time.Duration(minutes) * time.Minute,
+		}))
+	}
+
+	stats, err := LatencyReport(s.store, now.Add(-time.Hour))
+	s.Require().NoError(err)
+	s.Equal(5, stats.Count)
+	s.Equal(3*time.Minute, stats.P50)
+	s.Equal(20*time.Minute, stats.P95)
+	s.Equal(20*time.Minute, stats.Max)
+
+	empty, err := LatencyReport(s.store, now.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Equal(0, empty.Count)
+}
+
+func (s *SyncerSuite) TestRunReport() {
+	syncer := NewSyncer(s.cfg, s.store)
+
+	// No cache file: the run fails outright
+	_, err := syncer.Sync(SyncOptions{})
+	s.Error(err)
+
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{\"valid-doc\":{\"id\":\"valid-doc\",\"title\":\"Valid Meeting\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\"}},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+	_, err = syncer.Sync(SyncOptions{})
+	s.Require().NoError(err)
+
+	// Dry runs aren't recorded
+	_, err = syncer.Sync(SyncOptions{DryRun: true})
+	s.Require().NoError(err)
+
+	stats, err := RunReport(s.store, 10)
+	s.Require().NoError(err)
+	s.Equal(2, stats.Runs)
+	s.Equal(1, stats.FailedRuns)
+	s.Equal(1, stats.PagesWritten())
+	s.Equal(1, stats.NewJournals)
+	s.Require().NotNil(stats.LastRun)
+	s.Equal(1, stats.LastRun.NewMeetings)
+	s.Equal(state.TriggerWatch, stats.LastRun.Trigger)
+	s.Equal(map[string]int{state.TriggerWatch: 2}, stats.ByTrigger)
+
+	_, err = syncer.Sync(SyncOptions{Backfill: true})
+	s.Require().NoError(err)
+	stats, err = RunReport(s.store, 10)
+	s.Require().NoError(err)
+	s.Equal(state.TriggerBackfill, stats.LastRun.Trigger)
+}
+
+func (s *SyncerSuite) TestShow() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-1\":{\"id\":\"doc-1\",\"title\":\"Roadmap\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Ship it\"},` +
+		`\"other-doc\":{\"id\":\"other-doc\",\"title\":\"Other\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"google_calendar_event\":{\"id\":\"cal-1\",\"attendees\":[{\"email\":\"other@example.com\"}]}}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	syncer := NewSyncer(s.cfg, s.store)
+
+	detail, err := syncer.Show("doc-1")
+	s.Require().NoError(err)
+	s.Equal("Roadmap", detail.Doc.Title)
+	s.Equal("plain text", detail.NotesSource)
+	s.True(detail.IsAttendee)
+	s.Empty(detail.SkipReason)
+	s.Nil(detail.Synced)
+	s.Contains(detail.PagePath, "Roadmap.md")
+	s.Contains(detail.Content, "granola-id:: doc-1")
+	s.Contains(detail.Content, "Ship it")
+
+	detail, err = syncer.Show("other-doc")
+	s.Require().NoError(err)
+	s.False(detail.IsAttendee)
+	s.Equal("not an attendee", detail.SkipReason)
+	s.Equal("none", detail.NotesSource)
+
+	_, err = syncer.Show("missing")
+	s.ErrorContains(err, "not found")
+}
+
+func (s *SyncerSuite) TestRenderMeetings() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	newTime := time.Now().Format(time.RFC3339)
+
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-1\":{\"id\":\"doc-1\",\"title\":\"Roadmap\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Ship it\"},` +
+		`\"doc-2\":{\"id\":\"doc-2\",\"title\":\"Standup\",\"created_at\":\"` + newTime + `\",\"updated_at\":\"` + newTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Just finished\"},` +
+		`\"other-doc\":{\"id\":\"other-doc\",\"title\":\"Other\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"google_calendar_event\":{\"id\":\"cal-1\",\"attendees\":[{\"email\":\"other@example.com\"}]}}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	files, err := NewSyncer(s.cfg, s.store).RenderMeetings(SyncOptions{})
+	s.Require().NoError(err)
+
+	// Recent meetings are exported too, but not other people's meetings
+	s.Require().Len(files, 2)
+	var names []string
+	for _, f := range files {
+		s.True(strings.HasPrefix(f.Name, "pages/meetings___"), f.Name)
+		names = append(names, f.Name)
+	}
+	s.Contains(names[0]+names[1], "Roadmap.md")
+	s.Contains(names[0]+names[1], "Standup.md")
+
+	// Nothing is written to the graph or state
+	entries, err := os.ReadDir(filepath.Join(s.cfg.LogseqBasePath, "pages"))
+	s.NoError(err)
+	s.Empty(entries)
+	synced, err := s.store.GetSyncedDocument("doc-1")
+	s.NoError(err)
+	s.Nil(synced)
+}
+
+func (s *SyncerSuite) TestExportMeetings() {
+	oldTime := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+
+	cacheContent := `{
+		"cache": "{\"state\":{\"documents\":{` +
+		`\"doc-1\":{\"id\":\"doc-1\",\"title\":\"Roadmap\",\"created_at\":\"` + oldTime + `\",\"updated_at\":\"` + oldTime + `\",\"type\":\"meeting\",\"notes_plain\":\"Ship it\",\"google_calendar_event\":{\"id\":\"cal-1\",\"attendees\":[{\"email\":\"test@example.com\"},{\"email\":\"bob@example.com\",\"displayName\":\"Bob\"}]}}` +
+		`},\"documentPanels\":{}}}",
+		"version": 3
+	}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.cfg.GranolaDir, "cache-v4.json"), []byte(cacheContent), 0o644))
+
+	meetings, err := NewSyncer(s.cfg, s.store).ExportMeetings(SyncOptions{})
+	s.Require().NoError(err)
+	s.Require().Len(meetings, 1)
+	s.Equal("Roadmap", meetings[0].Title)
+	s.Equal("Ship it", meetings[0].Notes)
+	s.Contains(meetings[0].Attendees, export.Attendee{Name: "Bob", Email: "bob@example.com"})
+
+	s.cfg.AnonymizeAttendees = true
+	meetings, err = NewSyncer(s.cfg, s.store).ExportMeetings(SyncOptions{})
+	s.Require().NoError(err)
+	for _, a := range meetings[0].Attendees {
+		s.NotEqual("Bob", a.Name)
+		s.NotEqual("bob@example.com", a.Email)
+	}
+}
@@ -0,0 +1,216 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/config"
+	"github.com/philrhinehart/granola-sync/internal/granola"
+	"github.com/philrhinehart/granola-sync/internal/logseq"
+	"github.com/philrhinehart/granola-sync/internal/omnifocus"
+	"github.com/philrhinehart/granola-sync/internal/reminders"
+	"github.com/philrhinehart/granola-sync/internal/things"
+	"github.com/philrhinehart/granola-sync/internal/todoist"
+)
+
+// Output target names for action item exporters
+const (
+	TargetTodoist   = "todoist"
+	TargetReminders = "reminders"
+	TargetThings    = "things"
+	TargetOmniFocus = "omnifocus"
+)
+
+// actionItem is one of the user's action items from a meeting
+type actionItem struct {
+	Text         string
+	MeetingTitle string
+	PageName     string
+	PageURL      string // logseq:// link back to the meeting page
+}
+
+// taskExporter creates tasks for action items in an external app
+type taskExporter interface {
+	// target names the exporter in state and the circuit breaker
+	target() string
+	// export creates a task and returns its ID in the external app
+	export(ctx context.Context, item actionItem) (string, error)
+}
+
+// taskExporters builds the exporters enabled in config
+func (s *Syncer) taskExporters() []taskExporter {
+	var exporters []taskExporter
+	if s.cfg.TodoistTasks {
+		exporters = append(exporters, &todoistExporter{
+			token:     keychainToken{account: TargetTodoist},
+			projectID: s.cfg.TodoistProjectID,
+		})
+	}
+	if s.cfg.RemindersList != "" {
+		exporters = append(exporters, &remindersExporter{
+			client: reminders.NewClient(s.cfg.RemindersList),
+		})
+	}
+	if tm := s.cfg.TaskManager; tm != nil {
+		switch tm.App {
+		case config.TaskAppThings:
+			exporters = append(exporters, &thingsExporter{client: things.NewClient(tm.List, tm.Tags)})
+		case config.TaskAppOmniFocus:
+			exporters = append(exporters, &omnifocusExporter{client: omnifocus.NewClient(tm.List, tm.Tags)})
+		case config.TaskAppReminders:
+			exporters = append(exporters, &remindersExporter{client: reminders.NewClient(tm.List)})
+		}
+	}
+	return exporters
+}
+
+// actionItems returns the user's action items on a meeting's page
+func (s *Syncer) actionItems(g *graph, doc *granola.Document) []actionItem {
+	pageName := logseq.GetPageName(doc, s.cfg.PageNameTemplate)
+	var items []actionItem
+	for _, text := range g.writer.UserTodos(doc) {
+		items = append(items, actionItem{
+			Text:         text,
+			MeetingTitle: doc.Title,
+			PageName:     pageName,
+			PageURL:      logseq.PageURL(g.basePath, pageName),
+		})
+	}
+	return items
+}
+
+// exportActionItems sends the user's action items to each enabled exporter,
+// skipping items already exported so re-syncs don't create duplicates
+func (s *Syncer) exportActionItems(ctx context.Context, g *graph, doc *granola.Document) error {
+	if len(s.exporters) == 0 {
+		return nil
+	}
+	items := s.actionItems(g, doc)
+	if len(items) == 0 {
+		return nil
+	}
+
+	for _, exp := range s.exporters {
+		target := exp.target()
+		// Leave the document unsynced so the export is retried next run
+		if !s.breaker.allow(target) {
+			return errTargetDisabled(target)
+		}
+
+		created := 0
+		for _, item := range items {
+			key := taskKey(item.Text)
+			existing, err := s.store.GetExportedTask(target, doc.ID, key)
+			if err != nil {
+				return fmt.Errorf("checking exported tasks: %w", err)
+			}
+			if existing != "" {
+				continue
+			}
+
+			externalID, err := exp.export(ctx, item)
+			s.breaker.record(target, err)
+			if err != nil {
+				return fmt.Errorf("exporting action item to %s: %w", target, err)
+			}
+			if err := s.store.MarkTaskExported(target, doc.ID, key, externalID); err != nil {
+				return fmt.Errorf("recording exported task: %w", err)
+			}
+			created++
+		}
+		if created > 0 {
+			slog.Info("exported action items", "title", doc.Title, "target", target, "count", created)
+		}
+	}
+
+	return nil
+}
+
+// pendingActionItems counts the action items each exporter would create, for dry runs
+func (s *Syncer) pendingActionItems(g *graph, doc *granola.Document) map[string]int {
+	pending := make(map[string]int)
+	items := s.actionItems(g, doc)
+	for _, exp := range s.exporters {
+		for _, item := range items {
+			existing, err := s.store.GetExportedTask(exp.target(), doc.ID, taskKey(item.Text))
+			if err == nil && existing == "" {
+				pending[exp.target()]++
+			}
+		}
+	}
+	return pending
+}
+
+// taskKey identifies an action item within a meeting, ignoring case and spacing
+func taskKey(text string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// todoistExporter creates Todoist tasks
+type todoistExporter struct {
+	baseURL   string // Empty for the Todoist API; set in tests
+	token     keychainToken
+	projectID string
+}
+
+func (e *todoistExporter) target() string { return TargetTodoist }
+
+func (e *todoistExporter) export(ctx context.Context, item actionItem) (string, error) {
+	token, err := e.token.get()
+	if err != nil {
+		return "", err
+	}
+	return todoist.NewClient(e.baseURL, token).CreateTask(ctx, todoist.Task{
+		Content:     item.Text,
+		Description: fmt.Sprintf("From [%s](%s)", item.MeetingTitle, item.PageURL),
+		ProjectID:   e.projectID,
+	})
+}
+
+// remindersExporter creates Apple Reminders
+type remindersExporter struct {
+	client *reminders.Client
+}
+
+func (e *remindersExporter) target() string { return TargetReminders }
+
+func (e *remindersExporter) export(ctx context.Context, item actionItem) (string, error) {
+	return e.client.Create(ctx, reminders.Reminder{
+		Title: item.Text,
+		Notes: fmt.Sprintf("From %s\n%s", item.MeetingTitle, item.PageURL),
+	})
+}
+
+// thingsExporter creates Things to-dos
+type thingsExporter struct {
+	client *things.Client
+}
+
+func (e *thingsExporter) target() string { return TargetThings }
+
+func (e *thingsExporter) export(ctx context.Context, item actionItem) (string, error) {
+	return e.client.Create(ctx, things.ToDo{
+		Title: item.Text,
+		Notes: fmt.Sprintf("From %s\n%s", item.MeetingTitle, item.PageURL),
+	})
+}
+
+// omnifocusExporter creates OmniFocus tasks
+type omnifocusExporter struct {
+	client *omnifocus.Client
+}
+
+func (e *omnifocusExporter) target() string { return TargetOmniFocus }
+
+func (e *omnifocusExporter) export(ctx context.Context, item actionItem) (string, error) {
+	return e.client.Create(ctx, omnifocus.Task{
+		Title: item.Text,
+		Note:  fmt.Sprintf("From %s\n%s", item.MeetingTitle, item.PageURL),
+	})
+}
@@ -0,0 +1,73 @@
+// Package things creates to-dos in Things through osascript.
+package things
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/philrhinehart/granola-sync/internal/osascript"
+)
+
+// createScript makes a to-do in the project or area named by the first argument,
+// or the Inbox if it is empty, and prints the new to-do's ID. The destination is
+// checked first so a missing one doesn't leave a stray to-do in the Inbox.
+// Values are passed as arguments rather than interpolated so no quoting is
+// needed; tags are comma-separated, as Things expects.
+const createScript = `on run argv
+	set destName to item 1 of argv
+	tell application "Things3"
+		set destKind to "inbox"
+		if destName is not "" then
+			if exists project destName then
+				set destKind to "project"
+			else if exists area destName then
+				set destKind to "area"
+			else
+				error "no Things project or area named " & destName
+			end if
+		end if
+		set newToDo to make new to do with properties {name:(item 2 of argv), notes:(item 3 of argv), tag names:(item 4 of argv)}
+		if destKind is "project" then
+			set project of newToDo to project destName
+		else if destKind is "area" then
+			set area of newToDo to area destName
+		end if
+		return id of newToDo
+	end tell
+end run`
+
+// Client creates to-dos in one project or area.
+type Client struct {
+	list string
+	tags []string
+	// run is osascript.Run; replaced in tests
+	run func(ctx context.Context, script string, args ...string) ([]byte, error)
+}
+
+// NewClient creates a client for the named project or area, or the Inbox if
+// list is empty, tagging each to-do with tags.
+func NewClient(list string, tags []string) *Client {
+	return &Client{list: list, tags: tags, run: osascript.Run}
+}
+
+// ToDo is a to-do to create.
+type ToDo struct {
+	Title string
+	Notes string
+}
+
+// Create adds a to-do and returns its Things ID.
+func (c *Client) Create(ctx context.Context, todo ToDo) (string, error) {
+	output, err := c.run(ctx, createScript, c.list, todo.Title, todo.Notes, strings.Join(c.tags, ","))
+	if err != nil {
+		return "", fmt.Errorf("creating to-do: %w", err)
+	}
+
+	id := strings.TrimSpace(string(output))
+	if id == "" {
+		return "", errors.New("osascript returned no to-do id")
+	}
+	return id, nil
+}
@@ -0,0 +1,56 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreate() {
+	client := NewClient("Work", []string{"meetings", "follow-up"})
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		s.Equal(createScript, script)
+		s.Equal([]string{"Work", `Send the "deck"`, "From Roadmap\nlogseq://graph/notes?page=Roadmap", "meetings,follow-up"}, args)
+		return []byte("2Fb8YqKqNxUJ3VhYcPqJ4m\n"), nil
+	}
+
+	id, err := client.Create(context.Background(), ToDo{
+		Title: `Send the "deck"`,
+		Notes: "From Roadmap\nlogseq://graph/notes?page=Roadmap",
+	})
+
+	s.NoError(err)
+	s.Equal("2Fb8YqKqNxUJ3VhYcPqJ4m", id)
+}
+
+func (s *ClientSuite) TestCreateInInbox() {
+	client := NewClient("", nil)
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		s.Equal([]string{"", "x", "", ""}, args)
+		return []byte("abc\n"), nil
+	}
+
+	_, err := client.Create(context.Background(), ToDo{Title: "x"})
+	s.NoError(err)
+}
+
+func (s *ClientSuite) TestCreateError() {
+	client := NewClient("Wrok", nil)
+	client.run = func(ctx context.Context, script string, args ...string) ([]byte, error) {
+		return nil, errors.New("execution error: no Things project or area named Wrok (-2700): exit status 1")
+	}
+
+	_, err := client.Create(context.Background(), ToDo{Title: "x"})
+	s.Error(err)
+	s.Contains(err.Error(), "no Things project or area named Wrok")
+}
@@ -0,0 +1,91 @@
+// Package todoist creates tasks through the Todoist API.
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultBaseURL = "https://api.todoist.com/api/v1"
+
+// ErrUnauthorized is returned when Todoist rejects the API token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Client communicates with the Todoist API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewClient creates a new Todoist API client.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		baseURL: baseURL,
+		token:   token,
+	}
+}
+
+// Task is a task to create.
+type Task struct {
+	Content     string `json:"content"`
+	Description string `json:"description,omitempty"`
+	ProjectID   string `json:"project_id,omitempty"`
+}
+
+// taskResponse is the part of the created task we use.
+type taskResponse struct {
+	ID string `json:"id"`
+}
+
+// CreateTask creates a task and returns its Todoist ID.
+func (c *Client) CreateTask(ctx context.Context, task Task) (string, error) {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/tasks", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var created taskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if created.ID == "" {
+		return "", errors.New("API response has no task id")
+	}
+
+	return created.ID, nil
+}
@@ -0,0 +1,70 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) TestCreateTask() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("/tasks", r.URL.Path)
+		s.Equal("Bearer test-token", r.Header.Get("Authorization"))
+		s.Equal("application/json", r.Header.Get("Content-Type"))
+
+		var task Task
+		s.NoError(json.NewDecoder(r.Body).Decode(&task))
+		s.Equal("Send the deck", task.Content)
+		s.Equal("From [Roadmap](logseq://graph/notes?page=Roadmap)", task.Description)
+		s.Equal("proj-1", task.ProjectID)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "task-42", "content": "Send the deck"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	id, err := client.CreateTask(context.Background(), Task{
+		Content:     "Send the deck",
+		Description: "From [Roadmap](logseq://graph/notes?page=Roadmap)",
+		ProjectID:   "proj-1",
+	})
+
+	s.NoError(err)
+	s.Equal("task-42", id)
+}
+
+func (s *ClientSuite) TestCreateTaskUnauthorized() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "bad-token").CreateTask(context.Background(), Task{Content: "x"})
+	s.True(errors.Is(err, ErrUnauthorized))
+}
+
+func (s *ClientSuite) TestCreateTaskServerError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-token").CreateTask(context.Background(), Task{Content: "x"})
+	s.ErrorContains(err, "API returned 500: boom")
+}
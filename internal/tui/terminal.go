@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Terminal control sequences
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l" // Alternate screen, cursor hidden
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+// Run shows the screen on the terminal until the user quits. The terminal is
+// put in raw mode and restored on return.
+func Run(backend Backend) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("opening terminal: %w", err)
+	}
+	defer func() { _ = tty.Close() }()
+
+	saved, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		return fmt.Errorf("setting terminal to raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(int(tty.Fd()), saved) }()
+
+	_, _ = fmt.Fprint(tty, enterScreen)
+	defer func() { _, _ = fmt.Fprint(tty, leaveScreen) }()
+
+	m := newModel(backend)
+	m.message = "Loading meetings..."
+	draw(tty, m)
+	m.message = ""
+	m.refresh()
+
+	buf := make([]byte, 64)
+	for !m.quit {
+		draw(tty, m)
+		if len(m.syncIDs) > 0 {
+			m.runSync()
+			continue
+		}
+
+		n, err := tty.Read(buf)
+		if err != nil {
+			return fmt.Errorf("reading keys: %w", err)
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			m.handle(key)
+		}
+	}
+	return nil
+}
+
+// draw redraws the whole screen at the terminal's current size
+func draw(tty *os.File, m *model) {
+	if cols, rows, err := term.GetSize(int(tty.Fd())); err == nil && rows > 0 && cols > 0 {
+		m.height, m.width = rows, cols
+	}
+	m.move(0)
+	_, _ = fmt.Fprint(tty, clearScreen+m.view())
+}
+
+// escapeKeys are the escape sequences of the keys the screen uses
+var escapeKeys = map[string]string{
+	"\x1b[A":  "up",
+	"\x1b[B":  "down",
+	"\x1bOA":  "up",
+	"\x1bOB":  "down",
+	"\x1b[5~": "pgup",
+	"\x1b[6~": "pgdown",
+	"\x1b[H":  "home",
+	"\x1b[F":  "end",
+	"\x1b[1~": "home",
+	"\x1b[4~": "end",
+}
+
+// parseKeys turns bytes read from a raw terminal into key names: "up", "down",
+// "pgup", "pgdown", "home", "end", "enter", "esc", "space", "ctrl-c", or the
+// character typed. Unknown escape sequences are dropped.
+func parseKeys(b []byte) []string {
+	var keys []string
+	s := string(b)
+	for len(s) > 0 {
+		if s[0] == 0x1b {
+			if len(s) == 1 {
+				keys = append(keys, "esc")
+				break
+			}
+			matched := false
+			for seq, key := range escapeKeys {
+				if strings.HasPrefix(s, seq) {
+					keys = append(keys, key)
+					s = s[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// Skip an unknown sequence up to its final letter or ~
+				end := strings.IndexFunc(s[1:], func(r rune) bool {
+					return (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '~'
+				})
+				if end < 0 {
+					break
+				}
+				s = s[end+2:]
+			}
+			continue
+		}
+
+		r := []rune(s)[0]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case ' ':
+			keys = append(keys, "space")
+		case 0x03:
+			keys = append(keys, "ctrl-c")
+		default:
+			keys = append(keys, string(r))
+		}
+		s = s[len(string(r)):]
+	}
+	return keys
+}
@@ -0,0 +1,370 @@
+// Package tui is an interactive terminal screen for browsing the meetings in the
+// Granola cache, previewing their pages and syncing a selection.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Meeting is a meeting in the list
+type Meeting struct {
+	ID     string
+	Title  string
+	Date   time.Time
+	Status string // synced, unsynced or skipped
+	Reason string // Why the meeting is skipped or unsynced
+}
+
+// Backend is where the screen gets meetings and syncs them
+type Backend interface {
+	// Meetings lists the meetings in the cache with their sync status
+	Meetings() ([]Meeting, error)
+	// Preview returns the page a meeting renders to
+	Preview(id string) (string, error)
+	// Sync syncs the meetings and summarizes what happened
+	Sync(ids []string) (string, error)
+}
+
+// Statuses shown in the list
+const (
+	statusSynced   = "synced"
+	statusUnsynced = "unsynced"
+)
+
+// ANSI styles
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleGreen   = "\x1b[32m"
+	styleYellow  = "\x1b[33m"
+)
+
+// model is the screen's state. Keys change it and view draws it, so it can be
+// driven without a terminal.
+type model struct {
+	backend  Backend
+	meetings []Meeting
+	selected map[string]bool
+	cursor   int
+	offset   int // First meeting shown
+
+	// preview is the previewed page's lines, nil while listing
+	preview       []string
+	previewOffset int
+
+	message string
+	syncIDs []string // Meetings to sync once "Syncing..." has been drawn
+	quit    bool
+
+	width, height int
+}
+
+func newModel(backend Backend) *model {
+	return &model{backend: backend, selected: make(map[string]bool), width: 80, height: 24}
+}
+
+// refresh reloads the meetings, keeping the cursor on the same meeting and the
+// selection of meetings still listed
+func (m *model) refresh() {
+	var current string
+	if m.cursor < len(m.meetings) {
+		current = m.meetings[m.cursor].ID
+	}
+	meetings, err := m.backend.Meetings()
+	if err != nil {
+		m.message = fmt.Sprintf("Can't list meetings: %v", err)
+		return
+	}
+	m.meetings = meetings
+
+	listed := make(map[string]bool, len(meetings))
+	m.cursor = 0
+	for i, mt := range meetings {
+		listed[mt.ID] = true
+		if mt.ID == current {
+			m.cursor = i
+		}
+	}
+	for id := range m.selected {
+		if !listed[id] {
+			delete(m.selected, id)
+		}
+	}
+}
+
+// listRows is how many meetings fit on screen under the header and above the
+// message and help lines
+func (m *model) listRows() int {
+	return max(m.height-4, 1)
+}
+
+// handle applies a key (see parseKeys)
+func (m *model) handle(key string) {
+	if key == "ctrl-c" {
+		m.quit = true
+		return
+	}
+	if m.preview != nil {
+		m.handlePreview(key)
+		return
+	}
+
+	m.message = ""
+	switch key {
+	case "q", "esc":
+		m.quit = true
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.listRows())
+	case "pgdown":
+		m.move(m.listRows())
+	case "home", "g":
+		m.move(-len(m.meetings))
+	case "end", "G":
+		m.move(len(m.meetings))
+	case "space":
+		if mt, ok := m.current(); ok {
+			m.toggle(mt.ID)
+			m.move(1)
+		}
+	case "a":
+		m.selectUnsynced()
+	case "enter", "p":
+		m.openPreview()
+	case "s":
+		m.requestSync()
+	case "r":
+		m.refresh()
+	}
+}
+
+func (m *model) handlePreview(key string) {
+	rows := m.listRows()
+	switch key {
+	case "q", "esc", "enter", "p":
+		m.preview = nil
+	case "up", "k":
+		m.previewOffset--
+	case "down", "j":
+		m.previewOffset++
+	case "pgup":
+		m.previewOffset -= rows
+	case "pgdown":
+		m.previewOffset += rows
+	case "home", "g":
+		m.previewOffset = 0
+	case "end", "G":
+		m.previewOffset = len(m.preview)
+	case "space":
+		if mt, ok := m.current(); ok {
+			m.toggle(mt.ID)
+		}
+	case "s":
+		m.preview = nil
+		m.requestSync()
+		return
+	}
+	m.previewOffset = max(min(m.previewOffset, len(m.preview)-rows), 0)
+}
+
+func (m *model) current() (Meeting, bool) {
+	if m.cursor >= len(m.meetings) {
+		return Meeting{}, false
+	}
+	return m.meetings[m.cursor], true
+}
+
+// move moves the cursor by delta meetings, scrolling to keep it on screen
+func (m *model) move(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.meetings)-1), 0)
+	rows := m.listRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+func (m *model) toggle(id string) {
+	if m.selected[id] {
+		delete(m.selected, id)
+	} else {
+		m.selected[id] = true
+	}
+}
+
+// selectUnsynced selects every unsynced meeting, or clears the selection if
+// they are all selected already
+func (m *model) selectUnsynced() {
+	var unsynced []string
+	all := true
+	for _, mt := range m.meetings {
+		if mt.Status == statusUnsynced {
+			unsynced = append(unsynced, mt.ID)
+			all = all && m.selected[mt.ID]
+		}
+	}
+	if all {
+		clear(m.selected)
+		m.message = "Selection cleared"
+		return
+	}
+	for _, id := range unsynced {
+		m.selected[id] = true
+	}
+	m.message = fmt.Sprintf("Selected %d unsynced meetings", len(unsynced))
+}
+
+func (m *model) openPreview() {
+	mt, ok := m.current()
+	if !ok {
+		return
+	}
+	content, err := m.backend.Preview(mt.ID)
+	if err != nil {
+		m.message = fmt.Sprintf("Can't preview %s: %v", mt.Title, err)
+		return
+	}
+	m.preview = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	m.previewOffset = 0
+}
+
+// requestSync asks for the selected meetings, or the one under the cursor, to
+// be synced once the screen shows they are being synced
+func (m *model) requestSync() {
+	var ids []string
+	for _, mt := range m.meetings {
+		if m.selected[mt.ID] {
+			ids = append(ids, mt.ID)
+		}
+	}
+	if len(ids) == 0 {
+		mt, ok := m.current()
+		if !ok {
+			return
+		}
+		ids = []string{mt.ID}
+	}
+	m.syncIDs = ids
+	m.message = fmt.Sprintf("Syncing %d meetings...", len(ids))
+}
+
+// runSync runs a requested sync, then clears the selection and reloads the list
+func (m *model) runSync() {
+	if len(m.syncIDs) == 0 {
+		return
+	}
+	ids := m.syncIDs
+	m.syncIDs = nil
+	summary, err := m.backend.Sync(ids)
+	if err != nil {
+		m.message = fmt.Sprintf("Sync failed: %v", err)
+		return
+	}
+	clear(m.selected)
+	m.refresh()
+	m.message = summary
+}
+
+// view draws the screen as lines separated by \r\n, for a terminal in raw mode
+func (m *model) view() string {
+	var lines []string
+	if m.preview != nil {
+		lines = m.previewView()
+	} else {
+		lines = m.listView()
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+func (m *model) listView() []string {
+	header := fmt.Sprintf("%d meetings, %d selected", len(m.meetings), len(m.selected))
+	lines := []string{styleBold + truncate("granola-sync  "+header, m.width) + styleReset}
+
+	rows := m.listRows()
+	for i := m.offset; i < min(m.offset+rows, len(m.meetings)); i++ {
+		lines = append(lines, m.meetingLine(i))
+	}
+	for len(lines) < rows+1 {
+		lines = append(lines, "")
+	}
+
+	return append(lines,
+		"",
+		truncate(m.message, m.width),
+		styleDim+truncate("↑/↓ move  space select  a select unsynced  enter preview  s sync  r refresh  q quit", m.width)+styleReset,
+	)
+}
+
+func (m *model) meetingLine(i int) string {
+	mt := m.meetings[i]
+	check := "[ ]"
+	if m.selected[mt.ID] {
+		check = "[x]"
+	}
+	title := mt.Title
+	if mt.Reason != "" && mt.Status != statusSynced {
+		title += " (" + mt.Reason + ")"
+	}
+	line := truncate(fmt.Sprintf("%s %s  %-8s  %s", check, mt.Date.Local().Format("2006-01-02 15:04"), mt.Status, title), m.width)
+
+	if i == m.cursor {
+		return styleReverse + line + styleReset
+	}
+	switch mt.Status {
+	case statusSynced:
+		return styleGreen + line + styleReset
+	case statusUnsynced:
+		return styleYellow + line + styleReset
+	default:
+		return styleDim + line + styleReset
+	}
+}
+
+func (m *model) previewView() []string {
+	mt, _ := m.current()
+	check := ""
+	if m.selected[mt.ID] {
+		check = "  [selected]"
+	}
+	lines := []string{styleBold + truncate(mt.Title+check, m.width) + styleReset}
+
+	rows := m.listRows()
+	for i := m.previewOffset; i < min(m.previewOffset+rows, len(m.preview)); i++ {
+		lines = append(lines, truncate(expandTabs(m.preview[i]), m.width))
+	}
+	for len(lines) < rows+1 {
+		lines = append(lines, "")
+	}
+
+	return append(lines,
+		"",
+		"",
+		styleDim+truncate("↑/↓ scroll  space select  s sync  esc back", m.width)+styleReset,
+	)
+}
+
+// truncate shortens s to width characters
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// expandTabs replaces tabs, which Logseq indents with, by two spaces
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "  ")
+}
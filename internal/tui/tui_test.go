@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeBackend serves fixed meetings and marks synced ones
+type fakeBackend struct {
+	meetings []Meeting
+	synced   [][]string
+	syncErr  error
+}
+
+func (f *fakeBackend) Meetings() ([]Meeting, error) {
+	return append([]Meeting(nil), f.meetings...), nil
+}
+
+func (f *fakeBackend) Preview(id string) (string, error) {
+	return fmt.Sprintf("- Meeting %s\n\t- **Notes**\n\t\t- Ship the beta\n", id), nil
+}
+
+func (f *fakeBackend) Sync(ids []string) (string, error) {
+	if f.syncErr != nil {
+		return "", f.syncErr
+	}
+	f.synced = append(f.synced, ids)
+	for i := range f.meetings {
+		for _, id := range ids {
+			if f.meetings[i].ID == id {
+				f.meetings[i].Status = statusSynced
+				f.meetings[i].Reason = ""
+			}
+		}
+	}
+	return fmt.Sprintf("%d new", len(ids)), nil
+}
+
+type TUISuite struct {
+	suite.Suite
+	backend *fakeBackend
+	m       *model
+}
+
+func TestTUISuite(t *testing.T) {
+	suite.Run(t, new(TUISuite))
+}
+
+func (s *TUISuite) SetupTest() {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.Local) }
+	s.backend = &fakeBackend{meetings: []Meeting{
+		{ID: "m3", Title: "Standup", Date: day(28), Status: statusUnsynced, Reason: "not synced yet"},
+		{ID: "m2", Title: "Hiring", Date: day(20), Status: statusSynced},
+		{ID: "m1", Title: "Roadmap", Date: day(10), Status: statusUnsynced, Reason: "not synced yet"},
+		{ID: "m0", Title: "Lunch", Date: day(5), Status: "skipped", Reason: "title excluded"},
+	}}
+	s.m = newModel(s.backend)
+	s.m.refresh()
+}
+
+func (s *TUISuite) keys(keys ...string) {
+	for _, k := range keys {
+		s.m.handle(k)
+	}
+}
+
+func (s *TUISuite) TestSelectAndSync() {
+	s.keys("space", "space")
+	s.Equal(map[string]bool{"m3": true, "m2": true}, s.m.selected)
+	s.Equal(2, s.m.cursor)
+
+	s.keys("k", "space")
+	s.Equal(map[string]bool{"m3": true}, s.m.selected, "space on a selected meeting deselects it")
+
+	s.keys("s")
+	s.Equal("Syncing 1 meetings...", s.m.message)
+	s.Empty(s.backend.synced, "the sync waits until the screen shows it")
+
+	s.m.runSync()
+	s.Equal([][]string{{"m3"}}, s.backend.synced)
+	s.Equal("1 new", s.m.message)
+	s.Empty(s.m.selected)
+	s.Equal(statusSynced, s.m.meetings[0].Status)
+}
+
+func (s *TUISuite) TestSyncCurrentWithoutSelection() {
+	s.keys("j", "j", "s")
+	s.m.runSync()
+	s.Equal([][]string{{"m1"}}, s.backend.synced)
+	s.Equal(2, s.m.cursor, "the cursor stays on the meeting")
+}
+
+func (s *TUISuite) TestSyncError() {
+	s.backend.syncErr = errors.New("cache locked")
+	s.keys("a", "s")
+	s.m.runSync()
+	s.Equal("Sync failed: cache locked", s.m.message)
+	s.Len(s.m.selected, 2, "the selection is kept to retry")
+}
+
+func (s *TUISuite) TestSelectUnsynced() {
+	s.keys("a")
+	s.Equal(map[string]bool{"m3": true, "m1": true}, s.m.selected)
+	s.keys("a")
+	s.Empty(s.m.selected)
+}
+
+func (s *TUISuite) TestPreview() {
+	s.keys("j", "enter")
+	s.Equal([]string{"- Meeting m2", "\t- **Notes**", "\t\t- Ship the beta"}, s.m.preview)
+	view := s.m.view()
+	s.Contains(view, "Hiring")
+	s.Contains(view, "    - Ship the beta")
+
+	s.keys("space")
+	s.True(s.m.selected["m2"])
+	s.Equal(1, s.m.cursor, "selecting in the preview doesn't move")
+
+	s.keys("esc")
+	s.Nil(s.m.preview)
+	s.False(s.m.quit)
+	s.keys("q")
+	s.True(s.m.quit)
+}
+
+func (s *TUISuite) TestScrolling() {
+	s.m.height = 6 // Two meetings fit
+	s.keys("end")
+	s.Equal(3, s.m.cursor)
+	s.Equal(2, s.m.offset)
+
+	view := s.m.view()
+	s.Contains(view, "Lunch (title excluded)")
+	s.NotContains(view, "Standup")
+
+	s.keys("pgup")
+	s.Equal(1, s.m.cursor)
+	s.Equal(1, s.m.offset)
+}
+
+func (s *TUISuite) TestViewTruncates() {
+	s.m.width = 30
+	for _, line := range strings.Split(s.m.view(), "\r\n") {
+		for _, style := range []string{styleReset, styleBold, styleDim, styleReverse, styleGreen, styleYellow} {
+			line = strings.ReplaceAll(line, style, "")
+		}
+		s.LessOrEqual(len([]rune(line)), 30, line)
+	}
+}
+
+func (s *TUISuite) TestParseKeys() {
+	s.Equal([]string{"up", "down", "pgdown", "esc"}, parseKeys([]byte("\x1b[A\x1bOB\x1b[6~\x1b")))
+	s.Equal([]string{"j", "space", "enter", "ctrl-c"}, parseKeys([]byte("j \r\x03")))
+	s.Equal([]string{"q"}, parseKeys([]byte("\x1b[1;5Cq")), "unknown sequences are dropped")
+}